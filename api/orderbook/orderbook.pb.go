@@ -21,9 +21,11 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// Levels are returned best first; a depth of 0 returns every level.
 type OrderBookSnapshotRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Depth         int32                  `protobuf:"varint,2,opt,name=depth,proto3" json:"depth,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -65,6 +67,93 @@ func (x *OrderBookSnapshotRequest) GetSymbol() string {
 	return ""
 }
 
+func (x *OrderBookSnapshotRequest) GetDepth() int32 {
+	if x != nil {
+		return x.Depth
+	}
+	return 0
+}
+
+type ListSymbolsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSymbolsRequest) Reset() {
+	*x = ListSymbolsRequest{}
+	mi := &file_api_orderbook_orderbook_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSymbolsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSymbolsRequest) ProtoMessage() {}
+
+func (x *ListSymbolsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_orderbook_orderbook_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSymbolsRequest.ProtoReflect.Descriptor instead.
+func (*ListSymbolsRequest) Descriptor() ([]byte, []int) {
+	return file_api_orderbook_orderbook_proto_rawDescGZIP(), []int{1}
+}
+
+type ListSymbolsReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbols       []string               `protobuf:"bytes,1,rep,name=symbols,proto3" json:"symbols,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSymbolsReply) Reset() {
+	*x = ListSymbolsReply{}
+	mi := &file_api_orderbook_orderbook_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSymbolsReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSymbolsReply) ProtoMessage() {}
+
+func (x *ListSymbolsReply) ProtoReflect() protoreflect.Message {
+	mi := &file_api_orderbook_orderbook_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSymbolsReply.ProtoReflect.Descriptor instead.
+func (*ListSymbolsReply) Descriptor() ([]byte, []int) {
+	return file_api_orderbook_orderbook_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListSymbolsReply) GetSymbols() []string {
+	if x != nil {
+		return x.Symbols
+	}
+	return nil
+}
+
 type Order struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Price         string                 `protobuf:"bytes,1,opt,name=price,proto3" json:"price,omitempty"`
@@ -75,7 +164,7 @@ type Order struct {
 
 func (x *Order) Reset() {
 	*x = Order{}
-	mi := &file_api_orderbook_orderbook_proto_msgTypes[1]
+	mi := &file_api_orderbook_orderbook_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -87,7 +176,7 @@ func (x *Order) String() string {
 func (*Order) ProtoMessage() {}
 
 func (x *Order) ProtoReflect() protoreflect.Message {
-	mi := &file_api_orderbook_orderbook_proto_msgTypes[1]
+	mi := &file_api_orderbook_orderbook_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -100,7 +189,7 @@ func (x *Order) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Order.ProtoReflect.Descriptor instead.
 func (*Order) Descriptor() ([]byte, []int) {
-	return file_api_orderbook_orderbook_proto_rawDescGZIP(), []int{1}
+	return file_api_orderbook_orderbook_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *Order) GetPrice() string {
@@ -129,7 +218,7 @@ type GetSnapshotReply struct {
 
 func (x *GetSnapshotReply) Reset() {
 	*x = GetSnapshotReply{}
-	mi := &file_api_orderbook_orderbook_proto_msgTypes[2]
+	mi := &file_api_orderbook_orderbook_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -141,7 +230,7 @@ func (x *GetSnapshotReply) String() string {
 func (*GetSnapshotReply) ProtoMessage() {}
 
 func (x *GetSnapshotReply) ProtoReflect() protoreflect.Message {
-	mi := &file_api_orderbook_orderbook_proto_msgTypes[2]
+	mi := &file_api_orderbook_orderbook_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -154,7 +243,7 @@ func (x *GetSnapshotReply) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetSnapshotReply.ProtoReflect.Descriptor instead.
 func (*GetSnapshotReply) Descriptor() ([]byte, []int) {
-	return file_api_orderbook_orderbook_proto_rawDescGZIP(), []int{2}
+	return file_api_orderbook_orderbook_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *GetSnapshotReply) GetSymbol() string {
@@ -185,23 +274,1557 @@ func (x *GetSnapshotReply) GetAsks() []*Order {
 	return nil
 }
 
-var File_api_orderbook_orderbook_proto protoreflect.FileDescriptor
+type ConsolidatedSnapshotRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Instrument    string                 `protobuf:"bytes,1,opt,name=instrument,proto3" json:"instrument,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-const file_api_orderbook_orderbook_proto_rawDesc = "" +
-	"\n" +
-	"\x1dapi/orderbook/orderbook.proto\x12\torderbook\"2\n" +
-	"\x18OrderBookSnapshotRequest\x12\x16\n" +
-	"\x06symbol\x18\x01 \x01(\tR\x06symbol\"5\n" +
-	"\x05Order\x12\x14\n" +
-	"\x05price\x18\x01 \x01(\tR\x05price\x12\x16\n" +
-	"\x06amount\x18\x02 \x01(\tR\x06amount\"\x9a\x01\n" +
-	"\x10GetSnapshotReply\x12\x16\n" +
-	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\"\n" +
-	"\flastUpdateId\x18\x02 \x01(\tR\flastUpdateId\x12$\n" +
-	"\x04bids\x18\x03 \x03(\v2\x10.orderbook.OrderR\x04bids\x12$\n" +
-	"\x04asks\x18\x04 \x03(\v2\x10.orderbook.OrderR\x04asks2^\n" +
-	"\tOrderBook\x12Q\n" +
-	"\vGetSnapshot\x12#.orderbook.OrderBookSnapshotRequest\x1a\x1b.orderbook.GetSnapshotReply\"\x00B@Z>github.com/ChethiyaNishanath/market-data-hub/cmd/api/orderbookb\x06proto3"
+func (x *ConsolidatedSnapshotRequest) Reset() {
+	*x = ConsolidatedSnapshotRequest{}
+	mi := &file_api_orderbook_orderbook_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConsolidatedSnapshotRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConsolidatedSnapshotRequest) ProtoMessage() {}
+
+func (x *ConsolidatedSnapshotRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_orderbook_orderbook_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConsolidatedSnapshotRequest.ProtoReflect.Descriptor instead.
+func (*ConsolidatedSnapshotRequest) Descriptor() ([]byte, []int) {
+	return file_api_orderbook_orderbook_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ConsolidatedSnapshotRequest) GetInstrument() string {
+	if x != nil {
+		return x.Instrument
+	}
+	return ""
+}
+
+type VenueQuantity struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Venue         string                 `protobuf:"bytes,1,opt,name=venue,proto3" json:"venue,omitempty"`
+	Quantity      float64                `protobuf:"fixed64,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VenueQuantity) Reset() {
+	*x = VenueQuantity{}
+	mi := &file_api_orderbook_orderbook_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VenueQuantity) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VenueQuantity) ProtoMessage() {}
+
+func (x *VenueQuantity) ProtoReflect() protoreflect.Message {
+	mi := &file_api_orderbook_orderbook_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VenueQuantity.ProtoReflect.Descriptor instead.
+func (*VenueQuantity) Descriptor() ([]byte, []int) {
+	return file_api_orderbook_orderbook_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *VenueQuantity) GetVenue() string {
+	if x != nil {
+		return x.Venue
+	}
+	return ""
+}
+
+func (x *VenueQuantity) GetQuantity() float64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+type ConsolidatedLevel struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Price         float64                `protobuf:"fixed64,1,opt,name=price,proto3" json:"price,omitempty"`
+	Quantity      float64                `protobuf:"fixed64,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Venues        []*VenueQuantity       `protobuf:"bytes,3,rep,name=venues,proto3" json:"venues,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConsolidatedLevel) Reset() {
+	*x = ConsolidatedLevel{}
+	mi := &file_api_orderbook_orderbook_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConsolidatedLevel) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConsolidatedLevel) ProtoMessage() {}
+
+func (x *ConsolidatedLevel) ProtoReflect() protoreflect.Message {
+	mi := &file_api_orderbook_orderbook_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConsolidatedLevel.ProtoReflect.Descriptor instead.
+func (*ConsolidatedLevel) Descriptor() ([]byte, []int) {
+	return file_api_orderbook_orderbook_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ConsolidatedLevel) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *ConsolidatedLevel) GetQuantity() float64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *ConsolidatedLevel) GetVenues() []*VenueQuantity {
+	if x != nil {
+		return x.Venues
+	}
+	return nil
+}
+
+type ConsolidatedSnapshotReply struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Instrument     string                 `protobuf:"bytes,1,opt,name=instrument,proto3" json:"instrument,omitempty"`
+	Bids           []*ConsolidatedLevel   `protobuf:"bytes,2,rep,name=bids,proto3" json:"bids,omitempty"`
+	Asks           []*ConsolidatedLevel   `protobuf:"bytes,3,rep,name=asks,proto3" json:"asks,omitempty"`
+	Venues         []string               `protobuf:"bytes,4,rep,name=venues,proto3" json:"venues,omitempty"`
+	ExcludedVenues []string               `protobuf:"bytes,5,rep,name=excludedVenues,proto3" json:"excludedVenues,omitempty"`
+	Timestamp      int64                  `protobuf:"varint,6,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ConsolidatedSnapshotReply) Reset() {
+	*x = ConsolidatedSnapshotReply{}
+	mi := &file_api_orderbook_orderbook_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConsolidatedSnapshotReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConsolidatedSnapshotReply) ProtoMessage() {}
+
+func (x *ConsolidatedSnapshotReply) ProtoReflect() protoreflect.Message {
+	mi := &file_api_orderbook_orderbook_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConsolidatedSnapshotReply.ProtoReflect.Descriptor instead.
+func (*ConsolidatedSnapshotReply) Descriptor() ([]byte, []int) {
+	return file_api_orderbook_orderbook_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ConsolidatedSnapshotReply) GetInstrument() string {
+	if x != nil {
+		return x.Instrument
+	}
+	return ""
+}
+
+func (x *ConsolidatedSnapshotReply) GetBids() []*ConsolidatedLevel {
+	if x != nil {
+		return x.Bids
+	}
+	return nil
+}
+
+func (x *ConsolidatedSnapshotReply) GetAsks() []*ConsolidatedLevel {
+	if x != nil {
+		return x.Asks
+	}
+	return nil
+}
+
+func (x *ConsolidatedSnapshotReply) GetVenues() []string {
+	if x != nil {
+		return x.Venues
+	}
+	return nil
+}
+
+func (x *ConsolidatedSnapshotReply) GetExcludedVenues() []string {
+	if x != nil {
+		return x.ExcludedVenues
+	}
+	return nil
+}
+
+func (x *ConsolidatedSnapshotReply) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+type StatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatsRequest) Reset() {
+	*x = StatsRequest{}
+	mi := &file_api_orderbook_orderbook_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatsRequest) ProtoMessage() {}
+
+func (x *StatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_orderbook_orderbook_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatsRequest.ProtoReflect.Descriptor instead.
+func (*StatsRequest) Descriptor() ([]byte, []int) {
+	return file_api_orderbook_orderbook_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *StatsRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+type DepthBand struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Bps           float64                `protobuf:"fixed64,1,opt,name=bps,proto3" json:"bps,omitempty"`
+	BidQuantity   float64                `protobuf:"fixed64,2,opt,name=bidQuantity,proto3" json:"bidQuantity,omitempty"`
+	AskQuantity   float64                `protobuf:"fixed64,3,opt,name=askQuantity,proto3" json:"askQuantity,omitempty"`
+	BidNotional   float64                `protobuf:"fixed64,4,opt,name=bidNotional,proto3" json:"bidNotional,omitempty"`
+	AskNotional   float64                `protobuf:"fixed64,5,opt,name=askNotional,proto3" json:"askNotional,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DepthBand) Reset() {
+	*x = DepthBand{}
+	mi := &file_api_orderbook_orderbook_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DepthBand) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DepthBand) ProtoMessage() {}
+
+func (x *DepthBand) ProtoReflect() protoreflect.Message {
+	mi := &file_api_orderbook_orderbook_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DepthBand.ProtoReflect.Descriptor instead.
+func (*DepthBand) Descriptor() ([]byte, []int) {
+	return file_api_orderbook_orderbook_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *DepthBand) GetBps() float64 {
+	if x != nil {
+		return x.Bps
+	}
+	return 0
+}
+
+func (x *DepthBand) GetBidQuantity() float64 {
+	if x != nil {
+		return x.BidQuantity
+	}
+	return 0
+}
+
+func (x *DepthBand) GetAskQuantity() float64 {
+	if x != nil {
+		return x.AskQuantity
+	}
+	return 0
+}
+
+func (x *DepthBand) GetBidNotional() float64 {
+	if x != nil {
+		return x.BidNotional
+	}
+	return 0
+}
+
+func (x *DepthBand) GetAskNotional() float64 {
+	if x != nil {
+		return x.AskNotional
+	}
+	return 0
+}
+
+type StatsReply struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Symbol          string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	LastUpdateId    int64                  `protobuf:"varint,2,opt,name=lastUpdateId,proto3" json:"lastUpdateId,omitempty"`
+	BestBid         float64                `protobuf:"fixed64,3,opt,name=bestBid,proto3" json:"bestBid,omitempty"`
+	BestBidQuantity float64                `protobuf:"fixed64,4,opt,name=bestBidQuantity,proto3" json:"bestBidQuantity,omitempty"`
+	BestAsk         float64                `protobuf:"fixed64,5,opt,name=bestAsk,proto3" json:"bestAsk,omitempty"`
+	BestAskQuantity float64                `protobuf:"fixed64,6,opt,name=bestAskQuantity,proto3" json:"bestAskQuantity,omitempty"`
+	Mid             float64                `protobuf:"fixed64,7,opt,name=mid,proto3" json:"mid,omitempty"`
+	Microprice      float64                `protobuf:"fixed64,8,opt,name=microprice,proto3" json:"microprice,omitempty"`
+	SpreadBps       float64                `protobuf:"fixed64,9,opt,name=spreadBps,proto3" json:"spreadBps,omitempty"`
+	Imbalance       float64                `protobuf:"fixed64,10,opt,name=imbalance,proto3" json:"imbalance,omitempty"`
+	ImbalanceLevels int32                  `protobuf:"varint,11,opt,name=imbalanceLevels,proto3" json:"imbalanceLevels,omitempty"`
+	DepthBands      []*DepthBand           `protobuf:"bytes,12,rep,name=depthBands,proto3" json:"depthBands,omitempty"`
+	Timestamp       int64                  `protobuf:"varint,13,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *StatsReply) Reset() {
+	*x = StatsReply{}
+	mi := &file_api_orderbook_orderbook_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatsReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatsReply) ProtoMessage() {}
+
+func (x *StatsReply) ProtoReflect() protoreflect.Message {
+	mi := &file_api_orderbook_orderbook_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatsReply.ProtoReflect.Descriptor instead.
+func (*StatsReply) Descriptor() ([]byte, []int) {
+	return file_api_orderbook_orderbook_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *StatsReply) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *StatsReply) GetLastUpdateId() int64 {
+	if x != nil {
+		return x.LastUpdateId
+	}
+	return 0
+}
+
+func (x *StatsReply) GetBestBid() float64 {
+	if x != nil {
+		return x.BestBid
+	}
+	return 0
+}
+
+func (x *StatsReply) GetBestBidQuantity() float64 {
+	if x != nil {
+		return x.BestBidQuantity
+	}
+	return 0
+}
+
+func (x *StatsReply) GetBestAsk() float64 {
+	if x != nil {
+		return x.BestAsk
+	}
+	return 0
+}
+
+func (x *StatsReply) GetBestAskQuantity() float64 {
+	if x != nil {
+		return x.BestAskQuantity
+	}
+	return 0
+}
+
+func (x *StatsReply) GetMid() float64 {
+	if x != nil {
+		return x.Mid
+	}
+	return 0
+}
+
+func (x *StatsReply) GetMicroprice() float64 {
+	if x != nil {
+		return x.Microprice
+	}
+	return 0
+}
+
+func (x *StatsReply) GetSpreadBps() float64 {
+	if x != nil {
+		return x.SpreadBps
+	}
+	return 0
+}
+
+func (x *StatsReply) GetImbalance() float64 {
+	if x != nil {
+		return x.Imbalance
+	}
+	return 0
+}
+
+func (x *StatsReply) GetImbalanceLevels() int32 {
+	if x != nil {
+		return x.ImbalanceLevels
+	}
+	return 0
+}
+
+func (x *StatsReply) GetDepthBands() []*DepthBand {
+	if x != nil {
+		return x.DepthBands
+	}
+	return nil
+}
+
+func (x *StatsReply) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+type ImpactRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Side          string                 `protobuf:"bytes,2,opt,name=side,proto3" json:"side,omitempty"`
+	Quantity      float64                `protobuf:"fixed64,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Notional      float64                `protobuf:"fixed64,4,opt,name=notional,proto3" json:"notional,omitempty"`
+	Consolidated  bool                   `protobuf:"varint,5,opt,name=consolidated,proto3" json:"consolidated,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImpactRequest) Reset() {
+	*x = ImpactRequest{}
+	mi := &file_api_orderbook_orderbook_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImpactRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImpactRequest) ProtoMessage() {}
+
+func (x *ImpactRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_orderbook_orderbook_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImpactRequest.ProtoReflect.Descriptor instead.
+func (*ImpactRequest) Descriptor() ([]byte, []int) {
+	return file_api_orderbook_orderbook_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ImpactRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *ImpactRequest) GetSide() string {
+	if x != nil {
+		return x.Side
+	}
+	return ""
+}
+
+func (x *ImpactRequest) GetQuantity() float64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *ImpactRequest) GetNotional() float64 {
+	if x != nil {
+		return x.Notional
+	}
+	return 0
+}
+
+func (x *ImpactRequest) GetConsolidated() bool {
+	if x != nil {
+		return x.Consolidated
+	}
+	return false
+}
+
+type ImpactReply struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Symbol            string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Side              string                 `protobuf:"bytes,2,opt,name=side,proto3" json:"side,omitempty"`
+	Source            string                 `protobuf:"bytes,3,opt,name=source,proto3" json:"source,omitempty"`
+	LastUpdateId      int64                  `protobuf:"varint,4,opt,name=lastUpdateId,proto3" json:"lastUpdateId,omitempty"`
+	RequestedQuantity float64                `protobuf:"fixed64,5,opt,name=requestedQuantity,proto3" json:"requestedQuantity,omitempty"`
+	RequestedNotional float64                `protobuf:"fixed64,6,opt,name=requestedNotional,proto3" json:"requestedNotional,omitempty"`
+	FilledQuantity    float64                `protobuf:"fixed64,7,opt,name=filledQuantity,proto3" json:"filledQuantity,omitempty"`
+	FilledNotional    float64                `protobuf:"fixed64,8,opt,name=filledNotional,proto3" json:"filledNotional,omitempty"`
+	AveragePrice      float64                `protobuf:"fixed64,9,opt,name=averagePrice,proto3" json:"averagePrice,omitempty"`
+	WorstPrice        float64                `protobuf:"fixed64,10,opt,name=worstPrice,proto3" json:"worstPrice,omitempty"`
+	Mid               float64                `protobuf:"fixed64,11,opt,name=mid,proto3" json:"mid,omitempty"`
+	SlippageBps       float64                `protobuf:"fixed64,12,opt,name=slippageBps,proto3" json:"slippageBps,omitempty"`
+	LevelsConsumed    int32                  `protobuf:"varint,13,opt,name=levelsConsumed,proto3" json:"levelsConsumed,omitempty"`
+	FullyFilled       bool                   `protobuf:"varint,14,opt,name=fullyFilled,proto3" json:"fullyFilled,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *ImpactReply) Reset() {
+	*x = ImpactReply{}
+	mi := &file_api_orderbook_orderbook_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImpactReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImpactReply) ProtoMessage() {}
+
+func (x *ImpactReply) ProtoReflect() protoreflect.Message {
+	mi := &file_api_orderbook_orderbook_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImpactReply.ProtoReflect.Descriptor instead.
+func (*ImpactReply) Descriptor() ([]byte, []int) {
+	return file_api_orderbook_orderbook_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ImpactReply) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *ImpactReply) GetSide() string {
+	if x != nil {
+		return x.Side
+	}
+	return ""
+}
+
+func (x *ImpactReply) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *ImpactReply) GetLastUpdateId() int64 {
+	if x != nil {
+		return x.LastUpdateId
+	}
+	return 0
+}
+
+func (x *ImpactReply) GetRequestedQuantity() float64 {
+	if x != nil {
+		return x.RequestedQuantity
+	}
+	return 0
+}
+
+func (x *ImpactReply) GetRequestedNotional() float64 {
+	if x != nil {
+		return x.RequestedNotional
+	}
+	return 0
+}
+
+func (x *ImpactReply) GetFilledQuantity() float64 {
+	if x != nil {
+		return x.FilledQuantity
+	}
+	return 0
+}
+
+func (x *ImpactReply) GetFilledNotional() float64 {
+	if x != nil {
+		return x.FilledNotional
+	}
+	return 0
+}
+
+func (x *ImpactReply) GetAveragePrice() float64 {
+	if x != nil {
+		return x.AveragePrice
+	}
+	return 0
+}
+
+func (x *ImpactReply) GetWorstPrice() float64 {
+	if x != nil {
+		return x.WorstPrice
+	}
+	return 0
+}
+
+func (x *ImpactReply) GetMid() float64 {
+	if x != nil {
+		return x.Mid
+	}
+	return 0
+}
+
+func (x *ImpactReply) GetSlippageBps() float64 {
+	if x != nil {
+		return x.SlippageBps
+	}
+	return 0
+}
+
+func (x *ImpactReply) GetLevelsConsumed() int32 {
+	if x != nil {
+		return x.LevelsConsumed
+	}
+	return 0
+}
+
+func (x *ImpactReply) GetFullyFilled() bool {
+	if x != nil {
+		return x.FullyFilled
+	}
+	return false
+}
+
+// Timestamps are Unix milliseconds; a depth of 0 returns every level.
+type HistoricalSnapshotRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Timestamp     int64                  `protobuf:"varint,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Depth         int32                  `protobuf:"varint,3,opt,name=depth,proto3" json:"depth,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HistoricalSnapshotRequest) Reset() {
+	*x = HistoricalSnapshotRequest{}
+	mi := &file_api_orderbook_orderbook_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HistoricalSnapshotRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HistoricalSnapshotRequest) ProtoMessage() {}
+
+func (x *HistoricalSnapshotRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_orderbook_orderbook_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HistoricalSnapshotRequest.ProtoReflect.Descriptor instead.
+func (*HistoricalSnapshotRequest) Descriptor() ([]byte, []int) {
+	return file_api_orderbook_orderbook_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *HistoricalSnapshotRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *HistoricalSnapshotRequest) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *HistoricalSnapshotRequest) GetDepth() int32 {
+	if x != nil {
+		return x.Depth
+	}
+	return 0
+}
+
+type HistoricalRangeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	From          int64                  `protobuf:"varint,2,opt,name=from,proto3" json:"from,omitempty"`
+	To            int64                  `protobuf:"varint,3,opt,name=to,proto3" json:"to,omitempty"`
+	IntervalMs    int64                  `protobuf:"varint,4,opt,name=intervalMs,proto3" json:"intervalMs,omitempty"`
+	Depth         int32                  `protobuf:"varint,5,opt,name=depth,proto3" json:"depth,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HistoricalRangeRequest) Reset() {
+	*x = HistoricalRangeRequest{}
+	mi := &file_api_orderbook_orderbook_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HistoricalRangeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HistoricalRangeRequest) ProtoMessage() {}
+
+func (x *HistoricalRangeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_orderbook_orderbook_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HistoricalRangeRequest.ProtoReflect.Descriptor instead.
+func (*HistoricalRangeRequest) Descriptor() ([]byte, []int) {
+	return file_api_orderbook_orderbook_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *HistoricalRangeRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *HistoricalRangeRequest) GetFrom() int64 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *HistoricalRangeRequest) GetTo() int64 {
+	if x != nil {
+		return x.To
+	}
+	return 0
+}
+
+func (x *HistoricalRangeRequest) GetIntervalMs() int64 {
+	if x != nil {
+		return x.IntervalMs
+	}
+	return 0
+}
+
+func (x *HistoricalRangeRequest) GetDepth() int32 {
+	if x != nil {
+		return x.Depth
+	}
+	return 0
+}
+
+type HistoricalSnapshotReply struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	Symbol              string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Timestamp           int64                  `protobuf:"varint,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	LastUpdateId        int64                  `protobuf:"varint,3,opt,name=lastUpdateId,proto3" json:"lastUpdateId,omitempty"`
+	Bids                []*Order               `protobuf:"bytes,4,rep,name=bids,proto3" json:"bids,omitempty"`
+	Asks                []*Order               `protobuf:"bytes,5,rep,name=asks,proto3" json:"asks,omitempty"`
+	CheckpointTimestamp int64                  `protobuf:"varint,6,opt,name=checkpointTimestamp,proto3" json:"checkpointTimestamp,omitempty"`
+	DeltasApplied       int32                  `protobuf:"varint,7,opt,name=deltasApplied,proto3" json:"deltasApplied,omitempty"`
+	Complete            bool                   `protobuf:"varint,8,opt,name=complete,proto3" json:"complete,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *HistoricalSnapshotReply) Reset() {
+	*x = HistoricalSnapshotReply{}
+	mi := &file_api_orderbook_orderbook_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HistoricalSnapshotReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HistoricalSnapshotReply) ProtoMessage() {}
+
+func (x *HistoricalSnapshotReply) ProtoReflect() protoreflect.Message {
+	mi := &file_api_orderbook_orderbook_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HistoricalSnapshotReply.ProtoReflect.Descriptor instead.
+func (*HistoricalSnapshotReply) Descriptor() ([]byte, []int) {
+	return file_api_orderbook_orderbook_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *HistoricalSnapshotReply) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *HistoricalSnapshotReply) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *HistoricalSnapshotReply) GetLastUpdateId() int64 {
+	if x != nil {
+		return x.LastUpdateId
+	}
+	return 0
+}
+
+func (x *HistoricalSnapshotReply) GetBids() []*Order {
+	if x != nil {
+		return x.Bids
+	}
+	return nil
+}
+
+func (x *HistoricalSnapshotReply) GetAsks() []*Order {
+	if x != nil {
+		return x.Asks
+	}
+	return nil
+}
+
+func (x *HistoricalSnapshotReply) GetCheckpointTimestamp() int64 {
+	if x != nil {
+		return x.CheckpointTimestamp
+	}
+	return 0
+}
+
+func (x *HistoricalSnapshotReply) GetDeltasApplied() int32 {
+	if x != nil {
+		return x.DeltasApplied
+	}
+	return 0
+}
+
+func (x *HistoricalSnapshotReply) GetComplete() bool {
+	if x != nil {
+		return x.Complete
+	}
+	return false
+}
+
+// Timestamps are Unix milliseconds; a zero from or to leaves that end open.
+type CandlesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Interval      string                 `protobuf:"bytes,2,opt,name=interval,proto3" json:"interval,omitempty"`
+	From          int64                  `protobuf:"varint,3,opt,name=from,proto3" json:"from,omitempty"`
+	To            int64                  `protobuf:"varint,4,opt,name=to,proto3" json:"to,omitempty"`
+	Limit         int32                  `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CandlesRequest) Reset() {
+	*x = CandlesRequest{}
+	mi := &file_api_orderbook_orderbook_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CandlesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CandlesRequest) ProtoMessage() {}
+
+func (x *CandlesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_orderbook_orderbook_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CandlesRequest.ProtoReflect.Descriptor instead.
+func (*CandlesRequest) Descriptor() ([]byte, []int) {
+	return file_api_orderbook_orderbook_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *CandlesRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *CandlesRequest) GetInterval() string {
+	if x != nil {
+		return x.Interval
+	}
+	return ""
+}
+
+func (x *CandlesRequest) GetFrom() int64 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *CandlesRequest) GetTo() int64 {
+	if x != nil {
+		return x.To
+	}
+	return 0
+}
+
+func (x *CandlesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type Candle struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OpenTime      int64                  `protobuf:"varint,1,opt,name=openTime,proto3" json:"openTime,omitempty"`
+	Open          float64                `protobuf:"fixed64,2,opt,name=open,proto3" json:"open,omitempty"`
+	High          float64                `protobuf:"fixed64,3,opt,name=high,proto3" json:"high,omitempty"`
+	Low           float64                `protobuf:"fixed64,4,opt,name=low,proto3" json:"low,omitempty"`
+	Close         float64                `protobuf:"fixed64,5,opt,name=close,proto3" json:"close,omitempty"`
+	Volume        float64                `protobuf:"fixed64,6,opt,name=volume,proto3" json:"volume,omitempty"`
+	QuoteVolume   float64                `protobuf:"fixed64,7,opt,name=quoteVolume,proto3" json:"quoteVolume,omitempty"`
+	Trades        int32                  `protobuf:"varint,8,opt,name=trades,proto3" json:"trades,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Candle) Reset() {
+	*x = Candle{}
+	mi := &file_api_orderbook_orderbook_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Candle) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Candle) ProtoMessage() {}
+
+func (x *Candle) ProtoReflect() protoreflect.Message {
+	mi := &file_api_orderbook_orderbook_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Candle.ProtoReflect.Descriptor instead.
+func (*Candle) Descriptor() ([]byte, []int) {
+	return file_api_orderbook_orderbook_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *Candle) GetOpenTime() int64 {
+	if x != nil {
+		return x.OpenTime
+	}
+	return 0
+}
+
+func (x *Candle) GetOpen() float64 {
+	if x != nil {
+		return x.Open
+	}
+	return 0
+}
+
+func (x *Candle) GetHigh() float64 {
+	if x != nil {
+		return x.High
+	}
+	return 0
+}
+
+func (x *Candle) GetLow() float64 {
+	if x != nil {
+		return x.Low
+	}
+	return 0
+}
+
+func (x *Candle) GetClose() float64 {
+	if x != nil {
+		return x.Close
+	}
+	return 0
+}
+
+func (x *Candle) GetVolume() float64 {
+	if x != nil {
+		return x.Volume
+	}
+	return 0
+}
+
+func (x *Candle) GetQuoteVolume() float64 {
+	if x != nil {
+		return x.QuoteVolume
+	}
+	return 0
+}
+
+func (x *Candle) GetTrades() int32 {
+	if x != nil {
+		return x.Trades
+	}
+	return 0
+}
+
+type CandlesReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Interval      string                 `protobuf:"bytes,2,opt,name=interval,proto3" json:"interval,omitempty"`
+	Candles       []*Candle              `protobuf:"bytes,3,rep,name=candles,proto3" json:"candles,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CandlesReply) Reset() {
+	*x = CandlesReply{}
+	mi := &file_api_orderbook_orderbook_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CandlesReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CandlesReply) ProtoMessage() {}
+
+func (x *CandlesReply) ProtoReflect() protoreflect.Message {
+	mi := &file_api_orderbook_orderbook_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CandlesReply.ProtoReflect.Descriptor instead.
+func (*CandlesReply) Descriptor() ([]byte, []int) {
+	return file_api_orderbook_orderbook_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *CandlesReply) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *CandlesReply) GetInterval() string {
+	if x != nil {
+		return x.Interval
+	}
+	return ""
+}
+
+func (x *CandlesReply) GetCandles() []*Candle {
+	if x != nil {
+		return x.Candles
+	}
+	return nil
+}
+
+type TradesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	From          int64                  `protobuf:"varint,2,opt,name=from,proto3" json:"from,omitempty"`
+	To            int64                  `protobuf:"varint,3,opt,name=to,proto3" json:"to,omitempty"`
+	Limit         int32                  `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TradesRequest) Reset() {
+	*x = TradesRequest{}
+	mi := &file_api_orderbook_orderbook_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TradesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TradesRequest) ProtoMessage() {}
+
+func (x *TradesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_orderbook_orderbook_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TradesRequest.ProtoReflect.Descriptor instead.
+func (*TradesRequest) Descriptor() ([]byte, []int) {
+	return file_api_orderbook_orderbook_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *TradesRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *TradesRequest) GetFrom() int64 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *TradesRequest) GetTo() int64 {
+	if x != nil {
+		return x.To
+	}
+	return 0
+}
+
+func (x *TradesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type Trade struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Price         float64                `protobuf:"fixed64,2,opt,name=price,proto3" json:"price,omitempty"`
+	Quantity      float64                `protobuf:"fixed64,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Side          string                 `protobuf:"bytes,4,opt,name=side,proto3" json:"side,omitempty"`
+	Time          int64                  `protobuf:"varint,5,opt,name=time,proto3" json:"time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Trade) Reset() {
+	*x = Trade{}
+	mi := &file_api_orderbook_orderbook_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Trade) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Trade) ProtoMessage() {}
+
+func (x *Trade) ProtoReflect() protoreflect.Message {
+	mi := &file_api_orderbook_orderbook_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Trade.ProtoReflect.Descriptor instead.
+func (*Trade) Descriptor() ([]byte, []int) {
+	return file_api_orderbook_orderbook_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *Trade) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Trade) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *Trade) GetQuantity() float64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *Trade) GetSide() string {
+	if x != nil {
+		return x.Side
+	}
+	return ""
+}
+
+func (x *Trade) GetTime() int64 {
+	if x != nil {
+		return x.Time
+	}
+	return 0
+}
+
+type TradesReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Trades        []*Trade               `protobuf:"bytes,2,rep,name=trades,proto3" json:"trades,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TradesReply) Reset() {
+	*x = TradesReply{}
+	mi := &file_api_orderbook_orderbook_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TradesReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TradesReply) ProtoMessage() {}
+
+func (x *TradesReply) ProtoReflect() protoreflect.Message {
+	mi := &file_api_orderbook_orderbook_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TradesReply.ProtoReflect.Descriptor instead.
+func (*TradesReply) Descriptor() ([]byte, []int) {
+	return file_api_orderbook_orderbook_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *TradesReply) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *TradesReply) GetTrades() []*Trade {
+	if x != nil {
+		return x.Trades
+	}
+	return nil
+}
+
+var File_api_orderbook_orderbook_proto protoreflect.FileDescriptor
+
+const file_api_orderbook_orderbook_proto_rawDesc = "" +
+	"\n" +
+	"\x1dapi/orderbook/orderbook.proto\x12\torderbook\"H\n" +
+	"\x18OrderBookSnapshotRequest\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x14\n" +
+	"\x05depth\x18\x02 \x01(\x05R\x05depth\"\x14\n" +
+	"\x12ListSymbolsRequest\",\n" +
+	"\x10ListSymbolsReply\x12\x18\n" +
+	"\asymbols\x18\x01 \x03(\tR\asymbols\"5\n" +
+	"\x05Order\x12\x14\n" +
+	"\x05price\x18\x01 \x01(\tR\x05price\x12\x16\n" +
+	"\x06amount\x18\x02 \x01(\tR\x06amount\"\x9a\x01\n" +
+	"\x10GetSnapshotReply\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\"\n" +
+	"\flastUpdateId\x18\x02 \x01(\tR\flastUpdateId\x12$\n" +
+	"\x04bids\x18\x03 \x03(\v2\x10.orderbook.OrderR\x04bids\x12$\n" +
+	"\x04asks\x18\x04 \x03(\v2\x10.orderbook.OrderR\x04asks\"=\n" +
+	"\x1bConsolidatedSnapshotRequest\x12\x1e\n" +
+	"\n" +
+	"instrument\x18\x01 \x01(\tR\n" +
+	"instrument\"A\n" +
+	"\rVenueQuantity\x12\x14\n" +
+	"\x05venue\x18\x01 \x01(\tR\x05venue\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\x01R\bquantity\"w\n" +
+	"\x11ConsolidatedLevel\x12\x14\n" +
+	"\x05price\x18\x01 \x01(\x01R\x05price\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\x01R\bquantity\x120\n" +
+	"\x06venues\x18\x03 \x03(\v2\x18.orderbook.VenueQuantityR\x06venues\"\xfd\x01\n" +
+	"\x19ConsolidatedSnapshotReply\x12\x1e\n" +
+	"\n" +
+	"instrument\x18\x01 \x01(\tR\n" +
+	"instrument\x120\n" +
+	"\x04bids\x18\x02 \x03(\v2\x1c.orderbook.ConsolidatedLevelR\x04bids\x120\n" +
+	"\x04asks\x18\x03 \x03(\v2\x1c.orderbook.ConsolidatedLevelR\x04asks\x12\x16\n" +
+	"\x06venues\x18\x04 \x03(\tR\x06venues\x12&\n" +
+	"\x0eexcludedVenues\x18\x05 \x03(\tR\x0eexcludedVenues\x12\x1c\n" +
+	"\ttimestamp\x18\x06 \x01(\x03R\ttimestamp\"&\n" +
+	"\fStatsRequest\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\"\xa5\x01\n" +
+	"\tDepthBand\x12\x10\n" +
+	"\x03bps\x18\x01 \x01(\x01R\x03bps\x12 \n" +
+	"\vbidQuantity\x18\x02 \x01(\x01R\vbidQuantity\x12 \n" +
+	"\vaskQuantity\x18\x03 \x01(\x01R\vaskQuantity\x12 \n" +
+	"\vbidNotional\x18\x04 \x01(\x01R\vbidNotional\x12 \n" +
+	"\vaskNotional\x18\x05 \x01(\x01R\vaskNotional\"\xbc\x03\n" +
+	"\n" +
+	"StatsReply\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\"\n" +
+	"\flastUpdateId\x18\x02 \x01(\x03R\flastUpdateId\x12\x18\n" +
+	"\abestBid\x18\x03 \x01(\x01R\abestBid\x12(\n" +
+	"\x0fbestBidQuantity\x18\x04 \x01(\x01R\x0fbestBidQuantity\x12\x18\n" +
+	"\abestAsk\x18\x05 \x01(\x01R\abestAsk\x12(\n" +
+	"\x0fbestAskQuantity\x18\x06 \x01(\x01R\x0fbestAskQuantity\x12\x10\n" +
+	"\x03mid\x18\a \x01(\x01R\x03mid\x12\x1e\n" +
+	"\n" +
+	"microprice\x18\b \x01(\x01R\n" +
+	"microprice\x12\x1c\n" +
+	"\tspreadBps\x18\t \x01(\x01R\tspreadBps\x12\x1c\n" +
+	"\timbalance\x18\n" +
+	" \x01(\x01R\timbalance\x12(\n" +
+	"\x0fimbalanceLevels\x18\v \x01(\x05R\x0fimbalanceLevels\x124\n" +
+	"\n" +
+	"depthBands\x18\f \x03(\v2\x14.orderbook.DepthBandR\n" +
+	"depthBands\x12\x1c\n" +
+	"\ttimestamp\x18\r \x01(\x03R\ttimestamp\"\x97\x01\n" +
+	"\rImpactRequest\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x12\n" +
+	"\x04side\x18\x02 \x01(\tR\x04side\x12\x1a\n" +
+	"\bquantity\x18\x03 \x01(\x01R\bquantity\x12\x1a\n" +
+	"\bnotional\x18\x04 \x01(\x01R\bnotional\x12\"\n" +
+	"\fconsolidated\x18\x05 \x01(\bR\fconsolidated\"\xe3\x03\n" +
+	"\vImpactReply\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x12\n" +
+	"\x04side\x18\x02 \x01(\tR\x04side\x12\x16\n" +
+	"\x06source\x18\x03 \x01(\tR\x06source\x12\"\n" +
+	"\flastUpdateId\x18\x04 \x01(\x03R\flastUpdateId\x12,\n" +
+	"\x11requestedQuantity\x18\x05 \x01(\x01R\x11requestedQuantity\x12,\n" +
+	"\x11requestedNotional\x18\x06 \x01(\x01R\x11requestedNotional\x12&\n" +
+	"\x0efilledQuantity\x18\a \x01(\x01R\x0efilledQuantity\x12&\n" +
+	"\x0efilledNotional\x18\b \x01(\x01R\x0efilledNotional\x12\"\n" +
+	"\faveragePrice\x18\t \x01(\x01R\faveragePrice\x12\x1e\n" +
+	"\n" +
+	"worstPrice\x18\n" +
+	" \x01(\x01R\n" +
+	"worstPrice\x12\x10\n" +
+	"\x03mid\x18\v \x01(\x01R\x03mid\x12 \n" +
+	"\vslippageBps\x18\f \x01(\x01R\vslippageBps\x12&\n" +
+	"\x0elevelsConsumed\x18\r \x01(\x05R\x0elevelsConsumed\x12 \n" +
+	"\vfullyFilled\x18\x0e \x01(\bR\vfullyFilled\"g\n" +
+	"\x19HistoricalSnapshotRequest\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x1c\n" +
+	"\ttimestamp\x18\x02 \x01(\x03R\ttimestamp\x12\x14\n" +
+	"\x05depth\x18\x03 \x01(\x05R\x05depth\"\x8a\x01\n" +
+	"\x16HistoricalRangeRequest\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x12\n" +
+	"\x04from\x18\x02 \x01(\x03R\x04from\x12\x0e\n" +
+	"\x02to\x18\x03 \x01(\x03R\x02to\x12\x1e\n" +
+	"\n" +
+	"intervalMs\x18\x04 \x01(\x03R\n" +
+	"intervalMs\x12\x14\n" +
+	"\x05depth\x18\x05 \x01(\x05R\x05depth\"\xb3\x02\n" +
+	"\x17HistoricalSnapshotReply\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x1c\n" +
+	"\ttimestamp\x18\x02 \x01(\x03R\ttimestamp\x12\"\n" +
+	"\flastUpdateId\x18\x03 \x01(\x03R\flastUpdateId\x12$\n" +
+	"\x04bids\x18\x04 \x03(\v2\x10.orderbook.OrderR\x04bids\x12$\n" +
+	"\x04asks\x18\x05 \x03(\v2\x10.orderbook.OrderR\x04asks\x120\n" +
+	"\x13checkpointTimestamp\x18\x06 \x01(\x03R\x13checkpointTimestamp\x12$\n" +
+	"\rdeltasApplied\x18\a \x01(\x05R\rdeltasApplied\x12\x1a\n" +
+	"\bcomplete\x18\b \x01(\bR\bcomplete\"~\n" +
+	"\x0eCandlesRequest\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x1a\n" +
+	"\binterval\x18\x02 \x01(\tR\binterval\x12\x12\n" +
+	"\x04from\x18\x03 \x01(\x03R\x04from\x12\x0e\n" +
+	"\x02to\x18\x04 \x01(\x03R\x02to\x12\x14\n" +
+	"\x05limit\x18\x05 \x01(\x05R\x05limit\"\xc6\x01\n" +
+	"\x06Candle\x12\x1a\n" +
+	"\bopenTime\x18\x01 \x01(\x03R\bopenTime\x12\x12\n" +
+	"\x04open\x18\x02 \x01(\x01R\x04open\x12\x12\n" +
+	"\x04high\x18\x03 \x01(\x01R\x04high\x12\x10\n" +
+	"\x03low\x18\x04 \x01(\x01R\x03low\x12\x14\n" +
+	"\x05close\x18\x05 \x01(\x01R\x05close\x12\x16\n" +
+	"\x06volume\x18\x06 \x01(\x01R\x06volume\x12 \n" +
+	"\vquoteVolume\x18\a \x01(\x01R\vquoteVolume\x12\x16\n" +
+	"\x06trades\x18\b \x01(\x05R\x06trades\"o\n" +
+	"\fCandlesReply\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x1a\n" +
+	"\binterval\x18\x02 \x01(\tR\binterval\x12+\n" +
+	"\acandles\x18\x03 \x03(\v2\x11.orderbook.CandleR\acandles\"a\n" +
+	"\rTradesRequest\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x12\n" +
+	"\x04from\x18\x02 \x01(\x03R\x04from\x12\x0e\n" +
+	"\x02to\x18\x03 \x01(\x03R\x02to\x12\x14\n" +
+	"\x05limit\x18\x04 \x01(\x05R\x05limit\"q\n" +
+	"\x05Trade\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x14\n" +
+	"\x05price\x18\x02 \x01(\x01R\x05price\x12\x1a\n" +
+	"\bquantity\x18\x03 \x01(\x01R\bquantity\x12\x12\n" +
+	"\x04side\x18\x04 \x01(\tR\x04side\x12\x12\n" +
+	"\x04time\x18\x05 \x01(\x03R\x04time\"O\n" +
+	"\vTradesReply\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12(\n" +
+	"\x06trades\x18\x02 \x03(\v2\x10.orderbook.TradeR\x06trades2\xd0\x06\n" +
+	"\tOrderBook\x12Q\n" +
+	"\vGetSnapshot\x12#.orderbook.OrderBookSnapshotRequest\x1a\x1b.orderbook.GetSnapshotReply\"\x00\x12K\n" +
+	"\vListSymbols\x12\x1d.orderbook.ListSymbolsRequest\x1a\x1b.orderbook.ListSymbolsReply\"\x00\x12i\n" +
+	"\x17GetConsolidatedSnapshot\x12&.orderbook.ConsolidatedSnapshotRequest\x1a$.orderbook.ConsolidatedSnapshotReply\"\x00\x12f\n" +
+	"\x12StreamConsolidated\x12&.orderbook.ConsolidatedSnapshotRequest\x1a$.orderbook.ConsolidatedSnapshotReply\"\x000\x01\x12<\n" +
+	"\bGetStats\x12\x17.orderbook.StatsRequest\x1a\x15.orderbook.StatsReply\"\x00\x12D\n" +
+	"\x0eEstimateImpact\x12\x18.orderbook.ImpactRequest\x1a\x16.orderbook.ImpactReply\"\x00\x12c\n" +
+	"\x15GetHistoricalSnapshot\x12$.orderbook.HistoricalSnapshotRequest\x1a\".orderbook.HistoricalSnapshotReply\"\x00\x12b\n" +
+	"\x15StreamHistoricalRange\x12!.orderbook.HistoricalRangeRequest\x1a\".orderbook.HistoricalSnapshotReply\"\x000\x01\x12B\n" +
+	"\n" +
+	"GetCandles\x12\x19.orderbook.CandlesRequest\x1a\x17.orderbook.CandlesReply\"\x00\x12?\n" +
+	"\tGetTrades\x12\x18.orderbook.TradesRequest\x1a\x16.orderbook.TradesReply\"\x00B@Z>github.com/ChethiyaNishanath/market-data-hub/cmd/api/orderbookb\x06proto3"
 
 var (
 	file_api_orderbook_orderbook_proto_rawDescOnce sync.Once
@@ -215,22 +1838,68 @@ func file_api_orderbook_orderbook_proto_rawDescGZIP() []byte {
 	return file_api_orderbook_orderbook_proto_rawDescData
 }
 
-var file_api_orderbook_orderbook_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_api_orderbook_orderbook_proto_msgTypes = make([]protoimpl.MessageInfo, 23)
 var file_api_orderbook_orderbook_proto_goTypes = []any{
-	(*OrderBookSnapshotRequest)(nil), // 0: orderbook.OrderBookSnapshotRequest
-	(*Order)(nil),                    // 1: orderbook.Order
-	(*GetSnapshotReply)(nil),         // 2: orderbook.GetSnapshotReply
+	(*OrderBookSnapshotRequest)(nil),    // 0: orderbook.OrderBookSnapshotRequest
+	(*ListSymbolsRequest)(nil),          // 1: orderbook.ListSymbolsRequest
+	(*ListSymbolsReply)(nil),            // 2: orderbook.ListSymbolsReply
+	(*Order)(nil),                       // 3: orderbook.Order
+	(*GetSnapshotReply)(nil),            // 4: orderbook.GetSnapshotReply
+	(*ConsolidatedSnapshotRequest)(nil), // 5: orderbook.ConsolidatedSnapshotRequest
+	(*VenueQuantity)(nil),               // 6: orderbook.VenueQuantity
+	(*ConsolidatedLevel)(nil),           // 7: orderbook.ConsolidatedLevel
+	(*ConsolidatedSnapshotReply)(nil),   // 8: orderbook.ConsolidatedSnapshotReply
+	(*StatsRequest)(nil),                // 9: orderbook.StatsRequest
+	(*DepthBand)(nil),                   // 10: orderbook.DepthBand
+	(*StatsReply)(nil),                  // 11: orderbook.StatsReply
+	(*ImpactRequest)(nil),               // 12: orderbook.ImpactRequest
+	(*ImpactReply)(nil),                 // 13: orderbook.ImpactReply
+	(*HistoricalSnapshotRequest)(nil),   // 14: orderbook.HistoricalSnapshotRequest
+	(*HistoricalRangeRequest)(nil),      // 15: orderbook.HistoricalRangeRequest
+	(*HistoricalSnapshotReply)(nil),     // 16: orderbook.HistoricalSnapshotReply
+	(*CandlesRequest)(nil),              // 17: orderbook.CandlesRequest
+	(*Candle)(nil),                      // 18: orderbook.Candle
+	(*CandlesReply)(nil),                // 19: orderbook.CandlesReply
+	(*TradesRequest)(nil),               // 20: orderbook.TradesRequest
+	(*Trade)(nil),                       // 21: orderbook.Trade
+	(*TradesReply)(nil),                 // 22: orderbook.TradesReply
 }
 var file_api_orderbook_orderbook_proto_depIdxs = []int32{
-	1, // 0: orderbook.GetSnapshotReply.bids:type_name -> orderbook.Order
-	1, // 1: orderbook.GetSnapshotReply.asks:type_name -> orderbook.Order
-	0, // 2: orderbook.OrderBook.GetSnapshot:input_type -> orderbook.OrderBookSnapshotRequest
-	2, // 3: orderbook.OrderBook.GetSnapshot:output_type -> orderbook.GetSnapshotReply
-	3, // [3:4] is the sub-list for method output_type
-	2, // [2:3] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	3,  // 0: orderbook.GetSnapshotReply.bids:type_name -> orderbook.Order
+	3,  // 1: orderbook.GetSnapshotReply.asks:type_name -> orderbook.Order
+	6,  // 2: orderbook.ConsolidatedLevel.venues:type_name -> orderbook.VenueQuantity
+	7,  // 3: orderbook.ConsolidatedSnapshotReply.bids:type_name -> orderbook.ConsolidatedLevel
+	7,  // 4: orderbook.ConsolidatedSnapshotReply.asks:type_name -> orderbook.ConsolidatedLevel
+	10, // 5: orderbook.StatsReply.depthBands:type_name -> orderbook.DepthBand
+	3,  // 6: orderbook.HistoricalSnapshotReply.bids:type_name -> orderbook.Order
+	3,  // 7: orderbook.HistoricalSnapshotReply.asks:type_name -> orderbook.Order
+	18, // 8: orderbook.CandlesReply.candles:type_name -> orderbook.Candle
+	21, // 9: orderbook.TradesReply.trades:type_name -> orderbook.Trade
+	0,  // 10: orderbook.OrderBook.GetSnapshot:input_type -> orderbook.OrderBookSnapshotRequest
+	1,  // 11: orderbook.OrderBook.ListSymbols:input_type -> orderbook.ListSymbolsRequest
+	5,  // 12: orderbook.OrderBook.GetConsolidatedSnapshot:input_type -> orderbook.ConsolidatedSnapshotRequest
+	5,  // 13: orderbook.OrderBook.StreamConsolidated:input_type -> orderbook.ConsolidatedSnapshotRequest
+	9,  // 14: orderbook.OrderBook.GetStats:input_type -> orderbook.StatsRequest
+	12, // 15: orderbook.OrderBook.EstimateImpact:input_type -> orderbook.ImpactRequest
+	14, // 16: orderbook.OrderBook.GetHistoricalSnapshot:input_type -> orderbook.HistoricalSnapshotRequest
+	15, // 17: orderbook.OrderBook.StreamHistoricalRange:input_type -> orderbook.HistoricalRangeRequest
+	17, // 18: orderbook.OrderBook.GetCandles:input_type -> orderbook.CandlesRequest
+	20, // 19: orderbook.OrderBook.GetTrades:input_type -> orderbook.TradesRequest
+	4,  // 20: orderbook.OrderBook.GetSnapshot:output_type -> orderbook.GetSnapshotReply
+	2,  // 21: orderbook.OrderBook.ListSymbols:output_type -> orderbook.ListSymbolsReply
+	8,  // 22: orderbook.OrderBook.GetConsolidatedSnapshot:output_type -> orderbook.ConsolidatedSnapshotReply
+	8,  // 23: orderbook.OrderBook.StreamConsolidated:output_type -> orderbook.ConsolidatedSnapshotReply
+	11, // 24: orderbook.OrderBook.GetStats:output_type -> orderbook.StatsReply
+	13, // 25: orderbook.OrderBook.EstimateImpact:output_type -> orderbook.ImpactReply
+	16, // 26: orderbook.OrderBook.GetHistoricalSnapshot:output_type -> orderbook.HistoricalSnapshotReply
+	16, // 27: orderbook.OrderBook.StreamHistoricalRange:output_type -> orderbook.HistoricalSnapshotReply
+	19, // 28: orderbook.OrderBook.GetCandles:output_type -> orderbook.CandlesReply
+	22, // 29: orderbook.OrderBook.GetTrades:output_type -> orderbook.TradesReply
+	20, // [20:30] is the sub-list for method output_type
+	10, // [10:20] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
 }
 
 func init() { file_api_orderbook_orderbook_proto_init() }
@@ -244,7 +1913,7 @@ func file_api_orderbook_orderbook_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_orderbook_orderbook_proto_rawDesc), len(file_api_orderbook_orderbook_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   3,
+			NumMessages:   23,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -19,7 +19,16 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	OrderBook_GetSnapshot_FullMethodName = "/orderbook.OrderBook/GetSnapshot"
+	OrderBook_GetSnapshot_FullMethodName             = "/orderbook.OrderBook/GetSnapshot"
+	OrderBook_ListSymbols_FullMethodName             = "/orderbook.OrderBook/ListSymbols"
+	OrderBook_GetConsolidatedSnapshot_FullMethodName = "/orderbook.OrderBook/GetConsolidatedSnapshot"
+	OrderBook_StreamConsolidated_FullMethodName      = "/orderbook.OrderBook/StreamConsolidated"
+	OrderBook_GetStats_FullMethodName                = "/orderbook.OrderBook/GetStats"
+	OrderBook_EstimateImpact_FullMethodName          = "/orderbook.OrderBook/EstimateImpact"
+	OrderBook_GetHistoricalSnapshot_FullMethodName   = "/orderbook.OrderBook/GetHistoricalSnapshot"
+	OrderBook_StreamHistoricalRange_FullMethodName   = "/orderbook.OrderBook/StreamHistoricalRange"
+	OrderBook_GetCandles_FullMethodName              = "/orderbook.OrderBook/GetCandles"
+	OrderBook_GetTrades_FullMethodName               = "/orderbook.OrderBook/GetTrades"
 )
 
 // OrderBookClient is the client API for OrderBook service.
@@ -27,6 +36,15 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type OrderBookClient interface {
 	GetSnapshot(ctx context.Context, in *OrderBookSnapshotRequest, opts ...grpc.CallOption) (*GetSnapshotReply, error)
+	ListSymbols(ctx context.Context, in *ListSymbolsRequest, opts ...grpc.CallOption) (*ListSymbolsReply, error)
+	GetConsolidatedSnapshot(ctx context.Context, in *ConsolidatedSnapshotRequest, opts ...grpc.CallOption) (*ConsolidatedSnapshotReply, error)
+	StreamConsolidated(ctx context.Context, in *ConsolidatedSnapshotRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ConsolidatedSnapshotReply], error)
+	GetStats(ctx context.Context, in *StatsRequest, opts ...grpc.CallOption) (*StatsReply, error)
+	EstimateImpact(ctx context.Context, in *ImpactRequest, opts ...grpc.CallOption) (*ImpactReply, error)
+	GetHistoricalSnapshot(ctx context.Context, in *HistoricalSnapshotRequest, opts ...grpc.CallOption) (*HistoricalSnapshotReply, error)
+	StreamHistoricalRange(ctx context.Context, in *HistoricalRangeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[HistoricalSnapshotReply], error)
+	GetCandles(ctx context.Context, in *CandlesRequest, opts ...grpc.CallOption) (*CandlesReply, error)
+	GetTrades(ctx context.Context, in *TradesRequest, opts ...grpc.CallOption) (*TradesReply, error)
 }
 
 type orderBookClient struct {
@@ -47,11 +65,128 @@ func (c *orderBookClient) GetSnapshot(ctx context.Context, in *OrderBookSnapshot
 	return out, nil
 }
 
+func (c *orderBookClient) ListSymbols(ctx context.Context, in *ListSymbolsRequest, opts ...grpc.CallOption) (*ListSymbolsReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSymbolsReply)
+	err := c.cc.Invoke(ctx, OrderBook_ListSymbols_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderBookClient) GetConsolidatedSnapshot(ctx context.Context, in *ConsolidatedSnapshotRequest, opts ...grpc.CallOption) (*ConsolidatedSnapshotReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ConsolidatedSnapshotReply)
+	err := c.cc.Invoke(ctx, OrderBook_GetConsolidatedSnapshot_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderBookClient) StreamConsolidated(ctx context.Context, in *ConsolidatedSnapshotRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ConsolidatedSnapshotReply], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &OrderBook_ServiceDesc.Streams[0], OrderBook_StreamConsolidated_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ConsolidatedSnapshotRequest, ConsolidatedSnapshotReply]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type OrderBook_StreamConsolidatedClient = grpc.ServerStreamingClient[ConsolidatedSnapshotReply]
+
+func (c *orderBookClient) GetStats(ctx context.Context, in *StatsRequest, opts ...grpc.CallOption) (*StatsReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StatsReply)
+	err := c.cc.Invoke(ctx, OrderBook_GetStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderBookClient) EstimateImpact(ctx context.Context, in *ImpactRequest, opts ...grpc.CallOption) (*ImpactReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ImpactReply)
+	err := c.cc.Invoke(ctx, OrderBook_EstimateImpact_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderBookClient) GetHistoricalSnapshot(ctx context.Context, in *HistoricalSnapshotRequest, opts ...grpc.CallOption) (*HistoricalSnapshotReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HistoricalSnapshotReply)
+	err := c.cc.Invoke(ctx, OrderBook_GetHistoricalSnapshot_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderBookClient) StreamHistoricalRange(ctx context.Context, in *HistoricalRangeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[HistoricalSnapshotReply], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &OrderBook_ServiceDesc.Streams[1], OrderBook_StreamHistoricalRange_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[HistoricalRangeRequest, HistoricalSnapshotReply]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type OrderBook_StreamHistoricalRangeClient = grpc.ServerStreamingClient[HistoricalSnapshotReply]
+
+func (c *orderBookClient) GetCandles(ctx context.Context, in *CandlesRequest, opts ...grpc.CallOption) (*CandlesReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CandlesReply)
+	err := c.cc.Invoke(ctx, OrderBook_GetCandles_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderBookClient) GetTrades(ctx context.Context, in *TradesRequest, opts ...grpc.CallOption) (*TradesReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TradesReply)
+	err := c.cc.Invoke(ctx, OrderBook_GetTrades_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // OrderBookServer is the server API for OrderBook service.
 // All implementations must embed UnimplementedOrderBookServer
 // for forward compatibility.
 type OrderBookServer interface {
 	GetSnapshot(context.Context, *OrderBookSnapshotRequest) (*GetSnapshotReply, error)
+	ListSymbols(context.Context, *ListSymbolsRequest) (*ListSymbolsReply, error)
+	GetConsolidatedSnapshot(context.Context, *ConsolidatedSnapshotRequest) (*ConsolidatedSnapshotReply, error)
+	StreamConsolidated(*ConsolidatedSnapshotRequest, grpc.ServerStreamingServer[ConsolidatedSnapshotReply]) error
+	GetStats(context.Context, *StatsRequest) (*StatsReply, error)
+	EstimateImpact(context.Context, *ImpactRequest) (*ImpactReply, error)
+	GetHistoricalSnapshot(context.Context, *HistoricalSnapshotRequest) (*HistoricalSnapshotReply, error)
+	StreamHistoricalRange(*HistoricalRangeRequest, grpc.ServerStreamingServer[HistoricalSnapshotReply]) error
+	GetCandles(context.Context, *CandlesRequest) (*CandlesReply, error)
+	GetTrades(context.Context, *TradesRequest) (*TradesReply, error)
 	mustEmbedUnimplementedOrderBookServer()
 }
 
@@ -65,6 +200,33 @@ type UnimplementedOrderBookServer struct{}
 func (UnimplementedOrderBookServer) GetSnapshot(context.Context, *OrderBookSnapshotRequest) (*GetSnapshotReply, error) {
 	return nil, status.Error(codes.Unimplemented, "method GetSnapshot not implemented")
 }
+func (UnimplementedOrderBookServer) ListSymbols(context.Context, *ListSymbolsRequest) (*ListSymbolsReply, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListSymbols not implemented")
+}
+func (UnimplementedOrderBookServer) GetConsolidatedSnapshot(context.Context, *ConsolidatedSnapshotRequest) (*ConsolidatedSnapshotReply, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetConsolidatedSnapshot not implemented")
+}
+func (UnimplementedOrderBookServer) StreamConsolidated(*ConsolidatedSnapshotRequest, grpc.ServerStreamingServer[ConsolidatedSnapshotReply]) error {
+	return status.Error(codes.Unimplemented, "method StreamConsolidated not implemented")
+}
+func (UnimplementedOrderBookServer) GetStats(context.Context, *StatsRequest) (*StatsReply, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetStats not implemented")
+}
+func (UnimplementedOrderBookServer) EstimateImpact(context.Context, *ImpactRequest) (*ImpactReply, error) {
+	return nil, status.Error(codes.Unimplemented, "method EstimateImpact not implemented")
+}
+func (UnimplementedOrderBookServer) GetHistoricalSnapshot(context.Context, *HistoricalSnapshotRequest) (*HistoricalSnapshotReply, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetHistoricalSnapshot not implemented")
+}
+func (UnimplementedOrderBookServer) StreamHistoricalRange(*HistoricalRangeRequest, grpc.ServerStreamingServer[HistoricalSnapshotReply]) error {
+	return status.Error(codes.Unimplemented, "method StreamHistoricalRange not implemented")
+}
+func (UnimplementedOrderBookServer) GetCandles(context.Context, *CandlesRequest) (*CandlesReply, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetCandles not implemented")
+}
+func (UnimplementedOrderBookServer) GetTrades(context.Context, *TradesRequest) (*TradesReply, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetTrades not implemented")
+}
 func (UnimplementedOrderBookServer) mustEmbedUnimplementedOrderBookServer() {}
 func (UnimplementedOrderBookServer) testEmbeddedByValue()                   {}
 
@@ -104,6 +266,154 @@ func _OrderBook_GetSnapshot_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _OrderBook_ListSymbols_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSymbolsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderBookServer).ListSymbols(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderBook_ListSymbols_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderBookServer).ListSymbols(ctx, req.(*ListSymbolsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderBook_GetConsolidatedSnapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConsolidatedSnapshotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderBookServer).GetConsolidatedSnapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderBook_GetConsolidatedSnapshot_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderBookServer).GetConsolidatedSnapshot(ctx, req.(*ConsolidatedSnapshotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderBook_StreamConsolidated_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ConsolidatedSnapshotRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(OrderBookServer).StreamConsolidated(m, &grpc.GenericServerStream[ConsolidatedSnapshotRequest, ConsolidatedSnapshotReply]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type OrderBook_StreamConsolidatedServer = grpc.ServerStreamingServer[ConsolidatedSnapshotReply]
+
+func _OrderBook_GetStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderBookServer).GetStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderBook_GetStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderBookServer).GetStats(ctx, req.(*StatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderBook_EstimateImpact_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImpactRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderBookServer).EstimateImpact(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderBook_EstimateImpact_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderBookServer).EstimateImpact(ctx, req.(*ImpactRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderBook_GetHistoricalSnapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HistoricalSnapshotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderBookServer).GetHistoricalSnapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderBook_GetHistoricalSnapshot_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderBookServer).GetHistoricalSnapshot(ctx, req.(*HistoricalSnapshotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderBook_StreamHistoricalRange_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(HistoricalRangeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(OrderBookServer).StreamHistoricalRange(m, &grpc.GenericServerStream[HistoricalRangeRequest, HistoricalSnapshotReply]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type OrderBook_StreamHistoricalRangeServer = grpc.ServerStreamingServer[HistoricalSnapshotReply]
+
+func _OrderBook_GetCandles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CandlesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderBookServer).GetCandles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderBook_GetCandles_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderBookServer).GetCandles(ctx, req.(*CandlesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderBook_GetTrades_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TradesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderBookServer).GetTrades(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderBook_GetTrades_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderBookServer).GetTrades(ctx, req.(*TradesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // OrderBook_ServiceDesc is the grpc.ServiceDesc for OrderBook service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -115,7 +425,46 @@ var OrderBook_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetSnapshot",
 			Handler:    _OrderBook_GetSnapshot_Handler,
 		},
+		{
+			MethodName: "ListSymbols",
+			Handler:    _OrderBook_ListSymbols_Handler,
+		},
+		{
+			MethodName: "GetConsolidatedSnapshot",
+			Handler:    _OrderBook_GetConsolidatedSnapshot_Handler,
+		},
+		{
+			MethodName: "GetStats",
+			Handler:    _OrderBook_GetStats_Handler,
+		},
+		{
+			MethodName: "EstimateImpact",
+			Handler:    _OrderBook_EstimateImpact_Handler,
+		},
+		{
+			MethodName: "GetHistoricalSnapshot",
+			Handler:    _OrderBook_GetHistoricalSnapshot_Handler,
+		},
+		{
+			MethodName: "GetCandles",
+			Handler:    _OrderBook_GetCandles_Handler,
+		},
+		{
+			MethodName: "GetTrades",
+			Handler:    _OrderBook_GetTrades_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamConsolidated",
+			Handler:       _OrderBook_StreamConsolidated_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamHistoricalRange",
+			Handler:       _OrderBook_StreamHistoricalRange_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "api/orderbook/orderbook.proto",
 }
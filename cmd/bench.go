@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	pb "github.com/ChethiyaNishanath/market-data-hub/api/orderbook"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/bench"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+var benchCmd = &cobra.Command{
+	Use:   "bench",
+	Short: "Load-test a running hub",
+	Long: `Measure how a running hub copes with load, either WebSocket fan-out (bench ws)
+or GetSnapshot calls over gRPC (bench grpc). Pair it with a hub fed by the
+simulate command to get reproducible numbers.`,
+}
+
+var benchWSCmd = &cobra.Command{
+	Use:   "ws",
+	Short: "Open many WebSocket clients and measure delivery latency and drops",
+	Long: `Open --clients WebSocket connections, each subscribed to the depth of
+--subscriptions symbols taken round-robin from --symbol, and report the latency
+from the exchange event time to delivery, throughput, and the update IDs each
+client never received (dropped by the hub's send buffer).
+
+Event times have millisecond precision and latency is only as accurate as the
+clocks of the exchange (or simulator) and this host agree.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runBenchWS(cmd)
+	},
+}
+
+var benchGRPCCmd = &cobra.Command{
+	Use:   "grpc",
+	Short: "Call GetSnapshot at a set rate and measure round trips",
+	Long: `Call GetSnapshot for --symbol, round-robin, at --rate requests per second
+spread over --concurrency workers, and report round-trip percentiles and
+throughput. Requests due while every worker is busy are counted as missed. A
+--rate of 0 calls as fast as the hub answers.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runBenchGRPC(cmd)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(benchCmd)
+	benchCmd.AddCommand(benchWSCmd, benchGRPCCmd)
+
+	benchCmd.PersistentFlags().StringSlice("symbol", []string{"BTCUSDT"}, "Symbols to load")
+	benchCmd.PersistentFlags().Duration("duration", 30*time.Second, "Length of the measured run")
+	benchCmd.PersistentFlags().Duration("warmup", 5*time.Second, "Time before measuring starts")
+	benchCmd.PersistentFlags().StringP("output", "o", "text", "Report format: text|json")
+
+	benchWSCmd.Flags().String("url", "ws://localhost:8084/ws", "Hub WebSocket endpoint")
+	benchWSCmd.Flags().Int("clients", 100, "Number of WebSocket clients")
+	benchWSCmd.Flags().Int("subscriptions", 1, "Depth subscriptions per client")
+	benchWSCmd.Flags().Bool("distinct-addresses", false, "Give each client its own X-Forwarded-For address, for hubs that limit connections per IP")
+
+	benchGRPCCmd.Flags().String("addr", "0.0.0.0:50051", "gRPC server address")
+	benchGRPCCmd.Flags().Float64("rate", 100, "Requests per second, 0 for as fast as possible")
+	benchGRPCCmd.Flags().Int("concurrency", 8, "Concurrent requests")
+	benchGRPCCmd.Flags().Int32("depth", 20, "Levels per side requested, 0 for the full book")
+	benchGRPCCmd.Flags().Duration("timeout", 5*time.Second, "Time allowed for each request")
+	benchGRPCCmd.Flags().String("token", "", "Bearer token sent with each request (default $MDH_TOKEN)")
+}
+
+func runBenchWS(cmd *cobra.Command) error {
+	flags := cmd.Flags()
+	url, _ := flags.GetString("url")
+	clients, _ := flags.GetInt("clients")
+	subscriptions, _ := flags.GetInt("subscriptions")
+	distinct, _ := flags.GetBool("distinct-addresses")
+	symbols, _ := flags.GetStringSlice("symbol")
+	duration, _ := flags.GetDuration("duration")
+	warmup, _ := flags.GetDuration("warmup")
+	output, _ := flags.GetString("output")
+
+	if err := checkBenchOutput(output); err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	report, err := bench.RunWS(ctx, bench.WSConfig{
+		URL:               url,
+		Clients:           clients,
+		Subscriptions:     subscriptions,
+		Symbols:           symbols,
+		Duration:          duration,
+		Warmup:            warmup,
+		DistinctAddresses: distinct,
+	})
+	if err != nil && report.Connected == 0 {
+		return err
+	}
+	return writeBenchReport(cmd, output, report)
+}
+
+func runBenchGRPC(cmd *cobra.Command) error {
+	flags := cmd.Flags()
+	addr, _ := flags.GetString("addr")
+	rate, _ := flags.GetFloat64("rate")
+	concurrency, _ := flags.GetInt("concurrency")
+	depth, _ := flags.GetInt32("depth")
+	timeout, _ := flags.GetDuration("timeout")
+	symbols, _ := flags.GetStringSlice("symbol")
+	duration, _ := flags.GetDuration("duration")
+	warmup, _ := flags.GetDuration("warmup")
+	output, _ := flags.GetString("output")
+	token, _ := flags.GetString("token")
+
+	if err := checkBenchOutput(output); err != nil {
+		return err
+	}
+
+	// Tracing every call would measure the exporter as much as the hub, so
+	// unlike the other commands no stats handler is installed.
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return fmt.Errorf("connect to %s: %w", addr, err)
+	}
+	defer conn.Close()
+
+	ctx, stop := signal.NotifyContext(withToken(cmd.Context(), token), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	report, err := bench.RunGRPC(ctx, pb.NewOrderBookClient(conn), bench.GRPCConfig{
+		Target:         addr,
+		Symbols:        symbols,
+		Depth:          depth,
+		Rate:           rate,
+		Concurrency:    concurrency,
+		Duration:       duration,
+		Warmup:         warmup,
+		RequestTimeout: timeout,
+	})
+	if err != nil && report.Messages == 0 {
+		return err
+	}
+	return writeBenchReport(cmd, output, report)
+}
+
+func checkBenchOutput(output string) error {
+	if output != "text" && output != "json" {
+		return fmt.Errorf("unknown --output %q, expected text or json", output)
+	}
+	return nil
+}
+
+func writeBenchReport(cmd *cobra.Command, output string, report bench.Report) error {
+	if output == "json" {
+		return report.WriteJSON(cmd.OutOrStdout())
+	}
+	return report.WriteText(cmd.OutOrStdout())
+}
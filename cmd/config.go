@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/ChethiyaNishanath/market-data-hub/internal/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Validate or print the configuration",
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check the configuration and report every problem found",
+	Long: `Load the configuration the way serve does, from the config file, MDH_*
+environment variables and defaults, and check it. Every problem is reported
+with the key it is about. Exits with 2 when the configuration is invalid.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runConfigValidate(cmd)
+	},
+}
+
+var configPrintCmd = &cobra.Command{
+	Use:   "print",
+	Short: "Print the configuration file or the effective configuration",
+	Long: `Print the config file in use as written, or with --effective the typed result
+of merging the file, MDH_* environment variables and defaults. The effective
+configuration redacts secrets (server.admin.token, server.grpc.token,
+alerts.webhook.secret) and writes durations with units.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runConfigPrint(cmd)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configValidateCmd, configPrintCmd)
+
+	configPrintCmd.Flags().Bool("effective", false, "Print the merged, typed configuration")
+	configPrintCmd.Flags().StringP("output", "o", "yaml", "Output format of --effective: yaml|json")
+}
+
+func runConfigValidate(cmd *cobra.Command) error {
+	cmd.SilenceUsage = true
+
+	source := viper.ConfigFileUsed()
+	if source == "" {
+		source = "defaults and environment"
+	}
+
+	_, err := config.Load(viper.GetViper())
+	if err != nil {
+		problems := configProblems(err)
+		for _, problem := range problems {
+			fmt.Fprintln(cmd.ErrOrStderr(), problem)
+		}
+		return withExitCode(exitUsage, fmt.Errorf("%s is invalid", source))
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "%s: OK\n", source)
+	return nil
+}
+
+func runConfigPrint(cmd *cobra.Command) error {
+	flags := cmd.Flags()
+	effective, _ := flags.GetBool("effective")
+	output, _ := flags.GetString("output")
+
+	if output != "yaml" && output != "json" {
+		return withExitCode(exitUsage, fmt.Errorf("unknown --output %q, expected yaml or json", output))
+	}
+	cmd.SilenceUsage = true
+
+	if !effective {
+		path := viper.ConfigFileUsed()
+		if path == "" {
+			return errors.New("no config file found, use --effective to print the defaults")
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		_, err = cmd.OutOrStdout().Write(data)
+		return err
+	}
+
+	// An invalid config is printed anyway, so it can be inspected.
+	cfg, err := config.Load(viper.GetViper())
+	if cfg == nil {
+		return err
+	}
+	if err != nil {
+		fmt.Fprintln(cmd.ErrOrStderr(), "warning: the configuration is invalid, see config validate")
+	}
+
+	if output == "json" {
+		return cfg.WriteJSON(cmd.OutOrStdout())
+	}
+	return cfg.WriteYAML(cmd.OutOrStdout())
+}
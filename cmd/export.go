@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ChethiyaNishanath/market-data-hub/internal/export"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/history"
+	"github.com/spf13/cobra"
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Convert recorded order book history to Parquet or CSV",
+	Long: `Convert the order book history journal into the export datasets: depth (every
+L2 delta) and book (the top levels sampled every --interval). Files use the same
+layout and schemas as the live export. Trades are not journaled and only come
+from the live export.
+
+BoltDB allows no readers while the server holds the journal, so run this
+against a stopped server or a copy of the file.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runExport(cmd)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+
+	exportCmd.Flags().String("history", "data/history.db", "Order book history journal to read")
+	exportCmd.Flags().String("out", "data/export", "Directory to write the datasets to")
+	exportCmd.Flags().String("exchange", "binance", "Exchange the history was recorded from")
+	exportCmd.Flags().StringSlice("symbols", nil, "Symbols to export (default all recorded)")
+	exportCmd.Flags().StringSlice("datasets", []string{export.Depth.Name, export.Book.Name}, "Datasets to write: depth, book")
+	exportCmd.Flags().StringSlice("formats", []string{export.FormatParquet}, "Output formats: parquet, csv")
+	exportCmd.Flags().String("compression", "snappy", "Parquet compression: snappy, gzip or none")
+	exportCmd.Flags().String("from", "", "Start of the range (default start of the journal)")
+	exportCmd.Flags().String("to", "", "End of the range (default end of the journal)")
+	exportCmd.Flags().Duration("interval", time.Second, "Sampling interval of the book dataset")
+	exportCmd.Flags().Int("depth", 20, "Levels per side in the book dataset, 0 for the full book")
+}
+
+func runExport(cmd *cobra.Command) error {
+	flags := cmd.Flags()
+	path, _ := flags.GetString("history")
+	out, _ := flags.GetString("out")
+	venue, _ := flags.GetString("exchange")
+	symbols, _ := flags.GetStringSlice("symbols")
+	datasets, _ := flags.GetStringSlice("datasets")
+	formats, _ := flags.GetStringSlice("formats")
+	compression, _ := flags.GetString("compression")
+	from, _ := flags.GetString("from")
+	to, _ := flags.GetString("to")
+	interval, _ := flags.GetDuration("interval")
+	depth, _ := flags.GetInt("depth")
+
+	for _, name := range datasets {
+		if name == export.Trades.Name {
+			return errors.New("trades are not journaled; export them with the live exporter")
+		}
+	}
+
+	sinks, err := export.NewSinks(out, datasets, formats, export.SinkOptions{Compression: compression})
+	if err != nil {
+		return err
+	}
+
+	store, err := history.OpenReadOnly(path)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	if len(symbols) == 0 {
+		if symbols, err = store.Symbols(venue); err != nil {
+			return err
+		}
+	}
+
+	for _, symbol := range symbols {
+		symbol = strings.ToUpper(strings.TrimSpace(symbol))
+
+		start, end, ok := store.Bounds(venue, symbol)
+		if !ok {
+			fmt.Fprintf(cmd.ErrOrStderr(), "no history for %s\n", symbol)
+			continue
+		}
+		if from != "" {
+			ms, err := parseHistoryTime(from)
+			if err != nil {
+				return fmt.Errorf("--from: %w", err)
+			}
+			start = time.UnixMilli(ms)
+		}
+		if to != "" {
+			ms, err := parseHistoryTime(to)
+			if err != nil {
+				return fmt.Errorf("--to: %w", err)
+			}
+			end = time.UnixMilli(ms)
+		}
+
+		if err := exportSymbol(store, sinks, venue, symbol, start, end, interval, depth); err != nil {
+			return fmt.Errorf("export %s: %w", symbol, err)
+		}
+	}
+
+	var errs []error
+	for _, group := range sinks {
+		for _, sink := range group {
+			errs = append(errs, sink.Close())
+			for _, file := range sink.Written() {
+				fmt.Fprintln(cmd.OutOrStdout(), file)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func exportSymbol(store *history.Store, sinks map[string][]*export.Sink, venue, symbol string, from, to time.Time, interval time.Duration, depth int) error {
+	write := func(dataset string, at time.Time, rows [][]any) error {
+		for _, sink := range sinks[dataset] {
+			for _, row := range rows {
+				if err := sink.Write(venue, symbol, at, row); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	if sinks[export.Depth.Name] != nil {
+		err := store.Deltas(venue, symbol, from, to, func(d history.Delta) error {
+			return write(export.Depth.Name, time.UnixMilli(d.EventTime), export.DepthRows(venue, symbol, d))
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if sinks[export.Book.Name] != nil {
+		err := store.Replay(venue, symbol, from, to, interval, depth, func(snap history.Snapshot) error {
+			at := time.UnixMilli(snap.Timestamp)
+			return write(export.Book.Name, at, export.BookRows(venue, symbol, at, snap.Book(), depth))
+		})
+		if err != nil && !errors.Is(err, history.ErrNoHistory) {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 	"os"
 	"strings"
 
+	"github.com/ChethiyaNishanath/market-data-hub/internal/config"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -21,9 +22,28 @@ var rootCmd = &cobra.Command{
 	},
 }
 
+// exitError carries the process exit code for a failed command. Errors without
+// one exit with 1.
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string { return e.err.Error() }
+
+func (e *exitError) Unwrap() error { return e.err }
+
+func withExitCode(code int, err error) error {
+	return &exitError{code: code, err: err}
+}
+
 func Execute() {
 	err := rootCmd.Execute()
 	if err != nil {
+		var exit *exitError
+		if errors.As(err, &exit) {
+			os.Exit(exit.code)
+		}
 		os.Exit(1)
 	}
 }
@@ -34,8 +54,9 @@ func init() {
 
 func initializeConfig(cmd *cobra.Command) error {
 	viper.SetEnvPrefix("MDH")
-	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "*", "-", "*"))
+	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_", "-", "_"))
 	viper.AutomaticEnv()
+	config.SetDefaults(viper.GetViper())
 
 	if cfgFile != "" {
 		viper.SetConfigFile(cfgFile)
@@ -1,15 +1,13 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
 	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
-	"strconv"
-	"strings"
 	"syscall"
 	"time"
 
@@ -19,10 +17,10 @@ import (
 
 	"github.com/ChethiyaNishanath/market-data-hub/internal/app"
 	"github.com/ChethiyaNishanath/market-data-hub/internal/config"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/telemetry"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/cors"
-	"github.com/go-chi/httprate"
 )
 
 var serveCmd = &cobra.Command{
@@ -36,29 +34,37 @@ var serveCmd = &cobra.Command{
 func init() {
 	rootCmd.AddCommand(serveCmd)
 	serveCmd.Flags().Int("server.port", 8080, "Port to run the server on")
-	serveCmd.Flags().Int("server.shutdownTimeout", 10, "Server shutdown timeout")
-	serveCmd.Flags().String("integrations.binance.subscriptions", "BTCUSDT, BNBBTC", "Server shutdown timeout")
+	serveCmd.Flags().Duration("server.shutdownTimeout", 10*time.Second, "Time allowed for a graceful shutdown")
+	serveCmd.Flags().StringSlice("integrations.binance.subscriptions", nil, "Symbols to subscribe to, replacing the configured list")
 }
 
 func run() {
-	cfg, err := loadConfig()
+	cfg, err := config.Load(viper.GetViper())
 	if err != nil {
-		slog.Error("Invalid config", "error", err)
+		for _, problem := range configProblems(err) {
+			slog.Error("Invalid config", "error", problem)
+		}
 		os.Exit(1)
 	}
 
-	if b, err := json.MarshalIndent(cfg, "", "  "); err == nil {
-		slog.Debug("CONFIG LOADED\n", "values", string(b))
+	var values bytes.Buffer
+	if err := cfg.WriteJSON(&values); err == nil {
+		slog.Debug("CONFIG LOADED\n", "values", values.String())
 	}
 
-	slog.SetLogLoggerLevel(parseLogLevel(cfg.Logging.Level))
+	slog.SetLogLoggerLevel(cfg.Logging.SlogLevel())
 
 	port := cfg.Server.Port
-	shutdownTimeout := cfg.Server.ShutdownTimeout
 
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
+	shutdownTracing, err := telemetry.SetupTracing(ctx, cfg.Tracing)
+	if err != nil {
+		slog.Error("Tracing setup failed", "error", err)
+		os.Exit(1)
+	}
+
 	newApp := app.NewApp(&ctx, cfg)
 	r := chi.NewRouter()
 
@@ -71,26 +77,27 @@ func run() {
 
 	r.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   []string{"*"},
-		AllowedMethods:   []string{"GET"},
+		AllowedMethods:   []string{"GET", "POST", "DELETE"},
 		AllowedHeaders:   []string{"Accept", "Content-Type"},
 		ExposedHeaders:   []string{"Link"},
 		AllowCredentials: false,
 		MaxAge:           300,
 	}))
 
-	r.Use(httprate.LimitByIP(100, 1*time.Minute))
+	r.Use(newApp.RateLimit)
 
 	newApp.RegisterRoutes(r)
+	watchConfig(ctx, newApp)
 
-	go grpc.RunGrpcServer()
+	go grpc.RunGrpcServer(newApp.GrpcDependencies())
 
 	server := &http.Server{
-		Addr:    fmt.Sprintf(":%s", port),
+		Addr:    fmt.Sprintf(":%d", port),
 		Handler: r,
 	}
 
 	go func() {
-		slog.Info(fmt.Sprintf("Server starting on port %s", port))
+		slog.Info(fmt.Sprintf("Server starting on port %d", port))
 		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			slog.Error("Server failed", "error", err)
 		}
@@ -101,38 +108,70 @@ func run() {
 
 	stop()
 
-	shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Duration(func() int {
-		timeout, _ := strconv.Atoi(shutdownTimeout)
-		return timeout
-	}())*time.Second)
-
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
 	defer cancel()
 
 	if err := server.Shutdown(shutdownCtx); err != nil {
 		slog.Error("Forced server shutdown", "error", err)
 	}
 
+	newApp.Close()
+
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		slog.Error("Tracing shutdown failed", "error", err)
+	}
+
 	slog.Info("Shutdown complete")
 }
 
-func loadConfig() (*config.Config, error) {
-	var cfg config.Config
-
-	if err := viper.Unmarshal(&cfg); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
+// configProblems splits the joined validation errors of config.Load so each
+// is reported on its own.
+func configProblems(err error) []error {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		return joined.Unwrap()
 	}
-	return &cfg, nil
+	return []error{err}
 }
 
-func parseLogLevel(level string) slog.Level {
-	switch strings.ToLower(level) {
-	case "debug":
-		return slog.LevelDebug
-	case "warn", "warning":
-		return slog.LevelWarn
-	case "error":
-		return slog.LevelError
-	default:
-		return slog.LevelInfo
+// watchConfig reloads the config on SIGHUP and whenever the config file
+// changes, until ctx ends. A config that does not validate is logged and not
+// applied. SIGHUP is caught from the return on, so it no longer ends the
+// process.
+func watchConfig(ctx context.Context, a *app.App) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+
+	changed := make(chan struct{}, 1)
+	if path := viper.ConfigFileUsed(); path != "" {
+		if err := config.Watch(ctx, path, changed); err != nil {
+			slog.Warn("Config file not watched, reload with SIGHUP", "path", path, "error", err)
+		}
 	}
+
+	go func() {
+		defer signal.Stop(hup)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-hup:
+				slog.Info("SIGHUP received, reloading config")
+			case <-changed:
+				slog.Info("Config file changed, reloading config")
+			}
+
+			if err := viper.ReadInConfig(); err != nil {
+				slog.Error("Config reload failed", "error", err)
+				continue
+			}
+			cfg, err := config.Load(viper.GetViper())
+			if err != nil {
+				for _, problem := range configProblems(err) {
+					slog.Error("Config reload rejected", "error", problem)
+				}
+				continue
+			}
+			a.Reload(cfg)
+		}
+	}()
 }
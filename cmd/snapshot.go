@@ -2,22 +2,46 @@ package cmd
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
-	"log/slog"
+	"os"
+	"strings"
 	"time"
 
 	pb "github.com/ChethiyaNishanath/market-data-hub/api/orderbook"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/snapshot"
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Exit codes of the client commands.
+const (
+	exitUsage       = 2
+	exitUnavailable = 3
+	exitNotFound    = 4
+	exitMismatch    = 5
 )
 
 var snapshotCmd = &cobra.Command{
 	Use:   "snapshot",
 	Short: "Retrieve a snapshot of the current order book",
+	Long: `Fetch the order books of one or more symbols (--symbol, repeatable or comma
+separated) or of every symbol the hub carries (--all) and write them to stdout
+as a table, JSON, CSV or NDJSON. Symbols the hub does not carry are reported on
+stderr and the rest are still written.
+
+Exit codes: 0 success, 1 unexpected error, 2 invalid usage, 3 hub unavailable
+or timed out, 4 one or more symbols not found.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return runOrderBookGrpcClient()
+		return runOrderBookGrpcClient(cmd)
 	},
 }
 
@@ -25,36 +49,174 @@ func init() {
 	rootCmd.AddCommand(snapshotCmd)
 
 	snapshotCmd.Flags().String("addr", "0.0.0.0:50051", "gRPC server address")
-	snapshotCmd.Flags().String("symbol", "BTCUSDT", "Symbol to fetch the snapshot for")
+	snapshotCmd.Flags().StringSlice("symbol", []string{"BTCUSDT"}, "Symbols to fetch the snapshot for")
+	snapshotCmd.Flags().Bool("all", false, "Fetch every symbol the hub carries")
+	snapshotCmd.Flags().Int32("depth", 0, "Levels per side, 0 for the full book")
+	snapshotCmd.Flags().StringP("output", "o", snapshot.FormatTable, "Output format: "+strings.Join(snapshot.Formats, "|"))
+	snapshotCmd.Flags().Duration("timeout", 5*time.Second, "Time allowed for the whole fetch")
+	snapshotCmd.Flags().Bool("tls", false, "Connect over TLS")
+	snapshotCmd.Flags().String("ca-file", "", "CA bundle to verify the hub's certificate (implies --tls)")
+	snapshotCmd.Flags().String("cert", "", "Client certificate for mutual TLS (implies --tls)")
+	snapshotCmd.Flags().String("key", "", "Client key for mutual TLS")
+	snapshotCmd.Flags().String("server-name", "", "Name to verify the hub's certificate against")
+	snapshotCmd.Flags().Bool("insecure-skip-verify", false, "Do not verify the hub's certificate")
+	snapshotCmd.Flags().String("token", "", "Bearer token sent with each request (default $MDH_TOKEN)")
 
-	viper.BindPFlag("addr", snapshotCmd.Flags().Lookup("addr"))
-	viper.BindPFlag("symbol", snapshotCmd.Flags().Lookup("symbol"))
+	snapshotCmd.SetFlagErrorFunc(func(_ *cobra.Command, err error) error {
+		return withExitCode(exitUsage, err)
+	})
 }
 
-func runOrderBookGrpcClient() error {
-	addr := viper.GetString("addr")
-	sym := viper.GetString("symbol")
+func runOrderBookGrpcClient(cmd *cobra.Command) error {
+	flags := cmd.Flags()
+	addr, _ := flags.GetString("addr")
+	symbols, _ := flags.GetStringSlice("symbol")
+	all, _ := flags.GetBool("all")
+	depth, _ := flags.GetInt32("depth")
+	output, _ := flags.GetString("output")
+	timeout, _ := flags.GetDuration("timeout")
+	token, _ := flags.GetString("token")
 
-	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if all && flags.Changed("symbol") {
+		return withExitCode(exitUsage, errors.New("--symbol and --all cannot be used together"))
+	}
+	if flags.Changed("cert") != flags.Changed("key") {
+		return withExitCode(exitUsage, errors.New("--cert and --key must be set together"))
+	}
+	if !snapshot.ValidFormat(output) {
+		return withExitCode(exitUsage, fmt.Errorf("unknown --output %q, expected one of %s", output, strings.Join(snapshot.Formats, ", ")))
+	}
+	if depth < 0 {
+		return withExitCode(exitUsage, errors.New("--depth must not be negative"))
+	}
 
+	creds, err := snapshotCredentials(cmd)
 	if err != nil {
-		slog.Error("did not connect: %v", "error", err)
+		return withExitCode(exitUsage, err)
 	}
 
+	// From here on a failure is not a usage mistake.
+	cmd.SilenceUsage = true
+
+	conn, err := grpc.NewClient(addr,
+		grpc.WithTransportCredentials(creds),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+	)
+	if err != nil {
+		return withExitCode(exitUsage, fmt.Errorf("connect to %s: %w", addr, err))
+	}
 	defer conn.Close()
 
 	c := pb.NewOrderBookClient(conn)
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	ctx, cancel := context.WithTimeout(withToken(context.Background(), token), timeout)
 	defer cancel()
 
-	res, err := c.GetSnapshot(ctx, &pb.OrderBookSnapshotRequest{Symbol: sym})
+	if all {
+		res, err := c.ListSymbols(ctx, &pb.ListSymbolsRequest{})
+		if err != nil {
+			return snapshotError(addr, "symbols not received", err)
+		}
+		symbols = res.GetSymbols()
+	}
+
+	var (
+		books   []snapshot.Book
+		missing int
+	)
+	for _, sym := range symbols {
+		sym = strings.ToUpper(strings.TrimSpace(sym))
+		if sym == "" {
+			continue
+		}
 
-	if err != nil {
-		slog.Error("snapshot not received %w", "error", err)
-		return fmt.Errorf("snapshot not received: %w", err)
+		res, err := c.GetSnapshot(ctx, &pb.OrderBookSnapshotRequest{Symbol: sym, Depth: depth})
+		if status.Code(err) == codes.NotFound {
+			fmt.Fprintf(cmd.ErrOrStderr(), "%s: order book not found\n", sym)
+			missing++
+			continue
+		}
+		if err != nil {
+			return snapshotError(addr, sym+": snapshot not received", err)
+		}
+		books = append(books, snapshot.FromReply(res))
 	}
 
-	slog.Info("Snapshot received", "symbol", sym, "data", res)
+	if err := snapshot.Write(cmd.OutOrStdout(), output, books); err != nil {
+		return fmt.Errorf("write snapshot: %w", err)
+	}
 
+	if missing > 0 {
+		return withExitCode(exitNotFound, fmt.Errorf("%d of %d symbols not found", missing, len(symbols)))
+	}
 	return nil
 }
+
+// snapshotCredentials builds the transport credentials from the TLS flags.
+// Plaintext is used unless --tls or one of the certificate flags is set.
+func snapshotCredentials(cmd *cobra.Command) (credentials.TransportCredentials, error) {
+	flags := cmd.Flags()
+	useTLS, _ := flags.GetBool("tls")
+	caFile, _ := flags.GetString("ca-file")
+	certFile, _ := flags.GetString("cert")
+	keyFile, _ := flags.GetString("key")
+	serverName, _ := flags.GetString("server-name")
+	skipVerify, _ := flags.GetBool("insecure-skip-verify")
+
+	if !useTLS && caFile == "" && certFile == "" && serverName == "" && !skipVerify {
+		return insecure.NewCredentials(), nil
+	}
+
+	cfg := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         serverName,
+		InsecureSkipVerify: skipVerify,
+	}
+
+	if caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("read --ca-file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("--ca-file %s holds no PEM certificates", caFile)
+		}
+		cfg.RootCAs = pool
+	}
+
+	if certFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return credentials.NewTLS(cfg), nil
+}
+
+// withToken sends token, or $MDH_TOKEN when it is empty, as the bearer token
+// of every call made with ctx.
+func withToken(ctx context.Context, token string) context.Context {
+	if token == "" {
+		token = os.Getenv("MDH_TOKEN")
+	}
+	if token == "" {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+}
+
+// snapshotError maps a failed call to the command's exit code.
+func snapshotError(addr, msg string, err error) error {
+	err = fmt.Errorf("%s: %w", msg, err)
+
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return withExitCode(exitUnavailable, fmt.Errorf("hub at %s: %w", addr, err))
+	case codes.NotFound:
+		return withExitCode(exitNotFound, err)
+	default:
+		return err
+	}
+}
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ChethiyaNishanath/market-data-hub/internal/config"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/exchange/binance"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/verify"
+	"github.com/spf13/cobra"
+)
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Check a running hub's order books against exchange snapshots",
+	Long: `Subscribe to each --symbol on the hub's WebSocket endpoint, fetch a REST depth
+snapshot from the exchange, roll the older of the two books forward with the
+hub's updates until both stand at the same update ID and compare them level by
+level within the best --depth levels.
+
+Exit codes: 0 books match, 1 unexpected error, 2 invalid usage, 3 hub or
+exchange unavailable or books could not be lined up, 4 symbol not carried by
+the hub, 5 more than --max-mismatches levels differ.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runVerify(cmd)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(verifyCmd)
+
+	verifyCmd.Flags().String("url", "ws://localhost:8084/ws", "Hub WebSocket endpoint")
+	verifyCmd.Flags().String("rest-url", "https://api.binance.com/api/v3", "Exchange REST API the snapshots are fetched from")
+	verifyCmd.Flags().StringSlice("symbol", []string{"BTCUSDT"}, "Symbols to check")
+	verifyCmd.Flags().Int("depth", 100, "Levels per side to compare, 0 for every level in the snapshot")
+	verifyCmd.Flags().Int("limit", 1000, "Levels per side requested from the exchange")
+	verifyCmd.Flags().Int("max-mismatches", 0, "Differing levels tolerated per symbol")
+	verifyCmd.Flags().Duration("timeout", 30*time.Second, "Time allowed per symbol")
+	verifyCmd.Flags().StringP("output", "o", "text", "Output format: text|json")
+
+	verifyCmd.SetFlagErrorFunc(func(_ *cobra.Command, err error) error {
+		return withExitCode(exitUsage, err)
+	})
+}
+
+func runVerify(cmd *cobra.Command) error {
+	flags := cmd.Flags()
+	url, _ := flags.GetString("url")
+	restURL, _ := flags.GetString("rest-url")
+	symbols, _ := flags.GetStringSlice("symbol")
+	depth, _ := flags.GetInt("depth")
+	limit, _ := flags.GetInt("limit")
+	maxMismatches, _ := flags.GetInt("max-mismatches")
+	timeout, _ := flags.GetDuration("timeout")
+	output, _ := flags.GetString("output")
+
+	if output != "text" && output != "json" {
+		return withExitCode(exitUsage, fmt.Errorf("unknown --output %q, expected text or json", output))
+	}
+	if depth < 0 || limit <= 0 || maxMismatches < 0 {
+		return withExitCode(exitUsage, errors.New("--depth and --max-mismatches must not be negative and --limit must be positive"))
+	}
+
+	cmd.SilenceUsage = true
+
+	fetcher := binance.NewSnapshotFetcher(config.BinanceConfig{
+		RestApiUrlV3: restURL,
+		Snapshot:     config.SnapshotConfig{Limit: limit},
+	})
+	cfg := verify.RemoteConfig{URL: url, Exchange: binance.ExchangeName, Depth: depth}
+
+	var (
+		reports                      []verify.Report
+		unavailable, missing, failed int
+	)
+	for _, sym := range symbols {
+		sym = strings.ToUpper(strings.TrimSpace(sym))
+		if sym == "" {
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+		report, err := verify.CheckRemote(ctx, cfg, fetcher, sym)
+		cancel()
+
+		switch {
+		case errors.Is(err, verify.ErrUnknownSymbol):
+			fmt.Fprintf(cmd.ErrOrStderr(), "%s: %v\n", sym, err)
+			missing++
+			continue
+		case err != nil:
+			fmt.Fprintf(cmd.ErrOrStderr(), "%s: %v\n", sym, err)
+			unavailable++
+			continue
+		case report.Status == verify.StatusSkipped:
+			unavailable++
+		case len(report.Mismatches) > maxMismatches:
+			failed++
+		}
+		reports = append(reports, report)
+	}
+
+	write := verify.WriteText
+	if output == "json" {
+		write = verify.WriteJSON
+	}
+	if err := write(cmd.OutOrStdout(), reports); err != nil {
+		return fmt.Errorf("write report: %w", err)
+	}
+
+	switch {
+	case failed > 0:
+		return withExitCode(exitMismatch, fmt.Errorf("%d symbols differ from the exchange", failed))
+	case missing > 0:
+		return withExitCode(exitNotFound, fmt.Errorf("%d symbols not carried by the hub", missing))
+	case unavailable > 0:
+		return withExitCode(exitUnavailable, fmt.Errorf("%d symbols could not be checked", unavailable))
+	}
+	return nil
+}
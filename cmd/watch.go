@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/ChethiyaNishanath/market-data-hub/internal/ladder"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+)
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Show a live order book ladder in the terminal",
+	Long: `Follow a symbol over the hub's WebSocket feed and draw its book as a price
+ladder with size bars, the spread, the last trade, the update rate and the sync
+state. Levels that just changed are highlighted.
+
+Keys: ←/→ or tab switch between the --symbol list, / watches any other symbol,
++/- change the depth, r reloads the book and q quits.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runWatch(cmd)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(watchCmd)
+
+	watchCmd.Flags().String("url", "ws://localhost:8084/ws", "Hub WebSocket endpoint")
+	watchCmd.Flags().StringSlice("symbol", []string{"BTCUSDT"}, "Symbols to switch between, first shown first")
+	watchCmd.Flags().Int("depth", 20, "Levels per side")
+}
+
+func runWatch(cmd *cobra.Command) error {
+	flags := cmd.Flags()
+	url, _ := flags.GetString("url")
+	symbols, _ := flags.GetStringSlice("symbol")
+	depth, _ := flags.GetInt("depth")
+
+	cleaned := make([]string, 0, len(symbols))
+	for _, s := range symbols {
+		if s = strings.TrimSpace(s); s != "" {
+			cleaned = append(cleaned, s)
+		}
+	}
+	if len(cleaned) == 0 {
+		return errors.New("--symbol is required")
+	}
+
+	ctx, cancel := context.WithCancel(cmd.Context())
+	defer cancel()
+
+	feed := ladder.NewFeed(url)
+	go feed.Run(ctx)
+
+	model := ladder.NewModel(feed, feed.Messages(), cleaned, depth)
+	_, err := tea.NewProgram(model, tea.WithAltScreen(), tea.WithContext(ctx)).Run()
+	if errors.Is(err, tea.ErrProgramKilled) && ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,491 @@
+package alerting
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ChethiyaNishanath/market-data-hub/internal/analytics"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/config"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/domain/exchange"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/domain/subscription"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/exchange/binance"
+	"github.com/google/uuid"
+)
+
+const (
+	Method      = "alert"
+	TopicSuffix = "@alert"
+
+	// ConfiguredPrefix starts the ID of every alert defined in the config.
+	ConfiguredPrefix = "config:"
+
+	defaultEvaluationInterval = 250 * time.Millisecond
+)
+
+var (
+	ErrInvalidAlert  = errors.New("invalid alert")
+	ErrAlertNotFound = errors.New("alert not found")
+)
+
+// Target says where a fired alert is delivered. WebSocket delivery goes to the
+// client that owns the alert.
+type Target struct {
+	WebSocket  bool   `json:"websocket,omitempty"`
+	WebhookURL string `json:"webhookUrl,omitempty"`
+}
+
+// Alert is a registered rule. Without Repeat it fires once and is then disarmed;
+// with Repeat it fires again whenever the rule holds and the cooldown has passed.
+type Alert struct {
+	ID              string    `json:"id"`
+	ClientID        string    `json:"clientId,omitempty"`
+	Symbol          string    `json:"symbol"`
+	Rule            Rule      `json:"rule"`
+	Repeat          bool      `json:"repeat,omitempty"`
+	CooldownSeconds float64   `json:"cooldownSeconds,omitempty"`
+	Deliver         Target    `json:"deliver"`
+	Active          bool      `json:"active"`
+	FireCount       int       `json:"fireCount"`
+	LastFiredAt     time.Time `json:"lastFiredAt,omitzero"`
+	CreatedAt       time.Time `json:"createdAt"`
+}
+
+type Notification struct {
+	AlertID string    `json:"alertId"`
+	Symbol  string    `json:"symbol"`
+	Rule    string    `json:"rule"`
+	Value   float64   `json:"value"`
+	Message string    `json:"message"`
+	FiredAt time.Time `json:"firedAt"`
+}
+
+// Repository persists alert definitions and their fire state across restarts.
+type Repository interface {
+	SaveAlert(alert Alert) error
+	DeleteAlert(id string) error
+	LoadAlerts() ([]Alert, error)
+}
+
+// Engine evaluates every active alert on a fixed interval against the current
+// book of its symbol.
+type Engine struct {
+	cfg        config.AlertsConfig
+	source     exchange.BookSource
+	connMgr    subscription.ClientConnectionManager
+	dispatcher *Dispatcher
+	repo       Repository
+	now        func() time.Time
+
+	// retick carries a new evaluation interval to Start.
+	retick chan time.Duration
+
+	mu            sync.RWMutex
+	alerts        map[string]*Alert
+	unsyncedSince map[string]time.Time
+}
+
+// NewEngine creates an alert engine. dispatcher may be nil, in which case alerts
+// with a webhook target are rejected.
+func NewEngine(cfg config.AlertsConfig, source exchange.BookSource, connMgr subscription.ClientConnectionManager, dispatcher *Dispatcher) *Engine {
+	if cfg.EvaluationInterval <= 0 {
+		cfg.EvaluationInterval = defaultEvaluationInterval
+	}
+
+	return &Engine{
+		cfg:           cfg,
+		source:        source,
+		connMgr:       connMgr,
+		dispatcher:    dispatcher,
+		now:           time.Now,
+		retick:        make(chan time.Duration, 1),
+		alerts:        make(map[string]*Alert),
+		unsyncedSince: make(map[string]time.Time),
+	}
+}
+
+// UseRepository restores the alerts saved by a previous run and persists every
+// later change. Alerts delivered only over WebSocket are dropped, since the
+// client that owned them is gone. It must be called before Start.
+func (e *Engine) UseRepository(repo Repository) error {
+	alerts, err := repo.LoadAlerts()
+	if err != nil {
+		return fmt.Errorf("load alerts: %w", err)
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.repo = repo
+	for _, alert := range alerts {
+		if alert.Deliver.WebhookURL == "" {
+			if err := repo.DeleteAlert(alert.ID); err != nil {
+				slog.Error("Failed to delete orphaned alert", "id", alert.ID, "error", err)
+			}
+			continue
+		}
+		if e.dispatcher == nil {
+			slog.Warn("Restored alert has a webhook target but webhooks are not enabled", "id", alert.ID)
+		}
+		stored := alert
+		e.alerts[alert.ID] = &stored
+	}
+	return nil
+}
+
+func (e *Engine) Create(alert Alert) (Alert, error) {
+	alert, err := e.check(alert)
+	if err != nil {
+		return Alert{}, err
+	}
+
+	alert.ID = uuid.NewString()
+	alert.Active = true
+	alert.FireCount = 0
+	alert.LastFiredAt = time.Time{}
+	alert.CreatedAt = e.now()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.repo != nil {
+		if err := e.repo.SaveAlert(alert); err != nil {
+			return Alert{}, fmt.Errorf("save alert: %w", err)
+		}
+	}
+	stored := alert
+	e.alerts[alert.ID] = &stored
+	return alert, nil
+}
+
+// check normalizes an alert definition and reports why it cannot be registered.
+func (e *Engine) check(alert Alert) (Alert, error) {
+	alert.Symbol = strings.ToUpper(strings.TrimSpace(alert.Symbol))
+	alert.Rule = normalizeRule(alert.Rule)
+
+	if alert.Symbol == "" {
+		return Alert{}, fmt.Errorf("%w: symbol is required", ErrInvalidAlert)
+	}
+	if err := alert.Rule.Validate(); err != nil {
+		return Alert{}, fmt.Errorf("%w: %v", ErrInvalidAlert, err)
+	}
+	if !alert.Deliver.WebSocket && alert.Deliver.WebhookURL == "" {
+		return Alert{}, fmt.Errorf("%w: at least one delivery target is required", ErrInvalidAlert)
+	}
+	if alert.Deliver.WebSocket && alert.ClientID == "" {
+		return Alert{}, fmt.Errorf("%w: websocket delivery needs a clientId", ErrInvalidAlert)
+	}
+	if alert.Deliver.WebhookURL != "" && e.dispatcher == nil {
+		return Alert{}, fmt.Errorf("%w: webhooks are not enabled", ErrInvalidAlert)
+	}
+	if alert.Deliver.WebhookURL != "" {
+		if u, err := url.Parse(alert.Deliver.WebhookURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return Alert{}, fmt.Errorf("%w: webhookUrl must be an http or https URL with a host", ErrInvalidAlert)
+		}
+	}
+	if alert.CooldownSeconds < 0 {
+		return Alert{}, fmt.Errorf("%w: cooldownSeconds must not be negative", ErrInvalidAlert)
+	}
+	return alert, nil
+}
+
+// SetRules replaces the alerts defined in the config with rules. Their IDs are
+// the configured ones prefixed with ConfiguredPrefix. A rule whose definition
+// is unchanged keeps its fire state, so a reload neither re-arms a one-shot
+// alert nor resets a cooldown. Configured alerts are not persisted, since the
+// config brings them back on start. If any rule is invalid nothing changes.
+func (e *Engine) SetRules(rules []config.AlertRuleConfig) error {
+	next := make(map[string]Alert, len(rules))
+	for _, rule := range rules {
+		alert, err := e.check(Alert{
+			ID:     ConfiguredPrefix + rule.ID,
+			Symbol: rule.Symbol,
+			Rule: Rule{
+				Type:          rule.Type,
+				Side:          rule.Side,
+				Direction:     rule.Direction,
+				Price:         rule.Price,
+				SpreadBps:     rule.SpreadBps,
+				BandBps:       rule.BandBps,
+				MinQuantity:   rule.MinQuantity,
+				DesyncSeconds: rule.DesyncSeconds,
+			},
+			Repeat:          rule.Repeat,
+			CooldownSeconds: rule.Cooldown.Seconds(),
+			Deliver:         Target{WebhookURL: rule.WebhookURL},
+		})
+		if err != nil {
+			return fmt.Errorf("alert rule %s: %w", rule.ID, err)
+		}
+		next[alert.ID] = alert
+	}
+
+	now := e.now()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for id := range e.alerts {
+		if _, ok := next[id]; configured(id) && !ok {
+			delete(e.alerts, id)
+		}
+	}
+	for id, alert := range next {
+		if cur, ok := e.alerts[id]; ok && sameDefinition(*cur, alert) {
+			continue
+		}
+		alert.Active = true
+		alert.CreatedAt = now
+		e.alerts[id] = &alert
+	}
+	return nil
+}
+
+func configured(id string) bool {
+	return strings.HasPrefix(id, ConfiguredPrefix)
+}
+
+func sameDefinition(a, b Alert) bool {
+	return a.Symbol == b.Symbol && a.Rule == b.Rule && a.Repeat == b.Repeat &&
+		a.CooldownSeconds == b.CooldownSeconds && a.Deliver == b.Deliver
+}
+
+// Delete removes an alert. A non-empty clientID restricts deletion to that client's alerts.
+func (e *Engine) Delete(id, clientID string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	alert, ok := e.alerts[id]
+	if !ok || (clientID != "" && alert.ClientID != clientID) {
+		return fmt.Errorf("%w: %s", ErrAlertNotFound, id)
+	}
+	if configured(id) {
+		return fmt.Errorf("%w: %s is defined in the config", ErrInvalidAlert, id)
+	}
+	if e.repo != nil {
+		if err := e.repo.DeleteAlert(id); err != nil {
+			return fmt.Errorf("delete alert: %w", err)
+		}
+	}
+	delete(e.alerts, id)
+	return nil
+}
+
+func (e *Engine) Get(id string) (Alert, bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	alert, ok := e.alerts[id]
+	if !ok {
+		return Alert{}, false
+	}
+	return *alert, true
+}
+
+// List returns alerts ordered by creation. A non-empty clientID filters to that client.
+func (e *Engine) List(clientID string) []Alert {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	list := make([]Alert, 0, len(e.alerts))
+	for _, alert := range e.alerts {
+		if clientID != "" && alert.ClientID != clientID {
+			continue
+		}
+		list = append(list, *alert)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].CreatedAt.Before(list[j].CreatedAt) })
+	return list
+}
+
+func (e *Engine) Start(ctx context.Context) {
+	ticker := time.NewTicker(e.cfg.EvaluationInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case interval := <-e.retick:
+			ticker.Reset(interval)
+		case <-ticker.C:
+			e.Evaluate()
+		}
+	}
+}
+
+// SetEvaluationInterval changes how often Start evaluates the alerts. 0 or
+// less restores the default. Only the latest of several quick changes is kept.
+func (e *Engine) SetEvaluationInterval(interval time.Duration) {
+	if interval <= 0 {
+		interval = defaultEvaluationInterval
+	}
+	for {
+		select {
+		case e.retick <- interval:
+			return
+		default:
+		}
+		select {
+		case <-e.retick:
+		default:
+		}
+	}
+}
+
+// Evaluate checks every active alert once and delivers the ones that fire.
+func (e *Engine) Evaluate() {
+	now := e.now()
+
+	e.mu.Lock()
+	bySymbol := make(map[string][]*Alert)
+	for _, alert := range e.alerts {
+		if alert.Active {
+			bySymbol[alert.Symbol] = append(bySymbol[alert.Symbol], alert)
+		}
+	}
+	e.mu.Unlock()
+
+	fired := make([]Alert, 0)
+	notifications := make([]Notification, 0)
+
+	for symbol, alerts := range bySymbol {
+		state := e.marketState(symbol, alerts, now)
+
+		e.mu.Lock()
+		for _, alert := range alerts {
+			if _, ok := e.alerts[alert.ID]; !ok || !alert.Active || !e.canFire(alert, now) {
+				continue
+			}
+			hit, value, message := alert.Rule.Evaluate(state, now)
+			if !hit {
+				continue
+			}
+
+			alert.FireCount++
+			alert.LastFiredAt = now
+			if !alert.Repeat {
+				alert.Active = false
+			}
+
+			fired = append(fired, *alert)
+			notifications = append(notifications, Notification{
+				AlertID: alert.ID,
+				Symbol:  symbol,
+				Rule:    alert.Rule.Type,
+				Value:   value,
+				Message: message,
+				FiredAt: now,
+			})
+		}
+		e.mu.Unlock()
+	}
+
+	for i := range fired {
+		e.persist(fired[i])
+		e.deliver(fired[i], notifications[i])
+	}
+}
+
+// persist saves the fire state of an alert so a restart neither re-fires a
+// one-shot alert nor skips a cooldown. Alerts deleted since they fired stay deleted.
+func (e *Engine) persist(alert Alert) {
+	if e.repo == nil || configured(alert.ID) {
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if _, ok := e.alerts[alert.ID]; !ok {
+		return
+	}
+	if err := e.repo.SaveAlert(alert); err != nil {
+		slog.Error("Failed to save alert state", "id", alert.ID, "error", err)
+	}
+}
+
+func (e *Engine) canFire(alert *Alert, now time.Time) bool {
+	if alert.LastFiredAt.IsZero() {
+		return true
+	}
+	cooldown := time.Duration(alert.CooldownSeconds * float64(time.Second))
+	return now.Sub(alert.LastFiredAt) >= cooldown
+}
+
+func (e *Engine) marketState(symbol string, alerts []*Alert, now time.Time) MarketState {
+	state := MarketState{Synchronized: e.source.IsSynchronized(symbol)}
+
+	e.mu.Lock()
+	if state.Synchronized {
+		delete(e.unsyncedSince, symbol)
+	} else {
+		if _, ok := e.unsyncedSince[symbol]; !ok {
+			e.unsyncedSince[symbol] = now
+		}
+		state.UnsyncedSince = e.unsyncedSince[symbol]
+	}
+	e.mu.Unlock()
+
+	if !state.Synchronized {
+		return state
+	}
+
+	book := e.source.GetOrderBook(symbol)
+	if book == nil {
+		state.Synchronized = false
+		return state
+	}
+
+	bands := make([]float64, 0)
+	for _, alert := range alerts {
+		if alert.Rule.Type == RuleDepth {
+			bands = append(bands, alert.Rule.BandBps)
+		}
+	}
+	state.Stats = analytics.Compute(symbol, book, 0, bands)
+	return state
+}
+
+func (e *Engine) deliver(alert Alert, n Notification) {
+	slog.Info("Alert fired", "id", alert.ID, "symbol", alert.Symbol, "rule", alert.Rule.Type, "value", n.Value)
+
+	if alert.Deliver.WebSocket {
+		e.deliverWebSocket(alert, n)
+	}
+
+	if alert.Deliver.WebhookURL != "" && e.dispatcher != nil {
+		payload, err := json.Marshal(n)
+		if err != nil {
+			slog.Error("Failed to marshal alert notification", "id", alert.ID, "error", err)
+			return
+		}
+		if err := e.dispatcher.Enqueue(alert.Deliver.WebhookURL, payload); err != nil {
+			slog.Error("Failed to enqueue alert webhook", "id", alert.ID, "error", err)
+		}
+	}
+}
+
+func (e *Engine) deliverWebSocket(alert Alert, n Notification) {
+	client := e.connMgr.GetClientByID(alert.ClientID)
+	if client == nil {
+		slog.Debug("Alert client no longer connected", "id", alert.ID, "client_id", alert.ClientID)
+		return
+	}
+
+	data, err := json.Marshal(binance.WSMessage{
+		Method:  Method,
+		Success: true,
+		Topic:   strings.ToLower(alert.Symbol) + TopicSuffix,
+		Data:    n,
+	})
+	if err != nil {
+		slog.Error("Failed to marshal alert notification", "id", alert.ID, "error", err)
+		return
+	}
+	client.Send(data)
+}
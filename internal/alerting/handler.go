@@ -0,0 +1,163 @@
+package alerting
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/ChethiyaNishanath/market-data-hub/internal/exchange/binance"
+	"github.com/coder/websocket"
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	ActionCreate = "create"
+	ActionDelete = "delete"
+	ActionList   = "list"
+)
+
+// AlertRequest is the params of an "alert" WebSocket message.
+type AlertRequest struct {
+	Action string `json:"action"`
+	ID     string `json:"id,omitempty"`
+	Alert  Alert  `json:"alert"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// HandleWebSocket serves the "alert" method. Alerts created over WebSocket belong
+// to the calling client, which can only list and delete its own alerts.
+func (e *Engine) HandleWebSocket(ctx context.Context, conn *websocket.Conn, payload json.RawMessage) {
+	client := e.connMgr.GetClient(conn)
+	if client == nil {
+		return
+	}
+
+	var req AlertRequest
+	if err := json.Unmarshal(payload, &req); err != nil {
+		writeWSError(ctx, conn, binance.CodeInvalidParams, "Invalid alert payload")
+		return
+	}
+
+	switch req.Action {
+	case ActionCreate:
+		alert := req.Alert
+		alert.ClientID = client.ID()
+		if !alert.Deliver.WebSocket && alert.Deliver.WebhookURL == "" {
+			alert.Deliver.WebSocket = true
+		}
+
+		created, err := e.Create(alert)
+		if err != nil {
+			writeWSError(ctx, conn, errorCode(err), err.Error())
+			return
+		}
+		writeWS(ctx, conn, created)
+
+	case ActionDelete:
+		if err := e.Delete(req.ID, client.ID()); err != nil {
+			writeWSError(ctx, conn, errorCode(err), err.Error())
+			return
+		}
+		writeWS(ctx, conn, map[string]string{"id": req.ID})
+
+	case ActionList:
+		writeWS(ctx, conn, e.List(client.ID()))
+
+	default:
+		writeWSError(ctx, conn, binance.CodeInvalidParams, "Unknown alert action: "+req.Action)
+	}
+}
+
+func writeWS(ctx context.Context, conn *websocket.Conn, data any) {
+	_ = binance.Reply(ctx, conn, binance.WSMessage{
+		Method:  Method,
+		Success: true,
+		Data:    data,
+	})
+}
+
+func writeWSError(ctx context.Context, conn *websocket.Conn, code, errMsg string) {
+	_ = binance.Reply(ctx, conn, binance.WSMessage{
+		Method:  Method,
+		Success: false,
+		Code:    code,
+		Error:   errMsg,
+	})
+}
+
+// RegisterRoutes mounts POST /alerts, GET /alerts, GET /alerts/{id} and DELETE /alerts/{id}.
+func (e *Engine) RegisterRoutes(r chi.Router) {
+	r.Post("/alerts", e.handleCreate)
+	r.Get("/alerts", e.handleList)
+	r.Get("/alerts/{id}", e.handleGet)
+	r.Delete("/alerts/{id}", e.handleDelete)
+}
+
+func (e *Engine) handleCreate(w http.ResponseWriter, r *http.Request) {
+	var alert Alert
+	if err := json.NewDecoder(r.Body).Decode(&alert); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: "invalid alert payload"})
+		return
+	}
+
+	created, err := e.Create(alert)
+	if err != nil {
+		writeJSON(w, statusCode(err), errorResponse{Error: err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusCreated, created)
+}
+
+func (e *Engine) handleList(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, e.List(r.URL.Query().Get("clientId")))
+}
+
+func (e *Engine) handleGet(w http.ResponseWriter, r *http.Request) {
+	alert, ok := e.Get(chi.URLParam(r, "id"))
+	if !ok {
+		writeJSON(w, http.StatusNotFound, errorResponse{Error: ErrAlertNotFound.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, alert)
+}
+
+func (e *Engine) handleDelete(w http.ResponseWriter, r *http.Request) {
+	if err := e.Delete(chi.URLParam(r, "id"), ""); err != nil {
+		writeJSON(w, statusCode(err), errorResponse{Error: err.Error()})
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func statusCode(err error) int {
+	switch {
+	case errors.Is(err, ErrInvalidAlert):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrAlertNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// errorCode is the WebSocket counterpart of statusCode.
+func errorCode(err error) string {
+	switch {
+	case errors.Is(err, ErrInvalidAlert):
+		return binance.CodeInvalidParams
+	case errors.Is(err, ErrAlertNotFound):
+		return binance.CodeNotFound
+	default:
+		return binance.CodeInternal
+	}
+}
+
+func writeJSON(w http.ResponseWriter, code int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(body)
+}
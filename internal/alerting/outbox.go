@@ -0,0 +1,168 @@
+package alerting
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	pendingBucket = []byte("pending")
+	deadBucket    = []byte("dead")
+)
+
+// Delivery is one webhook call waiting in the outbox.
+type Delivery struct {
+	ID          uint64          `json:"id"`
+	URL         string          `json:"url"`
+	Payload     json.RawMessage `json:"payload"`
+	Attempts    int             `json:"attempts"`
+	NextAttempt time.Time       `json:"nextAttempt"`
+	LastError   string          `json:"lastError,omitempty"`
+	CreatedAt   time.Time       `json:"createdAt"`
+}
+
+// DeliveryStore persists webhook deliveries so they survive restarts. Deliveries
+// that exhaust their attempts are dead-lettered for inspection.
+type DeliveryStore interface {
+	Enqueue(url string, payload []byte, now time.Time) (Delivery, error)
+	// Due returns pending deliveries whose next attempt is at or before now, oldest first.
+	Due(now time.Time) ([]Delivery, error)
+	Pending() ([]Delivery, error)
+	Dead() ([]Delivery, error)
+	Complete(id uint64) error
+	Reschedule(d Delivery) error
+	// Bury moves a delivery from the pending set to the dead letters.
+	Bury(d Delivery) error
+}
+
+// Outbox is a DeliveryStore on BoltDB, with the dead letters in their own bucket.
+type Outbox struct {
+	db *bolt.DB
+}
+
+func OpenOutbox(path string) (*Outbox, error) {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("create outbox directory: %w", err)
+		}
+	}
+
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("open outbox: %w", err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{pendingBucket, deadBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("init outbox: %w", err)
+	}
+
+	return &Outbox{db: db}, nil
+}
+
+func (o *Outbox) Close() error {
+	return o.db.Close()
+}
+
+func (o *Outbox) Enqueue(url string, payload []byte, now time.Time) (Delivery, error) {
+	d := Delivery{URL: url, Payload: payload, NextAttempt: now, CreatedAt: now}
+
+	err := o.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(pendingBucket)
+		id, err := bucket.NextSequence()
+		if err != nil {
+			return err
+		}
+		d.ID = id
+		return putDelivery(bucket, d)
+	})
+	return d, err
+}
+
+func (o *Outbox) Due(now time.Time) ([]Delivery, error) {
+	due := make([]Delivery, 0)
+	err := o.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(pendingBucket).ForEach(func(_, v []byte) error {
+			var d Delivery
+			if err := json.Unmarshal(v, &d); err != nil {
+				return err
+			}
+			if !d.NextAttempt.After(now) {
+				due = append(due, d)
+			}
+			return nil
+		})
+	})
+	return due, err
+}
+
+// Pending returns every delivery still waiting to succeed.
+func (o *Outbox) Pending() ([]Delivery, error) {
+	return o.Due(time.Unix(1<<62, 0))
+}
+
+// Dead returns deliveries that ran out of attempts.
+func (o *Outbox) Dead() ([]Delivery, error) {
+	dead := make([]Delivery, 0)
+	err := o.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(deadBucket).ForEach(func(_, v []byte) error {
+			var d Delivery
+			if err := json.Unmarshal(v, &d); err != nil {
+				return err
+			}
+			dead = append(dead, d)
+			return nil
+		})
+	})
+	return dead, err
+}
+
+func (o *Outbox) Complete(id uint64) error {
+	return o.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(pendingBucket).Delete(key(id))
+	})
+}
+
+func (o *Outbox) Reschedule(d Delivery) error {
+	return o.db.Update(func(tx *bolt.Tx) error {
+		return putDelivery(tx.Bucket(pendingBucket), d)
+	})
+}
+
+func (o *Outbox) Bury(d Delivery) error {
+	return o.db.Update(func(tx *bolt.Tx) error {
+		if err := tx.Bucket(pendingBucket).Delete(key(d.ID)); err != nil {
+			return err
+		}
+		return putDelivery(tx.Bucket(deadBucket), d)
+	})
+}
+
+func putDelivery(bucket *bolt.Bucket, d Delivery) error {
+	data, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+	return bucket.Put(key(d.ID), data)
+}
+
+// key is big-endian so bucket iteration follows enqueue order.
+func key(id uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, id)
+	return b
+}
@@ -0,0 +1,145 @@
+package alerting
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ChethiyaNishanath/market-data-hub/internal/analytics"
+)
+
+const (
+	RulePriceCross = "price_cross"
+	RuleSpread     = "spread"
+	RuleDepth      = "depth"
+	RuleDesync     = "desync"
+
+	SideBid = "bid"
+	SideAsk = "ask"
+
+	DirectionAbove = "above"
+	DirectionBelow = "below"
+)
+
+// Rule is the condition an alert watches.
+//
+//   - price_cross: the best Side price is Direction of Price
+//   - spread: the spread is wider than SpreadBps
+//   - depth: the quantity within BandBps of mid on Side (or either side when empty)
+//     is below MinQuantity
+//   - desync: the book has been out of sync for longer than DesyncSeconds
+type Rule struct {
+	Type          string  `json:"type"`
+	Side          string  `json:"side,omitempty"`
+	Direction     string  `json:"direction,omitempty"`
+	Price         float64 `json:"price,omitempty"`
+	SpreadBps     float64 `json:"spreadBps,omitempty"`
+	BandBps       float64 `json:"bandBps,omitempty"`
+	MinQuantity   float64 `json:"minQuantity,omitempty"`
+	DesyncSeconds float64 `json:"desyncSeconds,omitempty"`
+}
+
+// MarketState is what rules are evaluated against. Stats is only meaningful while
+// Synchronized.
+type MarketState struct {
+	Synchronized  bool
+	UnsyncedSince time.Time
+	Stats         analytics.Stats
+}
+
+func (r Rule) Validate() error {
+	switch r.Type {
+	case RulePriceCross:
+		if r.Side != SideBid && r.Side != SideAsk {
+			return fmt.Errorf("price_cross needs side %q or %q", SideBid, SideAsk)
+		}
+		if r.Direction != DirectionAbove && r.Direction != DirectionBelow {
+			return fmt.Errorf("price_cross needs direction %q or %q", DirectionAbove, DirectionBelow)
+		}
+		if r.Price <= 0 {
+			return fmt.Errorf("price_cross needs a positive price")
+		}
+	case RuleSpread:
+		if r.SpreadBps <= 0 {
+			return fmt.Errorf("spread needs a positive spreadBps")
+		}
+	case RuleDepth:
+		if r.Side != "" && r.Side != SideBid && r.Side != SideAsk {
+			return fmt.Errorf("depth side must be %q, %q or empty", SideBid, SideAsk)
+		}
+		if r.BandBps <= 0 || r.MinQuantity <= 0 {
+			return fmt.Errorf("depth needs a positive bandBps and minQuantity")
+		}
+	case RuleDesync:
+		if r.DesyncSeconds <= 0 {
+			return fmt.Errorf("desync needs a positive desyncSeconds")
+		}
+	default:
+		return fmt.Errorf("unknown rule type %q", r.Type)
+	}
+	return nil
+}
+
+// Evaluate reports whether the rule holds, with the observed value and a short
+// description for the notification.
+func (r Rule) Evaluate(state MarketState, now time.Time) (bool, float64, string) {
+	if r.Type == RuleDesync {
+		if state.Synchronized || state.UnsyncedSince.IsZero() {
+			return false, 0, ""
+		}
+		unsynced := now.Sub(state.UnsyncedSince).Seconds()
+		return unsynced >= r.DesyncSeconds, unsynced,
+			fmt.Sprintf("book out of sync for %.0fs", unsynced)
+	}
+
+	stats := state.Stats
+	if !state.Synchronized || stats.Mid == 0 {
+		return false, 0, ""
+	}
+
+	switch r.Type {
+	case RulePriceCross:
+		price := stats.BestBid
+		if r.Side == SideAsk {
+			price = stats.BestAsk
+		}
+		hit := price >= r.Price
+		if r.Direction == DirectionBelow {
+			hit = price <= r.Price
+		}
+		return hit, price, fmt.Sprintf("best %s %v is %s %v", r.Side, price, r.Direction, r.Price)
+
+	case RuleSpread:
+		return stats.SpreadBps > r.SpreadBps, stats.SpreadBps,
+			fmt.Sprintf("spread %.2fbps wider than %vbps", stats.SpreadBps, r.SpreadBps)
+
+	case RuleDepth:
+		for _, band := range stats.DepthBands {
+			if band.Bps != r.BandBps {
+				continue
+			}
+			qty := min(band.BidQuantity, band.AskQuantity)
+			switch r.Side {
+			case SideBid:
+				qty = band.BidQuantity
+			case SideAsk:
+				qty = band.AskQuantity
+			}
+			side := r.Side
+			if side == "" {
+				side = "book"
+			}
+			return qty < r.MinQuantity, qty,
+				fmt.Sprintf("%s depth within %vbps is %v, below %v", side, r.BandBps, qty, r.MinQuantity)
+		}
+	}
+
+	return false, 0, ""
+}
+
+func normalizeRule(r Rule) Rule {
+	r.Type = strings.ToLower(r.Type)
+	r.Side = strings.ToLower(r.Side)
+	r.Direction = strings.ToLower(r.Direction)
+	return r
+}
@@ -0,0 +1,186 @@
+package alerting
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/ChethiyaNishanath/market-data-hub/internal/config"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/retry"
+)
+
+const (
+	SignatureHeader = "X-MDH-Signature"
+	TimestampHeader = "X-MDH-Timestamp"
+
+	defaultWebhookTimeout     = 5 * time.Second
+	defaultWebhookAttempts    = 8
+	defaultWebhookBackoff     = time.Second
+	defaultWebhookMaxBackoff  = 5 * time.Minute
+	defaultDispatcherInterval = time.Second
+)
+
+// Sign returns the hex HMAC-SHA256 of "<timestamp>.<body>". Receivers recompute
+// it with the shared secret and compare against the X-MDH-Signature header.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Dispatcher drains the outbox, retrying failed webhook calls with jittered
+// exponential backoff until they succeed or run out of attempts.
+type Dispatcher struct {
+	outbox      DeliveryStore
+	client      *http.Client
+	secret      atomic.Pointer[string]
+	maxAttempts int
+	backoff     *retry.Backoff
+	interval    time.Duration
+	wake        chan struct{}
+	now         func() time.Time
+}
+
+func NewDispatcher(outbox DeliveryStore, cfg config.WebhookConfig) *Dispatcher {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultWebhookTimeout
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = defaultWebhookAttempts
+	}
+	if cfg.InitialBackoff <= 0 {
+		cfg.InitialBackoff = defaultWebhookBackoff
+	}
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = defaultWebhookMaxBackoff
+	}
+
+	d := &Dispatcher{
+		outbox:      outbox,
+		client:      &http.Client{Timeout: cfg.Timeout},
+		maxAttempts: cfg.MaxAttempts,
+		backoff:     retry.NewBackoff(cfg.InitialBackoff, cfg.MaxBackoff),
+		interval:    defaultDispatcherInterval,
+		wake:        make(chan struct{}, 1),
+		now:         time.Now,
+	}
+	d.SetSecret(cfg.Secret)
+	return d
+}
+
+// SetSecret changes the secret deliveries are signed with from the next
+// attempt on. An empty secret sends them unsigned.
+func (d *Dispatcher) SetSecret(secret string) {
+	d.secret.Store(&secret)
+}
+
+// Enqueue persists a delivery and nudges the dispatcher to send it right away.
+func (d *Dispatcher) Enqueue(url string, payload []byte) error {
+	if _, err := d.outbox.Enqueue(url, payload, d.now()); err != nil {
+		return err
+	}
+
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Run blocks until ctx is done. Deliveries left in the outbox by a previous run
+// are picked up on the first pass.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		d.Flush(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-d.wake:
+		}
+	}
+}
+
+// Flush attempts every delivery that is due.
+func (d *Dispatcher) Flush(ctx context.Context) {
+	due, err := d.outbox.Due(d.now())
+	if err != nil {
+		slog.Error("Failed to read webhook outbox", "error", err)
+		return
+	}
+
+	for _, delivery := range due {
+		if ctx.Err() != nil {
+			return
+		}
+		d.attempt(ctx, delivery)
+	}
+}
+
+func (d *Dispatcher) attempt(ctx context.Context, delivery Delivery) {
+	err := d.send(ctx, delivery)
+	if err == nil {
+		if err := d.outbox.Complete(delivery.ID); err != nil {
+			slog.Error("Failed to complete webhook delivery", "id", delivery.ID, "error", err)
+		}
+		return
+	}
+
+	delivery.Attempts++
+	delivery.LastError = err.Error()
+
+	if delivery.Attempts >= d.maxAttempts {
+		slog.Error("Webhook delivery failed permanently", "id", delivery.ID, "url", delivery.URL, "attempts", delivery.Attempts, "error", err)
+		if err := d.outbox.Bury(delivery); err != nil {
+			slog.Error("Failed to dead-letter webhook delivery", "id", delivery.ID, "error", err)
+		}
+		return
+	}
+
+	delivery.NextAttempt = d.now().Add(d.backoff.Delay(delivery.Attempts - 1))
+	slog.Warn("Webhook delivery failed - retrying", "id", delivery.ID, "url", delivery.URL, "attempt", delivery.Attempts, "next", delivery.NextAttempt, "error", err)
+	if err := d.outbox.Reschedule(delivery); err != nil {
+		slog.Error("Failed to reschedule webhook delivery", "id", delivery.ID, "error", err)
+	}
+}
+
+func (d *Dispatcher) send(ctx context.Context, delivery Delivery) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return err
+	}
+
+	timestamp := d.now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	if secret := *d.secret.Load(); secret != "" {
+		req.Header.Set(SignatureHeader, Sign(secret, timestamp, delivery.Payload))
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
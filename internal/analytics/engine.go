@@ -0,0 +1,225 @@
+package analytics
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ChethiyaNishanath/market-data-hub/internal/bus"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/config"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/domain/exchange"
+)
+
+const (
+	StatsUpdate = "statsUpdate"
+	TopicSuffix = "@stats"
+
+	defaultPublishInterval = time.Second
+	defaultTopN            = 10
+)
+
+var defaultDepthBands = []float64{10, 25, 50}
+
+// Engine marks a symbol dirty on every applied delta and recomputes its
+// statistics only when they are published, at most once per PublishInterval,
+// or read while dirty. A busy book is then parsed once per interval rather
+// than once per delta.
+type Engine struct {
+	cfg    config.AnalyticsConfig
+	bus    bus.IBus
+	source exchange.BookSource
+
+	mu    sync.RWMutex
+	stats map[string]Stats
+	// dirty are the symbols whose book changed since they were last published.
+	dirty map[string]bool
+
+	// symbols are the symbols followed and subscribed those whose updates
+	// are subscribed to, which stay subscribed as the bus cannot unsubscribe.
+	symbols    []string
+	subscribed map[string]bool
+}
+
+func NewEngine(cfg config.AnalyticsConfig, eventBus bus.IBus, source exchange.BookSource, symbols []string) *Engine {
+	if cfg.PublishInterval <= 0 {
+		cfg.PublishInterval = defaultPublishInterval
+	}
+	if cfg.TopN <= 0 {
+		cfg.TopN = defaultTopN
+	}
+	if len(cfg.DepthBandsBps) == 0 {
+		cfg.DepthBandsBps = defaultDepthBands
+	}
+
+	return &Engine{
+		cfg:        cfg,
+		bus:        eventBus,
+		source:     source,
+		stats:      make(map[string]Stats),
+		dirty:      make(map[string]bool),
+		symbols:    normalize(symbols),
+		subscribed: make(map[string]bool),
+	}
+}
+
+// Topic is the bus and WebSocket topic a symbol's statistics are published on.
+func Topic(symbol string) string {
+	return strings.ToLower(symbol) + TopicSuffix
+}
+
+func (e *Engine) Topics() []string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	topics := make([]string, 0, len(e.symbols))
+	for _, symbol := range e.symbols {
+		topics = append(topics, Topic(symbol))
+	}
+	return topics
+}
+
+// AddSymbol follows a symbol added to the subscriptions while running.
+func (e *Engine) AddSymbol(symbol string) {
+	symbol = strings.ToUpper(symbol)
+	e.mu.Lock()
+	if !slices.Contains(e.symbols, symbol) {
+		e.symbols = append(e.symbols, symbol)
+	}
+	e.mu.Unlock()
+	e.subscribe(symbol)
+}
+
+// RemoveSymbol stops publishing a symbol removed from the subscriptions and
+// drops its statistics.
+func (e *Engine) RemoveSymbol(symbol string) {
+	symbol = strings.ToUpper(symbol)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.symbols = slices.DeleteFunc(e.symbols, func(s string) bool { return s == symbol })
+	delete(e.stats, symbol)
+	delete(e.dirty, symbol)
+}
+
+func (e *Engine) subscribe(symbol string) {
+	e.mu.Lock()
+	if e.subscribed[symbol] {
+		e.mu.Unlock()
+		return
+	}
+	e.subscribed[symbol] = true
+	e.mu.Unlock()
+
+	lower := strings.ToLower(symbol)
+	for _, topic := range []string{lower + "@depth", lower + "@depth.reset"} {
+		e.bus.Subscribe(topic, func(bus.Event) {
+			e.markDirty(symbol)
+		})
+	}
+}
+
+func (e *Engine) Start(ctx context.Context) {
+	e.mu.RLock()
+	symbols := slices.Clone(e.symbols)
+	e.mu.RUnlock()
+	for _, symbol := range symbols {
+		e.subscribe(symbol)
+	}
+
+	ticker := time.NewTicker(e.cfg.PublishInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			e.publishDirty()
+		}
+	}
+}
+
+func (e *Engine) markDirty(symbol string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if slices.Contains(e.symbols, symbol) {
+		e.dirty[symbol] = true
+	}
+}
+
+// Recompute refreshes a symbol's statistics from its current book and reports
+// whether it stored them. Calls can race, so a result older than the stored
+// one is dropped.
+func (e *Engine) Recompute(symbol string) bool {
+	if !e.source.IsSynchronized(symbol) {
+		return false
+	}
+
+	book := e.source.GetOrderBook(symbol)
+	if book == nil {
+		return false
+	}
+
+	stats := Compute(symbol, book, e.cfg.TopN, e.cfg.DepthBandsBps)
+	stats.Timestamp = time.Now().UnixMilli()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if !slices.Contains(e.symbols, symbol) {
+		return false
+	}
+	if prev, ok := e.stats[symbol]; ok && prev.LastUpdateID > stats.LastUpdateID {
+		return false
+	}
+	e.stats[symbol] = stats
+	return true
+}
+
+func (e *Engine) publishDirty() {
+	e.mu.Lock()
+	symbols := slices.Collect(maps.Keys(e.dirty))
+	e.dirty = make(map[string]bool)
+	e.mu.Unlock()
+
+	for _, symbol := range symbols {
+		if !e.Recompute(symbol) {
+			continue
+		}
+		if stats, ok := e.Get(symbol); ok {
+			e.bus.Publish(StatsUpdate, Topic(symbol), stats)
+		}
+	}
+}
+
+// Get returns the current statistics for a symbol, recomputing them first if
+// its book changed since they were.
+func (e *Engine) Get(symbol string) (Stats, bool) {
+	symbol = strings.ToUpper(symbol)
+
+	e.mu.RLock()
+	dirty := e.dirty[symbol]
+	e.mu.RUnlock()
+	if dirty {
+		e.Recompute(symbol)
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	stats, ok := e.stats[symbol]
+	return stats, ok
+}
+
+// Snapshot lets the WebSocket handler answer a subscribe with the current statistics.
+func (e *Engine) Snapshot(symbol string) (any, bool) {
+	return e.Get(symbol)
+}
+
+func normalize(symbols []string) []string {
+	upper := make([]string, len(symbols))
+	for i, symbol := range symbols {
+		upper[i] = strings.ToUpper(symbol)
+	}
+	return upper
+}
@@ -0,0 +1,116 @@
+package analytics
+
+import (
+	"github.com/ChethiyaNishanath/market-data-hub/internal/domain/orderbook"
+)
+
+// DepthBand is the cumulative resting quantity within Bps of the mid on each side.
+type DepthBand struct {
+	Bps         float64 `json:"bps"`
+	BidQuantity float64 `json:"bidQuantity"`
+	AskQuantity float64 `json:"askQuantity"`
+	BidNotional float64 `json:"bidNotional"`
+	AskNotional float64 `json:"askNotional"`
+}
+
+type Stats struct {
+	Symbol          string      `json:"symbol"`
+	LastUpdateID    int         `json:"lastUpdateId"`
+	BestBid         float64     `json:"bestBid"`
+	BestBidQuantity float64     `json:"bestBidQuantity"`
+	BestAsk         float64     `json:"bestAsk"`
+	BestAskQuantity float64     `json:"bestAskQuantity"`
+	Mid             float64     `json:"mid"`
+	Microprice      float64     `json:"microprice"`
+	SpreadBps       float64     `json:"spreadBps"`
+	Imbalance       float64     `json:"imbalance"`
+	ImbalanceLevels int         `json:"imbalanceLevels"`
+	DepthBands      []DepthBand `json:"depthBands"`
+	Timestamp       int64       `json:"timestamp"`
+}
+
+// Compute derives the book statistics. Price-based fields stay zero while either
+// side of the book is empty.
+func Compute(symbol string, ob *orderbook.OrderBook, topN int, bandsBps []float64) Stats {
+	stats := Stats{
+		Symbol:          symbol,
+		LastUpdateID:    ob.LastUpdateID,
+		ImbalanceLevels: topN,
+		DepthBands:      make([]DepthBand, 0, len(bandsBps)),
+	}
+
+	bids := ob.SortedBids()
+	asks := ob.SortedAsks()
+
+	stats.Imbalance = imbalance(bids, asks, topN)
+
+	if len(bids) == 0 || len(asks) == 0 {
+		return stats
+	}
+
+	bestBid, bestAsk := bids[0], asks[0]
+	stats.BestBid = bestBid.Price
+	stats.BestBidQuantity = bestBid.Quantity
+	stats.BestAsk = bestAsk.Price
+	stats.BestAskQuantity = bestAsk.Quantity
+	stats.Mid = (bestBid.Price + bestAsk.Price) / 2
+
+	if stats.Mid > 0 {
+		stats.SpreadBps = (bestAsk.Price - bestBid.Price) / stats.Mid * 10_000
+	}
+
+	// The microprice leans towards the side with less resting size, since that is
+	// the side more likely to be taken out next.
+	if top := bestBid.Quantity + bestAsk.Quantity; top > 0 {
+		stats.Microprice = (bestBid.Price*bestAsk.Quantity + bestAsk.Price*bestBid.Quantity) / top
+	}
+
+	for _, bps := range bandsBps {
+		stats.DepthBands = append(stats.DepthBands, depthBand(bids, asks, stats.Mid, bps))
+	}
+
+	return stats
+}
+
+// imbalance is (bid - ask) / (bid + ask) over the top N levels, in [-1, 1].
+func imbalance(bids, asks []orderbook.Level, topN int) float64 {
+	bidQty := sumQuantity(bids, topN)
+	askQty := sumQuantity(asks, topN)
+	if bidQty+askQty == 0 {
+		return 0
+	}
+	return (bidQty - askQty) / (bidQty + askQty)
+}
+
+func sumQuantity(levels []orderbook.Level, topN int) float64 {
+	total := 0.0
+	for i, lvl := range levels {
+		if topN > 0 && i >= topN {
+			break
+		}
+		total += lvl.Quantity
+	}
+	return total
+}
+
+func depthBand(bids, asks []orderbook.Level, mid, bps float64) DepthBand {
+	band := DepthBand{Bps: bps}
+	lower := mid * (1 - bps/10_000)
+	upper := mid * (1 + bps/10_000)
+
+	for _, lvl := range bids {
+		if lvl.Price < lower {
+			break
+		}
+		band.BidQuantity += lvl.Quantity
+		band.BidNotional += lvl.Price * lvl.Quantity
+	}
+	for _, lvl := range asks {
+		if lvl.Price > upper {
+			break
+		}
+		band.AskQuantity += lvl.Quantity
+		band.AskNotional += lvl.Price * lvl.Quantity
+	}
+	return band
+}
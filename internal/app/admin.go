@@ -0,0 +1,45 @@
+package app
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+	"sync/atomic"
+)
+
+// adminToken guards the admin routes with server.admin.token, which can be
+// replaced while serving. While it is empty the routes are off.
+type adminToken struct {
+	current atomic.Pointer[string]
+}
+
+func newAdminToken(token string) *adminToken {
+	t := &adminToken{}
+	t.set(token)
+	return t
+}
+
+func (t *adminToken) set(token string) {
+	t.current.Store(&token)
+}
+
+// RequireAdmin is the middleware of the admin routes. It lets a request through
+// only with an "Authorization: Bearer" header carrying server.admin.token, which
+// follows config reloads. Without a token the routes answer 404, as if they did
+// not exist.
+func (a *App) RequireAdmin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := *a.admin.current.Load()
+		if token == "" {
+			http.NotFound(w, r)
+			return
+		}
+		given, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="admin"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -2,18 +2,70 @@ package app
 
 import (
 	"context"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
 
+	"github.com/ChethiyaNishanath/market-data-hub/internal/alerting"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/analytics"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/arbitrage"
 	"github.com/ChethiyaNishanath/market-data-hub/internal/bus"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/candles"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/checkpoint"
 	"github.com/ChethiyaNishanath/market-data-hub/internal/config"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/consolidated"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/domain/candle"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/domain/subscription"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/domain/trade"
 	"github.com/ChethiyaNishanath/market-data-hub/internal/exchange/binance"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/export"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/health"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/history"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/impact"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/interfaces/servers/grpc"
 	"github.com/ChethiyaNishanath/market-data-hub/internal/store/memory"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/store/sqlite"
 	"github.com/ChethiyaNishanath/market-data-hub/internal/subcription"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/synthetic"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/verify"
 	"github.com/go-chi/chi/v5"
 )
 
+const (
+	defaultStaleThreshold = 30 * time.Second
+
+	StorageMemory = "memory"
+	StorageSQLite = "sqlite"
+)
+
 type App struct {
 	cfg              *config.Config
+	reloadMu         sync.Mutex
+	binance          *binance.Service
+	limiter          *rateLimiter
+	admin            *adminToken
 	WebSocketHandler *subcription.Handler
+	HealthHandler    *health.Handler
+	Consolidated     *consolidated.Engine
+	Synthetic        *synthetic.Engine
+	Analytics        *analytics.Engine
+	Impact           *impact.Calculator
+	Alerts           *alerting.Engine
+	History          *history.Reader
+	Candles          *candles.Engine
+	Verifier         *verify.Verifier
+
+	dispatcher       *alerting.Dispatcher
+	arbitrage        *arbitrage.Detector
+	forwarder        *topicForwarder
+	storage          *sqlite.Store
+	outbox           *alerting.Outbox
+	checkpoints      *checkpoint.Store
+	checkpointWriter *checkpoint.Writer
+	history          *history.Store
+	historyRecorder  *history.Recorder
+	exporter         *export.Exporter
 }
 
 func NewApp(ctx *context.Context, cfg *config.Config) *App {
@@ -22,16 +74,299 @@ func NewApp(ctx *context.Context, cfg *config.Config) *App {
 	connMgr := subcription.NewConnectionManager()
 
 	subscriptionService := subcription.NewService(connMgr)
+	forwarder := newTopicForwarder(eventBus, connMgr)
 	binanceService := binance.NewService(*ctx, eventBus, connMgr, cfg.Integrations.Binance)
+	limiter := newRateLimiter(cfg.Server.RateLimit)
+	subscriptionService.Handler.RegisterExchange(binanceService)
+	subscriptionService.Handler.UseRateLimit(limiter.config)
+	subscriptionService.Handler.UseOutbound(cfg.Server.WebSocket)
+
+	var (
+		checkpoints      *checkpoint.Store
+		checkpointWriter *checkpoint.Writer
+	)
+	if cfg.Checkpoints.Enabled {
+		var err error
+		if checkpoints, err = checkpoint.Open(cfg.Checkpoints.Path); err != nil {
+			slog.Error("Book checkpoints disabled", "error", err)
+		} else {
+			binanceService.UseCheckpoints(checkpoints, cfg.Checkpoints.MaxAge)
+			checkpointWriter = checkpoint.NewWriter(checkpoints, cfg.Checkpoints.Interval, binanceService)
+			go checkpointWriter.Run(*ctx)
+		}
+	}
+
+	var (
+		historyStore    *history.Store
+		historyRecorder *history.Recorder
+		historyReader   *history.Reader
+	)
+	if cfg.History.Enabled {
+		var err error
+		if historyStore, err = history.Open(cfg.History.Path); err != nil {
+			slog.Error("Order book history disabled", "error", err)
+		} else {
+			historyRecorder = history.NewRecorder(cfg.History, historyStore, eventBus, binanceService)
+			historyReader = history.NewReader(historyStore, binanceService.Name())
+			go historyRecorder.Run(*ctx)
+		}
+	}
+
+	var exporter *export.Exporter
+	if cfg.Export.Enabled {
+		var err error
+		if exporter, err = export.NewExporter(cfg.Export, eventBus, binanceService); err != nil {
+			slog.Error("Data export disabled", "error", err)
+		} else {
+			if !cfg.Integrations.Binance.Trades && slices.Contains(cfg.Export.Datasets, export.Trades.Name) {
+				slog.Warn("Trades export has no data: integrations.binance.trades is off")
+			}
+			go exporter.Run(*ctx)
+		}
+	}
+
+	var storage *sqlite.Store
+	switch cfg.Storage.Backend {
+	case "", StorageMemory:
+	case StorageSQLite:
+		var err error
+		if storage, err = sqlite.Open(cfg.Storage.Path); err != nil {
+			slog.Error("SQLite storage unavailable - keeping state in memory", "error", err)
+		}
+	default:
+		slog.Error("Unknown storage backend - keeping state in memory", "backend", cfg.Storage.Backend)
+	}
+
+	var candleEngine *candles.Engine
+	if cfg.Candles.Enabled {
+		var (
+			tradeRepo  trade.Repository  = memory.NewTradeStore(cfg.Candles.MaxTradesPerSymbol)
+			candleRepo candle.Repository = memory.NewCandleStore()
+			err        error
+		)
+		if storage != nil {
+			tradeRepo, candleRepo = storage.Trades(), storage.Candles()
+		}
+		if candleEngine, err = candles.NewEngine(cfg.Candles, eventBus, binanceService, tradeRepo, candleRepo); err != nil {
+			slog.Error("Candles disabled", "error", err)
+		} else {
+			if !cfg.Integrations.Binance.Trades {
+				slog.Warn("Candles have no data: integrations.binance.trades is off")
+			}
+			go candleEngine.Run(*ctx)
+		}
+	}
 
 	go binanceService.Start(*ctx)
 
+	var consolidatedEngine *consolidated.Engine
+	if cfg.Consolidated.Enabled {
+		consolidatedEngine = consolidated.NewEngine(cfg.Consolidated, eventBus, binanceService)
+		subscriptionService.Handler.RegisterTopic("cbbo", consolidatedEngine)
+		forwarder.forward(consolidatedEngine.Topics())
+		go consolidatedEngine.Start(*ctx)
+	}
+
+	var syntheticEngine *synthetic.Engine
+	if cfg.Synthetic.Enabled {
+		if err := synthetic.Validate(cfg.Synthetic); err != nil {
+			slog.Error("Synthetic instruments disabled", "error", err)
+		} else {
+			syntheticEngine = synthetic.NewEngine(cfg.Synthetic, eventBus, binanceService)
+			subscriptionService.Handler.RegisterTopic("synthetic", syntheticEngine)
+			forwarder.forward(syntheticEngine.Topics())
+			go syntheticEngine.Start(*ctx)
+		}
+	}
+
+	var analyticsEngine *analytics.Engine
+	if cfg.Analytics.Enabled {
+		analyticsEngine = analytics.NewEngine(cfg.Analytics, eventBus, binanceService, binanceService.SubscribedSymbols())
+		subscriptionService.Handler.RegisterTopic("stats", analyticsEngine)
+		forwarder.forward(analyticsEngine.Topics())
+		go analyticsEngine.Start(*ctx)
+	}
+
+	var detector *arbitrage.Detector
+	if cfg.Arbitrage.Enabled {
+		detector = arbitrage.NewDetector(cfg.Arbitrage, eventBus, binanceService)
+		subscriptionService.Handler.RegisterTopic("arb", detector)
+		forwarder.forward(detector.Topics())
+		go detector.Start(*ctx)
+	}
+
+	var verifier *verify.Verifier
+	if cfg.Verify.Enabled {
+		verifier = verify.NewVerifier(cfg.Verify, eventBus, binanceService)
+		subscriptionService.Handler.RegisterTopic("verify", verifier)
+		forwarder.forward(verifier.Topics())
+		go verifier.Start(*ctx)
+	}
+
+	var (
+		alertEngine *alerting.Engine
+		dispatcher  *alerting.Dispatcher
+		outbox      *alerting.Outbox
+	)
+	if cfg.Alerts.Enabled {
+		if cfg.Alerts.Webhook.Enabled {
+			if storage != nil {
+				dispatcher = alerting.NewDispatcher(storage.Outbox(), cfg.Alerts.Webhook)
+				go dispatcher.Run(*ctx)
+			} else {
+				var err error
+				if outbox, err = alerting.OpenOutbox(cfg.Alerts.Webhook.OutboxPath); err != nil {
+					slog.Error("Alert webhooks disabled", "error", err)
+				} else {
+					dispatcher = alerting.NewDispatcher(outbox, cfg.Alerts.Webhook)
+					go dispatcher.Run(*ctx)
+				}
+			}
+		}
+
+		alertEngine = alerting.NewEngine(cfg.Alerts, binanceService, connMgr, dispatcher)
+		if storage != nil {
+			if err := alertEngine.UseRepository(storage.Alerts()); err != nil {
+				slog.Error("Failed to restore alerts", "error", err)
+			}
+		}
+		if err := alertEngine.SetRules(cfg.Alerts.Rules); err != nil {
+			slog.Error("Configured alerts not loaded", "error", err)
+		}
+		subscriptionService.Router.Handle(alerting.Method, alertEngine.HandleWebSocket)
+		go alertEngine.Start(*ctx)
+	}
+
+	staleThreshold := cfg.Health.StaleThreshold
+	if staleThreshold <= 0 {
+		staleThreshold = defaultStaleThreshold
+	}
+
 	return &App{
 		cfg:              cfg,
+		binance:          binanceService,
+		limiter:          limiter,
+		admin:            newAdminToken(cfg.Server.Admin.Token),
 		WebSocketHandler: subscriptionService.Handler,
+		HealthHandler:    health.NewHandler(staleThreshold, binanceService),
+		Consolidated:     consolidatedEngine,
+		Synthetic:        syntheticEngine,
+		Analytics:        analyticsEngine,
+		Impact:           impact.NewCalculator(binanceService, consolidatedEngine),
+		Alerts:           alertEngine,
+		History:          historyReader,
+		Candles:          candleEngine,
+		Verifier:         verifier,
+		dispatcher:       dispatcher,
+		arbitrage:        detector,
+		forwarder:        forwarder,
+		storage:          storage,
+		outbox:           outbox,
+		checkpoints:      checkpoints,
+		checkpointWriter: checkpointWriter,
+		history:          historyStore,
+		historyRecorder:  historyRecorder,
+		exporter:         exporter,
+	}
+}
+
+// topicForwarder forwards bus events on engine topics to subscribed WebSocket
+// clients. The bus cannot unsubscribe, so each topic is forwarded once however
+// often its symbol is added.
+type topicForwarder struct {
+	bus     bus.IBus
+	connMgr subscription.ClientConnectionManager
+
+	mu     sync.Mutex
+	topics map[string]bool
+}
+
+func newTopicForwarder(eventBus bus.IBus, connMgr subscription.ClientConnectionManager) *topicForwarder {
+	return &topicForwarder{bus: eventBus, connMgr: connMgr, topics: make(map[string]bool)}
+}
+
+func (f *topicForwarder) forward(topics []string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, topic := range topics {
+		if f.topics[topic] {
+			continue
+		}
+		f.topics[topic] = true
+		f.bus.Subscribe(topic, func(e bus.Event) {
+			f.connMgr.BroadcastContext(e.Context, e.Topic, binance.WSMessage{
+				Topic: e.Topic,
+				Data:  e.Data,
+			})
+		})
+	}
+}
+
+func (a *App) GrpcDependencies() grpc.Dependencies {
+	return grpc.Dependencies{
+		Consolidated: a.Consolidated,
+		Analytics:    a.Analytics,
+		Impact:       a.Impact,
+		History:      a.History,
+		Candles:      a.Candles,
+		Token:        a.cfg.Server.Grpc.Token,
 	}
 }
 
 func (a *App) RegisterRoutes(r chi.Router) {
 	r.Get("/ws", a.WebSocketHandler.HandleWebSocket)
+	r.Get("/healthz", a.HealthHandler.HandleLiveness)
+	r.Get("/readyz", a.HealthHandler.HandleReadiness)
+	r.Get("/impact", a.Impact.HandleEstimate)
+	// Alerts hold every client's webhook URLs, so only admins manage them over
+	// REST; WebSocket clients manage their own.
+	r.Group(func(r chi.Router) {
+		r.Use(a.RequireAdmin)
+		r.Get("/admin/clients", a.WebSocketHandler.HandleClients)
+		if a.Alerts != nil {
+			a.Alerts.RegisterRoutes(r)
+		}
+	})
+	if a.Candles != nil {
+		a.Candles.RegisterRoutes(r)
+	}
+	if a.Verifier != nil {
+		a.Verifier.RegisterRoutes(r)
+	}
+}
+
+// Close releases resources held by the app once the servers have stopped.
+func (a *App) Close() {
+	if a.checkpointWriter != nil {
+		<-a.checkpointWriter.Done()
+	}
+	if a.checkpoints != nil {
+		if err := a.checkpoints.Close(); err != nil {
+			slog.Error("Failed to close book checkpoints", "error", err)
+		}
+	}
+	if a.historyRecorder != nil {
+		<-a.historyRecorder.Done()
+	}
+	if a.history != nil {
+		if err := a.history.Close(); err != nil {
+			slog.Error("Failed to close order book history", "error", err)
+		}
+	}
+	if a.exporter != nil {
+		<-a.exporter.Done()
+	}
+	if a.outbox != nil {
+		if err := a.outbox.Close(); err != nil {
+			slog.Error("Failed to close alert outbox", "error", err)
+		}
+	}
+	if a.Candles != nil {
+		<-a.Candles.Done()
+	}
+	if a.storage != nil {
+		if err := a.storage.Close(); err != nil {
+			slog.Error("Failed to close storage", "error", err)
+		}
+	}
 }
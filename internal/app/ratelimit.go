@@ -0,0 +1,52 @@
+package app
+
+import (
+	"net/http"
+	"sync/atomic"
+
+	"github.com/ChethiyaNishanath/market-data-hub/internal/config"
+	"github.com/go-chi/httprate"
+)
+
+// rateLimiter limits the HTTP requests of each client IP with settings that
+// can be replaced while serving. Clients start a fresh window when they are.
+type rateLimiter struct {
+	current atomic.Pointer[limit]
+}
+
+type limit struct {
+	cfg     config.RateLimitConfig
+	limiter *httprate.RateLimiter // nil when off
+}
+
+func newRateLimiter(cfg config.RateLimitConfig) *rateLimiter {
+	l := &rateLimiter{}
+	l.set(cfg)
+	return l
+}
+
+func (l *rateLimiter) set(cfg config.RateLimitConfig) {
+	next := &limit{cfg: cfg}
+	if cfg.Requests > 0 {
+		next.limiter = httprate.NewRateLimiter(cfg.Requests, cfg.Window, httprate.WithKeyByIP())
+	}
+	l.current.Store(next)
+}
+
+// config is the limit in force.
+func (l *rateLimiter) config() config.RateLimitConfig {
+	return l.current.Load().cfg
+}
+
+// RateLimit is the middleware applying server.rateLimit, which follows config
+// reloads.
+func (a *App) RateLimit(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := a.limiter.current.Load()
+		if current.limiter == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		current.limiter.Handler(next).ServeHTTP(w, r)
+	})
+}
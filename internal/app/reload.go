@@ -0,0 +1,163 @@
+package app
+
+import (
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/ChethiyaNishanath/market-data-hub/internal/analytics"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/arbitrage"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/config"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/verify"
+)
+
+// liveKeys are the config keys Reload can apply while running, with every key
+// below them. A change to any other key needs a restart.
+var liveKeys = []string{
+	"logging.level",
+	"server.rateLimit",
+	"server.admin.token",
+	subscriptionsKey,
+	"alerts.evaluationInterval",
+	"alerts.webhook.secret",
+	"alerts.rules",
+}
+
+const subscriptionsKey = "integrations.binance.subscriptions"
+
+// Reload applies the settings of a new, valid config that can change while
+// running: the log level, the HTTP rate limit, the admin token, the Binance
+// subscriptions, the alert evaluation interval, the webhook signing secret and
+// the configured alert rules. Engines that follow each symbol follow added symbols too. Other changed keys
+// are logged as needing a restart and keep their running values. It returns the
+// live keys it applied and the other keys that changed.
+func (a *App) Reload(cfg *config.Config) (applied, rejected []string) {
+	a.reloadMu.Lock()
+	defer a.reloadMu.Unlock()
+
+	for _, key := range config.Changes(a.cfg, cfg) {
+		i := slices.IndexFunc(liveKeys, func(live string) bool { return underKey(key, live) })
+		switch {
+		case i < 0:
+			rejected = append(rejected, key)
+		case !slices.Contains(applied, liveKeys[i]):
+			applied = append(applied, liveKeys[i])
+		}
+	}
+	for _, key := range rejected {
+		slog.Warn("Config change not applied: restart required", "key", key)
+	}
+	if len(applied) == 0 {
+		slog.Info("Config reloaded: nothing to apply")
+		return applied, rejected
+	}
+
+	next := *a.cfg
+	changed := func(live string) bool { return slices.Contains(applied, live) }
+
+	if changed("logging.level") {
+		next.Logging = cfg.Logging
+		slog.SetLogLoggerLevel(next.Logging.SlogLevel())
+	}
+	if changed("server.rateLimit") {
+		next.Server.RateLimit = cfg.Server.RateLimit
+		a.limiter.set(next.Server.RateLimit)
+	}
+	if changed("server.admin.token") {
+		next.Server.Admin = cfg.Server.Admin
+		a.admin.set(next.Server.Admin.Token)
+	}
+	if changed(subscriptionsKey) {
+		// The engines subscribe before the symbols stream, so they miss no update.
+		a.addSymbols(addedSymbols(a.cfg, cfg))
+		next.Integrations.Binance.Subscriptions = cfg.Integrations.Binance.Subscriptions
+		added, removed := a.binance.Reconfigure(next.Integrations.Binance.Subscriptions)
+		a.removeSymbols(removed)
+		slog.Info("Subscriptions reloaded", "added", added, "removed", removed)
+	}
+	if changed("alerts.evaluationInterval") {
+		next.Alerts.EvaluationInterval = cfg.Alerts.EvaluationInterval
+		if a.Alerts != nil {
+			a.Alerts.SetEvaluationInterval(next.Alerts.EvaluationInterval)
+		}
+	}
+	if changed("alerts.webhook.secret") {
+		next.Alerts.Webhook.Secret = cfg.Alerts.Webhook.Secret
+		if a.dispatcher != nil {
+			a.dispatcher.SetSecret(next.Alerts.Webhook.Secret)
+		}
+	}
+	if changed("alerts.rules") {
+		if a.Alerts == nil {
+			next.Alerts.Rules = cfg.Alerts.Rules
+		} else if err := a.Alerts.SetRules(cfg.Alerts.Rules); err != nil {
+			slog.Error("Config change not applied", "key", "alerts.rules", "error", err)
+			applied = slices.DeleteFunc(applied, func(key string) bool { return key == "alerts.rules" })
+			rejected = append(rejected, "alerts.rules")
+		} else {
+			next.Alerts.Rules = cfg.Alerts.Rules
+		}
+	}
+
+	a.cfg = &next
+	slog.Info("Config reloaded", "applied", applied)
+	return applied, rejected
+}
+
+// addedSymbols lists the symbols next subscribes to and cur does not.
+func addedSymbols(cur, next *config.Config) []string {
+	before := cur.Integrations.Binance.Symbols()
+	var added []string
+	for _, symbol := range next.Integrations.Binance.Symbols() {
+		if !slices.Contains(before, symbol) {
+			added = append(added, symbol)
+		}
+	}
+	return added
+}
+
+// addSymbols has the engines that follow each symbol follow symbols added while
+// running, and forwards their topics to WebSocket clients.
+func (a *App) addSymbols(symbols []string) {
+	for _, symbol := range symbols {
+		var topics []string
+		if a.Analytics != nil {
+			a.Analytics.AddSymbol(symbol)
+			topics = append(topics, analytics.Topic(symbol))
+		}
+		if a.arbitrage != nil && a.cfg.Arbitrage.DetectCrossed {
+			topics = append(topics, arbitrage.Topic(symbol))
+		}
+		if a.Candles != nil {
+			a.Candles.AddSymbol(symbol)
+		}
+		if a.exporter != nil {
+			a.exporter.AddSymbol(symbol)
+		}
+		if a.historyRecorder != nil {
+			a.historyRecorder.AddSymbol(symbol)
+		}
+		if a.Verifier != nil {
+			a.Verifier.AddSymbol(symbol)
+			topics = append(topics, verify.Topic(symbol))
+		}
+		a.forwarder.forward(topics)
+	}
+}
+
+// removeSymbols drops the statistics of removed symbols. The other engines go
+// by the subscribed symbols, and nothing more is published for them.
+func (a *App) removeSymbols(symbols []string) {
+	if a.Analytics == nil {
+		return
+	}
+	for _, symbol := range symbols {
+		a.Analytics.RemoveSymbol(symbol)
+	}
+}
+
+// underKey reports whether key is live or a key below it.
+func underKey(key, live string) bool {
+	rest, ok := strings.CutPrefix(key, live)
+	return ok && (rest == "" || rest[0] == '.' || rest[0] == '[')
+}
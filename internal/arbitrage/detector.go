@@ -0,0 +1,385 @@
+package arbitrage
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ChethiyaNishanath/market-data-hub/internal/bus"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/config"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/domain/exchange"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/domain/orderbook"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/synthetic"
+)
+
+const (
+	ArbEvent    = "arb"
+	TopicSuffix = "@arb"
+
+	TypeCrossVenue  = "cross_venue"
+	TypeTriangle    = "triangle"
+	TypeCrossedBook = "crossed_book"
+
+	defaultInterval = 500 * time.Millisecond
+	defaultVenue    = "binance"
+)
+
+// Opportunity is published when a profitable cross opens (Active) and again when
+// it closes. Prices are net of fees.
+type Opportunity struct {
+	Type       string  `json:"type"`
+	Instrument string  `json:"instrument"`
+	Active     bool    `json:"active"`
+	BuyVenue   string  `json:"buyVenue"`
+	BuySymbol  string  `json:"buySymbol"`
+	BuyPrice   float64 `json:"buyPrice"`
+	SellVenue  string  `json:"sellVenue"`
+	SellSymbol string  `json:"sellSymbol"`
+	SellPrice  float64 `json:"sellPrice"`
+	ProfitBps  float64 `json:"profitBps"`
+	Quantity   float64 `json:"quantity"`
+	Timestamp  int64   `json:"timestamp"`
+}
+
+// CrossedBook flags a venue's own book showing a bid at or above its ask, which
+// can only mean the local copy is corrupt.
+type CrossedBook struct {
+	Type            string  `json:"type"`
+	Venue           string  `json:"venue"`
+	Symbol          string  `json:"symbol"`
+	BestBid         float64 `json:"bestBid"`
+	BestAsk         float64 `json:"bestAsk"`
+	ResyncRequested bool    `json:"resyncRequested"`
+	Timestamp       int64   `json:"timestamp"`
+}
+
+type quote struct {
+	bid, bidQty float64
+	ask, askQty float64
+}
+
+// Detector periodically compares top of book across venues and triangles and
+// checks every venue book for crossed prices.
+type Detector struct {
+	cfg     config.ArbitrageConfig
+	bus     bus.IBus
+	sources map[string]exchange.BookSource
+
+	mu      sync.RWMutex
+	open    map[string]Opportunity
+	crossed map[string]bool
+}
+
+func NewDetector(cfg config.ArbitrageConfig, eventBus bus.IBus, sources ...exchange.BookSource) *Detector {
+	if cfg.Interval <= 0 {
+		cfg.Interval = defaultInterval
+	}
+
+	bySource := make(map[string]exchange.BookSource, len(sources))
+	for _, src := range sources {
+		bySource[strings.ToLower(src.Name())] = src
+	}
+
+	return &Detector{
+		cfg:     cfg,
+		bus:     eventBus,
+		sources: bySource,
+		open:    make(map[string]Opportunity),
+		crossed: make(map[string]bool),
+	}
+}
+
+// Topic is the bus and WebSocket topic arb events for an instrument, triangle or
+// venue symbol are published on.
+func Topic(name string) string {
+	return strings.ToLower(name) + TopicSuffix
+}
+
+func (d *Detector) Topics() []string {
+	topics := make([]string, 0)
+	for _, inst := range d.cfg.Instruments {
+		topics = append(topics, Topic(inst.Name))
+	}
+	for _, tri := range d.cfg.Triangles {
+		topics = append(topics, Topic(tri.Name))
+	}
+	if d.cfg.DetectCrossed {
+		for _, src := range d.sources {
+			if lister, ok := src.(exchange.SymbolLister); ok {
+				for _, symbol := range lister.SubscribedSymbols() {
+					topics = append(topics, Topic(symbol))
+				}
+			}
+		}
+	}
+	return topics
+}
+
+func (d *Detector) Start(ctx context.Context) {
+	ticker := time.NewTicker(d.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			d.Check()
+		}
+	}
+}
+
+// Check runs every configured comparison once.
+func (d *Detector) Check() {
+	now := time.Now().UnixMilli()
+
+	if d.cfg.DetectCrossed {
+		d.checkCrossed(now)
+	}
+	for _, inst := range d.cfg.Instruments {
+		d.checkCrossVenue(inst, now)
+	}
+	for _, tri := range d.cfg.Triangles {
+		d.checkTriangle(tri, now)
+	}
+}
+
+func (d *Detector) checkCrossed(now int64) {
+	for venue, src := range d.sources {
+		lister, ok := src.(exchange.SymbolLister)
+		if !ok {
+			continue
+		}
+
+		for _, symbol := range lister.SubscribedSymbols() {
+			key := venue + ":" + symbol
+			q, ok := d.quote(src, symbol)
+			isCrossed := ok && q.bid >= q.ask
+
+			d.mu.Lock()
+			wasCrossed := d.crossed[key]
+			d.crossed[key] = isCrossed
+			d.mu.Unlock()
+
+			if !isCrossed || wasCrossed {
+				continue
+			}
+
+			event := CrossedBook{
+				Type:      TypeCrossedBook,
+				Venue:     venue,
+				Symbol:    symbol,
+				BestBid:   q.bid,
+				BestAsk:   q.ask,
+				Timestamp: now,
+			}
+
+			reason := fmt.Sprintf("crossed book: bid %v >= ask %v", q.bid, q.ask)
+			if resyncer, ok := src.(exchange.Resyncer); ok {
+				event.ResyncRequested = resyncer.RequestResync(symbol, reason)
+			}
+
+			slog.Warn("Crossed local order book", "venue", venue, "symbol", symbol, "bid", q.bid, "ask", q.ask,
+				"resyncRequested", event.ResyncRequested)
+			d.bus.Publish(ArbEvent, Topic(symbol), event)
+		}
+	}
+}
+
+func (d *Detector) checkCrossVenue(inst config.ConsolidatedInstrument, now int64) {
+	type venueQuote struct {
+		src config.VenueSource
+		q   quote
+	}
+
+	quotes := make([]venueQuote, 0, len(inst.Sources))
+	for _, src := range inst.Sources {
+		source, ok := d.sources[strings.ToLower(src.Venue)]
+		if !ok {
+			continue
+		}
+		symbol := strings.ToUpper(src.Symbol)
+		if q, ok := d.quote(source, symbol); ok {
+			quotes = append(quotes, venueQuote{src: src, q: q})
+		}
+	}
+
+	for _, buy := range quotes {
+		for _, sell := range quotes {
+			if buy.src.Venue == sell.src.Venue && buy.src.Symbol == sell.src.Symbol {
+				continue
+			}
+
+			buyPrice := buy.q.ask * (1 + buy.src.FeeBps/10_000)
+			sellPrice := sell.q.bid * (1 - sell.src.FeeBps/10_000)
+
+			d.report(fmt.Sprintf("%s:%s>%s", inst.Name, buy.src.Venue, sell.src.Venue), Opportunity{
+				Type:       TypeCrossVenue,
+				Instrument: strings.ToUpper(inst.Name),
+				BuyVenue:   buy.src.Venue,
+				BuySymbol:  strings.ToUpper(buy.src.Symbol),
+				BuyPrice:   buyPrice,
+				SellVenue:  sell.src.Venue,
+				SellSymbol: strings.ToUpper(sell.src.Symbol),
+				SellPrice:  sellPrice,
+				Quantity:   math.Min(buy.q.askQty, sell.q.bidQty),
+				Timestamp:  now,
+			})
+		}
+	}
+}
+
+// checkTriangle compares the direct book with the top of the book composed from
+// the legs, in both directions.
+func (d *Detector) checkTriangle(tri config.ArbitrageTriangle, now int64) {
+	directVenue := venueOrDefault(tri.Direct.Venue)
+	directSource, ok := d.sources[strings.ToLower(directVenue)]
+	if !ok {
+		return
+	}
+	directSymbol := strings.ToUpper(tri.Direct.Symbol)
+	direct, ok := d.quote(directSource, directSymbol)
+	if !ok {
+		return
+	}
+
+	ladders := make([]synthetic.Ladder, 0, len(tri.Legs))
+	ops := make([]string, 0, len(tri.Legs))
+	names := make([]string, 0, len(tri.Legs))
+	for i, leg := range tri.Legs {
+		source, ok := d.sources[strings.ToLower(venueOrDefault(leg.Venue))]
+		if !ok {
+			return
+		}
+		symbol := strings.ToUpper(leg.Symbol)
+		if !source.IsSynchronized(symbol) {
+			return
+		}
+		book := source.GetOrderBook(symbol)
+		if book == nil {
+			return
+		}
+		ladders = append(ladders, synthetic.LadderFromBook(book))
+		if i > 0 {
+			ops = append(ops, strings.ToLower(leg.Operation))
+		}
+		names = append(names, symbol)
+	}
+
+	composed := synthetic.Compose(ladders, ops, 1)
+	if len(composed.Bids) == 0 || len(composed.Asks) == 0 {
+		return
+	}
+
+	fee := tri.FeeBps / 10_000
+	legFees := math.Pow(1-fee, float64(len(tri.Legs)))
+	legsName := strings.Join(names, "/")
+
+	d.report(tri.Name+":direct>legs", Opportunity{
+		Type:       TypeTriangle,
+		Instrument: strings.ToUpper(tri.Name),
+		BuyVenue:   directVenue,
+		BuySymbol:  directSymbol,
+		BuyPrice:   direct.ask * (1 + fee),
+		SellVenue:  directVenue,
+		SellSymbol: legsName,
+		SellPrice:  composed.Bids[0].Price * legFees,
+		Quantity:   math.Min(direct.askQty, composed.Bids[0].Quantity),
+		Timestamp:  now,
+	})
+
+	d.report(tri.Name+":legs>direct", Opportunity{
+		Type:       TypeTriangle,
+		Instrument: strings.ToUpper(tri.Name),
+		BuyVenue:   directVenue,
+		BuySymbol:  legsName,
+		BuyPrice:   composed.Asks[0].Price / legFees,
+		SellVenue:  directVenue,
+		SellSymbol: directSymbol,
+		SellPrice:  direct.bid * (1 - fee),
+		Quantity:   math.Min(direct.bidQty, composed.Asks[0].Quantity),
+		Timestamp:  now,
+	})
+}
+
+// report publishes an opportunity when it opens and when it closes.
+func (d *Detector) report(key string, opp Opportunity) {
+	if opp.BuyPrice > 0 {
+		opp.ProfitBps = (opp.SellPrice - opp.BuyPrice) / opp.BuyPrice * 10_000
+	}
+	opp.Active = opp.ProfitBps > 0 && opp.ProfitBps >= d.cfg.MinProfitBps
+
+	d.mu.Lock()
+	prev, wasOpen := d.open[key]
+	if opp.Active {
+		d.open[key] = opp
+	} else {
+		delete(d.open, key)
+	}
+	d.mu.Unlock()
+
+	switch {
+	case opp.Active && !wasOpen:
+		slog.Info("Arbitrage opportunity opened", "instrument", opp.Instrument, "type", opp.Type,
+			"buy", opp.BuySymbol, "sell", opp.SellSymbol, "profitBps", opp.ProfitBps)
+		d.bus.Publish(ArbEvent, Topic(opp.Instrument), opp)
+	case !opp.Active && wasOpen:
+		prev.Active = false
+		prev.Timestamp = opp.Timestamp
+		d.bus.Publish(ArbEvent, Topic(opp.Instrument), prev)
+	}
+}
+
+// Open returns the currently open opportunities for an instrument or triangle.
+func (d *Detector) Open(name string) []Opportunity {
+	name = strings.ToUpper(name)
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	open := make([]Opportunity, 0)
+	for _, opp := range d.open {
+		if opp.Instrument == name {
+			open = append(open, opp)
+		}
+	}
+	return open
+}
+
+// Snapshot lets the WebSocket handler answer a subscribe with the open opportunities.
+func (d *Detector) Snapshot(name string) (any, bool) {
+	return d.Open(name), true
+}
+
+func (d *Detector) quote(source exchange.BookSource, symbol string) (quote, bool) {
+	if !source.IsSynchronized(symbol) {
+		return quote{}, false
+	}
+	book := source.GetOrderBook(symbol)
+	if book == nil {
+		return quote{}, false
+	}
+	return topOfBook(book)
+}
+
+func topOfBook(book *orderbook.OrderBook) (quote, bool) {
+	bids, asks := book.SortedBids(), book.SortedAsks()
+	if len(bids) == 0 || len(asks) == 0 {
+		return quote{}, false
+	}
+	return quote{
+		bid: bids[0].Price, bidQty: bids[0].Quantity,
+		ask: asks[0].Price, askQty: asks[0].Quantity,
+	}, true
+}
+
+func venueOrDefault(venue string) string {
+	if venue == "" {
+		return defaultVenue
+	}
+	return venue
+}
@@ -0,0 +1,134 @@
+package bench
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	pb "github.com/ChethiyaNishanath/market-data-hub/api/orderbook"
+)
+
+const defaultRequestTimeout = 5 * time.Second
+
+// GRPCConfig calls GetSnapshot for Symbols, round-robin, at Rate requests per
+// second spread over Concurrency workers. A Rate of 0 lets every worker call
+// as fast as the hub answers.
+type GRPCConfig struct {
+	Target         string
+	Symbols        []string
+	Depth          int32
+	Rate           float64
+	Concurrency    int
+	Duration       time.Duration
+	Warmup         time.Duration
+	RequestTimeout time.Duration
+}
+
+// RunGRPC measures GetSnapshot round trips against client. Requests the rate
+// asks for while every worker is still busy are counted as missed rather than
+// queued, so a saturated hub shows up as missed requests, not as latency that
+// grows with the run.
+func RunGRPC(ctx context.Context, client pb.OrderBookClient, cfg GRPCConfig) (Report, error) {
+	symbols := make([]string, 0, len(cfg.Symbols))
+	for _, s := range cfg.Symbols {
+		if s = strings.ToUpper(strings.TrimSpace(s)); s != "" {
+			symbols = append(symbols, s)
+		}
+	}
+	if len(symbols) == 0 {
+		return Report{}, errors.New("at least one symbol is required")
+	}
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = 1
+	}
+	if cfg.RequestTimeout <= 0 {
+		cfg.RequestTimeout = defaultRequestTimeout
+	}
+
+	report := Report{
+		Mode:        ModeGRPC,
+		Target:      cfg.Target,
+		Duration:    cfg.Duration,
+		Rate:        cfg.Rate,
+		Concurrency: cfg.Concurrency,
+	}
+
+	start := time.Now()
+	win := window{from: start.Add(cfg.Warmup), to: start.Add(cfg.Warmup + cfg.Duration)}
+	runCtx, stop := context.WithDeadline(ctx, win.to)
+	defer stop()
+
+	var (
+		mu        sync.Mutex
+		latencies []time.Duration
+		wg        sync.WaitGroup
+		jobs      = make(chan string)
+	)
+
+	call := func(symbol string) {
+		reqCtx, cancel := context.WithTimeout(runCtx, cfg.RequestTimeout)
+		defer cancel()
+
+		sent := time.Now()
+		_, err := client.GetSnapshot(reqCtx, &pb.OrderBookSnapshotRequest{Symbol: symbol, Depth: cfg.Depth})
+		done := time.Now()
+		if !win.contains(sent) || runCtx.Err() != nil {
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		report.Messages++
+		if err != nil {
+			report.Errors++
+			return
+		}
+		latencies = append(latencies, done.Sub(sent))
+	}
+
+	for w := range cfg.Concurrency {
+		wg.Go(func() {
+			if cfg.Rate > 0 {
+				for symbol := range jobs {
+					call(symbol)
+				}
+				return
+			}
+			for n := w; runCtx.Err() == nil; n += cfg.Concurrency {
+				call(symbols[n%len(symbols)])
+			}
+		})
+	}
+
+	if cfg.Rate > 0 {
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / cfg.Rate))
+		for n := 0; runCtx.Err() == nil; {
+			select {
+			case <-runCtx.Done():
+			case now := <-ticker.C:
+				select {
+				case jobs <- symbols[n%len(symbols)]:
+					n++
+				default:
+					if win.contains(now) {
+						mu.Lock()
+						report.Missed++
+						mu.Unlock()
+					}
+				}
+			}
+		}
+		ticker.Stop()
+		close(jobs)
+	}
+	wg.Wait()
+
+	if cfg.Duration > 0 {
+		report.Throughput = float64(report.Messages-report.Errors) / cfg.Duration.Seconds()
+	}
+	report.Latency = Summarize(latencies)
+
+	return report, ctx.Err()
+}
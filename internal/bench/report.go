@@ -0,0 +1,117 @@
+package bench
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"text/tabwriter"
+	"time"
+)
+
+// Latency summarises the measured latencies.
+type Latency struct {
+	Samples int           `json:"samples"`
+	Mean    time.Duration `json:"mean"`
+	P50     time.Duration `json:"p50"`
+	P90     time.Duration `json:"p90"`
+	P99     time.Duration `json:"p99"`
+	P999    time.Duration `json:"p999"`
+	Max     time.Duration `json:"max"`
+}
+
+// Summarize computes the percentiles of samples, which it sorts in place.
+func Summarize(samples []time.Duration) Latency {
+	if len(samples) == 0 {
+		return Latency{}
+	}
+	slices.Sort(samples)
+
+	var total time.Duration
+	for _, s := range samples {
+		total += s
+	}
+
+	return Latency{
+		Samples: len(samples),
+		Mean:    total / time.Duration(len(samples)),
+		P50:     percentile(samples, 0.50),
+		P90:     percentile(samples, 0.90),
+		P99:     percentile(samples, 0.99),
+		P999:    percentile(samples, 0.999),
+		Max:     samples[len(samples)-1],
+	}
+}
+
+// percentile uses the nearest-rank method on sorted samples.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(float64(len(sorted))*p+0.5) - 1
+	return sorted[max(0, min(rank, len(sorted)-1))]
+}
+
+// Report is the result of a benchmark run. Counts cover the measured window
+// only, after the warm-up.
+type Report struct {
+	Mode     string        `json:"mode"`
+	Target   string        `json:"target"`
+	Duration time.Duration `json:"duration"`
+
+	// WebSocket fan-out.
+	Clients       int   `json:"clients,omitempty"`
+	Subscriptions int   `json:"subscriptions,omitempty"`
+	Connected     int   `json:"connected,omitempty"`
+	Disconnects   int64 `json:"disconnects,omitempty"`
+	Dropped       int64 `json:"dropped"`
+	Reordered     int64 `json:"reordered,omitempty"`
+	Resets        int64 `json:"resets,omitempty"`
+
+	// gRPC.
+	Rate        float64 `json:"rate,omitempty"`
+	Concurrency int     `json:"concurrency,omitempty"`
+	Missed      int64   `json:"missed,omitempty"`
+
+	Messages   int64   `json:"messages"`
+	Errors     int64   `json:"errors"`
+	Throughput float64 `json:"throughput"`
+	Latency    Latency `json:"latency"`
+}
+
+// WriteText prints the report as aligned name/value lines.
+func (r Report) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "mode\t%s\n", r.Mode)
+	fmt.Fprintf(tw, "target\t%s\n", r.Target)
+	fmt.Fprintf(tw, "duration\t%v\n", r.Duration)
+
+	switch r.Mode {
+	case ModeWS:
+		fmt.Fprintf(tw, "clients\t%d connected of %d, %d subscriptions each\n", r.Connected, r.Clients, r.Subscriptions)
+		fmt.Fprintf(tw, "messages\t%d (%.1f/s)\n", r.Messages, r.Throughput)
+		fmt.Fprintf(tw, "dropped\t%d updates\n", r.Dropped)
+		fmt.Fprintf(tw, "reordered\t%d updates\n", r.Reordered)
+		fmt.Fprintf(tw, "resets\t%d\n", r.Resets)
+		fmt.Fprintf(tw, "disconnects\t%d\n", r.Disconnects)
+	case ModeGRPC:
+		fmt.Fprintf(tw, "requests\t%d (%.1f/s, target %v/s, concurrency %d)\n", r.Messages, r.Throughput, r.Rate, r.Concurrency)
+		fmt.Fprintf(tw, "missed\t%d (no worker free)\n", r.Missed)
+	}
+	fmt.Fprintf(tw, "errors\t%d\n", r.Errors)
+
+	l := r.Latency
+	fmt.Fprintf(tw, "latency\tmean %v  p50 %v  p90 %v  p99 %v  p99.9 %v  max %v (%d samples)\n",
+		round(l.Mean), round(l.P50), round(l.P90), round(l.P99), round(l.P999), round(l.Max), l.Samples)
+
+	return tw.Flush()
+}
+
+// WriteJSON prints the report as one JSON object. Durations are nanoseconds.
+func (r Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+func round(d time.Duration) time.Duration {
+	return d.Round(time.Microsecond)
+}
@@ -0,0 +1,313 @@
+package bench
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/coder/websocket"
+)
+
+const (
+	ModeWS   = "ws"
+	ModeGRPC = "grpc"
+
+	dialParallelism = 64
+	dialTimeout     = 10 * time.Second
+)
+
+// WSConfig opens Clients connections to URL, each subscribed to the depth of
+// Subscriptions symbols taken round-robin from Symbols. Nothing is measured
+// during Warmup; the report covers the Duration after it.
+type WSConfig struct {
+	URL           string
+	Clients       int
+	Subscriptions int
+	Symbols       []string
+	Duration      time.Duration
+	Warmup        time.Duration
+
+	// DistinctAddresses sends each client with its own X-Forwarded-For
+	// address, so a hub that rate limits upgrades per IP (and trusts that
+	// header) counts them as separate clients.
+	DistinctAddresses bool
+}
+
+// window is the measured part of a run.
+type window struct {
+	from, to time.Time
+}
+
+func (w window) contains(t time.Time) bool {
+	return !t.Before(w.from) && t.Before(w.to)
+}
+
+// wsResult is what one client observed during the window.
+type wsResult struct {
+	messages  int64
+	dropped   int64
+	reordered int64
+	resets    int64
+	errors    int64
+	latencies []time.Duration
+}
+
+// RunWS measures the hub's WebSocket fan-out. Latency is taken from the event
+// time the exchange embeds in each depth update ("E", in milliseconds), so it
+// includes the exchange-to-hub leg and is only as precise as the clocks of the
+// exchange and this host agree. Drops are the update IDs missing from each
+// client's sequence, which is how a client notices the hub's send buffer
+// overflowing.
+func RunWS(ctx context.Context, cfg WSConfig) (Report, error) {
+	symbols := make([]string, 0, len(cfg.Symbols))
+	for _, s := range cfg.Symbols {
+		if s = strings.ToLower(strings.TrimSpace(s)); s != "" {
+			symbols = append(symbols, s)
+		}
+	}
+	switch {
+	case cfg.Clients <= 0:
+		return Report{}, errors.New("at least one client is required")
+	case len(symbols) == 0:
+		return Report{}, errors.New("at least one symbol is required")
+	case cfg.Subscriptions <= 0 || cfg.Subscriptions > len(symbols):
+		return Report{}, fmt.Errorf("subscriptions per client must be between 1 and the %d symbols given", len(symbols))
+	}
+
+	report := Report{
+		Mode:          ModeWS,
+		Target:        cfg.URL,
+		Duration:      cfg.Duration,
+		Clients:       cfg.Clients,
+		Subscriptions: cfg.Subscriptions,
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	conns := make([]*websocket.Conn, cfg.Clients)
+	var (
+		dialErr  error
+		dialOnce sync.Once
+		sem      = make(chan struct{}, dialParallelism)
+		wg       sync.WaitGroup
+	)
+	for i := range conns {
+		topics := make([]string, cfg.Subscriptions)
+		for j := range topics {
+			topics[j] = symbols[(i*cfg.Subscriptions+j)%len(symbols)]
+		}
+
+		sem <- struct{}{}
+		wg.Go(func() {
+			defer func() { <-sem }()
+			var header http.Header
+			if cfg.DistinctAddresses {
+				header = http.Header{"X-Forwarded-For": {fmt.Sprintf("10.%d.%d.%d", i>>16&0xff, i>>8&0xff, i&0xff)}}
+			}
+			conn, err := dialClient(ctx, cfg.URL, header, topics)
+			if err != nil {
+				dialOnce.Do(func() { dialErr = err })
+				return
+			}
+			conns[i] = conn
+		})
+	}
+	wg.Wait()
+
+	defer func() {
+		for _, conn := range conns {
+			if conn != nil {
+				conn.CloseNow()
+			}
+		}
+	}()
+
+	for _, conn := range conns {
+		if conn != nil {
+			report.Connected++
+		}
+	}
+	if report.Connected == 0 {
+		return report, fmt.Errorf("no client connected: %w", dialErr)
+	}
+
+	start := time.Now()
+	win := window{from: start.Add(cfg.Warmup), to: start.Add(cfg.Warmup + cfg.Duration)}
+
+	var (
+		mu          sync.Mutex
+		results     []wsResult
+		disconnects atomic.Int64
+	)
+	runCtx, stop := context.WithDeadline(ctx, win.to)
+	defer stop()
+
+	for _, conn := range conns {
+		if conn == nil {
+			continue
+		}
+		wg.Go(func() {
+			res, err := readClient(runCtx, conn, win)
+			if err != nil && runCtx.Err() == nil {
+				disconnects.Add(1)
+			}
+			mu.Lock()
+			results = append(results, res)
+			mu.Unlock()
+		})
+	}
+	wg.Wait()
+
+	var latencies []time.Duration
+	for _, res := range results {
+		report.Messages += res.messages
+		report.Dropped += res.dropped
+		report.Reordered += res.reordered
+		report.Resets += res.resets
+		report.Errors += res.errors
+		latencies = append(latencies, res.latencies...)
+	}
+	report.Disconnects = disconnects.Load()
+	report.Errors += int64(cfg.Clients - report.Connected)
+	if cfg.Duration > 0 {
+		report.Throughput = float64(report.Messages) / cfg.Duration.Seconds()
+	}
+	report.Latency = Summarize(latencies)
+
+	return report, ctx.Err()
+}
+
+// dialClient connects and subscribes to the depth and reset topics of symbols.
+func dialClient(ctx context.Context, url string, header http.Header, symbols []string) (*websocket.Conn, error) {
+	ctx, cancel := context.WithTimeout(ctx, dialTimeout)
+	defer cancel()
+
+	conn, _, err := websocket.Dial(ctx, url, &websocket.DialOptions{HTTPHeader: header})
+	if err != nil {
+		return nil, err
+	}
+	conn.SetReadLimit(64 * 1024 * 1024)
+
+	for _, symbol := range symbols {
+		for _, topic := range []string{symbol + "@depth", symbol + "@depth.reset"} {
+			data, _ := json.Marshal(map[string]any{"method": "subscribe", "params": map[string]string{"topic": topic}})
+			if err := conn.Write(ctx, websocket.MessageText, data); err != nil {
+				conn.CloseNow()
+				return nil, err
+			}
+		}
+	}
+	return conn, nil
+}
+
+func readClient(ctx context.Context, conn *websocket.Conn, win window) (res wsResult, err error) {
+	sequences := make(map[string]*sequence)
+	defer func() {
+		for _, seq := range sequences {
+			res.dropped += seq.missing()
+		}
+	}()
+
+	for {
+		_, data, err := conn.Read(ctx)
+		if err != nil {
+			return res, err
+		}
+		now := time.Now()
+		if !win.contains(now) {
+			continue
+		}
+
+		var msg struct {
+			Method  string `json:"method"`
+			Success bool   `json:"success"`
+			Error   string `json:"error"`
+			Data    struct {
+				// "e" has to be named, or it is matched case-insensitively to "E".
+				EventType string `json:"e"`
+				EventTime int64  `json:"E"`
+				Symbol    string `json:"s"`
+				First     int64  `json:"U"`
+				Final     int64  `json:"u"`
+
+				ResetSymbol string `json:"symbol"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(data, &msg); err != nil {
+			res.errors++
+			continue
+		}
+
+		switch msg.Method {
+		case "":
+			if msg.Data.Symbol == "" {
+				continue
+			}
+			res.messages++
+			res.latencies = append(res.latencies, now.Sub(time.UnixMilli(msg.Data.EventTime)))
+
+			seq, ok := sequences[msg.Data.Symbol]
+			if !ok {
+				seq = &sequence{}
+				sequences[msg.Data.Symbol] = seq
+			}
+			if seq.add(msg.Data.First, msg.Data.Final) {
+				res.reordered++
+			}
+
+		case "orderbook_reset":
+			res.messages++
+			res.resets++
+			// Updates after a reset continue from the new snapshot, so the
+			// sequence so far is closed here.
+			if seq, ok := sequences[strings.ToUpper(msg.Data.ResetSymbol)]; ok {
+				res.dropped += seq.missing()
+				delete(sequences, strings.ToUpper(msg.Data.ResetSymbol))
+			}
+
+		case "subscribe", "unsubscribe":
+			if !msg.Success {
+				res.errors++
+			}
+		}
+	}
+}
+
+// sequence tracks the update IDs one client received for one symbol. It
+// tolerates updates arriving out of order, which the hub does not rule out.
+type sequence struct {
+	first, last int64
+	covered     int64
+}
+
+// add records an update covering IDs first..final and reports whether it
+// arrived after a later one.
+func (s *sequence) add(first, final int64) bool {
+	if s.covered == 0 {
+		s.first, s.last, s.covered = first, final, final-first+1
+		return false
+	}
+
+	s.covered += final - first + 1
+	s.first = min(s.first, first)
+	if final < s.last {
+		return true
+	}
+	s.last = final
+	return false
+}
+
+// missing is the number of IDs in the observed range that never arrived.
+func (s *sequence) missing() int64 {
+	if s.covered == 0 {
+		return 0
+	}
+	return max(0, s.last-s.first+1-s.covered)
+}
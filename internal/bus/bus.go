@@ -1,11 +1,13 @@
 package bus
 
 import (
+	"context"
 	"sync"
 )
 
 type IBus interface {
 	Publish(action string, topic string, data any)
+	PublishContext(ctx context.Context, action string, topic string, data any)
 	Subscribe(topic string, fn Subscriber)
 }
 
@@ -23,6 +25,10 @@ func New() *Bus {
 }
 
 func (b *Bus) Publish(action string, topic string, data any) {
+	b.PublishContext(context.Background(), action, topic, data)
+}
+
+func (b *Bus) PublishContext(ctx context.Context, action string, topic string, data any) {
 	b.mu.RLock()
 	subs, ok := b.subscribers[topic] // FEEDBACK: why do we need to lock here for reading subscribers? If subscriptions changes during running still the slice is not protected no
 	b.mu.RUnlock()
@@ -31,7 +37,7 @@ func (b *Bus) Publish(action string, topic string, data any) {
 		return
 	}
 
-	event := Event{Action: action, Topic: topic, Data: data}
+	event := Event{Action: action, Topic: topic, Data: data, Context: ctx}
 	for _, sub := range subs {
 		go sub(event) // FEEDBACK: Creating a goroutine per message can lead to unbounded goroutine growth. Also it may create out-of-order processing issues.
 	}
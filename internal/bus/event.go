@@ -1,7 +1,12 @@
 package bus
 
+import "context"
+
 type Event struct {
 	Action string
 	Topic  string
 	Data   any
+
+	// Context carries the trace of the publisher so subscribers can continue it.
+	Context context.Context
 }
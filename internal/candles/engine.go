@@ -0,0 +1,439 @@
+package candles
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ChethiyaNishanath/market-data-hub/internal/bus"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/config"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/domain/candle"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/domain/exchange"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/domain/trade"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/exchange/binance"
+)
+
+const (
+	DefaultLimit = 500
+	MaxLimit     = 1000
+
+	defaultFlushInterval      = time.Second
+	defaultPruneInterval      = 10 * time.Minute
+	defaultTradeRetention     = 24 * time.Hour
+	defaultMaxTradesPerSymbol = 100_000
+	defaultCandleRetention    = 30 * 24 * time.Hour
+
+	// lateTradeGrace is how long a candle stays open after its interval ends to
+	// take trades delivered late. Later trades for it are dropped.
+	lateTradeGrace = time.Minute
+)
+
+var ErrInvalidQuery = errors.New("invalid query")
+
+// DefaultIntervals are aggregated when the config lists none.
+var DefaultIntervals = []string{"1m", "5m", "1h"}
+
+// Source is the venue whose trades are aggregated.
+type Source interface {
+	exchange.SymbolLister
+	Name() string
+}
+
+type interval struct {
+	label    string
+	duration time.Duration
+}
+
+type seriesKey struct {
+	symbol   string
+	interval string
+	openTime int64
+}
+
+// bucket is a candle still taking trades. Open and close follow trade IDs rather
+// than arrival order, since bus handlers may deliver trades out of order.
+type bucket struct {
+	candle  candle.Candle
+	end     time.Time
+	openID  int64
+	closeID int64
+	dirty   bool
+}
+
+// Engine aggregates the trades of a venue into OHLCV candles and keeps both in
+// their repositories. Trades and changed candles are buffered and written once
+// per flush interval; queries merge in the buffered ones, so they are never stale.
+type Engine struct {
+	cfg       config.CandlesConfig
+	bus       bus.IBus
+	source    Source
+	trades    trade.Repository
+	candles   candle.Repository
+	intervals []interval
+	now       func() time.Time
+
+	mu         sync.Mutex
+	pending    []trade.Trade
+	open       map[seriesKey]*bucket
+	subscribed map[string]bool
+
+	done chan struct{}
+}
+
+func NewEngine(cfg config.CandlesConfig, eventBus bus.IBus, source Source, trades trade.Repository, candles candle.Repository) (*Engine, error) {
+	if len(cfg.Intervals) == 0 {
+		cfg.Intervals = DefaultIntervals
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = defaultFlushInterval
+	}
+	if cfg.PruneInterval <= 0 {
+		cfg.PruneInterval = defaultPruneInterval
+	}
+	if cfg.TradeRetention <= 0 {
+		cfg.TradeRetention = defaultTradeRetention
+	}
+	if cfg.MaxTradesPerSymbol <= 0 {
+		cfg.MaxTradesPerSymbol = defaultMaxTradesPerSymbol
+	}
+	if cfg.CandleRetention <= 0 {
+		cfg.CandleRetention = defaultCandleRetention
+	}
+
+	intervals := make([]interval, 0, len(cfg.Intervals))
+	for _, label := range cfg.Intervals {
+		label = strings.TrimSpace(label)
+		d, err := ParseInterval(label)
+		if err != nil {
+			return nil, err
+		}
+		intervals = append(intervals, interval{label: label, duration: d})
+	}
+
+	return &Engine{
+		cfg:        cfg,
+		bus:        eventBus,
+		source:     source,
+		trades:     trades,
+		candles:    candles,
+		intervals:  intervals,
+		now:        time.Now,
+		open:       make(map[seriesKey]*bucket),
+		subscribed: make(map[string]bool),
+		done:       make(chan struct{}),
+	}, nil
+}
+
+// ParseInterval parses a candle interval such as 1m, 4h or 1d. Intervals must
+// divide a day evenly so candles align to UTC midnight.
+func ParseInterval(label string) (time.Duration, error) {
+	var (
+		d   time.Duration
+		err error
+	)
+	if n, ok := strings.CutSuffix(label, "d"); ok {
+		var days int
+		days, err = strconv.Atoi(n)
+		d = time.Duration(days) * 24 * time.Hour
+	} else {
+		d, err = time.ParseDuration(label)
+	}
+	if err != nil || d < time.Second || (d <= 24*time.Hour && (24*time.Hour)%d != 0) || (d > 24*time.Hour && d%(24*time.Hour) != 0) {
+		return 0, fmt.Errorf("invalid candle interval %q", label)
+	}
+	return d, nil
+}
+
+// Intervals returns the configured interval labels.
+func (e *Engine) Intervals() []string {
+	labels := make([]string, len(e.intervals))
+	for i, iv := range e.intervals {
+		labels[i] = iv.label
+	}
+	return labels
+}
+
+// Done is closed once Run has flushed its final writes and returned.
+func (e *Engine) Done() <-chan struct{} {
+	return e.done
+}
+
+func (e *Engine) Run(ctx context.Context) {
+	defer close(e.done)
+
+	for _, symbol := range e.source.SubscribedSymbols() {
+		e.AddSymbol(symbol)
+	}
+
+	e.prune()
+
+	flush := time.NewTicker(e.cfg.FlushInterval)
+	defer flush.Stop()
+	prune := time.NewTicker(e.cfg.PruneInterval)
+	defer prune.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			e.Flush()
+			return
+		case <-flush.C:
+			e.Flush()
+		case <-prune.C:
+			e.prune()
+		}
+	}
+}
+
+// AddSymbol records the trades of a symbol, including one added to the
+// subscriptions while running. Each symbol is subscribed to once.
+func (e *Engine) AddSymbol(symbol string) {
+	symbol = strings.ToUpper(symbol)
+	e.mu.Lock()
+	if e.subscribed[symbol] {
+		e.mu.Unlock()
+		return
+	}
+	e.subscribed[symbol] = true
+	e.mu.Unlock()
+
+	e.bus.Subscribe(strings.ToLower(symbol)+"@trade", func(ev bus.Event) {
+		if t, ok := ev.Data.(binance.TradeEvent); ok {
+			e.Record(t)
+		}
+	})
+}
+
+// Record adds a trade to the trade history and to the candle of every interval.
+func (e *Engine) Record(ev binance.TradeEvent) {
+	price, err := strconv.ParseFloat(ev.Price, 64)
+	if err != nil {
+		return
+	}
+	qty, err := strconv.ParseFloat(ev.Quantity, 64)
+	if err != nil {
+		return
+	}
+
+	t := trade.Trade{
+		Exchange: e.source.Name(),
+		Symbol:   strings.ToUpper(ev.Symbol),
+		ID:       ev.TradeID,
+		Price:    price,
+		Quantity: qty,
+		Side:     ev.Side,
+		Time:     time.UnixMilli(ev.TradeTime).UTC(),
+	}
+	now := e.now()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.pending = append(e.pending, t)
+	for _, iv := range e.intervals {
+		openTime := t.Time.Truncate(iv.duration)
+		if openTime.Add(iv.duration + lateTradeGrace).Before(now) {
+			slog.Debug("Dropping late trade for a closed candle", "symbol", t.Symbol, "interval", iv.label, "trade_id", t.ID)
+			continue
+		}
+
+		key := seriesKey{t.Symbol, iv.label, openTime.UnixMilli()}
+		b, ok := e.open[key]
+		if !ok {
+			b = e.newBucket(t, iv, openTime)
+			e.open[key] = b
+		}
+		b.add(t)
+	}
+}
+
+// newBucket starts a candle, resuming the stored one when the hub restarted in
+// the middle of its interval. Trades after a restart are always newer, so the
+// stored open is kept and any new trade moves the close.
+func (e *Engine) newBucket(t trade.Trade, iv interval, openTime time.Time) *bucket {
+	b := &bucket{
+		candle: candle.Candle{
+			Exchange: t.Exchange,
+			Symbol:   t.Symbol,
+			Interval: iv.label,
+			OpenTime: openTime,
+			High:     math.Inf(-1),
+			Low:      math.Inf(1),
+		},
+		end:     openTime.Add(iv.duration),
+		openID:  math.MaxInt64,
+		closeID: math.MinInt64,
+	}
+
+	stored, err := e.candles.Range(t.Exchange, t.Symbol, iv.label, openTime, openTime, 1)
+	if err != nil {
+		slog.Error("Failed to load open candle", "symbol", t.Symbol, "interval", iv.label, "error", err)
+	} else if len(stored) == 1 {
+		b.candle = stored[0]
+		b.openID = math.MinInt64
+	}
+	return b
+}
+
+func (b *bucket) add(t trade.Trade) {
+	c := &b.candle
+	if t.ID < b.openID {
+		c.Open, b.openID = t.Price, t.ID
+	}
+	if t.ID > b.closeID {
+		c.Close, b.closeID = t.Price, t.ID
+	}
+	c.High = math.Max(c.High, t.Price)
+	c.Low = math.Min(c.Low, t.Price)
+	c.Volume += t.Quantity
+	c.QuoteVolume += t.Price * t.Quantity
+	c.Trades++
+	b.dirty = true
+}
+
+// Flush writes buffered trades and changed candles, then forgets candles that
+// no longer take trades.
+func (e *Engine) Flush() {
+	now := e.now()
+
+	e.mu.Lock()
+	trades := e.pending
+	e.pending = nil
+	changed := make([]candle.Candle, 0)
+	for key, b := range e.open {
+		if b.dirty {
+			changed = append(changed, b.candle)
+			b.dirty = false
+		}
+		if b.end.Add(lateTradeGrace).Before(now) {
+			delete(e.open, key)
+		}
+	}
+	e.mu.Unlock()
+
+	if err := e.trades.Add(trades...); err != nil {
+		slog.Error("Failed to store trades", "count", len(trades), "error", err)
+	}
+	if err := e.candles.Upsert(changed...); err != nil {
+		slog.Error("Failed to store candles", "count", len(changed), "error", err)
+	}
+}
+
+func (e *Engine) prune() {
+	now := e.now()
+	if err := e.trades.Prune(now.Add(-e.cfg.TradeRetention), e.cfg.MaxTradesPerSymbol); err != nil {
+		slog.Error("Failed to prune trades", "error", err)
+	}
+	if err := e.candles.Prune(now.Add(-e.cfg.CandleRetention)); err != nil {
+		slog.Error("Failed to prune candles", "error", err)
+	}
+}
+
+// Candles returns up to limit of the latest candles opened between from and to,
+// oldest first. A zero from or to leaves that end open.
+func (e *Engine) Candles(symbol, interval string, from, to time.Time, limit int) ([]candle.Candle, error) {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	if err := validate(symbol, from, to, &limit); err != nil {
+		return nil, err
+	}
+	if !e.hasInterval(interval) {
+		return nil, fmt.Errorf("%w: interval must be one of %s", ErrInvalidQuery, strings.Join(e.Intervals(), ", "))
+	}
+
+	stored, err := e.candles.Range(e.source.Name(), symbol, interval, from, to, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	byOpen := make(map[int64]candle.Candle, len(stored))
+	for _, c := range stored {
+		byOpen[c.OpenTime.UnixMilli()] = c
+	}
+	e.mu.Lock()
+	for key, b := range e.open {
+		if key.symbol == symbol && key.interval == interval && within(b.candle.OpenTime, from, to) {
+			byOpen[key.openTime] = b.candle
+		}
+	}
+	e.mu.Unlock()
+
+	merged := make([]candle.Candle, 0, len(byOpen))
+	for _, c := range byOpen {
+		merged = append(merged, c)
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].OpenTime.Before(merged[j].OpenTime) })
+	return latest(merged, limit), nil
+}
+
+// Trades returns up to limit of the latest trades between from and to, oldest
+// first. A zero from or to leaves that end open.
+func (e *Engine) Trades(symbol string, from, to time.Time, limit int) ([]trade.Trade, error) {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	if err := validate(symbol, from, to, &limit); err != nil {
+		return nil, err
+	}
+
+	stored, err := e.trades.Recent(e.source.Name(), symbol, from, to, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[int64]bool, len(stored))
+	for _, t := range stored {
+		seen[t.ID] = true
+	}
+	e.mu.Lock()
+	for _, t := range e.pending {
+		if t.Symbol == symbol && !seen[t.ID] && within(t.Time, from, to) {
+			stored = append(stored, t)
+			seen[t.ID] = true
+		}
+	}
+	e.mu.Unlock()
+
+	sort.Slice(stored, func(i, j int) bool { return stored[i].ID < stored[j].ID })
+	return latest(stored, limit), nil
+}
+
+func (e *Engine) hasInterval(label string) bool {
+	for _, iv := range e.intervals {
+		if iv.label == label {
+			return true
+		}
+	}
+	return false
+}
+
+func validate(symbol string, from, to time.Time, limit *int) error {
+	if symbol == "" {
+		return fmt.Errorf("%w: symbol is required", ErrInvalidQuery)
+	}
+	if !from.IsZero() && !to.IsZero() && to.Before(from) {
+		return fmt.Errorf("%w: to is before from", ErrInvalidQuery)
+	}
+	if *limit <= 0 {
+		*limit = DefaultLimit
+	}
+	if *limit > MaxLimit {
+		return fmt.Errorf("%w: limit must not exceed %d", ErrInvalidQuery, MaxLimit)
+	}
+	return nil
+}
+
+func within(t, from, to time.Time) bool {
+	return (from.IsZero() || !t.Before(from)) && (to.IsZero() || !t.After(to))
+}
+
+func latest[T any](list []T, limit int) []T {
+	if len(list) > limit {
+		return list[len(list)-limit:]
+	}
+	return list
+}
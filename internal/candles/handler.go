@@ -0,0 +1,97 @@
+package candles
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// RegisterRoutes mounts GET /candles and GET /trades.
+func (e *Engine) RegisterRoutes(r chi.Router) {
+	r.Get("/candles", e.handleCandles)
+	r.Get("/trades", e.handleTrades)
+}
+
+// handleCandles serves GET /candles?symbol=BTCUSDT&interval=1m&from=..&to=..&limit=100.
+// from and to are Unix milliseconds or RFC 3339 times.
+func (e *Engine) handleCandles(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	from, to, limit, ok := parseRange(w, r)
+	if !ok {
+		return
+	}
+
+	candles, err := e.Candles(query.Get("symbol"), query.Get("interval"), from, to, limit)
+	if err != nil {
+		writeJSON(w, statusCode(err), errorResponse{Error: err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, candles)
+}
+
+// handleTrades serves GET /trades?symbol=BTCUSDT&from=..&to=..&limit=100.
+func (e *Engine) handleTrades(w http.ResponseWriter, r *http.Request) {
+	from, to, limit, ok := parseRange(w, r)
+	if !ok {
+		return
+	}
+
+	trades, err := e.Trades(r.URL.Query().Get("symbol"), from, to, limit)
+	if err != nil {
+		writeJSON(w, statusCode(err), errorResponse{Error: err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, trades)
+}
+
+func parseRange(w http.ResponseWriter, r *http.Request) (from, to time.Time, limit int, ok bool) {
+	query := r.URL.Query()
+
+	var err error
+	if from, err = parseTime(query.Get("from")); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: "invalid from"})
+		return
+	}
+	if to, err = parseTime(query.Get("to")); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: "invalid to"})
+		return
+	}
+	if raw := query.Get("limit"); raw != "" {
+		if limit, err = strconv.Atoi(raw); err != nil || limit < 0 {
+			writeJSON(w, http.StatusBadRequest, errorResponse{Error: "invalid limit"})
+			return
+		}
+	}
+	return from, to, limit, true
+}
+
+func parseTime(raw string) (time.Time, error) {
+	if raw == "" {
+		return time.Time{}, nil
+	}
+	if ms, err := strconv.ParseInt(raw, 10, 64); err == nil {
+		return time.UnixMilli(ms), nil
+	}
+	return time.Parse(time.RFC3339Nano, raw)
+}
+
+func statusCode(err error) int {
+	if errors.Is(err, ErrInvalidQuery) {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+func writeJSON(w http.ResponseWriter, code int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(body)
+}
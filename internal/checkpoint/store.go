@@ -0,0 +1,87 @@
+package checkpoint
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ChethiyaNishanath/market-data-hub/internal/domain/orderbook"
+	bolt "go.etcd.io/bbolt"
+)
+
+var booksBucket = []byte("books")
+
+type record struct {
+	SavedAt time.Time           `json:"savedAt"`
+	Book    orderbook.OrderBook `json:"book"`
+}
+
+// Store is a BoltDB-backed orderbook.CheckpointStore holding the latest book per
+// venue and symbol.
+type Store struct {
+	db *bolt.DB
+}
+
+func Open(path string) (*Store, error) {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("create checkpoint directory: %w", err)
+		}
+	}
+
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("open checkpoints: %w", err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(booksBucket)
+		return err
+	})
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("init checkpoints: %w", err)
+	}
+
+	return &Store{db: db}, nil
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+func (s *Store) Save(venue, symbol string, book *orderbook.OrderBook) error {
+	data, err := json.Marshal(record{SavedAt: time.Now(), Book: *book})
+	if err != nil {
+		return err
+	}
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(booksBucket).Put(bookKey(venue, symbol), data)
+	})
+}
+
+func (s *Store) Load(venue, symbol string) (*orderbook.OrderBook, time.Time, bool) {
+	var rec record
+	found := false
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(booksBucket).Get(bookKey(venue, symbol))
+		if data == nil {
+			return nil
+		}
+		found = true
+		return json.Unmarshal(data, &rec)
+	})
+	if err != nil || !found {
+		return nil, time.Time{}, false
+	}
+
+	return &rec.Book, rec.SavedAt, true
+}
+
+func bookKey(venue, symbol string) []byte {
+	return []byte(venue + "/" + symbol)
+}
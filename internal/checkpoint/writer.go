@@ -0,0 +1,77 @@
+package checkpoint
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/ChethiyaNishanath/market-data-hub/internal/domain/exchange"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/domain/orderbook"
+)
+
+const defaultInterval = 10 * time.Second
+
+// Source is a venue whose synchronised books are checkpointed. FullOrderBook
+// returns the book with every level, as a book restored from a checkpoint cut
+// to the published depth would stay short of levels until the next snapshot.
+type Source interface {
+	exchange.BookSource
+	exchange.SymbolLister
+	FullOrderBook(symbol string) *orderbook.OrderBook
+}
+
+// Writer periodically saves every synchronised book, and once more on shutdown.
+type Writer struct {
+	store    orderbook.CheckpointStore
+	sources  []Source
+	interval time.Duration
+	done     chan struct{}
+}
+
+func NewWriter(store orderbook.CheckpointStore, interval time.Duration, sources ...Source) *Writer {
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+	return &Writer{store: store, sources: sources, interval: interval, done: make(chan struct{})}
+}
+
+// Done is closed once Run has written its final checkpoint and returned.
+func (w *Writer) Done() <-chan struct{} {
+	return w.done
+}
+
+func (w *Writer) Run(ctx context.Context) {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.SaveAll()
+			return
+		case <-ticker.C:
+			w.SaveAll()
+		}
+	}
+}
+
+// SaveAll writes the current book of every synchronised symbol. Books that are
+// resyncing keep their previous checkpoint.
+func (w *Writer) SaveAll() {
+	for _, src := range w.sources {
+		for _, symbol := range src.SubscribedSymbols() {
+			if !src.IsSynchronized(symbol) {
+				continue
+			}
+			book := src.FullOrderBook(symbol)
+			if book == nil {
+				continue
+			}
+			if err := w.store.Save(src.Name(), symbol, book); err != nil {
+				slog.Error("Failed to checkpoint order book", "venue", src.Name(), "symbol", symbol, "error", err)
+			}
+		}
+	}
+}
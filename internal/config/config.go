@@ -1,18 +1,87 @@
 package config
 
+import (
+	"log/slog"
+	"strings"
+	"time"
+)
+
 type Config struct {
 	Server       Server             `mapstructure:"server"`
 	Integrations IntegrationsConfig `mapstructure:"integrations"`
 	Logging      Logging            `mapstructure:"logging"`
+	Health       HealthConfig       `mapstructure:"health"`
+	Tracing      TracingConfig      `mapstructure:"tracing"`
+	Consolidated ConsolidatedConfig `mapstructure:"consolidated"`
+	Synthetic    SyntheticConfig    `mapstructure:"synthetic"`
+	Analytics    AnalyticsConfig    `mapstructure:"analytics"`
+	Alerts       AlertsConfig       `mapstructure:"alerts"`
+	Arbitrage    ArbitrageConfig    `mapstructure:"arbitrage"`
+	Checkpoints  CheckpointConfig   `mapstructure:"checkpoints"`
+	History      HistoryConfig      `mapstructure:"history"`
+	Export       ExportConfig       `mapstructure:"export"`
+	Storage      StorageConfig      `mapstructure:"storage"`
+	Candles      CandlesConfig      `mapstructure:"candles"`
+	Verify       VerifyConfig       `mapstructure:"verify"`
 }
 
 type Logging struct {
 	Level string `mapstructure:"level"`
 }
 
+// SlogLevel is Level as a slog level, info when unset.
+func (l Logging) SlogLevel() slog.Level {
+	switch strings.ToLower(l.Level) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn", "warning":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
 type Server struct {
-	Port            string `mapstructure:"port"`
-	ShutdownTimeout string `mapstructure:"shutdownTimeout"`
+	Port            int             `mapstructure:"port"`
+	ShutdownTimeout time.Duration   `mapstructure:"shutdownTimeout"`
+	RateLimit       RateLimitConfig `mapstructure:"rateLimit"`
+	WebSocket       WebSocketConfig `mapstructure:"websocket"`
+	Admin           AdminConfig     `mapstructure:"admin"`
+	Grpc            GrpcConfig      `mapstructure:"grpc"`
+}
+
+// AdminConfig guards the admin routes. Requests must send Token as a bearer
+// token; while it is empty the routes are off.
+type AdminConfig struct {
+	Token string `mapstructure:"token" redact:"true"`
+}
+
+// GrpcConfig guards the gRPC API. When Token is set every call must send it as
+// a bearer token in its authorization metadata.
+type GrpcConfig struct {
+	Token string `mapstructure:"token" redact:"true"`
+}
+
+// WebSocketConfig sets the outbound queue of each WebSocket client. Once
+// HighWaterMark messages are queued the client is slow and SlowConsumer
+// decides what happens to its pushes: "resnapshot", "disconnect" or
+// "conflate". WriteTimeout bounds a single write. Zero values take the
+// defaults: a queue of 256, a high-water mark at its capacity, 10s and
+// resnapshot.
+type WebSocketConfig struct {
+	SendBuffer    int           `mapstructure:"sendBuffer"`
+	HighWaterMark int           `mapstructure:"highWaterMark"`
+	WriteTimeout  time.Duration `mapstructure:"writeTimeout"`
+	SlowConsumer  string        `mapstructure:"slowConsumer"`
+}
+
+// RateLimitConfig caps the HTTP requests of one client IP to Requests per
+// Window. A Requests of 0 turns the limit off.
+type RateLimitConfig struct {
+	Requests int           `mapstructure:"requests"`
+	Window   time.Duration `mapstructure:"window"`
 }
 
 type IntegrationsConfig struct {
@@ -20,7 +89,258 @@ type IntegrationsConfig struct {
 }
 
 type BinanceConfig struct {
-	WsStreamUrl   string `mapstructure:"wsStreamUrl"`
-	RestApiUrlV3  string `mapstructure:"restApiUrlV3"`
-	Subscriptions string `mapstructure:"subscriptions"`
+	WsStreamUrl   string           `mapstructure:"wsStreamUrl"`
+	RestApiUrlV3  string           `mapstructure:"restApiUrlV3"`
+	Subscriptions []SymbolConfig   `mapstructure:"subscriptions"`
+	Trades        bool             `mapstructure:"trades"`
+	Snapshot      SnapshotConfig   `mapstructure:"snapshot"`
+	Stream        StreamConfig     `mapstructure:"stream"`
+	Redundancy    RedundancyConfig `mapstructure:"redundancy"`
+}
+
+// SymbolConfig is one subscribed symbol. DepthLimit caps the levels per side
+// published to clients (the full book is still maintained), SnapshotLimit
+// overrides snapshot.limit for its REST snapshots and Conflation, when set,
+// merges the depth updates sent to clients into one per interval. A plain
+// symbol string in the config stands for an enabled symbol with no overrides.
+type SymbolConfig struct {
+	Symbol        string        `mapstructure:"symbol"`
+	Enabled       bool          `mapstructure:"enabled"`
+	DepthLimit    int           `mapstructure:"depthLimit"`
+	SnapshotLimit int           `mapstructure:"snapshotLimit"`
+	Conflation    time.Duration `mapstructure:"conflation"`
+}
+
+// Symbols lists the enabled symbols, upper-cased, in configured order.
+func (c BinanceConfig) Symbols() []string {
+	symbols := make([]string, 0, len(c.Subscriptions))
+	for _, sub := range c.Subscriptions {
+		if symbol := strings.ToUpper(strings.TrimSpace(sub.Symbol)); sub.Enabled && symbol != "" {
+			symbols = append(symbols, symbol)
+		}
+	}
+	return symbols
+}
+
+// Symbol returns the settings of an enabled symbol.
+func (c BinanceConfig) Symbol(symbol string) (SymbolConfig, bool) {
+	for _, sub := range c.Subscriptions {
+		if sub.Enabled && strings.EqualFold(strings.TrimSpace(sub.Symbol), symbol) {
+			return sub, true
+		}
+	}
+	return SymbolConfig{}, false
+}
+
+// RedundancyConfig enables a second, independent upstream connection per symbol.
+type RedundancyConfig struct {
+	Enabled              bool          `mapstructure:"enabled"`
+	SecondaryWsStreamUrl string        `mapstructure:"secondaryWsStreamUrl"`
+	GapFillTimeout       time.Duration `mapstructure:"gapFillTimeout"`
+}
+
+type StreamConfig struct {
+	DialTimeout      time.Duration `mapstructure:"dialTimeout"`
+	PingInterval     time.Duration `mapstructure:"pingInterval"`
+	StaleTimeout     time.Duration `mapstructure:"staleTimeout"`
+	MaxConnectionAge time.Duration `mapstructure:"maxConnectionAge"`
+	InitialBackoff   time.Duration `mapstructure:"initialBackoff"`
+	MaxBackoff       time.Duration `mapstructure:"maxBackoff"`
+}
+
+type SnapshotConfig struct {
+	Limit          int           `mapstructure:"limit"`
+	Timeout        time.Duration `mapstructure:"timeout"`
+	MaxConcurrent  int           `mapstructure:"maxConcurrent"`
+	MaxRetries     int           `mapstructure:"maxRetries"`
+	InitialBackoff time.Duration `mapstructure:"initialBackoff"`
+	MaxBackoff     time.Duration `mapstructure:"maxBackoff"`
+	WeightLimit    int           `mapstructure:"weightLimit"`
+}
+
+type HealthConfig struct {
+	StaleThreshold time.Duration `mapstructure:"staleThreshold"`
+}
+
+type TracingConfig struct {
+	Enabled     bool    `mapstructure:"enabled"`
+	Exporter    string  `mapstructure:"exporter"`
+	Endpoint    string  `mapstructure:"endpoint"`
+	Insecure    bool    `mapstructure:"insecure"`
+	SampleRatio float64 `mapstructure:"sampleRatio"`
+	ServiceName string  `mapstructure:"serviceName"`
+}
+
+type ConsolidatedConfig struct {
+	Enabled         bool                     `mapstructure:"enabled"`
+	PublishInterval time.Duration            `mapstructure:"publishInterval"`
+	Depth           int                      `mapstructure:"depth"`
+	Instruments     []ConsolidatedInstrument `mapstructure:"instruments"`
+}
+
+type ConsolidatedInstrument struct {
+	Name    string        `mapstructure:"name"`
+	Sources []VenueSource `mapstructure:"sources"`
+}
+
+// VenueSource is one venue's book feeding a consolidated instrument. Weight scales
+// the quantity the venue contributes and FeeBps adjusts its prices for taker fees.
+type VenueSource struct {
+	Venue  string  `mapstructure:"venue"`
+	Symbol string  `mapstructure:"symbol"`
+	Weight float64 `mapstructure:"weight"`
+	FeeBps float64 `mapstructure:"feeBps"`
+}
+
+type SyntheticConfig struct {
+	Enabled     bool                  `mapstructure:"enabled"`
+	Depth       int                   `mapstructure:"depth"`
+	Instruments []SyntheticInstrument `mapstructure:"instruments"`
+}
+
+// SyntheticInstrument derives a book by composing its legs left to right. The
+// first leg is taken as-is; each later leg multiplies or divides the running price.
+type SyntheticInstrument struct {
+	Name string         `mapstructure:"name"`
+	Legs []SyntheticLeg `mapstructure:"legs"`
+}
+
+type SyntheticLeg struct {
+	Venue     string `mapstructure:"venue"`
+	Symbol    string `mapstructure:"symbol"`
+	Operation string `mapstructure:"operation"`
+}
+
+// AnalyticsConfig controls the per-symbol statistics stream. TopN is the number of
+// levels used for the imbalance and DepthBandsBps the distances from mid, in basis
+// points, at which cumulative depth is reported.
+type AnalyticsConfig struct {
+	Enabled         bool          `mapstructure:"enabled"`
+	PublishInterval time.Duration `mapstructure:"publishInterval"`
+	TopN            int           `mapstructure:"topN"`
+	DepthBandsBps   []float64     `mapstructure:"depthBandsBps"`
+}
+
+type AlertsConfig struct {
+	Enabled            bool              `mapstructure:"enabled"`
+	EvaluationInterval time.Duration     `mapstructure:"evaluationInterval"`
+	Webhook            WebhookConfig     `mapstructure:"webhook"`
+	Rules              []AlertRuleConfig `mapstructure:"rules"`
+}
+
+// AlertRuleConfig is an alert defined in the config rather than over the API.
+// The rule fields are those of an alert's rule; fired alerts go to WebhookURL.
+type AlertRuleConfig struct {
+	ID            string        `mapstructure:"id"`
+	Symbol        string        `mapstructure:"symbol"`
+	Type          string        `mapstructure:"type"`
+	Side          string        `mapstructure:"side"`
+	Direction     string        `mapstructure:"direction"`
+	Price         float64       `mapstructure:"price"`
+	SpreadBps     float64       `mapstructure:"spreadBps"`
+	BandBps       float64       `mapstructure:"bandBps"`
+	MinQuantity   float64       `mapstructure:"minQuantity"`
+	DesyncSeconds float64       `mapstructure:"desyncSeconds"`
+	Repeat        bool          `mapstructure:"repeat"`
+	Cooldown      time.Duration `mapstructure:"cooldown"`
+	WebhookURL    string        `mapstructure:"webhookUrl"`
+}
+
+// WebhookConfig controls signed webhook delivery. Deliveries are persisted in the
+// outbox at OutboxPath and retried until MaxAttempts is reached.
+type WebhookConfig struct {
+	Enabled        bool          `mapstructure:"enabled"`
+	Secret         string        `mapstructure:"secret" redact:"true"`
+	OutboxPath     string        `mapstructure:"outboxPath"`
+	Timeout        time.Duration `mapstructure:"timeout"`
+	MaxAttempts    int           `mapstructure:"maxAttempts"`
+	InitialBackoff time.Duration `mapstructure:"initialBackoff"`
+	MaxBackoff     time.Duration `mapstructure:"maxBackoff"`
+}
+
+// ArbitrageConfig controls the opportunity detector. Instruments compare the same
+// market across venues, Triangles compare a direct book with one composed from its
+// legs. MinProfitBps is the net edge, after fees, required to report an opportunity.
+type ArbitrageConfig struct {
+	Enabled       bool                     `mapstructure:"enabled"`
+	Interval      time.Duration            `mapstructure:"interval"`
+	MinProfitBps  float64                  `mapstructure:"minProfitBps"`
+	DetectCrossed bool                     `mapstructure:"detectCrossed"`
+	Instruments   []ConsolidatedInstrument `mapstructure:"instruments"`
+	Triangles     []ArbitrageTriangle      `mapstructure:"triangles"`
+}
+
+// ArbitrageTriangle pays FeeBps on the direct book and on every leg.
+type ArbitrageTriangle struct {
+	Name   string         `mapstructure:"name"`
+	Direct SyntheticLeg   `mapstructure:"direct"`
+	Legs   []SyntheticLeg `mapstructure:"legs"`
+	FeeBps float64        `mapstructure:"feeBps"`
+}
+
+// CheckpointConfig controls periodic book persistence for warm restarts. Books
+// saved longer than MaxAge ago are ignored on start.
+type CheckpointConfig struct {
+	Enabled  bool          `mapstructure:"enabled"`
+	Path     string        `mapstructure:"path"`
+	Interval time.Duration `mapstructure:"interval"`
+	MaxAge   time.Duration `mapstructure:"maxAge"`
+}
+
+// HistoryConfig controls the on-disk delta journal and the periodic full
+// checkpoints used to rebuild past books. Data older than Retention is pruned.
+type HistoryConfig struct {
+	Enabled            bool          `mapstructure:"enabled"`
+	Path               string        `mapstructure:"path"`
+	CheckpointInterval time.Duration `mapstructure:"checkpointInterval"`
+	FlushInterval      time.Duration `mapstructure:"flushInterval"`
+	Retention          time.Duration `mapstructure:"retention"`
+}
+
+// ExportConfig controls the data lake export. Each dataset (depth, book,
+// trades) is written in every format (parquet, csv) under Path.
+type ExportConfig struct {
+	Enabled        bool          `mapstructure:"enabled"`
+	Path           string        `mapstructure:"path"`
+	Formats        []string      `mapstructure:"formats"`
+	Datasets       []string      `mapstructure:"datasets"`
+	Compression    string        `mapstructure:"compression"`
+	RowGroupSize   int           `mapstructure:"rowGroupSize"`
+	RollInterval   time.Duration `mapstructure:"rollInterval"`
+	SampleInterval time.Duration `mapstructure:"sampleInterval"`
+	TopN           int           `mapstructure:"topN"`
+}
+
+// StorageConfig selects where trades, candles, alerts and webhook deliveries are
+// kept: memory (lost on restart) or sqlite (the file at Path). With sqlite the
+// webhook outbox lives in the same file and alerts.webhook.outboxPath is unused.
+type StorageConfig struct {
+	Backend string `mapstructure:"backend"`
+	Path    string `mapstructure:"path"`
+}
+
+// CandlesConfig controls trade history and candle aggregation. Trades older
+// than TradeRetention, or beyond MaxTradesPerSymbol, and candles older than
+// CandleRetention are pruned every PruneInterval.
+type CandlesConfig struct {
+	Enabled            bool          `mapstructure:"enabled"`
+	Intervals          []string      `mapstructure:"intervals"`
+	FlushInterval      time.Duration `mapstructure:"flushInterval"`
+	PruneInterval      time.Duration `mapstructure:"pruneInterval"`
+	TradeRetention     time.Duration `mapstructure:"tradeRetention"`
+	MaxTradesPerSymbol int           `mapstructure:"maxTradesPerSymbol"`
+	CandleRetention    time.Duration `mapstructure:"candleRetention"`
+}
+
+// VerifyConfig controls the book consistency checker. Every Interval each book
+// is compared with a fresh exchange snapshot within the best Depth levels (all
+// levels the snapshot holds when negative). With Resync on, more than
+// MaxMismatches differing levels discard the book and reload it.
+type VerifyConfig struct {
+	Enabled       bool          `mapstructure:"enabled"`
+	Interval      time.Duration `mapstructure:"interval"`
+	Depth         int           `mapstructure:"depth"`
+	MaxMismatches int           `mapstructure:"maxMismatches"`
+	AlignTimeout  time.Duration `mapstructure:"alignTimeout"`
+	Resync        bool          `mapstructure:"resync"`
 }
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"time"
+)
+
+// Changes lists the keys whose values differ between old and new, as leaf
+// paths such as server.port or integrations.binance.subscriptions[1].symbol,
+// sorted. Secrets are compared but never printed, so their keys are listed
+// like any other.
+func Changes(old, new *Config) []string {
+	before := make(map[string]string)
+	after := make(map[string]string)
+	leaves(before, "", reflect.ValueOf(*old))
+	leaves(after, "", reflect.ValueOf(*new))
+
+	var changed []string
+	for key, value := range before {
+		if other, ok := after[key]; !ok || other != value {
+			changed = append(changed, key)
+		}
+	}
+	for key := range after {
+		if _, ok := before[key]; !ok {
+			changed = append(changed, key)
+		}
+	}
+	slices.Sort(changed)
+	return changed
+}
+
+func leaves(out map[string]string, key string, v reflect.Value) {
+	if v.Type() == durationType {
+		out[key] = time.Duration(v.Int()).String()
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		t := v.Type()
+		for i := range t.NumField() {
+			name := t.Field(i).Tag.Get("mapstructure")
+			if name == "" {
+				continue
+			}
+			if key != "" {
+				name = key + "." + name
+			}
+			leaves(out, name, v.Field(i))
+		}
+	case reflect.Slice:
+		for i := range v.Len() {
+			leaves(out, fmt.Sprintf("%s[%d]", key, i), v.Index(i))
+		}
+	default:
+		out[key] = fmt.Sprint(v.Interface())
+	}
+}
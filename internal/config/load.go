@@ -0,0 +1,158 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/spf13/viper"
+)
+
+// SetDefaults registers the values used when neither the config file, the
+// environment nor a flag sets them. Components apply their own defaults to
+// the zero values of their sections.
+func SetDefaults(v *viper.Viper) {
+	v.SetDefault("server.port", 8080)
+	v.SetDefault("server.shutdownTimeout", "10s")
+	v.SetDefault("server.rateLimit.requests", 100)
+	v.SetDefault("server.rateLimit.window", "1m")
+	v.SetDefault("logging.level", "info")
+}
+
+// Load decodes the merged settings of v and validates them. The config is
+// returned together with the validation error so it can still be printed.
+func Load(v *viper.Viper) (*Config, error) {
+	var cfg Config
+	if err := v.Unmarshal(&cfg, viper.DecodeHook(DecodeHook())); err != nil {
+		return nil, decodeProblems(err)
+	}
+	for i, sub := range cfg.Integrations.Binance.Subscriptions {
+		cfg.Integrations.Binance.Subscriptions[i].Symbol = strings.ToUpper(strings.TrimSpace(sub.Symbol))
+	}
+	return &cfg, cfg.Validate()
+}
+
+// decodeProblems restates decoding errors in the "key: problem" form Validate
+// uses, one joined error per key.
+func decodeProblems(err error) error {
+	var p problems
+	var walk func(err error)
+	walk = func(err error) {
+		switch e := err.(type) {
+		case interface{ Unwrap() []error }:
+			for _, err := range e.Unwrap() {
+				walk(err)
+			}
+		case *mapstructure.DecodeError:
+			if _, nested := e.Unwrap().(interface{ Unwrap() []error }); nested {
+				walk(e.Unwrap())
+			} else {
+				p.add(e.Name(), "%v", e.Unwrap())
+			}
+		default:
+			if inner := errors.Unwrap(err); inner != nil {
+				walk(inner)
+			} else {
+				p = append(p, err)
+			}
+		}
+	}
+	walk(err)
+	return errors.Join(p...)
+}
+
+// DecodeHook converts the loosely typed values found in files, environment
+// variables and flags into the schema's types.
+func DecodeHook() mapstructure.DecodeHookFunc {
+	return mapstructure.ComposeDecodeHookFunc(
+		durationHook,
+		symbolsHook,
+		mapstructure.StringToSliceHookFunc(","),
+	)
+}
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	symbolsType  = reflect.TypeOf([]SymbolConfig(nil))
+)
+
+// durationHook parses durations such as "500ms" or "1h30m". A bare number
+// other than 0 is rejected rather than read as nanoseconds.
+func durationHook(_ reflect.Type, to reflect.Type, data any) (any, error) {
+	if to != durationType {
+		return data, nil
+	}
+
+	switch v := data.(type) {
+	case string:
+		v = strings.TrimSpace(v)
+		if v == "" || v == "0" {
+			return time.Duration(0), nil
+		}
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a duration, expected e.g. 500ms, 10s or 1m", v)
+		}
+		return d, nil
+	case time.Duration:
+		return v, nil
+	case int, int32, int64, uint, uint32, uint64, float32, float64:
+		if reflect.ValueOf(v).IsZero() {
+			return time.Duration(0), nil
+		}
+		return nil, fmt.Errorf("%v has no unit, expected e.g. %vs", v, v)
+	}
+	return data, nil
+}
+
+// symbolsHook accepts subscriptions as a comma separated string, a list of
+// symbols, a list of symbol objects or a mix of the last two. Objects without
+// an enabled key are enabled.
+func symbolsHook(_ reflect.Type, to reflect.Type, data any) (any, error) {
+	if to != symbolsType {
+		return data, nil
+	}
+
+	var items []any
+	switch v := data.(type) {
+	case string:
+		for symbol := range strings.SplitSeq(v, ",") {
+			items = append(items, symbol)
+		}
+	case []string:
+		for _, symbol := range v {
+			items = append(items, symbol)
+		}
+	case []any:
+		items = v
+	default:
+		return data, nil
+	}
+
+	subs := make([]any, 0, len(items))
+	for _, item := range items {
+		switch v := item.(type) {
+		case string:
+			if symbol := strings.TrimSpace(v); symbol != "" {
+				subs = append(subs, map[string]any{"symbol": symbol, "enabled": true})
+			}
+		case map[string]any:
+			sub := make(map[string]any, len(v)+1)
+			enabled := false
+			for key, value := range v {
+				sub[key] = value
+				enabled = enabled || strings.EqualFold(key, "enabled")
+			}
+			if !enabled {
+				sub["enabled"] = true
+			}
+			subs = append(subs, sub)
+		default:
+			subs = append(subs, item)
+		}
+	}
+	return subs, nil
+}
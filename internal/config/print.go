@@ -0,0 +1,83 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"time"
+
+	"go.yaml.in/yaml/v3"
+)
+
+const redacted = "REDACTED"
+
+// WriteYAML prints the config with the keys the config file uses, in schema
+// order. Durations are written as e.g. 1m30s and the fields tagged
+// redact:"true" are redacted when set.
+func (c *Config) WriteYAML(w io.Writer) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(toNode(reflect.ValueOf(*c), false)); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// WriteJSON prints the same document as WriteYAML, as JSON.
+func (c *Config) WriteJSON(w io.Writer) error {
+	var doc map[string]any
+	if err := toNode(reflect.ValueOf(*c), false).Decode(&doc); err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
+
+func toNode(v reflect.Value, secret bool) *yaml.Node {
+	switch {
+	case v.Type() == durationType:
+		return scalar("!!str", time.Duration(v.Int()).String())
+	case secret:
+		if v.String() == "" {
+			return scalar("!!str", "")
+		}
+		return scalar("!!str", redacted)
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		t := v.Type()
+		for i := range t.NumField() {
+			field := t.Field(i)
+			key := field.Tag.Get("mapstructure")
+			if key == "" {
+				continue
+			}
+			node.Content = append(node.Content, scalar("!!str", key), toNode(v.Field(i), field.Tag.Get("redact") == "true"))
+		}
+		return node
+	case reflect.Slice:
+		node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		for i := range v.Len() {
+			node.Content = append(node.Content, toNode(v.Index(i), false))
+		}
+		return node
+	case reflect.String:
+		return scalar("!!str", v.String())
+	case reflect.Bool:
+		return scalar("!!bool", strconv.FormatBool(v.Bool()))
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		return scalar("!!int", strconv.FormatInt(v.Int(), 10))
+	case reflect.Float32, reflect.Float64:
+		return scalar("!!float", strconv.FormatFloat(v.Float(), 'g', -1, 64))
+	}
+	return scalar("!!str", fmt.Sprint(v.Interface()))
+}
+
+func scalar(tag, value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: value}
+}
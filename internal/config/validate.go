@@ -0,0 +1,243 @@
+package config
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// maxSnapshotLimit is the deepest snapshot Binance serves.
+const maxSnapshotLimit = 5000
+
+// defaultSendBuffer is the send buffer of a WebSocket client when
+// server.websocket.sendBuffer is unset.
+const defaultSendBuffer = 256
+
+var symbolPattern = regexp.MustCompile(`^[A-Za-z0-9]+$`)
+
+// problems collects validation errors, each prefixed with the key it is about.
+type problems []error
+
+func (p *problems) add(key, format string, args ...any) {
+	*p = append(*p, fmt.Errorf(key+": "+format, args...))
+}
+
+// Validate reports every problem found in the config, not just the first.
+func (c *Config) Validate() error {
+	var p problems
+
+	if c.Server.Port < 1 || c.Server.Port > 65535 {
+		p.add("server.port", "%d is not a port, expected 1-65535", c.Server.Port)
+	}
+	if limit := c.Server.RateLimit; limit.Requests < 0 {
+		p.add("server.rateLimit.requests", "must not be negative")
+	} else if limit.Requests > 0 && limit.Window == 0 {
+		p.add("server.rateLimit.window", "must be set when requests is")
+	}
+	if ws := c.Server.WebSocket; ws.SendBuffer < 0 {
+		p.add("server.websocket.sendBuffer", "must not be negative")
+	} else if buffer := cmp.Or(ws.SendBuffer, defaultSendBuffer); ws.HighWaterMark < 0 || ws.HighWaterMark > buffer {
+		p.add("server.websocket.highWaterMark", "%d is outside 0-%d, the send buffer", ws.HighWaterMark, buffer)
+	}
+	if policy := c.Server.WebSocket.SlowConsumer; !slices.Contains([]string{"", "resnapshot", "disconnect", "conflate"}, policy) {
+		p.add("server.websocket.slowConsumer", "unknown policy %q, expected resnapshot, disconnect or conflate", policy)
+	}
+	if level := strings.ToLower(c.Logging.Level); !slices.Contains([]string{"", "debug", "info", "warn", "warning", "error"}, level) {
+		p.add("logging.level", "unknown level %q, expected debug, info, warn or error", c.Logging.Level)
+	}
+
+	c.Integrations.Binance.validate(&p, "integrations.binance")
+
+	if t := c.Tracing; t.Enabled {
+		if !slices.Contains([]string{"", "otlp", "stdout"}, t.Exporter) {
+			p.add("tracing.exporter", "unknown exporter %q, expected otlp or stdout", t.Exporter)
+		}
+		if t.SampleRatio < 0 || t.SampleRatio > 1 {
+			p.add("tracing.sampleRatio", "%v is outside 0-1", t.SampleRatio)
+		}
+	}
+
+	switch c.Storage.Backend {
+	case "", "memory":
+	case "sqlite":
+		if c.Storage.Path == "" {
+			p.add("storage.path", "is required with the sqlite backend")
+		}
+	default:
+		p.add("storage.backend", "unknown backend %q, expected memory or sqlite", c.Storage.Backend)
+	}
+
+	if c.Alerts.Enabled {
+		c.Alerts.validateRules(&p)
+	}
+
+	c.checkSubscribed(&p)
+
+	if c.Verify.MaxMismatches < 0 {
+		p.add("verify.maxMismatches", "must not be negative")
+	}
+
+	negativeDurations(&p, "", reflect.ValueOf(*c))
+
+	return errors.Join(p...)
+}
+
+func (b BinanceConfig) validate(p *problems, key string) {
+	checkURL(p, key+".wsStreamUrl", b.WsStreamUrl, "ws", "wss")
+	checkURL(p, key+".restApiUrlV3", b.RestApiUrlV3, "http", "https")
+	if b.Redundancy.Enabled {
+		checkURL(p, key+".redundancy.secondaryWsStreamUrl", b.Redundancy.SecondaryWsStreamUrl, "ws", "wss")
+	}
+
+	if b.Snapshot.Limit < 0 || b.Snapshot.Limit > maxSnapshotLimit {
+		p.add(key+".snapshot.limit", "%d is outside 0-%d, where 0 means the default", b.Snapshot.Limit, maxSnapshotLimit)
+	}
+
+	if len(b.Symbols()) == 0 {
+		p.add(key+".subscriptions", "no enabled symbol")
+	}
+
+	seen := make(map[string]bool, len(b.Subscriptions))
+	for i, sub := range b.Subscriptions {
+		subKey := fmt.Sprintf("%s.subscriptions[%d]", key, i)
+		symbol := strings.ToUpper(strings.TrimSpace(sub.Symbol))
+
+		switch {
+		case symbol == "":
+			p.add(subKey+".symbol", "is required")
+		case !symbolPattern.MatchString(symbol):
+			p.add(subKey+".symbol", "%q is not a symbol, expected letters and digits only", sub.Symbol)
+		case seen[symbol]:
+			p.add(subKey+".symbol", "%s is listed more than once", symbol)
+		}
+		seen[symbol] = true
+
+		if sub.DepthLimit < 0 {
+			p.add(subKey+".depthLimit", "must not be negative")
+		}
+		if sub.SnapshotLimit < 0 || sub.SnapshotLimit > maxSnapshotLimit {
+			p.add(subKey+".snapshotLimit", "%d is outside 0-%d, where 0 means the default", sub.SnapshotLimit, maxSnapshotLimit)
+		}
+	}
+}
+
+// validateRules checks what the config can tell about alert rules. The rule
+// conditions themselves are checked by the alert engine.
+func (a AlertsConfig) validateRules(p *problems) {
+	if len(a.Rules) > 0 && !a.Webhook.Enabled {
+		p.add("alerts.rules", "configured alerts deliver to webhooks, which are not enabled")
+	}
+
+	seen := make(map[string]bool, len(a.Rules))
+	for i, rule := range a.Rules {
+		key := fmt.Sprintf("alerts.rules[%d]", i)
+		switch {
+		case rule.ID == "":
+			p.add(key+".id", "is required")
+		case seen[rule.ID]:
+			p.add(key+".id", "%s is listed more than once", rule.ID)
+		}
+		seen[rule.ID] = true
+
+		if strings.TrimSpace(rule.Symbol) == "" {
+			p.add(key+".symbol", "is required")
+		}
+		checkURL(p, key+".webhookUrl", rule.WebhookURL, "http", "https")
+	}
+}
+
+// checkSubscribed reports the Binance symbols an enabled feature reads that
+// are not subscribed, as their books would never fill.
+func (c *Config) checkSubscribed(p *problems) {
+	subscribed := c.Integrations.Binance.Symbols()
+	check := func(key, venue, symbol string) {
+		symbol = strings.ToUpper(strings.TrimSpace(symbol))
+		if (venue != "" && !strings.EqualFold(venue, "binance")) || symbol == "" {
+			return
+		}
+		if !slices.Contains(subscribed, symbol) {
+			p.add(key, "%s is not subscribed", symbol)
+		}
+	}
+	sources := func(key string, instruments []ConsolidatedInstrument) {
+		for i, inst := range instruments {
+			for j, src := range inst.Sources {
+				check(fmt.Sprintf("%s[%d].sources[%d].symbol", key, i, j), src.Venue, src.Symbol)
+			}
+		}
+	}
+	legs := func(key string, legs []SyntheticLeg) {
+		for i, leg := range legs {
+			check(fmt.Sprintf("%s[%d].symbol", key, i), leg.Venue, leg.Symbol)
+		}
+	}
+
+	if c.Consolidated.Enabled {
+		sources("consolidated.instruments", c.Consolidated.Instruments)
+	}
+	if c.Synthetic.Enabled {
+		for i, inst := range c.Synthetic.Instruments {
+			legs(fmt.Sprintf("synthetic.instruments[%d].legs", i), inst.Legs)
+		}
+	}
+	if c.Alerts.Enabled {
+		for i, rule := range c.Alerts.Rules {
+			check(fmt.Sprintf("alerts.rules[%d].symbol", i), "", rule.Symbol)
+		}
+	}
+	if c.Arbitrage.Enabled {
+		sources("arbitrage.instruments", c.Arbitrage.Instruments)
+		for i, tri := range c.Arbitrage.Triangles {
+			key := fmt.Sprintf("arbitrage.triangles[%d]", i)
+			check(key+".direct.symbol", tri.Direct.Venue, tri.Direct.Symbol)
+			legs(key+".legs", tri.Legs)
+		}
+	}
+}
+
+func checkURL(p *problems, key, raw string, schemes ...string) {
+	if raw == "" {
+		p.add(key, "is required")
+		return
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		p.add(key, "%q is not a URL", raw)
+		return
+	}
+	if !slices.Contains(schemes, u.Scheme) {
+		p.add(key, "scheme %q is not supported, expected %s", u.Scheme, strings.Join(schemes, " or "))
+	}
+}
+
+// negativeDurations reports every duration below zero, wherever it is.
+func negativeDurations(p *problems, key string, v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Struct:
+		t := v.Type()
+		for i := range t.NumField() {
+			name := t.Field(i).Tag.Get("mapstructure")
+			if name == "" {
+				continue
+			}
+			if key != "" {
+				name = key + "." + name
+			}
+			negativeDurations(p, name, v.Field(i))
+		}
+	case reflect.Slice:
+		for i := range v.Len() {
+			negativeDurations(p, fmt.Sprintf("%s[%d]", key, i), v.Index(i))
+		}
+	case reflect.Int64:
+		if v.Type() == durationType && time.Duration(v.Int()) < 0 {
+			p.add(key, "must not be negative")
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package config
+
+import (
+	"context"
+	"log/slog"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchSettle is how long Watch waits for a burst of writes to end.
+const watchSettle = 100 * time.Millisecond
+
+// Watch sends on changed when the file at path is written or replaced, until
+// ctx ends. Its directory is watched rather than the file, so editors that
+// save by renaming a new file over the old one are followed. A burst of events
+// is reported once, after it settles; a send is dropped while one is pending.
+func Watch(ctx context.Context, path string, changed chan<- struct{}) error {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	if err := watcher.Add(filepath.Dir(path)); err != nil {
+		watcher.Close()
+		return err
+	}
+
+	go func() {
+		defer watcher.Close()
+
+		var settle <-chan time.Time
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case ev, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if filepath.Clean(ev.Name) == path && ev.Has(fsnotify.Write|fsnotify.Create) {
+					settle = time.After(watchSettle)
+				}
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				slog.Warn("Config file watch error", "path", path, "error", err)
+			case <-settle:
+				settle = nil
+				select {
+				case changed <- struct{}{}:
+				default:
+				}
+			}
+		}
+	}()
+	return nil
+}
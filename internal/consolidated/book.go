@@ -0,0 +1,107 @@
+package consolidated
+
+import (
+	"sort"
+
+	"github.com/ChethiyaNishanath/market-data-hub/internal/domain/orderbook"
+)
+
+type VenueQuantity struct {
+	Venue    string  `json:"venue"`
+	Quantity float64 `json:"quantity"`
+}
+
+type Level struct {
+	Price    float64         `json:"price"`
+	Quantity float64         `json:"quantity"`
+	Venues   []VenueQuantity `json:"venues"`
+}
+
+type Book struct {
+	Instrument     string   `json:"instrument"`
+	Bids           []Level  `json:"bids"`
+	Asks           []Level  `json:"asks"`
+	Venues         []string `json:"venues"`
+	ExcludedVenues []string `json:"excludedVenues,omitempty"`
+	Timestamp      int64    `json:"timestamp"`
+}
+
+// VenueInput is one venue's current book together with its weighting.
+type VenueInput struct {
+	Venue  string
+	Book   *orderbook.OrderBook
+	Weight float64
+	FeeBps float64
+}
+
+// Merge aggregates venue books by fee-adjusted price. Bids are lowered and asks
+// raised by the venue's fee so a level reflects what a taker would actually pay.
+// A depth of zero or less keeps every level.
+func Merge(instrument string, inputs []VenueInput, depth int) Book {
+	bids := make(map[float64]*Level)
+	asks := make(map[float64]*Level)
+	venues := make([]string, 0, len(inputs))
+
+	for _, in := range inputs {
+		if in.Book == nil {
+			continue
+		}
+		venues = append(venues, in.Venue)
+
+		weight := in.Weight
+		if weight <= 0 {
+			weight = 1
+		}
+		fee := in.FeeBps / 10_000
+
+		for _, lvl := range orderbook.ParseLevels(in.Book.Bids) {
+			addLevel(bids, lvl.Price*(1-fee), lvl.Quantity*weight, in.Venue)
+		}
+		for _, lvl := range orderbook.ParseLevels(in.Book.Asks) {
+			addLevel(asks, lvl.Price*(1+fee), lvl.Quantity*weight, in.Venue)
+		}
+	}
+
+	return Book{
+		Instrument: instrument,
+		Bids:       sortLevels(bids, true, depth),
+		Asks:       sortLevels(asks, false, depth),
+		Venues:     venues,
+	}
+}
+
+func addLevel(levels map[float64]*Level, price, qty float64, venue string) {
+	lvl, ok := levels[price]
+	if !ok {
+		lvl = &Level{Price: price}
+		levels[price] = lvl
+	}
+
+	lvl.Quantity += qty
+	for i := range lvl.Venues {
+		if lvl.Venues[i].Venue == venue {
+			lvl.Venues[i].Quantity += qty
+			return
+		}
+	}
+	lvl.Venues = append(lvl.Venues, VenueQuantity{Venue: venue, Quantity: qty})
+}
+
+func sortLevels(levels map[float64]*Level, descending bool, depth int) []Level {
+	sorted := make([]Level, 0, len(levels))
+	for _, lvl := range levels {
+		sorted = append(sorted, *lvl)
+	}
+
+	sort.Slice(sorted, func(i, j int) bool {
+		if descending {
+			return sorted[i].Price > sorted[j].Price
+		}
+		return sorted[i].Price < sorted[j].Price
+	})
+
+	if depth > 0 && len(sorted) > depth {
+		sorted = sorted[:depth]
+	}
+	return sorted
+}
@@ -0,0 +1,234 @@
+package consolidated
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ChethiyaNishanath/market-data-hub/internal/bus"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/config"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/domain/exchange"
+)
+
+const (
+	ConsolidatedBookUpdate = "consolidatedBook"
+	TopicSuffix            = "@cbbo"
+
+	defaultPublishInterval = 250 * time.Millisecond
+	defaultDepth           = 50
+)
+
+// Engine keeps a consolidated book per configured instrument and republishes it,
+// throttled to PublishInterval, whenever one of its source books changes.
+type Engine struct {
+	cfg     config.ConsolidatedConfig
+	bus     bus.IBus
+	sources map[string]exchange.BookSource
+
+	mu       sync.RWMutex
+	books    map[string]Book
+	dirty    map[string]bool
+	watchers map[string]map[chan Book]struct{}
+}
+
+func NewEngine(cfg config.ConsolidatedConfig, eventBus bus.IBus, sources ...exchange.BookSource) *Engine {
+	if cfg.PublishInterval <= 0 {
+		cfg.PublishInterval = defaultPublishInterval
+	}
+	if cfg.Depth <= 0 {
+		cfg.Depth = defaultDepth
+	}
+
+	bySource := make(map[string]exchange.BookSource, len(sources))
+	for _, src := range sources {
+		bySource[strings.ToLower(src.Name())] = src
+	}
+
+	return &Engine{
+		cfg:      cfg,
+		bus:      eventBus,
+		sources:  bySource,
+		books:    make(map[string]Book),
+		dirty:    make(map[string]bool),
+		watchers: make(map[string]map[chan Book]struct{}),
+	}
+}
+
+// Topic is the bus and WebSocket topic a consolidated instrument is published on.
+func Topic(instrument string) string {
+	return strings.ToLower(instrument) + TopicSuffix
+}
+
+// Topics lists the topics of every configured instrument.
+func (e *Engine) Topics() []string {
+	topics := make([]string, 0, len(e.cfg.Instruments))
+	for _, inst := range e.cfg.Instruments {
+		topics = append(topics, Topic(inst.Name))
+	}
+	return topics
+}
+
+func (e *Engine) Start(ctx context.Context) {
+	for _, inst := range e.cfg.Instruments {
+		name := strings.ToUpper(inst.Name)
+		for _, src := range inst.Sources {
+			symbol := strings.ToLower(src.Symbol)
+			for _, topic := range []string{symbol + "@depth", symbol + "@depth.reset", symbol + "@connection"} {
+				e.bus.Subscribe(topic, func(bus.Event) {
+					e.markDirty(name)
+				})
+			}
+		}
+		e.markDirty(name)
+	}
+
+	ticker := time.NewTicker(e.cfg.PublishInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			e.publishDirty()
+		}
+	}
+}
+
+func (e *Engine) markDirty(instrument string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.dirty[instrument] = true
+}
+
+func (e *Engine) publishDirty() {
+	e.mu.Lock()
+	pending := e.dirty
+	e.dirty = make(map[string]bool)
+	e.mu.Unlock()
+
+	for _, inst := range e.cfg.Instruments {
+		name := strings.ToUpper(inst.Name)
+		if !pending[name] {
+			continue
+		}
+
+		book := e.build(inst)
+
+		e.mu.Lock()
+		e.books[name] = book
+		for ch := range e.watchers[name] {
+			offerLatest(ch, book)
+		}
+		e.mu.Unlock()
+
+		e.bus.Publish(ConsolidatedBookUpdate, Topic(name), book)
+	}
+}
+
+// build merges every synchronised source. Venues whose feed is not in sync are
+// listed as excluded rather than contributing stale levels.
+func (e *Engine) build(inst config.ConsolidatedInstrument) Book {
+	inputs := make([]VenueInput, 0, len(inst.Sources))
+	excluded := make([]string, 0)
+
+	for _, src := range inst.Sources {
+		source, ok := e.sources[strings.ToLower(src.Venue)]
+		if !ok {
+			slog.Warn("Consolidated source venue not configured", "instrument", inst.Name, "venue", src.Venue)
+			excluded = append(excluded, src.Venue)
+			continue
+		}
+
+		symbol := strings.ToUpper(src.Symbol)
+		if !source.IsSynchronized(symbol) {
+			excluded = append(excluded, src.Venue)
+			continue
+		}
+
+		book := source.GetOrderBook(symbol)
+		if book == nil {
+			excluded = append(excluded, src.Venue)
+			continue
+		}
+
+		inputs = append(inputs, VenueInput{
+			Venue:  src.Venue,
+			Book:   book,
+			Weight: src.Weight,
+			FeeBps: src.FeeBps,
+		})
+	}
+
+	book := Merge(strings.ToUpper(inst.Name), inputs, e.cfg.Depth)
+	book.ExcludedVenues = excluded
+	book.Timestamp = time.Now().UnixMilli()
+	return book
+}
+
+// Get returns the last published consolidated book for an instrument.
+func (e *Engine) Get(instrument string) (Book, bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	book, ok := e.books[strings.ToUpper(instrument)]
+	return book, ok
+}
+
+// Snapshot lets the WebSocket handler answer a subscribe with the current book.
+func (e *Engine) Snapshot(instrument string) (any, bool) {
+	return e.Get(instrument)
+}
+
+// Instruments lists the configured consolidated instrument names.
+func (e *Engine) Instruments() []string {
+	names := make([]string, 0, len(e.cfg.Instruments))
+	for _, inst := range e.cfg.Instruments {
+		names = append(names, strings.ToUpper(inst.Name))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Watch streams every published book for an instrument. Slow readers only see the
+// latest book. The returned function must be called to stop watching.
+func (e *Engine) Watch(instrument string) (<-chan Book, func()) {
+	name := strings.ToUpper(instrument)
+	ch := make(chan Book, 1)
+
+	e.mu.Lock()
+	if _, ok := e.watchers[name]; !ok {
+		e.watchers[name] = make(map[chan Book]struct{})
+	}
+	e.watchers[name][ch] = struct{}{}
+	if book, ok := e.books[name]; ok {
+		ch <- book
+	}
+	e.mu.Unlock()
+
+	return ch, func() {
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		delete(e.watchers[name], ch)
+	}
+}
+
+func offerLatest(ch chan Book, book Book) {
+	select {
+	case ch <- book:
+		return
+	default:
+	}
+
+	select {
+	case <-ch:
+	default:
+	}
+
+	select {
+	case ch <- book:
+	default:
+	}
+}
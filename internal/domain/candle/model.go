@@ -0,0 +1,18 @@
+package candle
+
+import "time"
+
+// Candle aggregates the trades of one interval. Interval is its label, e.g. 1m.
+type Candle struct {
+	Exchange    string    `json:"exchange"`
+	Symbol      string    `json:"symbol"`
+	Interval    string    `json:"interval"`
+	OpenTime    time.Time `json:"openTime"`
+	Open        float64   `json:"open"`
+	High        float64   `json:"high"`
+	Low         float64   `json:"low"`
+	Close       float64   `json:"close"`
+	Volume      float64   `json:"volume"`
+	QuoteVolume float64   `json:"quoteVolume"`
+	Trades      int       `json:"trades"`
+}
@@ -0,0 +1,14 @@
+package candle
+
+import "time"
+
+// Repository keeps aggregated candles per exchange, symbol and interval.
+type Repository interface {
+	// Upsert stores candles, replacing any with the same open time.
+	Upsert(candles ...Candle) error
+	// Range returns up to limit of the latest candles opened between from and to,
+	// oldest first. A zero from or to leaves that end open.
+	Range(exchange, symbol, interval string, from, to time.Time, limit int) ([]Candle, error)
+	// Prune drops candles opened before the given time.
+	Prune(before time.Time) error
+}
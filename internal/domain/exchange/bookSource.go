@@ -0,0 +1,37 @@
+package exchange
+
+import (
+	"context"
+
+	"github.com/ChethiyaNishanath/market-data-hub/internal/domain/orderbook"
+)
+
+// BookSource exposes the books a venue integration maintains.
+type BookSource interface {
+	Name() string
+	GetOrderBook(symbol string) *orderbook.OrderBook
+	IsSynchronized(symbol string) bool
+}
+
+// Resyncer is implemented by sources that can discard a local book they no longer
+// trust and rebuild it from a fresh snapshot.
+type Resyncer interface {
+	RequestResync(symbol string, reason string) bool
+}
+
+// SymbolLister is implemented by sources that know which symbols they maintain.
+type SymbolLister interface {
+	SubscribedSymbols() []string
+}
+
+// SnapshotSource is implemented by sources that can download the venue's own
+// depth snapshot, e.g. to check the local book against it.
+type SnapshotSource interface {
+	FetchSnapshot(ctx context.Context, symbol string) (*orderbook.OrderBook, error)
+}
+
+// DepthLimiter is implemented by sources that publish only the best levels of
+// some books.
+type DepthLimiter interface {
+	DepthLimit(symbol string) int
+}
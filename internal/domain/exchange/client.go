@@ -3,13 +3,17 @@ package exchange
 import "time"
 
 type Client struct {
-	URL          string
-	PingInterval time.Duration
+	URL              string
+	DialTimeout      time.Duration
+	PingInterval     time.Duration
+	StaleTimeout     time.Duration
+	MaxConnectionAge time.Duration
 }
 
 func New(url string) Client {
 	return Client{
 		URL:          url,
+		DialTimeout:  10 * time.Second,
 		PingInterval: 20 * time.Second,
 	}
 }
@@ -0,0 +1,157 @@
+package orderbook
+
+import (
+	"math"
+	"sort"
+	"strconv"
+)
+
+// Level is a parsed price level.
+type Level struct {
+	Price    float64 `json:"price"`
+	Quantity float64 `json:"quantity"`
+}
+
+// ParseLevels converts [price, quantity] string pairs, skipping malformed entries
+// and empty levels.
+func ParseLevels(levels [][]string) []Level {
+	parsed := make([]Level, 0, len(levels))
+	for _, lvl := range levels {
+		if len(lvl) < 2 {
+			continue
+		}
+
+		price, err := strconv.ParseFloat(lvl[0], 64)
+		if err != nil {
+			continue
+		}
+		qty, err := strconv.ParseFloat(lvl[1], 64)
+		if err != nil || qty <= 0 {
+			continue
+		}
+
+		parsed = append(parsed, Level{Price: price, Quantity: qty})
+	}
+	return parsed
+}
+
+// SortedBids returns bids best first (highest price).
+func (ob *OrderBook) SortedBids() []Level {
+	levels := ParseLevels(ob.Bids)
+	sort.Slice(levels, func(i, j int) bool { return levels[i].Price > levels[j].Price })
+	return levels
+}
+
+// SortedAsks returns asks best first (lowest price).
+func (ob *OrderBook) SortedAsks() []Level {
+	levels := ParseLevels(ob.Asks)
+	sort.Slice(levels, func(i, j int) bool { return levels[i].Price < levels[j].Price })
+	return levels
+}
+
+// Clone returns a deep copy so the caller can read it while the source keeps changing.
+func (ob *OrderBook) Clone() OrderBook {
+	return OrderBook{
+		LastUpdateID: ob.LastUpdateID,
+		Bids:         cloneLevels(ob.Bids),
+		Asks:         cloneLevels(ob.Asks),
+		Initialized:  ob.Initialized,
+		Provisional:  ob.Provisional,
+	}
+}
+
+func cloneLevels(levels [][]string) [][]string {
+	if levels == nil {
+		return nil
+	}
+
+	cpy := make([][]string, len(levels))
+	for i, lvl := range levels {
+		cpy[i] = append([]string(nil), lvl...)
+	}
+	return cpy
+}
+
+// BestLevels sorts levels by price, highest first for bids, and keeps depth of
+// them. Levels with an unparsable price sort last.
+func BestLevels(levels [][]string, depth int, bids bool) [][]string {
+	type priced struct {
+		price float64
+		level []string
+	}
+
+	parsed := make([]priced, 0, len(levels))
+	for _, lvl := range levels {
+		if len(lvl) < 2 {
+			continue
+		}
+		price, err := strconv.ParseFloat(lvl[0], 64)
+		if err != nil {
+			price = math.NaN()
+		}
+		parsed = append(parsed, priced{price, lvl})
+	}
+
+	sort.SliceStable(parsed, func(i, j int) bool {
+		a, b := parsed[i].price, parsed[j].price
+		if math.IsNaN(b) {
+			return !math.IsNaN(a)
+		}
+		if bids {
+			return a > b
+		}
+		return a < b
+	})
+
+	if depth > 0 && len(parsed) > depth {
+		parsed = parsed[:depth]
+	}
+	out := make([][]string, len(parsed))
+	for i, p := range parsed {
+		out[i] = p.level
+	}
+	return out
+}
+
+// Limit keeps the best depth levels of each side, best first. A depth of 0
+// leaves the book as it is.
+func (ob *OrderBook) Limit(depth int) {
+	if depth <= 0 {
+		return
+	}
+	ob.Bids = BestLevels(ob.Bids, depth, true)
+	ob.Asks = BestLevels(ob.Asks, depth, false)
+}
+
+// ChangedLevels lists the levels a delta needs to turn before into after: the
+// levels of after that are new or changed, in order, then the prices of before
+// that are gone, with a zero quantity.
+func ChangedLevels(before, after [][]string) [][]string {
+	old := make(map[string]string, len(before))
+	for _, lvl := range before {
+		if len(lvl) >= 2 {
+			old[lvl[0]] = lvl[1]
+		}
+	}
+
+	var changed [][]string
+	for _, lvl := range after {
+		if len(lvl) < 2 {
+			continue
+		}
+		if qty, ok := old[lvl[0]]; !ok || qty != lvl[1] {
+			changed = append(changed, []string{lvl[0], lvl[1]})
+		}
+		delete(old, lvl[0])
+	}
+	for _, lvl := range before {
+		if len(lvl) < 2 {
+			continue
+		}
+		if _, gone := old[lvl[0]]; gone {
+			changed = append(changed, []string{lvl[0], "0"})
+			delete(old, lvl[0])
+		}
+	}
+	return changed
+}
@@ -5,4 +5,8 @@ type OrderBook struct {
 	Bids         [][]string `json:"bids"`
 	Asks         [][]string `json:"asks"`
 	Initialized  bool       `json:"-"`
+
+	// Provisional marks a book restored from a checkpoint that has not yet been
+	// reconciled with the live stream.
+	Provisional bool `json:"provisional,omitempty"`
 }
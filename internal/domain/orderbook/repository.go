@@ -1,5 +1,7 @@
 package orderbook
 
+import "time"
+
 type Repository interface {
 	GetAll() map[string]*OrderBook
 	GetItem(symbol string) (*OrderBook, bool)
@@ -7,3 +9,10 @@ type Repository interface {
 	DeleteItem(symbol string)
 	Clear()
 }
+
+// CheckpointStore keeps the latest book per venue and symbol on durable storage
+// so a restart can resume from it instead of a full snapshot download.
+type CheckpointStore interface {
+	Save(venue, symbol string, book *OrderBook) error
+	Load(venue, symbol string) (book *OrderBook, savedAt time.Time, ok bool)
+}
@@ -0,0 +1,203 @@
+package orderbook
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Step is what to do with a delta, given the last update ID of the book.
+type Step int
+
+const (
+	// Skip means the book already holds the delta.
+	Skip Step = iota
+	// Apply means the delta continues the book.
+	Apply
+	// Gap means updates between the book and the delta are missing.
+	Gap
+)
+
+// Sequence classifies the delta covering update IDs first through final
+// against a book at last. This is the exchange's rule, applied alike to the
+// first delta after a snapshot and to the ones after it: drop a delta with
+// final <= last, apply one with first <= last+1, and treat anything later as
+// a gap. Overlapping deltas can be applied because levels carry absolute
+// quantities.
+func Sequence[ID ~int | ~int64](last, first, final ID) Step {
+	switch {
+	case final <= last:
+		return Skip
+	case first <= last+1:
+		return Apply
+	default:
+		return Gap
+	}
+}
+
+// LevelMutator is the part of a book a delta changes.
+type LevelMutator interface {
+	UpdateBid(price, qty string)
+	RemoveBid(price string)
+	UpdateAsk(price, qty string)
+	RemoveAsk(price string)
+}
+
+// ApplyLevels writes the [price, quantity] levels of a delta to m: a zero
+// quantity removes the level, any other sets it. Malformed levels are skipped
+// and reported together; the rest are still applied.
+func ApplyLevels(m LevelMutator, bids, asks [][]string) error {
+	var errs []error
+	for _, lvl := range bids {
+		errs = append(errs, applyLevel(lvl, m.UpdateBid, m.RemoveBid))
+	}
+	for _, lvl := range asks {
+		errs = append(errs, applyLevel(lvl, m.UpdateAsk, m.RemoveAsk))
+	}
+	return errors.Join(errs...)
+}
+
+func applyLevel(lvl []string, update func(price, qty string), remove func(price string)) error {
+	if len(lvl) < 2 {
+		return fmt.Errorf("level %v: want [price, quantity]", lvl)
+	}
+	qty, err := strconv.ParseFloat(lvl[1], 64)
+	if err != nil {
+		return fmt.Errorf("level %v: %w", lvl, err)
+	}
+
+	if qty == 0 {
+		remove(lvl[0])
+	} else {
+		update(lvl[0], lvl[1])
+	}
+	return nil
+}
+
+// Delta is a change of levels covering update IDs First through Final.
+type Delta struct {
+	First int64
+	Final int64
+	Bids  [][]string
+	Asks  [][]string
+}
+
+// Sequencer puts the deltas of a stream in order for a book. A delta that
+// arrives before the snapshot, or after a gap, is held until the deltas before
+// it arrive. If an update stays missing for longer than the gap timeout, or too
+// many deltas are held, the sequencer is out of sync and the book needs a new
+// snapshot. A Sequencer is not safe for concurrent use.
+type Sequencer struct {
+	gapTimeout time.Duration
+	maxPending int
+
+	last      int64
+	loaded    bool
+	pending   map[int64]Delta // keyed by First
+	gapSince  time.Time
+	outOfSync bool
+}
+
+func NewSequencer(gapTimeout time.Duration, maxPending int) *Sequencer {
+	return &Sequencer{
+		gapTimeout: gapTimeout,
+		maxPending: maxPending,
+		pending:    make(map[int64]Delta),
+	}
+}
+
+// Last is the update ID of the last delta returned, or of the snapshot.
+func (s *Sequencer) Last() int64 {
+	return s.last
+}
+
+// Loaded reports whether a snapshot has been loaded since the start or the
+// last Clear.
+func (s *Sequencer) Loaded() bool {
+	return s.loaded
+}
+
+// OutOfSync reports whether the book needs a new snapshot.
+func (s *Sequencer) OutOfSync() bool {
+	return s.outOfSync
+}
+
+// Load starts over from a snapshot at last. It returns the held deltas that
+// continue the snapshot, in order.
+func (s *Sequencer) Load(last int64, now time.Time) []Delta {
+	s.last = last
+	s.loaded = true
+	s.outOfSync = false
+	s.gapSince = time.Time{}
+	return s.drain(now)
+}
+
+// Clear forgets the snapshot. Deltas are held until the next Load.
+func (s *Sequencer) Clear() {
+	s.loaded = false
+	s.gapSince = time.Time{}
+}
+
+// Push adds a delta. It returns the deltas that can be applied now, in order:
+// none if d is stale or has to wait for the snapshot or a missing update.
+func (s *Sequencer) Push(d Delta, now time.Time) []Delta {
+	if s.loaded && Sequence(s.last, d.First, d.Final) == Skip {
+		return nil
+	}
+
+	if len(s.pending) >= s.maxPending {
+		clear(s.pending)
+		s.outOfSync = true
+		return nil
+	}
+	s.pending[d.First] = d
+	if !s.loaded {
+		return nil
+	}
+	return s.drain(now)
+}
+
+// Check marks the sequencer out of sync once an update has been missing for
+// longer than the gap timeout, and reports whether it is.
+func (s *Sequencer) Check(now time.Time) bool {
+	if s.loaded && !s.gapSince.IsZero() && now.Sub(s.gapSince) > s.gapTimeout {
+		s.outOfSync = true
+	}
+	return s.outOfSync
+}
+
+// drain takes the held deltas for as long as they continue the book.
+func (s *Sequencer) drain(now time.Time) []Delta {
+	var ready []Delta
+	for {
+		next, ok := s.next()
+		if !ok {
+			break
+		}
+		delete(s.pending, next.First)
+		ready = append(ready, next)
+		s.last = next.Final
+	}
+
+	if len(s.pending) == 0 {
+		s.gapSince = time.Time{}
+	} else if s.gapSince.IsZero() {
+		s.gapSince = now
+	}
+	return ready
+}
+
+// next finds the held delta that continues the book, dropping the ones the
+// book has already passed.
+func (s *Sequencer) next() (Delta, bool) {
+	for first, d := range s.pending {
+		switch Sequence(s.last, d.First, d.Final) {
+		case Skip:
+			delete(s.pending, first)
+		case Apply:
+			return d, true
+		}
+	}
+	return Delta{}, false
+}
@@ -1,6 +1,8 @@
 package subscription
 
 import (
+	"context"
+
 	"github.com/coder/websocket"
 )
 
@@ -10,6 +12,8 @@ type ClientConnectionManager interface {
 	Subscribe(client Client, topic string)
 	Unsubscribe(client Client, topic string)
 	Broadcast(topic string, msg any)
+	BroadcastContext(ctx context.Context, topic string, msg any)
 	GetClient(conn *websocket.Conn) Client
 	GetClientByID(id string) Client
+	Clients() []Client
 }
@@ -2,6 +2,7 @@ package subscription
 
 import (
 	"context"
+	"time"
 
 	"github.com/coder/websocket"
 )
@@ -12,10 +13,34 @@ type Client interface {
 
 	AddTopic(topic string)
 	RemoveTopic(topic string)
+	Topics() []string
 
 	Send(data []byte)
+	SendContext(ctx context.Context, data []byte)
+	// SendTopic queues a push of topic, so a slow client's pushes can be
+	// conflated or replaced by the topic's current state.
+	SendTopic(ctx context.Context, topic string, data []byte)
 	Close(reason string) error
 
+	Stats() ClientStats
+
 	ReadPump(ctx context.Context, m ClientConnectionManager)
 	WritePump(ctx context.Context)
 }
+
+// ClientStats describe the outbound queue of a client. Lag is how long the
+// last written message waited in the queue.
+type ClientStats struct {
+	ID            string    `json:"id"`
+	ConnectedAt   time.Time `json:"connectedAt"`
+	Topics        []string  `json:"topics"`
+	Queued        int       `json:"queued"`
+	PeakQueued    int       `json:"peakQueued"`
+	HighWaterMark int       `json:"highWaterMark"`
+	Slow          bool      `json:"slow"`
+	LagMs         int64     `json:"lagMs"`
+	Sent          uint64    `json:"sent"`
+	Dropped       uint64    `json:"dropped"`
+	Conflated     uint64    `json:"conflated"`
+	Resyncs       uint64    `json:"resyncs"`
+}
@@ -0,0 +1,14 @@
+package trade
+
+import "time"
+
+// Trade is an executed trade. Side is the taker's side, buy or sell.
+type Trade struct {
+	Exchange string    `json:"exchange"`
+	Symbol   string    `json:"symbol"`
+	ID       int64     `json:"id"`
+	Price    float64   `json:"price"`
+	Quantity float64   `json:"quantity"`
+	Side     string    `json:"side"`
+	Time     time.Time `json:"time"`
+}
@@ -0,0 +1,13 @@
+package trade
+
+import "time"
+
+// Repository keeps a bounded history of trades per exchange and symbol.
+type Repository interface {
+	Add(trades ...Trade) error
+	// Recent returns up to limit of the latest trades between from and to, oldest
+	// first. A zero from or to leaves that end open.
+	Recent(exchange, symbol string, from, to time.Time, limit int) ([]Trade, error)
+	// Prune drops trades older than before and keeps at most keep per symbol.
+	Prune(before time.Time, keep int) error
+}
@@ -3,5 +3,7 @@ package binance
 const (
 	OrderBookReset  = "orderbookReset"
 	OrderBookUpdate = "depthUpdate"
+	ConnectionState = "connectionState"
+	Trade           = "trade"
 )
 //FEEDBACK : no need to have multiple files to define different messages. move it to one called message.go or model.go
\ No newline at end of file
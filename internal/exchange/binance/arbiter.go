@@ -0,0 +1,248 @@
+package binance
+
+import (
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ChethiyaNishanath/market-data-hub/internal/health"
+)
+
+const (
+	PrimaryLeg   = "A"
+	SecondaryLeg = "B"
+
+	defaultGapFillTimeout = 500 * time.Millisecond
+
+	// latencySmoothing is the weight of the newest sample in the per-leg latency average.
+	latencySmoothing = 0.1
+	// leaderWindow is the number of recent update IDs used to pick the leading leg.
+	leaderWindow = 100
+)
+
+type legStats struct {
+	url          string
+	connected    bool
+	firstArrival int
+	duplicates   int
+	avgLatencyMs float64
+	lastMessage  time.Time
+	recentWins   []bool
+}
+
+// FeedArbiter merges two independent upstream connections for one symbol. Every
+// update ID is forwarded once, from whichever leg delivers it first. When one leg
+// skips ahead it waits up to gapTimeout for the other leg to fill the hole before
+// forwarding anyway and letting the book consumer fall back to a snapshot.
+type FeedArbiter struct {
+	symbol     string
+	out        chan<- DepthUpdateMessage
+	gapTimeout time.Duration
+	now        func() time.Time
+
+	mu            sync.Mutex
+	legs          map[string]*legStats
+	lastForwarded int
+	pending       map[int]DepthUpdateMessage
+	gapTimer      *time.Timer
+}
+
+func NewFeedArbiter(symbol string, urls map[string]string, out chan<- DepthUpdateMessage, gapTimeout time.Duration) *FeedArbiter {
+	if gapTimeout <= 0 {
+		gapTimeout = defaultGapFillTimeout
+	}
+
+	legs := make(map[string]*legStats, len(urls))
+	for name, url := range urls {
+		legs[name] = &legStats{url: url}
+	}
+
+	return &FeedArbiter{
+		symbol:     symbol,
+		out:        out,
+		gapTimeout: gapTimeout,
+		now:        time.Now,
+		legs:       legs,
+		pending:    make(map[int]DepthUpdateMessage),
+	}
+}
+
+// Offer is called by each leg for every depth update it receives.
+func (a *FeedArbiter) Offer(leg string, update DepthUpdateMessage) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := a.now()
+	stats := a.legs[leg]
+	stats.lastMessage = now
+	if update.EventTime > 0 {
+		latency := float64(now.UnixMilli() - int64(update.EventTime))
+		if stats.avgLatencyMs == 0 {
+			stats.avgLatencyMs = latency
+		} else {
+			stats.avgLatencyMs += latencySmoothing * (latency - stats.avgLatencyMs)
+		}
+	}
+
+	_, isPending := a.pending[update.FirstUpdateEventID]
+	if update.FinalUpdateEventID <= a.lastForwarded || isPending {
+		stats.duplicates++
+		a.recordWin(leg, false)
+		return
+	}
+
+	stats.firstArrival++
+	a.recordWin(leg, true)
+
+	if a.lastForwarded == 0 || update.FirstUpdateEventID <= a.lastForwarded+1 {
+		a.forward(update)
+		a.drainPending()
+		return
+	}
+
+	a.pending[update.FirstUpdateEventID] = update
+	if a.gapTimer == nil {
+		a.gapTimer = time.AfterFunc(a.gapTimeout, a.flushGap)
+	}
+}
+
+func (a *FeedArbiter) recordWin(leg string, won bool) {
+	stats := a.legs[leg]
+	stats.recentWins = append(stats.recentWins, won)
+	if len(stats.recentWins) > leaderWindow {
+		stats.recentWins = stats.recentWins[1:]
+	}
+}
+
+func (a *FeedArbiter) forward(update DepthUpdateMessage) {
+	a.lastForwarded = update.FinalUpdateEventID
+
+	select {
+	case a.out <- update:
+	default:
+		slog.Warn("Dropping depth update", "symbol", a.symbol)
+	}
+}
+
+// drainPending forwards held updates that now connect to the last forwarded ID.
+func (a *FeedArbiter) drainPending() {
+	for {
+		found := false
+		for first, update := range a.pending {
+			if update.FinalUpdateEventID <= a.lastForwarded {
+				delete(a.pending, first)
+				continue
+			}
+			if first <= a.lastForwarded+1 {
+				delete(a.pending, first)
+				a.forward(update)
+				found = true
+				break
+			}
+		}
+		if !found {
+			break
+		}
+	}
+
+	if len(a.pending) == 0 && a.gapTimer != nil {
+		a.gapTimer.Stop()
+		a.gapTimer = nil
+	}
+}
+
+// flushGap gives up on filling the gap and forwards whatever is held, in order.
+func (a *FeedArbiter) flushGap() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.gapTimer = nil
+	if len(a.pending) == 0 {
+		return
+	}
+
+	slog.Warn("Neither feed leg filled the gap", "symbol", a.symbol, "lastForwarded", a.lastForwarded)
+
+	ids := make([]int, 0, len(a.pending))
+	for first := range a.pending {
+		ids = append(ids, first)
+	}
+	sort.Ints(ids)
+
+	for _, first := range ids {
+		update := a.pending[first]
+		delete(a.pending, first)
+		if update.FinalUpdateEventID > a.lastForwarded {
+			a.forward(update)
+		}
+	}
+}
+
+func (a *FeedArbiter) SetConnected(leg string, connected bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.legs[leg].connected = connected
+}
+
+func (a *FeedArbiter) AnyConnected() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for _, stats := range a.legs {
+		if stats.connected {
+			return true
+		}
+	}
+	return false
+}
+
+// leader is the connected leg that delivered most of the recent update IDs first.
+func (a *FeedArbiter) leader() string {
+	leader := ""
+	best := -1
+	for name, stats := range a.legs {
+		if !stats.connected {
+			continue
+		}
+		wins := 0
+		for _, won := range stats.recentWins {
+			if won {
+				wins++
+			}
+		}
+		if wins > best || (wins == best && name < leader) {
+			leader = name
+			best = wins
+		}
+	}
+	return leader
+}
+
+func (a *FeedArbiter) Status() []health.FeedStatus {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	leader := a.leader()
+	now := a.now()
+
+	feeds := make([]health.FeedStatus, 0, len(a.legs))
+	for name, stats := range a.legs {
+		feed := health.FeedStatus{
+			Leg:           name,
+			URL:           stats.url,
+			Connected:     stats.connected,
+			Leading:       name == leader,
+			FirstArrivals: stats.firstArrival,
+			Duplicates:    stats.duplicates,
+			AvgLatencyMs:  stats.avgLatencyMs,
+		}
+		if !stats.lastMessage.IsZero() {
+			feed.LastMessageAgeMs = now.Sub(stats.lastMessage).Milliseconds()
+		}
+		feeds = append(feeds, feed)
+	}
+
+	sort.Slice(feeds, func(i, j int) bool { return feeds[i].Leg < feeds[j].Leg })
+	return feeds
+}
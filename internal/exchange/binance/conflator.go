@@ -0,0 +1,139 @@
+package binance
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// conflator merges the depth updates of one symbol published within an
+// interval into a single update covering all of their IDs, so slow clients
+// get fewer, larger messages that still chain U to the previous u.
+type conflator struct {
+	interval time.Duration
+	emit     func(ctx context.Context, ev DepthUpdateEvent)
+
+	mu      sync.Mutex
+	ctx     context.Context
+	pending []DepthUpdateEvent
+	flushed int
+	timer   *time.Timer
+}
+
+func newConflator(interval time.Duration, emit func(ctx context.Context, ev DepthUpdateEvent)) *conflator {
+	return &conflator{interval: interval, emit: emit}
+}
+
+// setInterval changes the window from the next flush on. With 0 updates are
+// passed on as they come once the pending window is flushed.
+func (c *conflator) setInterval(interval time.Duration) {
+	c.mu.Lock()
+	c.interval = interval
+	c.mu.Unlock()
+}
+
+// add queues ev for the next flush. Only a run of updates chaining U to the
+// previous u is merged: an update that does not continue the pending run
+// flushes it and starts a new one, so a gap or a reordering by the bus stays
+// visible to clients. Updates the bus delivers after their window was flushed
+// are passed on alone rather than merged out of order.
+func (c *conflator) add(ctx context.Context, ev DepthUpdateEvent) {
+	c.mu.Lock()
+	var broken []DepthUpdateEvent
+	var brokenCtx context.Context
+	if n := len(c.pending); n > 0 && ev.FirstUpdateEventID != c.pending[n-1].FinalUpdateEventID+1 {
+		broken, brokenCtx = c.take()
+	}
+
+	if ev.FinalUpdateEventID <= c.flushed || (c.interval <= 0 && len(c.pending) == 0) {
+		c.mu.Unlock()
+		c.emitRun(brokenCtx, broken)
+		c.emit(ctx, ev)
+		return
+	}
+	if len(c.pending) == 0 {
+		c.ctx = ctx
+	}
+	c.pending = append(c.pending, ev)
+	if c.timer == nil {
+		c.timer = time.AfterFunc(c.interval, c.flush)
+	}
+	c.mu.Unlock()
+
+	c.emitRun(brokenCtx, broken)
+}
+
+func (c *conflator) flush() {
+	c.mu.Lock()
+	pending, ctx := c.take()
+	c.mu.Unlock()
+
+	c.emitRun(ctx, pending)
+}
+
+// take removes the pending run and stops its timer. The caller holds mu.
+func (c *conflator) take() ([]DepthUpdateEvent, context.Context) {
+	pending, ctx := c.pending, c.ctx
+	if c.timer != nil {
+		c.timer.Stop()
+	}
+	c.pending, c.ctx, c.timer = nil, nil, nil
+	if len(pending) > 0 {
+		c.flushed = max(c.flushed, pending[len(pending)-1].FinalUpdateEventID)
+	}
+	return pending, ctx
+}
+
+func (c *conflator) emitRun(ctx context.Context, run []DepthUpdateEvent) {
+	if len(run) > 0 {
+		c.emit(ctx, mergeDepthUpdates(run))
+	}
+}
+
+// mergeDepthUpdates applies a run of contiguous events in order, so a level
+// changed several times keeps its last quantity.
+func mergeDepthUpdates(events []DepthUpdateEvent) DepthUpdateEvent {
+	if len(events) == 1 {
+		return events[0]
+	}
+
+	merged := DepthUpdateEvent{
+		EventType:          events[0].EventType,
+		Symbol:             events[0].Symbol,
+		FirstUpdateEventID: events[0].FirstUpdateEventID,
+		FinalUpdateEventID: events[len(events)-1].FinalUpdateEventID,
+	}
+	var bids, asks levelMerge
+	for _, ev := range events {
+		merged.EventTime = max(merged.EventTime, ev.EventTime)
+		bids.add(ev.BidsToUpdated)
+		asks.add(ev.AsksToUpdated)
+	}
+	merged.BidsToUpdated = bids.levels
+	merged.AsksToUpdated = asks.levels
+	return merged
+}
+
+// levelMerge keeps one entry per price, in the order prices first appeared.
+type levelMerge struct {
+	index  map[string]int
+	levels [][]string
+}
+
+func (m *levelMerge) add(levels [][]string) {
+	if m.index == nil {
+		m.index = make(map[string]int)
+		m.levels = make([][]string, 0, len(levels))
+	}
+	for _, lvl := range levels {
+		if len(lvl) < 2 {
+			continue
+		}
+		if i, ok := m.index[lvl[0]]; ok {
+			m.levels[i] = lvl
+			continue
+		}
+		m.index[lvl[0]] = len(m.levels)
+		m.levels = append(m.levels, lvl)
+	}
+}
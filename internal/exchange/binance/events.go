@@ -10,6 +10,25 @@ type DepthUpdateEvent struct {
 	FinalUpdateEventID int        `json:"u"`
 	BidsToUpdated      [][]string `json:"b"`
 	AsksToUpdated      [][]string `json:"a"`
+
+	// published holds the levels clients of a depth-limited symbol get
+	// instead of the delta. Engines on the bus still see the full delta.
+	published *publishedLevels
+}
+
+// publishedLevels are the changes a delta made to the best levels a
+// depth-limited symbol publishes.
+type publishedLevels struct {
+	bids, asks [][]string
+}
+
+// forClients returns the update as WebSocket clients get it.
+func (e DepthUpdateEvent) forClients() DepthUpdateEvent {
+	if e.published != nil {
+		e.BidsToUpdated, e.AsksToUpdated = e.published.bids, e.published.asks
+		e.published = nil
+	}
+	return e
 }
 
 type OrderBookResetEvent struct {
@@ -18,4 +37,24 @@ type OrderBookResetEvent struct {
 	Reason    string              `json:"reason"`
 	Timestamp int64               `json:"timestamp"`
 }
+
+// TradeEvent is published on <symbol>@trade. Side is the taker's side.
+type TradeEvent struct {
+	Symbol    string `json:"symbol"`
+	TradeID   int64  `json:"tradeId"`
+	Price     string `json:"price"`
+	Quantity  string `json:"quantity"`
+	Side      string `json:"side"`
+	TradeTime int64  `json:"tradeTime"`
+	EventTime int64  `json:"eventTime"`
+}
+
+type ConnectionStateEvent struct {
+	Exchange  string `json:"exchange"`
+	Symbol    string `json:"symbol"`
+	Leg       string `json:"leg,omitempty"`
+	State     string `json:"state"`
+	Reason    string `json:"reason,omitempty"`
+	Timestamp int64  `json:"timestamp"`
+}
 //FEEDBACK : no need to have multiple files to define different messages. move it to one called message.go or model.go
\ No newline at end of file
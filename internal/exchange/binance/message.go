@@ -1,6 +1,10 @@
 package binance
 
-import "encoding/json"
+import (
+	"encoding/json"
+
+	"go.opentelemetry.io/otel/trace"
+)
 
 type DepthUpdateMessage struct {
 	EventTime          int        `json:"E"`
@@ -10,6 +14,19 @@ type DepthUpdateMessage struct {
 	EventType          string     `json:"e"`
 	BidsToUpdated      [][]string `json:"b"`
 	AsksToUpdated      [][]string `json:"a"`
+
+	SpanContext trace.SpanContext `json:"-"`
+}
+
+type TradeMessage struct {
+	EventType    string `json:"e"`
+	EventTime    int64  `json:"E"`
+	Symbol       string `json:"s"`
+	TradeID      int64  `json:"t"`
+	Price        string `json:"p"`
+	Quantity     string `json:"q"`
+	TradeTime    int64  `json:"T"`
+	BuyerIsMaker bool   `json:"m"`
 }
 
 type OrderBookDepthUpdateStreamMessage struct {
@@ -19,16 +36,48 @@ type OrderBookDepthUpdateStreamMessage struct {
 	Asks         [][]string `json:"asks"`
 }
 
+// Error codes of failed WebSocket replies. Clients should branch on these
+// rather than on the error text, which may change.
+const (
+	CodeInvalidRequest   = "invalid_request"
+	CodeUnknownMethod    = "unknown_method"
+	CodeInvalidParams    = "invalid_params"
+	CodeInvalidTopic     = "invalid_topic"
+	CodeUnsupportedEvent = "unsupported_event"
+	CodeUnknownSymbol    = "unknown_symbol"
+	CodeNotFound         = "not_found"
+	CodeInternal         = "internal"
+)
+
+// ErrorCodes lists every code a failed reply can carry.
+var ErrorCodes = []string{
+	CodeInvalidRequest,
+	CodeUnknownMethod,
+	CodeInvalidParams,
+	CodeInvalidTopic,
+	CodeUnsupportedEvent,
+	CodeUnknownSymbol,
+	CodeNotFound,
+	CodeInternal,
+}
+
+// WSRequest is a request from a WebSocket client. ID, a string or a number, is
+// echoed in the reply so clients can match replies to requests.
 type WSRequest struct {
+	ID     json.RawMessage `json:"id,omitempty"`
 	Method string          `json:"method"`
 	Params json.RawMessage `json:"params"`
 }
 
+// WSMessage is a reply or a push to a WebSocket client. Only replies carry an
+// ID; failed replies carry a Code from ErrorCodes and a human-readable Error.
 type WSMessage struct {
-	Method  string `json:"method,omitempty"`
-	Success bool   `json:"success,omitempty"`
-	Error   string `json:"error,omitempty"`
-	Topic   string `json:"topic,omitempty"`
-	Data    any    `json:"data,omitempty"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Success bool            `json:"success,omitempty"`
+	Code    string          `json:"code,omitempty"`
+	Error   string          `json:"error,omitempty"`
+	Topic   string          `json:"topic,omitempty"`
+	Data    any             `json:"data,omitempty"`
 }
 //FEEDBACK : no need to have multiple files to define different messages. move it to one called message.go or model.go
\ No newline at end of file
@@ -53,13 +53,15 @@ func (s *OrderBookSnapshot) RemoveAsk(price string) {
 	}
 }
 
+// ToOrderBook returns a deep copy, safe to hand to readers on other goroutines.
 func (s *OrderBookSnapshot) ToOrderBook() orderbook.OrderBook {
-	return orderbook.OrderBook{
+	ob := orderbook.OrderBook{
 		LastUpdateID: s.LastUpdateID,
 		Bids:         s.Bids,
 		Asks:         s.Asks,
 		Initialized:  s.Initialized,
 	}
+	return ob.Clone()
 }
 
 func (s *OrderBookSnapshot) ToSnapshot() *OrderBookSnapshot {
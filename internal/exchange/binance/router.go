@@ -16,6 +16,8 @@ type Router struct {
 	Routes map[string]HandlerFunc
 }
 
+type requestIDKey struct{}
+
 func NewRouter() *Router {
 	return &Router{
 		Routes: make(map[string]HandlerFunc),
@@ -26,7 +28,11 @@ func (r *Router) Handle(action string, handler HandlerFunc) {
 	r.Routes[action] = handler
 }
 
+// Dispatch runs the handler of msg.Method. The request's ID travels in ctx, so
+// replies written with Reply carry it.
 func (r *Router) Dispatch(ctx context.Context, conn *websocket.Conn, msg WSRequest) {
+	ctx = context.WithValue(ctx, requestIDKey{}, msg.ID)
+
 	handler, ok := r.Routes[strings.ToLower(msg.Method)]
 	if !ok {
 		slog.Warn("Unknown WebSocket action", "action", msg.Method)
@@ -34,10 +40,11 @@ func (r *Router) Dispatch(ctx context.Context, conn *websocket.Conn, msg WSReque
 		wsmsg := WSMessage{
 			Method:  msg.Method,
 			Success: false,
+			Code:    CodeUnknownMethod,
 			Error:   "unknown action",
 		}
 
-		err := wsInterface.WriteJSON(ctx, conn, wsmsg)
+		err := Reply(ctx, conn, wsmsg)
 		if err != nil {
 			return
 		}
@@ -45,3 +52,15 @@ func (r *Router) Dispatch(ctx context.Context, conn *websocket.Conn, msg WSReque
 	}
 	handler(ctx, conn, msg.Params)
 }
+
+// RequestID is the ID of the request handled in ctx, nil when it had none.
+func RequestID(ctx context.Context) json.RawMessage {
+	id, _ := ctx.Value(requestIDKey{}).(json.RawMessage)
+	return id
+}
+
+// Reply writes msg as the reply to the request handled in ctx, with its ID.
+func Reply(ctx context.Context, conn *websocket.Conn, msg WSMessage) error {
+	msg.ID = RequestID(ctx)
+	return wsInterface.WriteJSON(ctx, conn, msg)
+}
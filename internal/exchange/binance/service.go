@@ -5,7 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
-	"strconv"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -15,18 +15,47 @@ import (
 	"github.com/ChethiyaNishanath/market-data-hub/internal/domain/exchange"
 	"github.com/ChethiyaNishanath/market-data-hub/internal/domain/orderbook"
 	"github.com/ChethiyaNishanath/market-data-hub/internal/domain/subscription"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/health"
 	wsInterface "github.com/ChethiyaNishanath/market-data-hub/internal/interfaces/clients/websocket"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/retry"
 	"github.com/ChethiyaNishanath/market-data-hub/internal/store/memory"
 	"github.com/coder/websocket"
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/ChethiyaNishanath/market-data-hub/internal/exchange/binance")
+
+const ExchangeName = "binance"
+
+const (
+	// Binance closes stream connections after 24 hours, so reconnect shortly before.
+	defaultMaxConnectionAge = 23*time.Hour + 50*time.Minute
+	defaultStaleTimeout     = 30 * time.Second
 )
 
 type Service struct {
 	ctx           context.Context
 	bus           bus.IBus
+	symbolsMu     sync.RWMutex
 	Symbols       map[string]*SymbolState
 	config        config.BinanceConfig
 	clientConnMgr subscription.ClientConnectionManager
+	fetcher       *SnapshotFetcher
+	startedAt     time.Time
+
+	// subscriptions replaces config.Subscriptions once the service runs, as
+	// Reconfigure changes it. runCtx is the context symbols added later run in,
+	// and conflators holds one per symbol whose topics are forwarded.
+	subsMu        sync.RWMutex
+	subscriptions []config.SymbolConfig
+	runCtx        context.Context
+	conflators    map[string]*conflator
+
+	checkpoints      orderbook.CheckpointStore
+	checkpointMaxAge time.Duration
 }
 
 type SubscribeAck struct {
@@ -40,49 +69,210 @@ func NewService(ctx context.Context, bus bus.IBus, connMgr subscription.ClientCo
 		ctx:           ctx,
 		bus:           bus,
 		config:        cfg,
+		subscriptions: slices.Clone(cfg.Subscriptions),
 		clientConnMgr: connMgr,
+		fetcher:       NewSnapshotFetcher(cfg),
+		startedAt:     time.Now(),
 	}
 }
 
 func (s *Service) Start(ctx context.Context) {
 	s.RegisterEventSubscribers(s.config, s.clientConnMgr, s.bus) //
 
-	symbols := strings.Split(s.config.Subscriptions, ",")
+	validSymbols := s.SubscribedSymbols()
+
+	states := make(map[string]*SymbolState, len(validSymbols))
+	contexts := make(map[string]context.Context, len(validSymbols))
+	for _, symbol := range validSymbols {
+		states[symbol], contexts[symbol] = s.newSymbolState(ctx, symbol)
+	}
 
-	s.Symbols = make(map[string]*SymbolState)
+	s.symbolsMu.Lock()
+	s.Symbols = states
+	s.runCtx = ctx
+	s.symbolsMu.Unlock()
 
 	wg := &sync.WaitGroup{}
-	wg.Add(len(symbols))
-	validSymbols := make([]string, 0)
-
-	for _, sym := range symbols {
-		symbol := strings.TrimSpace(sym)
-		symbol = strings.ToUpper(symbol)
-		if symbol != "" {
-			validSymbols = append(validSymbols, symbol)
-		}
-	}
+	wg.Add(len(validSymbols))
 
 	for _, symbol := range validSymbols {
-		st := NewMarketState()
-		s.Symbols[symbol] = st
-		go s.streamDepthUpdates(ctx, symbol, st.UpdateCh, st.SnapshotReady, wg)
+		readyCh := make(chan struct{})
+		readyOnce := sync.Once{}
+		go func() {
+			select {
+			case <-readyCh:
+			case <-ctx.Done():
+			}
+			wg.Done()
+		}()
+
+		go s.streamDepthUpdates(contexts[symbol], symbol, states[symbol], func() {
+			readyOnce.Do(func() { close(readyCh) })
+		})
 	}
 
-	slog.Info("Waiting for all WebSocket connections to be ready", "symbols", s.config.Subscriptions)
+	slog.Info("Waiting for all WebSocket connections to be ready", "symbols", validSymbols)
 	wg.Wait()
 	slog.Info("All WebSocket connections ready, starting snapshot fetches")
 
 	for _, symbol := range validSymbols {
-		st := s.Symbols[symbol]
-		go s.initializeSymbol(ctx, symbol, st)
+		go s.initializeSymbol(contexts[symbol], symbol, states[symbol])
+	}
+}
+
+// newSymbolState prepares the state of a symbol and the context its stream
+// and book run in, which ends when the symbol is removed.
+func (s *Service) newSymbolState(ctx context.Context, symbol string) (*SymbolState, context.Context) {
+	st := NewMarketState()
+	if urls := s.feedURLs(); len(urls) > 1 {
+		st.arbiter = NewFeedArbiter(symbol, urls, st.UpdateCh, s.config.Redundancy.GapFillTimeout)
+	}
+	ctx, st.cancel = context.WithCancel(ctx)
+	return st, ctx
+}
+
+// Reconfigure applies a new subscription list to the running service. Symbols
+// no longer enabled are stopped and their books dropped, new ones are streamed
+// and synchronised without disturbing the others, and the depth, snapshot and
+// conflation settings of the rest apply from their next update or snapshot.
+// It returns the symbols added and removed.
+func (s *Service) Reconfigure(subs []config.SymbolConfig) (added, removed []string) {
+	before := s.SubscribedSymbols()
+
+	s.subsMu.Lock()
+	s.subscriptions = slices.Clone(subs)
+	s.subsMu.Unlock()
+	s.fetcher.SetLimits(subs)
+
+	after := s.SubscribedSymbols()
+	for _, symbol := range before {
+		if !slices.Contains(after, symbol) {
+			s.removeSymbol(symbol)
+			removed = append(removed, symbol)
+		}
+	}
+	for _, symbol := range after {
+		if !slices.Contains(before, symbol) {
+			s.addSymbol(symbol)
+			added = append(added, symbol)
+		}
+	}
+
+	current := s.subscription()
+	s.symbolsMu.RLock()
+	for symbol, depth := range s.conflators {
+		sub, _ := current.Symbol(symbol)
+		depth.setInterval(sub.Conflation)
+	}
+	s.symbolsMu.RUnlock()
+
+	return added, removed
+}
+
+// addSymbol starts a symbol on a running service.
+func (s *Service) addSymbol(symbol string) {
+	s.symbolsMu.RLock()
+	ctx := s.runCtx
+	s.symbolsMu.RUnlock()
+	if ctx == nil {
+		return
+	}
+
+	s.registerSymbolTopics(s.clientConnMgr, s.bus, symbol)
+
+	st, ctx := s.newSymbolState(ctx, symbol)
+	st.added = true
+	s.symbolsMu.Lock()
+	s.Symbols[symbol] = st
+	s.symbolsMu.Unlock()
+
+	readyOnce := sync.Once{}
+	go s.streamDepthUpdates(ctx, symbol, st, func() {
+		readyOnce.Do(func() { go s.initializeSymbol(ctx, symbol, st) })
+	})
+	slog.Info("Symbol added", "symbol", symbol)
+}
+
+// removeSymbol stops the stream of a symbol and drops its book. Its clients
+// see the stream closed; their subscriptions stay in place in case it returns.
+func (s *Service) removeSymbol(symbol string) {
+	s.symbolsMu.Lock()
+	st, ok := s.Symbols[symbol]
+	delete(s.Symbols, symbol)
+	s.symbolsMu.Unlock()
+	if !ok {
+		return
+	}
+
+	st.cancel()
+	memory.GetOrderBookStore().DeleteItem(symbol)
+	slog.Info("Symbol removed", "symbol", symbol)
+}
+
+// subscription is the Binance config with the current subscriptions.
+func (s *Service) subscription() config.BinanceConfig {
+	s.subsMu.RLock()
+	defer s.subsMu.RUnlock()
+	cfg := s.config
+	cfg.Subscriptions = s.subscriptions
+	return cfg
+}
+
+// SubscribedSymbols lists the enabled symbols, upper-cased.
+func (s *Service) SubscribedSymbols() []string {
+	return s.subscription().Symbols()
+}
+
+// DepthLimit is the number of levels per side published for symbol, 0 for all.
+func (s *Service) DepthLimit(symbol string) int {
+	sub, _ := s.subscription().Symbol(symbol)
+	return sub.DepthLimit
+}
+
+// publishedBook copies the live book as clients are given it, cut to the
+// symbol's depth limit. The uncut copy is kept for FullOrderBook and the cut
+// one for publishedChanges.
+func (s *Service) publishedBook(symbol string, st *SymbolState) orderbook.OrderBook {
+	full := st.OrderBook.ToOrderBook()
+	st.setFullBook(&full)
+
+	ob := full
+	ob.Limit(s.DepthLimit(symbol))
+	st.published, st.publishedDepth = ob, s.DepthLimit(symbol)
+	return ob
+}
+
+// publishedChanges is how the book clients are given changed from before to
+// after. Clients of a depth-limited symbol get these levels instead of the
+// delta, so levels beyond the limit never reach them and levels that move into
+// range arrive whole. It is nil when neither book was cut.
+func publishedChanges(before orderbook.OrderBook, beforeDepth int, after orderbook.OrderBook, afterDepth int) *publishedLevels {
+	if beforeDepth == 0 && afterDepth == 0 {
+		return nil
+	}
+	return &publishedLevels{
+		bids: orderbook.ChangedLevels(before.Bids, after.Bids),
+		asks: orderbook.ChangedLevels(before.Asks, after.Asks),
 	}
 }
 
+// UseCheckpoints lets Start resume books from checkpoints no older than maxAge
+// instead of downloading a snapshot. It must be called before Start.
+func (s *Service) UseCheckpoints(store orderbook.CheckpointStore, maxAge time.Duration) {
+	s.checkpoints = store
+	s.checkpointMaxAge = maxAge
+}
+
 func (s *Service) initializeSymbol(ctx context.Context, symbol string, st *SymbolState) {
-	snapshot, err := FetchSnapshot(symbol, s.config)
+	if s.warmStart(symbol, st) {
+		s.announceAdded(symbol, st)
+		close(st.SnapshotReady)
+		go s.applyDepthEvents(ctx, symbol, st)
+		return
+	}
+
+	snapshot, err := s.loadSnapshot(ctx, symbol, st)
 	if err != nil {
-		slog.Error("Snapshot load failed", "symbol", symbol, "error", err)
 		return
 	}
 
@@ -91,29 +281,32 @@ func (s *Service) initializeSymbol(ctx context.Context, symbol string, st *Symbo
 
 	slog.Info("Snapshot loaded", "symbol", symbol, "lastUpdateId", st.OrderBook.LastUpdateID)
 
+	s.announceAdded(symbol, st)
 	close(st.SnapshotReady)
 	firstApplied := false
 
 	for {
 		select {
 		case update := <-st.UpdateCh:
+			step := orderbook.Sequence(st.OrderBook.LastUpdateID, update.FirstUpdateEventID, update.FinalUpdateEventID)
 			if !firstApplied {
-				if update.FirstUpdateEventID <= st.OrderBook.LastUpdateID+1 &&
-					update.FinalUpdateEventID >= st.OrderBook.LastUpdateID {
-					s.applyDelta(symbol, update, st)
+				if step == orderbook.Apply {
+					s.applyDelta(trace.ContextWithSpanContext(ctx, update.SpanContext), symbol, update, st)
 					st.OrderBook.LastUpdateID = update.FinalUpdateEventID
 					st.OrderBook.Initialized = true
 					firstApplied = true
+					st.markSynchronized()
 					slog.Info("Order book synchronized live stream in sync", "symbol", symbol,
 						"lastUpdateId", st.OrderBook.LastUpdateID)
 				}
 				continue
 			}
 
-			if update.FirstUpdateEventID == st.OrderBook.LastUpdateID+1 {
-				s.applyDelta(symbol, update, st)
+			switch step {
+			case orderbook.Apply:
+				s.applyDelta(trace.ContextWithSpanContext(ctx, update.SpanContext), symbol, update, st)
 				st.OrderBook.LastUpdateID = update.FinalUpdateEventID
-			} else {
+			case orderbook.Gap:
 				slog.Warn("Gap detected in buffered updates",
 					"symbol", symbol,
 					"expected", st.OrderBook.LastUpdateID+1,
@@ -126,87 +319,237 @@ func (s *Service) initializeSymbol(ctx context.Context, symbol string, st *Symbo
 	}
 }
 
+// announceAdded broadcasts the first book of a symbol added while running as a
+// reset, as clients may have subscribed to it before it was there.
+func (s *Service) announceAdded(symbol string, st *SymbolState) {
+	if st.added {
+		s.BroadcastOrderBookReset(symbol, "Symbol added", s.publishedBook(symbol, st))
+	}
+}
+
+// warmStart seeds the book from its checkpoint. The book is served as provisional
+// and stays unsynchronised until applyDepthEvents bridges the stored update ID to
+// the live stream, or replaces it with a REST snapshot when it cannot.
+func (s *Service) warmStart(symbol string, st *SymbolState) bool {
+	if s.checkpoints == nil {
+		return false
+	}
+
+	book, savedAt, ok := s.checkpoints.Load(ExchangeName, symbol)
+	if !ok {
+		return false
+	}
+	if s.checkpointMaxAge > 0 && time.Since(savedAt) > s.checkpointMaxAge {
+		slog.Info("Checkpoint too old for warm start", "symbol", symbol, "savedAt", savedAt)
+		return false
+	}
+
+	st.OrderBook = &OrderBookSnapshot{LastUpdateID: book.LastUpdateID, Bids: book.Bids, Asks: book.Asks}
+	st.markProvisional()
+
+	provisional := s.publishedBook(symbol, st)
+	provisional.Provisional = true
+	memory.GetOrderBookStore().SetItem(symbol, &provisional)
+
+	slog.Info("Order book restored from checkpoint", "symbol", symbol,
+		"lastUpdateId", book.LastUpdateID, "savedAt", savedAt)
+	return true
+}
+
+// loadSnapshot keeps fetching until a snapshot arrives or ctx is done. Each time the
+// fetcher gives up the symbol is marked degraded before the next round starts.
+func (s *Service) loadSnapshot(ctx context.Context, symbol string, st *SymbolState) (*OrderBookSnapshot, error) {
+	for {
+		snapshot, err := s.fetcher.Fetch(ctx, symbol)
+		if err == nil {
+			return snapshot, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		slog.Error("Snapshot load failed", "symbol", symbol, "error", err)
+		st.markDegraded("snapshot load failed: " + err.Error())
+
+		if err := retry.Sleep(ctx, s.fetcher.RetryDelay()); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// streamDepthUpdates keeps the upstream depth stream alive through a supervisor,
+// or two when redundancy is enabled. ready is called once, after the first
+// subscription is acknowledged on any leg.
 func (s *Service) streamDepthUpdates(
 	ctx context.Context,
 	symbol string,
-	updateCh chan<- DepthUpdateMessage,
-	snapshotReady <-chan struct{},
-	wg *sync.WaitGroup,
+	st *SymbolState,
+	ready func(),
 ) {
-	internalRequestId, _ := uuid.NewUUID()
-
 	go func() {
 		select {
-		case <-snapshotReady:
+		case <-st.SnapshotReady:
 			slog.Debug("Buffering stopped", "symbol", symbol)
 		case <-ctx.Done():
 			return
 		}
 	}()
 
-	for {
+	if st.arbiter == nil {
+		connections := 0
+		s.runFeedLeg(ctx, symbol, st, "", s.config.WsStreamUrl, ready,
+			func(update DepthUpdateMessage) {
+				select {
+				case st.UpdateCh <- update:
+				default:
+					slog.Warn("Dropping depth update", "symbol", symbol)
+				}
+			},
+			func(state wsInterface.ConnectionState) {
+				st.setConnected(state == wsInterface.StateConnected)
+				if state != wsInterface.StateConnected {
+					return
+				}
+				connections++
+				if connections > 1 {
+					st.signalReconnected()
+				}
+			})
+		return
+	}
 
-		readyCh := make(chan struct{})
-		wsReadyOnce := sync.Once{}
+	// With two legs a reconnect on one is covered by the other, and real gaps
+	// still reach applyDepthEvents once the arbiter stops waiting for a fill.
+	wg := sync.WaitGroup{}
+	for leg, url := range s.feedURLs() {
+		wg.Go(func() {
+			s.runFeedLeg(ctx, symbol, st, leg, url, ready,
+				func(update DepthUpdateMessage) {
+					st.arbiter.Offer(leg, update)
+				},
+				func(state wsInterface.ConnectionState) {
+					st.arbiter.SetConnected(leg, state == wsInterface.StateConnected)
+					st.setConnected(st.arbiter.AnyConnected())
+				})
+		})
+	}
+	wg.Wait()
+}
 
-		go func() {
-			select {
-			case <-readyCh:
-				wg.Done()
-			case <-ctx.Done():
-				wg.Done()
-			}
-		}()
+func (s *Service) feedURLs() map[string]string {
+	urls := map[string]string{PrimaryLeg: s.config.WsStreamUrl}
+	if s.config.Redundancy.Enabled && s.config.Redundancy.SecondaryWsStreamUrl != "" {
+		urls[SecondaryLeg] = s.config.Redundancy.SecondaryWsStreamUrl
+	}
+	return urls
+}
 
-		newStream := exchange.New(s.config.WsStreamUrl)
-		client := wsInterface.New(ctx, newStream)
+func (s *Service) runFeedLeg(
+	ctx context.Context,
+	symbol string,
+	st *SymbolState,
+	leg string,
+	url string,
+	ready func(),
+	deliver func(update DepthUpdateMessage),
+	onState func(state wsInterface.ConnectionState),
+) {
+	internalRequestId, _ := uuid.NewUUID()
+	wsReadyOnce := sync.Once{}
 
-		client.OnMessage = func(mt websocket.MessageType, data []byte) {
-			var ack SubscribeAck
-			if json.Unmarshal(data, &ack) == nil && ack.ID == internalRequestId.String() {
-				wsReadyOnce.Do(func() {
-					close(readyCh)
-					slog.Info("WebSocket ready", "symbol", symbol)
-				})
-			}
+	supervisor := wsInterface.NewSupervisor(s.streamModel(url), s.config.Stream.InitialBackoff, s.config.Stream.MaxBackoff)
 
-			var update DepthUpdateMessage
-			if err := json.Unmarshal(data, &update); err == nil && update.EventType == OrderBookUpdate {
-				select {
-				case updateCh <- update:
-				default:
-					slog.Warn("Dropping depth update", "symbol", symbol)
-				}
-				return
-			}
+	supervisor.OnMessage = func(mt websocket.MessageType, data []byte) {
+		_, span := tracer.Start(ctx, "binance.depth.receive")
+		defer span.End()
+		span.SetAttributes(attribute.String("symbol", symbol), attribute.String("leg", leg), attribute.Int("bytes", len(data)))
+
+		var ack SubscribeAck
+		if json.Unmarshal(data, &ack) == nil && ack.ID == internalRequestId.String() {
+			wsReadyOnce.Do(func() {
+				slog.Info("WebSocket ready", "symbol", symbol, "leg", leg)
+				ready()
+			})
 		}
 
-		if err := client.Connect(); err != nil {
-			slog.Error("WS connect failed", "err", err)
-			time.Sleep(time.Second)
-			continue
+		var update DepthUpdateMessage
+		if err := json.Unmarshal(data, &update); err == nil && update.EventType == OrderBookUpdate {
+			span.SetAttributes(depthUpdateAttributes(update)...)
+			update.SpanContext = span.SpanContext()
+			deliver(update)
+			return
 		}
 
-		stream := fmt.Sprintf("%s@depth", strings.ToLower(symbol))
+		if s.config.Trades {
+			var trade TradeMessage
+			if err := json.Unmarshal(data, &trade); err == nil && trade.EventType == Trade && st.acceptTrade(trade.TradeID) {
+				s.publishTrade(trade)
+			}
+		}
+	}
+
+	supervisor.OnConnect = func(client *wsInterface.Client) error {
+		streams := []string{fmt.Sprintf("%s@depth", strings.ToLower(symbol))}
+		if s.config.Trades {
+			streams = append(streams, fmt.Sprintf("%s@trade", strings.ToLower(symbol)))
+		}
 		sub := map[string]any{
 			"method": "SUBSCRIBE",
-			"params": []string{stream},
+			"params": streams,
 			"id":     internalRequestId.String(),
 		}
 		if err := client.SendJSON(sub); err != nil {
-			slog.Error("Binance subscribe failed", "error", err)
-			client.Close()
-			continue
+			return fmt.Errorf("binance subscribe failed: %w", err)
 		}
 
-		slog.Info("Subscribed", "symbol", symbol)
+		slog.Info("Subscribed", "symbol", symbol, "leg", leg)
+		return nil
+	}
 
-		if err := client.BlockUntilClosed(); err != nil {
-			slog.Warn("WS disconnected - reconnecting", "err", err)
-			time.Sleep(time.Second)
-			continue
-		}
+	supervisor.OnStateChange = func(state wsInterface.ConnectionState, reason error) {
+		onState(state)
+		s.publishConnectionState(symbol, leg, state, reason)
 	}
+
+	supervisor.Run(ctx)
+}
+
+func (s *Service) streamModel(url string) exchange.Client {
+	model := exchange.New(url)
+
+	if s.config.Stream.DialTimeout > 0 {
+		model.DialTimeout = s.config.Stream.DialTimeout
+	}
+	if s.config.Stream.PingInterval > 0 {
+		model.PingInterval = s.config.Stream.PingInterval
+	}
+
+	model.StaleTimeout = defaultStaleTimeout
+	if s.config.Stream.StaleTimeout > 0 {
+		model.StaleTimeout = s.config.Stream.StaleTimeout
+	}
+
+	model.MaxConnectionAge = defaultMaxConnectionAge
+	if s.config.Stream.MaxConnectionAge > 0 {
+		model.MaxConnectionAge = s.config.Stream.MaxConnectionAge
+	}
+
+	return model
+}
+
+func (s *Service) publishConnectionState(symbol string, leg string, state wsInterface.ConnectionState, reason error) {
+	event := ConnectionStateEvent{
+		Exchange:  ExchangeName,
+		Symbol:    symbol,
+		Leg:       leg,
+		State:     string(state),
+		Timestamp: time.Now().UnixMilli(),
+	}
+	if reason != nil {
+		event.Reason = reason.Error()
+	}
+
+	s.bus.Publish(ConnectionState, fmt.Sprintf("%s@connection", strings.ToLower(symbol)), event)
 }
 
 func (s *Service) applyDepthEvents(ctx context.Context, symbol string, st *SymbolState) {
@@ -214,6 +557,20 @@ func (s *Service) applyDepthEvents(ctx context.Context, symbol string, st *Symbo
 		select {
 		case <-ctx.Done():
 			return
+		case <-st.reconnected:
+			if !st.OrderBook.Initialized {
+				continue
+			}
+			slog.Warn("Upstream reconnected: fetching new snapshot", "symbol", symbol)
+			st.markUnsynchronized("upstream reconnected")
+			s.resync(ctx, symbol, st, "Upstream reconnected")
+		case reason := <-st.resyncRequest:
+			if !st.OrderBook.Initialized {
+				continue
+			}
+			slog.Warn("Resync requested: fetching new snapshot", "symbol", symbol, "reason", reason)
+			st.markUnsynchronized(reason)
+			s.resync(ctx, symbol, st, reason)
 		case update, ok := <-st.UpdateCh:
 			if !ok {
 				return
@@ -222,88 +579,95 @@ func (s *Service) applyDepthEvents(ctx context.Context, symbol string, st *Symbo
 			U := update.FirstUpdateEventID
 			u := update.FinalUpdateEventID
 			last := st.OrderBook.LastUpdateID
+			updateCtx := trace.ContextWithSpanContext(ctx, update.SpanContext)
+			step := orderbook.Sequence(last, U, u)
+
+			if step == orderbook.Skip {
+				continue
+			}
 
 			if !st.OrderBook.Initialized {
-				if U <= last+1 && u >= last {
-					s.applyDelta(symbol, update, st)
+				if step == orderbook.Apply {
+					published := s.applyDelta(updateCtx, symbol, update, st)
 					st.OrderBook.LastUpdateID = u
 					st.OrderBook.Initialized = true
+					st.markSynchronized()
 					slog.Info("Order book synchronized live stream in sync", "symbol", symbol,
 						"lastUpdateId", st.OrderBook.LastUpdateID)
 
-					s.broadcastDepthUpdate(update)
+					s.broadcastDepthUpdate(updateCtx, update, published)
 					continue
 				}
+
+				reason := "Snapshot behind live stream"
+				if st.isProvisional() {
+					reason = "Checkpoint cannot be bridged to live stream"
+				}
+				slog.Warn(reason+": fetching new snapshot", "symbol", symbol,
+					"expected", last+1, "got", U)
+				s.resync(ctx, symbol, st, reason)
 				continue
 			}
 
-			if U == last+1 {
-				s.applyDelta(symbol, update, st)
+			if step == orderbook.Apply {
+				published := s.applyDelta(updateCtx, symbol, update, st)
 				st.OrderBook.LastUpdateID = u
 
-				s.broadcastDepthUpdate(update)
+				s.broadcastDepthUpdate(updateCtx, update, published)
 				continue
 			}
 
 			slog.Warn("Order book de-sync detected: fetching new snapshot", "symbol", symbol,
 				"expected", last+1, "got", U)
-
-			snapshot, err := FetchSnapshot(symbol, s.config)
-			if err != nil {
-				slog.Error("Snapshot reload failed", "error", err)
-				continue
-			}
-
-			st.OrderBook.ApplySnapshot(snapshot)
-			st.OrderBook.Initialized = false
-			slog.Info("Snapshot resynced", "lastUpdateId", st.OrderBook)
-			orderBookSnapshot := st.OrderBook.ToOrderBook()
-			s.BroadcastOrderBookReset(symbol, "Orderbook desync detected", orderBookSnapshot)
+			st.markUnsynchronized(fmt.Sprintf("gap detected: expected %d, got %d", last+1, U))
+			s.resync(ctx, symbol, st, "Orderbook desync detected")
 		}
 	}
 }
 
-func (s *Service) applyDelta(symbol string, update DepthUpdateMessage, st *SymbolState) {
-	for _, bid := range update.BidsToUpdated {
-		price := bid[0]
-		quantity := bid[1]
-
-		qty, err := strconv.ParseFloat(quantity, 64)
-		if err != nil {
-			slog.Error("Failed to convert string to int:", "error", err) // FEEDBACK: poor error handling as the process continues
-		}
+// resync replaces the local book with a fresh snapshot. On failure the book stays
+// uninitialised and degraded, so the next update triggers another attempt.
+func (s *Service) resync(ctx context.Context, symbol string, st *SymbolState, reason string) {
+	st.OrderBook.Initialized = false
 
-		if qty == 0 {
-			st.OrderBook.RemoveBid(price)
-		} else {
-			st.OrderBook.UpdateBid(price, quantity)
-		}
+	snapshot, err := s.fetcher.Fetch(ctx, symbol)
+	if err != nil {
+		slog.Error("Snapshot reload failed", "symbol", symbol, "error", err)
+		st.markDegraded("snapshot reload failed: " + err.Error())
+		return
 	}
 
-	for _, ask := range update.AsksToUpdated {
-		price := ask[0]
-		quantity := ask[1]
+	st.OrderBook.ApplySnapshot(snapshot)
+	slog.Info("Snapshot resynced", "symbol", symbol, "lastUpdateId", st.OrderBook.LastUpdateID)
+	s.BroadcastOrderBookReset(symbol, reason, s.publishedBook(symbol, st))
+}
 
-		qty, err := strconv.ParseFloat(quantity, 64)
-		if err != nil {
-			slog.Error("Failed to convert string to int:", "error", err)
-		}
+// applyDelta updates the live book and its published copy and returns how the
+// delta changed the book clients are given, see publishedChanges.
+func (s *Service) applyDelta(ctx context.Context, symbol string, update DepthUpdateMessage, st *SymbolState) *publishedLevels {
+	_, span := tracer.Start(ctx, "orderbook.applyDelta")
+	defer span.End()
+	span.SetAttributes(depthUpdateAttributes(update)...)
 
-		if qty == 0 {
-			st.OrderBook.RemoveAsk(price)
-		} else {
-			st.OrderBook.UpdateAsk(price, quantity)
-		}
+	if err := orderbook.ApplyLevels(st.OrderBook, update.BidsToUpdated, update.AsksToUpdated); err != nil {
+		slog.Error("Malformed levels in depth update skipped", "symbol", symbol,
+			"firstUpdateId", update.FirstUpdateEventID, "finalUpdateId", update.FinalUpdateEventID, "error", err)
 	}
 
-	orderBookSnapshot := st.OrderBook.ToOrderBook()
+	st.OrderBook.LastUpdateID = update.FinalUpdateEventID
+	before, beforeDepth := st.published, st.publishedDepth
+	orderBookSnapshot := s.publishedBook(symbol, st)
 
 	memory.GetOrderBookStore().SetItem(symbol, &orderBookSnapshot)
-
-	st.OrderBook.LastUpdateID = update.FinalUpdateEventID
+	st.touch()
+	return publishedChanges(before, beforeDepth, orderBookSnapshot, st.publishedDepth)
 }
 
-func (s *Service) broadcastDepthUpdate(update DepthUpdateMessage) {
+func (s *Service) broadcastDepthUpdate(ctx context.Context, update DepthUpdateMessage, published *publishedLevels) {
+	ctx, span := tracer.Start(ctx, "bus.publish")
+	defer span.End()
+	span.SetAttributes(depthUpdateAttributes(update)...)
+
 	event := DepthUpdateEvent{
 		EventType:          "depthUpdate",
 		EventTime:          update.EventTime,
@@ -312,21 +676,116 @@ func (s *Service) broadcastDepthUpdate(update DepthUpdateMessage) {
 		FinalUpdateEventID: update.FinalUpdateEventID,
 		BidsToUpdated:      update.BidsToUpdated,
 		AsksToUpdated:      update.AsksToUpdated,
+		published:          published,
 	}
 
-	s.bus.Publish(OrderBookUpdate, fmt.Sprintf("%s@depth", strings.ToLower(update.Symbol)), event)
+	s.bus.PublishContext(ctx, OrderBookUpdate, fmt.Sprintf("%s@depth", strings.ToLower(update.Symbol)), event)
 }
 
+func (s *Service) publishTrade(trade TradeMessage) {
+	side := "buy"
+	if trade.BuyerIsMaker {
+		side = "sell"
+	}
+
+	s.bus.Publish(Trade, fmt.Sprintf("%s@trade", strings.ToLower(trade.Symbol)), TradeEvent{
+		Symbol:    trade.Symbol,
+		TradeID:   trade.TradeID,
+		Price:     trade.Price,
+		Quantity:  trade.Quantity,
+		Side:      side,
+		TradeTime: trade.TradeTime,
+		EventTime: trade.EventTime,
+	})
+}
+
+func depthUpdateAttributes(update DepthUpdateMessage) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("symbol", update.Symbol),
+		attribute.Int("firstUpdateId", update.FirstUpdateEventID),
+		attribute.Int("finalUpdateId", update.FinalUpdateEventID),
+	}
+}
+
+func (s *Service) Name() string {
+	return ExchangeName
+}
+
+// GetOrderBook returns the last published copy of the book, which is safe to read
+// while the live book keeps changing.
 func (s *Service) GetOrderBook(symbol string) *orderbook.OrderBook {
-	symbolBook, ok := s.Symbols[symbol]
+	book, ok := memory.GetOrderBookStore().GetItem(symbol)
+	if !ok {
+		return nil
+	}
+	return book
+}
+
+// FullOrderBook returns the last published copy of the book with every level,
+// however deep the symbol's depth limit cuts the book clients get.
+func (s *Service) FullOrderBook(symbol string) *orderbook.OrderBook {
+	s.symbolsMu.RLock()
+	st, ok := s.Symbols[symbol]
+	s.symbolsMu.RUnlock()
 
-	if s.Symbols == nil || !ok {
+	if !ok {
 		return nil
 	}
+	return st.getFullBook()
+}
 
-	ob := *symbolBook.OrderBook
-	snapshot := ob.ToOrderBook()
-	return &snapshot
+// FetchSnapshot downloads a REST depth snapshot through the shared fetcher, so it
+// counts against the same request weight budget as resyncs.
+func (s *Service) FetchSnapshot(ctx context.Context, symbol string) (*orderbook.OrderBook, error) {
+	return s.fetcher.FetchSnapshot(ctx, symbol)
+}
+
+func (s *Service) IsSynchronized(symbol string) bool {
+	s.symbolsMu.RLock()
+	st, ok := s.Symbols[symbol]
+	s.symbolsMu.RUnlock()
+
+	if !ok {
+		return false
+	}
+	return st.isSynchronized()
+}
+
+// RequestResync discards the local book of a symbol and rebuilds it from a fresh
+// snapshot, e.g. when a consumer finds it crossed. It reports whether the request
+// was accepted.
+func (s *Service) RequestResync(symbol string, reason string) bool {
+	s.symbolsMu.RLock()
+	st, ok := s.Symbols[strings.ToUpper(symbol)]
+	s.symbolsMu.RUnlock()
+
+	if !ok {
+		return false
+	}
+	return st.requestResync(reason)
+}
+
+// Health reports the sync state of every configured symbol, including ones whose
+// stream has not been set up yet.
+func (s *Service) Health() []health.SymbolStatus {
+	s.symbolsMu.RLock()
+	defer s.symbolsMu.RUnlock()
+
+	statuses := make([]health.SymbolStatus, 0)
+	for _, symbol := range s.SubscribedSymbols() {
+		st, ok := s.Symbols[symbol]
+		if !ok {
+			statuses = append(statuses, health.SymbolStatus{
+				Exchange:      ExchangeName,
+				Symbol:        symbol,
+				UnsyncedSince: s.startedAt,
+			})
+			continue
+		}
+		statuses = append(statuses, st.status(symbol))
+	}
+
+	return statuses
 }
 
 func (s *Service) BroadcastOrderBookReset(symbol string, reason string, orderBook orderbook.OrderBook) {
@@ -342,32 +801,65 @@ func (s *Service) BroadcastOrderBookReset(symbol string, reason string, orderBoo
 
 // FEEDBACK: why this is public?
 func (s *Service) RegisterEventSubscribers(config config.BinanceConfig, connMgr subscription.ClientConnectionManager, eventBus bus.IBus) {
-	symbols := strings.SplitSeq(strings.ToLower(config.Subscriptions), ",")
+	for _, symbol := range config.Symbols() {
+		s.registerSymbolTopics(connMgr, eventBus, symbol)
+	}
+}
 
-	for symbol := range symbols {
+// registerSymbolTopics forwards the bus topics of symbol to clients. The bus
+// cannot unsubscribe, so a symbol removed and added again keeps its handlers.
+func (s *Service) registerSymbolTopics(connMgr subscription.ClientConnectionManager, eventBus bus.IBus, symbol string) {
+	cleaned := strings.ToLower(symbol)
 
-		cleaned := strings.TrimSpace(symbol)
-		cleaned = strings.ToLower(cleaned)
-		if cleaned == "" {
-			continue
-		}
+	depthTopic := cleaned + "@depth"
+	resetTopic := cleaned + "@depth.reset"
+	connectionTopic := cleaned + "@connection"
+	tradeTopic := cleaned + "@trade"
 
-		depthTopic := cleaned + "@depth"
-		resetTopic := cleaned + "@depth.reset"
+	sub, _ := s.subscription().Symbol(symbol)
+	depth := newConflator(sub.Conflation, func(ctx context.Context, evt DepthUpdateEvent) {
+		connMgr.BroadcastContext(ctx, depthTopic, WSMessage{
+			Data: evt,
+		})
+	})
 
-		eventBus.Subscribe(depthTopic, func(e bus.Event) { // FEEDBACK: why Binance service publish to the bus and then subscribe to it again to all connMgr.Broadcast?
-			evt := e.Data.(DepthUpdateEvent)
-			connMgr.Broadcast(e.Topic, WSMessage{
-				Data: evt,
-			})
+	s.symbolsMu.Lock()
+	if _, ok := s.conflators[symbol]; ok {
+		s.symbolsMu.Unlock()
+		return
+	}
+	if s.conflators == nil {
+		s.conflators = make(map[string]*conflator)
+	}
+	s.conflators[symbol] = depth
+	s.symbolsMu.Unlock()
+
+	eventBus.Subscribe(depthTopic, func(e bus.Event) { // FEEDBACK: why Binance service publish to the bus and then subscribe to it again to all connMgr.Broadcast?
+		depth.add(e.Context, e.Data.(DepthUpdateEvent).forClients())
+	})
+
+	eventBus.Subscribe(resetTopic, func(e bus.Event) {
+		evt := e.Data.(OrderBookResetEvent)
+		connMgr.Broadcast(e.Topic, WSMessage{
+			Method: "orderbook_reset",
+			Data:   evt,
 		})
+	})
 
-		eventBus.Subscribe(resetTopic, func(e bus.Event) {
-			evt := e.Data.(OrderBookResetEvent)
-			connMgr.Broadcast(e.Topic, WSMessage{
-				Method: "orderbook_reset",
-				Data:   evt,
-			})
+	eventBus.Subscribe(connectionTopic, func(e bus.Event) {
+		evt := e.Data.(ConnectionStateEvent)
+		connMgr.Broadcast(e.Topic, WSMessage{
+			Method: "connection_state",
+			Data:   evt,
 		})
-	}
+	})
+
+	eventBus.Subscribe(tradeTopic, func(e bus.Event) {
+		evt := e.Data.(TradeEvent)
+		connMgr.Broadcast(e.Topic, WSMessage{
+			Method: Trade,
+			Topic:  e.Topic,
+			Data:   evt,
+		})
+	})
 }
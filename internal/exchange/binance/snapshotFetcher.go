@@ -2,17 +2,191 @@ package binance
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/ChethiyaNishanath/market-data-hub/internal/config"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/domain/orderbook"
 	"github.com/ChethiyaNishanath/market-data-hub/internal/interfaces/clients/rest"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/retry"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 )
 
-func FetchSnapshot(symbol string, cfg config.BinanceConfig) (*OrderBookSnapshot, error) {
-	restClient := rest.New(cfg.RestApiUrlV3, 1*time.Second)
+const (
+	defaultSnapshotLimit          = 1000
+	defaultSnapshotTimeout        = 5 * time.Second
+	defaultSnapshotMaxConcurrent  = 2
+	defaultSnapshotMaxRetries     = 5
+	defaultSnapshotInitialBackoff = 500 * time.Millisecond
+	defaultSnapshotMaxBackoff     = 30 * time.Second
+	defaultWeightLimit            = 6000
 
-	ctx := context.Background()
+	usedWeightHeader = "X-MBX-USED-WEIGHT-1M"
+	weightWindow     = time.Minute
+	banFallbackDelay = time.Minute
+)
+
+var ErrSnapshotUnavailable = errors.New("snapshot unavailable")
+
+// SnapshotFetcher downloads REST depth snapshots while staying inside Binance's
+// request weight budget. It is shared by all symbols of a service so concurrency
+// and weight accounting apply across them.
+type SnapshotFetcher struct {
+	client *rest.Client
+	cfg    config.SnapshotConfig
+	limits map[string]int
+	sem    chan struct{}
+
+	mu            sync.Mutex
+	usedWeight    int
+	weightResetAt time.Time
+	blockedUntil  time.Time
+}
+
+func NewSnapshotFetcher(cfg config.BinanceConfig) *SnapshotFetcher {
+	snapshotCfg := withSnapshotDefaults(cfg.Snapshot)
+
+	f := &SnapshotFetcher{
+		client: rest.New(cfg.RestApiUrlV3, snapshotCfg.Timeout),
+		cfg:    snapshotCfg,
+		sem:    make(chan struct{}, snapshotCfg.MaxConcurrent),
+	}
+	f.SetLimits(cfg.Subscriptions)
+	return f
+}
+
+// SetLimits replaces the per-symbol snapshot depths. Fetches already under way
+// keep the depth they started with.
+func (f *SnapshotFetcher) SetLimits(subs []config.SymbolConfig) {
+	limits := make(map[string]int)
+	for _, sub := range subs {
+		if sub.SnapshotLimit > 0 {
+			limits[strings.ToUpper(strings.TrimSpace(sub.Symbol))] = sub.SnapshotLimit
+		}
+	}
+
+	f.mu.Lock()
+	f.limits = limits
+	f.mu.Unlock()
+}
+
+// limit is the snapshot depth requested for symbol.
+func (f *SnapshotFetcher) limit(symbol string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if limit, ok := f.limits[strings.ToUpper(symbol)]; ok {
+		return limit
+	}
+	return f.cfg.Limit
+}
+
+func withSnapshotDefaults(cfg config.SnapshotConfig) config.SnapshotConfig {
+	if cfg.Limit <= 0 {
+		cfg.Limit = defaultSnapshotLimit
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultSnapshotTimeout
+	}
+	if cfg.MaxConcurrent <= 0 {
+		cfg.MaxConcurrent = defaultSnapshotMaxConcurrent
+	}
+	if cfg.MaxRetries < 0 {
+		cfg.MaxRetries = 0
+	} else if cfg.MaxRetries == 0 {
+		cfg.MaxRetries = defaultSnapshotMaxRetries
+	}
+	if cfg.InitialBackoff <= 0 {
+		cfg.InitialBackoff = defaultSnapshotInitialBackoff
+	}
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = defaultSnapshotMaxBackoff
+	}
+	if cfg.WeightLimit <= 0 {
+		cfg.WeightLimit = defaultWeightLimit
+	}
+	return cfg
+}
+
+// snapshotWeight mirrors the request weight Binance charges for GET /api/v3/depth.
+func snapshotWeight(limit int) int {
+	switch {
+	case limit <= 100:
+		return 5
+	case limit <= 500:
+		return 25
+	case limit <= 1000:
+		return 50
+	default:
+		return 250
+	}
+}
+
+// Fetch retries with jittered exponential backoff, honouring Retry-After on 429/418.
+// Client errors other than rate limiting are returned without retrying.
+func (f *SnapshotFetcher) Fetch(ctx context.Context, symbol string) (*OrderBookSnapshot, error) {
+	ctx, span := tracer.Start(ctx, "binance.snapshot.fetch")
+	defer span.End()
+	span.SetAttributes(attribute.String("symbol", symbol), attribute.Int("limit", f.limit(symbol)))
+
+	backoff := retry.NewBackoff(f.cfg.InitialBackoff, f.cfg.MaxBackoff)
+
+	var lastErr error
+	for attempt := 0; attempt <= f.cfg.MaxRetries; attempt++ {
+		if attempt > 0 {
+			delay := backoff.Next()
+			slog.Warn("Retrying snapshot fetch", "symbol", symbol, "attempt", attempt, "delay", delay, "error", lastErr)
+			if err := retry.Sleep(ctx, delay); err != nil {
+				return nil, err
+			}
+		}
+
+		snapshot, err := f.fetchOnce(ctx, symbol)
+		if err == nil {
+			span.SetAttributes(attribute.Int("lastUpdateId", snapshot.LastUpdateID), attribute.Int("attempts", attempt+1))
+			return snapshot, nil
+		}
+
+		lastErr = err
+		if ctx.Err() != nil || !isRetryable(err) {
+			break
+		}
+	}
+
+	span.RecordError(lastErr)
+	span.SetStatus(codes.Error, "snapshot fetch failed")
+
+	return nil, fmt.Errorf("%w: %s: %w", ErrSnapshotUnavailable, symbol, lastErr)
+}
+
+// FetchSnapshot fetches the snapshot of symbol as a domain order book.
+func (f *SnapshotFetcher) FetchSnapshot(ctx context.Context, symbol string) (*orderbook.OrderBook, error) {
+	snapshot, err := f.Fetch(ctx, strings.ToUpper(symbol))
+	if err != nil {
+		return nil, err
+	}
+	book := snapshot.ToOrderBook()
+	return &book, nil
+}
+
+func (f *SnapshotFetcher) fetchOnce(ctx context.Context, symbol string) (*OrderBookSnapshot, error) {
+	select {
+	case f.sem <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	defer func() { <-f.sem }()
+
+	limit := f.limit(symbol)
+	if err := f.reserveWeight(ctx, snapshotWeight(limit)); err != nil {
+		return nil, err
+	}
 
 	requestOpts := rest.RequestOptions{
 		Headers: map[string]string{
@@ -22,14 +196,106 @@ func FetchSnapshot(symbol string, cfg config.BinanceConfig) (*OrderBookSnapshot,
 
 	var orderBook OrderBookSnapshot
 
-	path := fmt.Sprintf("/depth?symbol=%s&limit=1000", symbol)
+	path := fmt.Sprintf("/depth?symbol=%s&limit=%d", symbol, limit)
+
+	header, err := f.client.GetWithHeader(ctx, path, requestOpts, &orderBook)
+	f.recordUsedWeight(header)
 
-	err := restClient.Get(ctx, path, requestOpts, &orderBook)
+	var statusErr *rest.StatusError
+	if errors.As(err, &statusErr) && isRateLimited(statusErr.StatusCode) {
+		delay, ok := statusErr.RetryAfter()
+		if !ok {
+			delay = banFallbackDelay
+		}
+		f.blockFor(delay)
+		slog.Warn("Binance rate limit hit, backing off", "status", statusErr.StatusCode, "retryAfter", delay)
+	}
 
 	if err != nil {
 		return nil, err
 	}
 
 	return &orderBook, nil
+}
+
+// reserveWeight blocks until the request fits in the current weight window and no
+// Retry-After ban is in force, then books the weight against the window.
+func (f *SnapshotFetcher) reserveWeight(ctx context.Context, weight int) error {
+	for {
+		f.mu.Lock()
+		now := time.Now()
+		if !now.Before(f.weightResetAt) {
+			f.usedWeight = 0
+			f.weightResetAt = now.Truncate(weightWindow).Add(weightWindow)
+		}
+
+		var wait time.Duration
+		switch {
+		case now.Before(f.blockedUntil):
+			wait = f.blockedUntil.Sub(now)
+		case f.usedWeight+weight > f.cfg.WeightLimit:
+			wait = f.weightResetAt.Sub(now)
+		default:
+			f.usedWeight += weight
+			f.mu.Unlock()
+			return nil
+		}
+		f.mu.Unlock()
+
+		slog.Debug("Waiting for snapshot weight budget", "wait", wait)
+		if err := retry.Sleep(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
 
+// recordUsedWeight trusts the exchange's own count over local bookkeeping.
+func (f *SnapshotFetcher) recordUsedWeight(header http.Header) {
+	if header == nil {
+		return
+	}
+
+	used, err := strconv.Atoi(header.Get(usedWeightHeader))
+	if err != nil {
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.usedWeight = used
+}
+
+func (f *SnapshotFetcher) blockFor(delay time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	until := time.Now().Add(delay)
+	if until.After(f.blockedUntil) {
+		f.blockedUntil = until
+	}
+}
+
+// UsedWeight returns the request weight consumed in the current window.
+func (f *SnapshotFetcher) UsedWeight() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.usedWeight
+}
+
+// RetryDelay is how long a caller should wait before starting another round of
+// attempts once Fetch has given up.
+func (f *SnapshotFetcher) RetryDelay() time.Duration {
+	return f.cfg.MaxBackoff
+}
+
+func isRateLimited(code int) bool {
+	return code == http.StatusTooManyRequests || code == http.StatusTeapot
+}
+
+func isRetryable(err error) bool {
+	var statusErr *rest.StatusError
+	if !errors.As(err, &statusErr) {
+		return true
+	}
+	return isRateLimited(statusErr.StatusCode) || statusErr.StatusCode >= http.StatusInternalServerError
 }
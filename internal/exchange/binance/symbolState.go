@@ -1,9 +1,41 @@
 package binance
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ChethiyaNishanath/market-data-hub/internal/domain/orderbook"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/health"
+)
+
 // FEEDBACK : Why is this public. its not used outsode the package no?
 type SymbolState struct {
 	OrderBook     *OrderBookSnapshot
 	UpdateCh      chan DepthUpdateMessage
 	SnapshotReady chan struct{}
+
+	reconnected   chan struct{}
+	resyncRequest chan string
+	arbiter       *FeedArbiter
+	cancel        context.CancelFunc
+	added         bool // started by Reconfigure rather than Start
+
+	mu               sync.RWMutex
+	connected        bool
+	synchronized     bool
+	degraded         bool
+	provisional      bool
+	lastUpdate       time.Time
+	unsyncedSince    time.Time
+	lastResyncReason string
+	lastTradeID      int64
+	fullBook         *orderbook.OrderBook
+
+	// published is the last book given to clients and publishedDepth the
+	// depth limit it was cut to. Only the symbol's own goroutine uses them.
+	published      orderbook.OrderBook
+	publishedDepth int
 }
 
 func NewMarketState() *SymbolState { // FEEDBACK: Why this is public
@@ -11,5 +43,143 @@ func NewMarketState() *SymbolState { // FEEDBACK: Why this is public
 		OrderBook:     nil,
 		UpdateCh:      make(chan DepthUpdateMessage, 100),
 		SnapshotReady: make(chan struct{}),
+		reconnected:   make(chan struct{}, 1),
+		resyncRequest: make(chan string, 1),
+		unsyncedSince: time.Now(),
+	}
+}
+
+func (st *SymbolState) setConnected(connected bool) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.connected = connected
+}
+
+func (st *SymbolState) markSynchronized() {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.synchronized = true
+	st.degraded = false
+	st.provisional = false
+	st.lastUpdate = time.Now()
+	st.unsyncedSince = time.Time{}
+}
+
+func (st *SymbolState) markUnsynchronized(reason string) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if st.synchronized || st.unsyncedSince.IsZero() {
+		st.unsyncedSince = time.Now()
+	}
+	st.synchronized = false
+	st.lastResyncReason = reason
+}
+
+// markProvisional flags a book restored from a checkpoint. It is served to readers
+// but not reported as synchronised until it is bridged to the live stream.
+func (st *SymbolState) markProvisional() {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.provisional = true
+	st.lastResyncReason = "restored from checkpoint"
+}
+
+// setFullBook keeps the last published copy of the book before it was cut to
+// the depth limit.
+func (st *SymbolState) setFullBook(book *orderbook.OrderBook) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.fullBook = book
+}
+
+func (st *SymbolState) getFullBook() *orderbook.OrderBook {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+	return st.fullBook
+}
+
+func (st *SymbolState) isProvisional() bool {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+	return st.provisional
+}
+
+// markDegraded flags a symbol whose snapshot keeps failing so it shows up in
+// health reports instead of silently waiting for the next gap.
+func (st *SymbolState) markDegraded(reason string) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if st.unsyncedSince.IsZero() {
+		st.unsyncedSince = time.Now()
+	}
+	st.synchronized = false
+	st.degraded = true
+	st.lastResyncReason = reason
+}
+
+// signalReconnected tells the book consumer the stream was re-established and
+// updates were likely missed. Repeated signals collapse into one.
+func (st *SymbolState) signalReconnected() {
+	select {
+	case st.reconnected <- struct{}{}:
+	default:
+	}
+}
+
+// requestResync asks the book consumer to rebuild the book from a snapshot.
+// Requests made while one is already pending are dropped.
+func (st *SymbolState) requestResync(reason string) bool {
+	select {
+	case st.resyncRequest <- reason:
+		return true
+	default:
+		return false
+	}
+}
+
+// acceptTrade reports whether a trade has not been seen yet. With redundant legs
+// every trade arrives twice and only the first copy is kept.
+func (st *SymbolState) acceptTrade(id int64) bool {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if id <= st.lastTradeID {
+		return false
+	}
+	st.lastTradeID = id
+	return true
+}
+
+func (st *SymbolState) isSynchronized() bool {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+	return st.synchronized
+}
+
+func (st *SymbolState) touch() {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.lastUpdate = time.Now()
+}
+
+func (st *SymbolState) status(symbol string) health.SymbolStatus {
+	var feeds []health.FeedStatus
+	if st.arbiter != nil {
+		feeds = st.arbiter.Status()
+	}
+
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+
+	return health.SymbolStatus{
+		Feeds:            feeds,
+		Exchange:         ExchangeName,
+		Symbol:           symbol,
+		Connected:        st.connected,
+		Synchronized:     st.synchronized,
+		Degraded:         st.degraded,
+		Provisional:      st.provisional,
+		LastUpdate:       st.lastUpdate,
+		UnsyncedSince:    st.unsyncedSince,
+		LastResyncReason: st.lastResyncReason,
 	}
 }
@@ -0,0 +1,262 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ChethiyaNishanath/market-data-hub/internal/bus"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/config"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/domain/exchange"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/exchange/binance"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/history"
+)
+
+const (
+	defaultSampleInterval = time.Second
+	defaultTopN           = 20
+	defaultRollInterval   = time.Hour
+
+	// rollCheckInterval is how often files are checked against the roll interval.
+	rollCheckInterval = 10 * time.Second
+)
+
+// Source is a venue whose data is exported.
+type Source interface {
+	exchange.BookSource
+	exchange.SymbolLister
+}
+
+type record struct {
+	dataset  string
+	exchange string
+	symbol   string
+	at       time.Time
+	rows     [][]any
+}
+
+// Exporter writes the live depth deltas, trades and sampled books of a venue to
+// rolling files. Bus handlers hand rows to a single writer goroutine, so files
+// are only touched from Run.
+type Exporter struct {
+	cfg    config.ExportConfig
+	bus    bus.IBus
+	source Source
+	sinks  map[string][]*Sink
+
+	records chan record
+	stopped chan struct{} // closed once Run stops taking records
+	done    chan struct{}
+
+	mu         sync.Mutex
+	subscribed map[string]bool
+}
+
+func NewExporter(cfg config.ExportConfig, eventBus bus.IBus, source Source) (*Exporter, error) {
+	if cfg.SampleInterval <= 0 {
+		cfg.SampleInterval = defaultSampleInterval
+	}
+	if cfg.TopN <= 0 {
+		cfg.TopN = defaultTopN
+	}
+	if cfg.RollInterval <= 0 {
+		cfg.RollInterval = defaultRollInterval
+	}
+	if len(cfg.Formats) == 0 {
+		cfg.Formats = []string{FormatParquet}
+	}
+	if len(cfg.Datasets) == 0 {
+		cfg.Datasets = []string{Depth.Name, Book.Name, Trades.Name}
+	}
+
+	sinks, err := NewSinks(cfg.Path, cfg.Datasets, cfg.Formats, SinkOptions{
+		Compression:  cfg.Compression,
+		RowGroupSize: cfg.RowGroupSize,
+		RollInterval: cfg.RollInterval,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Exporter{
+		cfg:        cfg,
+		bus:        eventBus,
+		source:     source,
+		sinks:      sinks,
+		records:    make(chan record, 1024),
+		stopped:    make(chan struct{}),
+		done:       make(chan struct{}),
+		subscribed: make(map[string]bool),
+	}, nil
+}
+
+// NewSinks creates a sink per dataset and format, keyed by dataset name.
+func NewSinks(root string, names, formats []string, opts SinkOptions) (map[string][]*Sink, error) {
+	sinks := make(map[string][]*Sink, len(names))
+	for _, name := range names {
+		dataset, err := LookupDataset(strings.TrimSpace(name))
+		if err != nil {
+			return nil, err
+		}
+		for _, format := range formats {
+			sink, err := NewSink(root, dataset, strings.TrimSpace(format), opts)
+			if err != nil {
+				return nil, err
+			}
+			sinks[dataset.Name] = append(sinks[dataset.Name], sink)
+		}
+	}
+	return sinks, nil
+}
+
+// Done is closed once Run has closed every file and returned.
+func (e *Exporter) Done() <-chan struct{} {
+	return e.done
+}
+
+func (e *Exporter) Run(ctx context.Context) {
+	defer close(e.done)
+
+	for _, symbol := range e.source.SubscribedSymbols() {
+		e.AddSymbol(symbol)
+	}
+
+	sample := time.NewTicker(e.cfg.SampleInterval)
+	defer sample.Stop()
+	roll := time.NewTicker(rollCheckInterval)
+	defer roll.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			close(e.stopped)
+			e.drain()
+			e.closeAll()
+			return
+		case rec := <-e.records:
+			e.write(rec)
+		case now := <-sample.C:
+			if e.sinks[Book.Name] != nil {
+				e.sampleBooks(now)
+			}
+		case now := <-roll.C:
+			e.roll(now)
+		}
+	}
+}
+
+// AddSymbol exports a symbol, including one added to the subscriptions while
+// running. Each symbol is subscribed to once.
+func (e *Exporter) AddSymbol(symbol string) {
+	symbol = strings.ToUpper(symbol)
+	lower := strings.ToLower(symbol)
+	venue := e.source.Name()
+
+	e.mu.Lock()
+	if e.subscribed[symbol] {
+		e.mu.Unlock()
+		return
+	}
+	e.subscribed[symbol] = true
+	e.mu.Unlock()
+
+	if e.sinks[Depth.Name] != nil {
+		e.bus.Subscribe(lower+"@depth", func(ev bus.Event) {
+			update, ok := ev.Data.(binance.DepthUpdateEvent)
+			if !ok {
+				return
+			}
+			rows := DepthRows(venue, symbol, history.Delta{
+				EventTime:     int64(update.EventTime),
+				FirstUpdateID: update.FirstUpdateEventID,
+				FinalUpdateID: update.FinalUpdateEventID,
+				Bids:          update.BidsToUpdated,
+				Asks:          update.AsksToUpdated,
+			})
+			e.enqueue(record{Depth.Name, venue, symbol, time.UnixMilli(int64(update.EventTime)), rows})
+		})
+	}
+
+	if e.sinks[Trades.Name] != nil {
+		e.bus.Subscribe(lower+"@trade", func(ev bus.Event) {
+			trade, ok := ev.Data.(binance.TradeEvent)
+			if !ok {
+				return
+			}
+			if row, ok := TradeRow(venue, trade); ok {
+				e.enqueue(record{Trades.Name, venue, symbol, time.UnixMilli(trade.TradeTime), [][]any{row}})
+			}
+		})
+	}
+}
+
+func (e *Exporter) enqueue(rec record) {
+	if len(rec.rows) == 0 {
+		return
+	}
+	select {
+	case e.records <- rec:
+	case <-e.stopped:
+	}
+}
+
+func (e *Exporter) sampleBooks(now time.Time) {
+	for _, symbol := range e.source.SubscribedSymbols() {
+		if !e.source.IsSynchronized(symbol) {
+			continue
+		}
+		book := e.source.GetOrderBook(symbol)
+		if book == nil {
+			continue
+		}
+		e.write(record{Book.Name, e.source.Name(), symbol, now, BookRows(e.source.Name(), symbol, now, book, e.cfg.TopN)})
+	}
+}
+
+func (e *Exporter) write(rec record) {
+	for _, sink := range e.sinks[rec.dataset] {
+		for _, row := range rec.rows {
+			if err := sink.Write(rec.exchange, rec.symbol, rec.at, row); err != nil {
+				slog.Error("Failed to export row", "dataset", rec.dataset, "symbol", rec.symbol, "error", err)
+				break
+			}
+		}
+	}
+}
+
+// drain writes the records queued before shutdown.
+func (e *Exporter) drain() {
+	for {
+		select {
+		case rec := <-e.records:
+			e.write(rec)
+		default:
+			return
+		}
+	}
+}
+
+func (e *Exporter) roll(now time.Time) {
+	for _, sinks := range e.sinks {
+		for _, sink := range sinks {
+			if err := sink.Roll(now); err != nil {
+				slog.Error("Failed to roll export files", "error", err)
+			}
+		}
+	}
+}
+
+func (e *Exporter) closeAll() {
+	var errs []error
+	for _, sinks := range e.sinks {
+		for _, sink := range sinks {
+			errs = append(errs, sink.Close())
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		slog.Error("Failed to close export files", "error", err)
+	}
+}
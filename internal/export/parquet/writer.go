@@ -0,0 +1,216 @@
+// Package parquet writes the flat Parquet files of the export datasets: one
+// required column per dataset field, in schema order, on top of parquet-go.
+package parquet
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"time"
+
+	pq "github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/compress"
+)
+
+// Type is a column's logical type.
+type Type int
+
+const (
+	Int32 Type = iota
+	Int64
+	Double
+	String
+	TimestampMillis
+)
+
+// goType is the Go type parquet-go maps onto the column.
+func (t Type) goType() reflect.Type {
+	switch t {
+	case Int32:
+		return reflect.TypeFor[int32]()
+	case Double:
+		return reflect.TypeFor[float64]()
+	case String:
+		return reflect.TypeFor[string]()
+	default:
+		return reflect.TypeFor[int64]()
+	}
+}
+
+type Column struct {
+	Name string
+	Type Type
+}
+
+// Codec is the page compression codec, numbered as in the Parquet format.
+type Codec int32
+
+const (
+	Uncompressed Codec = 0
+	Snappy       Codec = 1
+	Gzip         Codec = 2
+)
+
+func ParseCodec(name string) (Codec, error) {
+	switch name {
+	case "", "snappy":
+		return Snappy, nil
+	case "gzip":
+		return Gzip, nil
+	case "none", "uncompressed":
+		return Uncompressed, nil
+	default:
+		return 0, fmt.Errorf("unknown parquet compression %q", name)
+	}
+}
+
+func (c Codec) codec() compress.Codec {
+	switch c {
+	case Snappy:
+		return &pq.Snappy
+	case Gzip:
+		return &pq.Gzip
+	default:
+		return &pq.Uncompressed
+	}
+}
+
+const defaultRowGroupSize = 100_000
+
+type Options struct {
+	Codec Codec
+	// RowGroupSize is the number of rows buffered before a row group is
+	// written. Parquet counts the values of a page in an int32, so it cannot
+	// exceed math.MaxInt32.
+	RowGroupSize int
+	// Metadata is stored as key/value pairs in the file footer.
+	Metadata map[string]string
+}
+
+// Writer writes rows into row groups of RowGroupSize rows. Close must be
+// called to write the footer.
+type Writer struct {
+	w       *pq.Writer
+	columns []Column
+	row     reflect.Type
+	rows    int64
+}
+
+func NewWriter(out io.Writer, columns []Column, opts Options) (*Writer, error) {
+	if opts.RowGroupSize <= 0 {
+		opts.RowGroupSize = defaultRowGroupSize
+	}
+	if opts.RowGroupSize > math.MaxInt32 {
+		return nil, fmt.Errorf("parquet: row group size %d exceeds %d", opts.RowGroupSize, math.MaxInt32)
+	}
+
+	// A struct keeps the columns in schema order, where a parquet-go group
+	// would sort them by name.
+	fields := make([]reflect.StructField, len(columns))
+	for i, col := range columns {
+		tag := col.Name
+		if col.Type == TimestampMillis {
+			tag += ",timestamp(millisecond)"
+		}
+		fields[i] = reflect.StructField{
+			Name: fmt.Sprintf("F%d", i),
+			Type: col.Type.goType(),
+			Tag:  reflect.StructTag(fmt.Sprintf("parquet:%q", tag)),
+		}
+	}
+	row := reflect.StructOf(fields)
+
+	options := []pq.WriterOption{
+		pq.NewSchema("schema", pq.SchemaOf(reflect.New(row).Interface())),
+		pq.Compression(opts.Codec.codec()),
+		pq.MaxRowsPerRowGroup(int64(opts.RowGroupSize)),
+		pq.CreatedBy("market-data-hub", "", ""),
+	}
+	for k, v := range opts.Metadata {
+		options = append(options, pq.KeyValueMetadata(k, v))
+	}
+
+	return &Writer{
+		w:       pq.NewWriter(out, options...),
+		columns: columns,
+		row:     row,
+	}, nil
+}
+
+// Rows is the number of rows written so far.
+func (w *Writer) Rows() int64 {
+	return w.rows
+}
+
+// Write appends one row. Values must match the column types: int32 or int for
+// Int32, int64 or int for Int64, float64 for Double, string for String, and
+// int64 milliseconds or time.Time for TimestampMillis.
+func (w *Writer) Write(row ...any) error {
+	if len(row) != len(w.columns) {
+		return fmt.Errorf("parquet: row has %d values, schema has %d columns", len(row), len(w.columns))
+	}
+
+	rec := reflect.New(w.row)
+	for i, v := range row {
+		value, err := convert(w.columns[i].Type, v)
+		if err != nil {
+			return fmt.Errorf("parquet: column %s: %w", w.columns[i].Name, err)
+		}
+		rec.Elem().Field(i).Set(reflect.ValueOf(value))
+	}
+
+	if err := w.w.Write(rec.Interface()); err != nil {
+		return err
+	}
+	w.rows++
+	return nil
+}
+
+// Close writes any buffered rows and the footer. It does not close the
+// underlying writer.
+func (w *Writer) Close() error {
+	return w.w.Close()
+}
+
+// convert returns v as the Go type of a typ column.
+func convert(typ Type, v any) (any, error) {
+	switch typ {
+	case Int32:
+		switch x := v.(type) {
+		case int32:
+			return x, nil
+		case int:
+			if x < math.MinInt32 || x > math.MaxInt32 {
+				return nil, fmt.Errorf("%d overflows int32", x)
+			}
+			return int32(x), nil
+		}
+		return nil, fmt.Errorf("expected int32, got %T", v)
+
+	case Int64, TimestampMillis:
+		switch x := v.(type) {
+		case int64:
+			return x, nil
+		case int:
+			return int64(x), nil
+		case time.Time:
+			if typ == TimestampMillis {
+				return x.UnixMilli(), nil
+			}
+		}
+		return nil, fmt.Errorf("expected int64, got %T", v)
+
+	case Double:
+		if x, ok := v.(float64); ok {
+			return x, nil
+		}
+		return nil, fmt.Errorf("expected float64, got %T", v)
+
+	default:
+		if x, ok := v.(string); ok {
+			return x, nil
+		}
+		return nil, fmt.Errorf("expected string, got %T", v)
+	}
+}
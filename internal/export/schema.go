@@ -0,0 +1,151 @@
+package export
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ChethiyaNishanath/market-data-hub/internal/domain/orderbook"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/exchange/binance"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/export/parquet"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/history"
+)
+
+// Dataset is a versioned export schema. A breaking change to the columns must
+// bump Version, which moves the files to a new v<Version> directory.
+type Dataset struct {
+	Name    string
+	Version int
+	Columns []parquet.Column
+}
+
+func (d Dataset) String() string {
+	return fmt.Sprintf("%s/v%d", d.Name, d.Version)
+}
+
+// Depth holds one row per price level changed by an L2 delta; a zero quantity
+// removes the level.
+var Depth = Dataset{
+	Name:    "depth",
+	Version: 1,
+	Columns: []parquet.Column{
+		{Name: "event_time", Type: parquet.TimestampMillis},
+		{Name: "exchange", Type: parquet.String},
+		{Name: "symbol", Type: parquet.String},
+		{Name: "first_update_id", Type: parquet.Int64},
+		{Name: "final_update_id", Type: parquet.Int64},
+		{Name: "side", Type: parquet.String},
+		{Name: "price", Type: parquet.Double},
+		{Name: "quantity", Type: parquet.Double},
+	},
+}
+
+// Book holds the top levels of each side sampled at a fixed interval, one row
+// per level. Level 0 is the best price.
+var Book = Dataset{
+	Name:    "book",
+	Version: 1,
+	Columns: []parquet.Column{
+		{Name: "sample_time", Type: parquet.TimestampMillis},
+		{Name: "exchange", Type: parquet.String},
+		{Name: "symbol", Type: parquet.String},
+		{Name: "last_update_id", Type: parquet.Int64},
+		{Name: "side", Type: parquet.String},
+		{Name: "level", Type: parquet.Int32},
+		{Name: "price", Type: parquet.Double},
+		{Name: "quantity", Type: parquet.Double},
+	},
+}
+
+// Trades holds one row per trade. Side is the taker's side.
+var Trades = Dataset{
+	Name:    "trades",
+	Version: 1,
+	Columns: []parquet.Column{
+		{Name: "trade_time", Type: parquet.TimestampMillis},
+		{Name: "event_time", Type: parquet.TimestampMillis},
+		{Name: "exchange", Type: parquet.String},
+		{Name: "symbol", Type: parquet.String},
+		{Name: "trade_id", Type: parquet.Int64},
+		{Name: "side", Type: parquet.String},
+		{Name: "price", Type: parquet.Double},
+		{Name: "quantity", Type: parquet.Double},
+	},
+}
+
+var datasets = map[string]Dataset{Depth.Name: Depth, Book.Name: Book, Trades.Name: Trades}
+
+func LookupDataset(name string) (Dataset, error) {
+	d, ok := datasets[name]
+	if !ok {
+		return Dataset{}, fmt.Errorf("unknown export dataset %q", name)
+	}
+	return d, nil
+}
+
+// DepthRows flattens a delta into Depth rows, skipping malformed levels.
+func DepthRows(exchange, symbol string, d history.Delta) [][]any {
+	rows := make([][]any, 0, len(d.Bids)+len(d.Asks))
+	for _, side := range []struct {
+		name   string
+		levels [][]string
+	}{{"bid", d.Bids}, {"ask", d.Asks}} {
+		for _, lvl := range side.levels {
+			price, qty, ok := parseLevel(lvl)
+			if !ok {
+				continue
+			}
+			rows = append(rows, []any{
+				d.EventTime, exchange, symbol,
+				int64(d.FirstUpdateID), int64(d.FinalUpdateID),
+				side.name, price, qty,
+			})
+		}
+	}
+	return rows
+}
+
+// BookRows flattens the best topN levels of each side into Book rows. All
+// levels are kept when topN is zero.
+func BookRows(exchange, symbol string, at time.Time, book *orderbook.OrderBook, topN int) [][]any {
+	rows := make([][]any, 0, 2*topN)
+	for _, side := range []struct {
+		name   string
+		levels []orderbook.Level
+	}{{"bid", book.SortedBids()}, {"ask", book.SortedAsks()}} {
+		for i, lvl := range side.levels {
+			if topN > 0 && i >= topN {
+				break
+			}
+			rows = append(rows, []any{
+				at.UnixMilli(), exchange, symbol, int64(book.LastUpdateID),
+				side.name, int32(i), lvl.Price, lvl.Quantity,
+			})
+		}
+	}
+	return rows
+}
+
+// TradeRow maps a trade event to a Trades row.
+func TradeRow(exchange string, t binance.TradeEvent) ([]any, bool) {
+	price, qty, ok := parseLevel([]string{t.Price, t.Quantity})
+	if !ok {
+		return nil, false
+	}
+	return []any{t.TradeTime, t.EventTime, exchange, t.Symbol, t.TradeID, t.Side, price, qty}, true
+}
+
+func parseLevel(lvl []string) (price, qty float64, ok bool) {
+	if len(lvl) < 2 {
+		return 0, 0, false
+	}
+	price, err := strconv.ParseFloat(lvl[0], 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	qty, err = strconv.ParseFloat(lvl[1], 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	return price, qty, true
+}
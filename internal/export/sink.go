@@ -0,0 +1,248 @@
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/ChethiyaNishanath/market-data-hub/internal/export/parquet"
+)
+
+const (
+	FormatParquet = "parquet"
+	FormatCSV     = "csv"
+)
+
+// inProgressSuffix marks files still being written. They are renamed once
+// complete, so readers of the lake never see a partial file.
+const inProgressSuffix = ".inprogress"
+
+type SinkOptions struct {
+	// Compression applies to Parquet only: snappy (default), gzip or none.
+	Compression  string
+	RowGroupSize int
+	// RollInterval closes a file once it has been open this long. Zero keeps
+	// files open until Close.
+	RollInterval time.Duration
+}
+
+type rowEncoder interface {
+	Write(row ...any) error
+	Close() error
+}
+
+type partition struct {
+	date     string
+	exchange string
+	symbol   string
+}
+
+type partFile struct {
+	file   *os.File
+	buf    *bufio.Writer
+	enc    rowEncoder
+	path   string
+	opened time.Time
+}
+
+// Sink writes one dataset in one format under
+// <root>/<dataset>/v<version>/date=YYYY-MM-DD/exchange=<exchange>/symbol=<symbol>/.
+// It is not safe for concurrent use.
+type Sink struct {
+	root    string
+	dataset Dataset
+	format  string
+	codec   parquet.Codec
+	opts    SinkOptions
+
+	files   map[partition]*partFile
+	written []string
+}
+
+func NewSink(root string, dataset Dataset, format string, opts SinkOptions) (*Sink, error) {
+	if format != FormatParquet && format != FormatCSV {
+		return nil, fmt.Errorf("unknown export format %q", format)
+	}
+
+	codec, err := parquet.ParseCodec(opts.Compression)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Sink{
+		root:    root,
+		dataset: dataset,
+		format:  format,
+		codec:   codec,
+		opts:    opts,
+		files:   make(map[partition]*partFile),
+	}, nil
+}
+
+// Write appends a row to the partition of the given exchange, symbol and the
+// UTC date of at.
+func (s *Sink) Write(exchange, symbol string, at time.Time, row []any) error {
+	key := partition{date: at.UTC().Format(time.DateOnly), exchange: exchange, symbol: symbol}
+
+	f, ok := s.files[key]
+	if !ok {
+		var err error
+		if f, err = s.open(key); err != nil {
+			return err
+		}
+		s.files[key] = f
+	}
+
+	return f.enc.Write(row...)
+}
+
+// Roll closes the files that have been open for at least the roll interval.
+func (s *Sink) Roll(now time.Time) error {
+	if s.opts.RollInterval <= 0 {
+		return nil
+	}
+
+	var errs []error
+	for key, f := range s.files {
+		if now.Sub(f.opened) < s.opts.RollInterval {
+			continue
+		}
+		errs = append(errs, s.finish(f))
+		delete(s.files, key)
+	}
+	return errors.Join(errs...)
+}
+
+// Close finishes every open file.
+func (s *Sink) Close() error {
+	var errs []error
+	for key, f := range s.files {
+		errs = append(errs, s.finish(f))
+		delete(s.files, key)
+	}
+	return errors.Join(errs...)
+}
+
+// Written lists the completed files.
+func (s *Sink) Written() []string {
+	return s.written
+}
+
+func (s *Sink) open(key partition) (*partFile, error) {
+	dir := filepath.Join(s.root, s.dataset.Name, "v"+strconv.Itoa(s.dataset.Version),
+		"date="+key.date, "exchange="+key.exchange, "symbol="+key.symbol)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create export directory: %w", err)
+	}
+
+	opened := time.Now()
+	path := s.fileName(dir, opened)
+
+	file, err := os.Create(path + inProgressSuffix)
+	if err != nil {
+		return nil, fmt.Errorf("create export file: %w", err)
+	}
+	buf := bufio.NewWriter(file)
+
+	var enc rowEncoder
+	if s.format == FormatParquet {
+		enc, err = parquet.NewWriter(buf, s.dataset.Columns, parquet.Options{
+			Codec:        s.codec,
+			RowGroupSize: s.opts.RowGroupSize,
+			Metadata:     map[string]string{"mdh.schema": s.dataset.String()},
+		})
+	} else {
+		enc, err = newCSVEncoder(buf, s.dataset)
+	}
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+
+	return &partFile{file: file, buf: buf, enc: enc, path: path, opened: opened}, nil
+}
+
+// fileName picks an unused name stamped with the opening time.
+func (s *Sink) fileName(dir string, opened time.Time) string {
+	base := s.dataset.Name + "-" + opened.UTC().Format("20060102T150405.000Z")
+	path := filepath.Join(dir, base+"."+s.format)
+	for n := 1; exists(path) || exists(path+inProgressSuffix); n++ {
+		path = filepath.Join(dir, fmt.Sprintf("%s-%d.%s", base, n, s.format))
+	}
+	return path
+}
+
+func (s *Sink) finish(f *partFile) error {
+	err := errors.Join(f.enc.Close(), f.buf.Flush(), f.file.Close())
+	if err != nil {
+		return fmt.Errorf("finish %s: %w", f.path, err)
+	}
+	if err := os.Rename(f.path+inProgressSuffix, f.path); err != nil {
+		return err
+	}
+	s.written = append(s.written, f.path)
+	return nil
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// csvEncoder writes a header row, timestamps as RFC 3339 UTC with milliseconds
+// and numbers in their shortest exact form.
+type csvEncoder struct {
+	w       *csv.Writer
+	columns []parquet.Column
+	record  []string
+}
+
+func newCSVEncoder(buf *bufio.Writer, dataset Dataset) (*csvEncoder, error) {
+	w := csv.NewWriter(buf)
+
+	header := make([]string, len(dataset.Columns))
+	for i, col := range dataset.Columns {
+		header[i] = col.Name
+	}
+	if err := w.Write(header); err != nil {
+		return nil, err
+	}
+
+	return &csvEncoder{w: w, columns: dataset.Columns, record: make([]string, len(dataset.Columns))}, nil
+}
+
+func (e *csvEncoder) Write(row ...any) error {
+	if len(row) != len(e.columns) {
+		return fmt.Errorf("csv: row has %d values, schema has %d columns", len(row), len(e.columns))
+	}
+
+	for i, v := range row {
+		switch x := v.(type) {
+		case string:
+			e.record[i] = x
+		case float64:
+			e.record[i] = strconv.FormatFloat(x, 'f', -1, 64)
+		case int32:
+			e.record[i] = strconv.FormatInt(int64(x), 10)
+		case int64:
+			if e.columns[i].Type == parquet.TimestampMillis {
+				e.record[i] = time.UnixMilli(x).UTC().Format("2006-01-02T15:04:05.000Z07:00")
+			} else {
+				e.record[i] = strconv.FormatInt(x, 10)
+			}
+		default:
+			return fmt.Errorf("csv: column %s: unexpected %T", e.columns[i].Name, v)
+		}
+	}
+	return e.w.Write(e.record)
+}
+
+func (e *csvEncoder) Close() error {
+	e.w.Flush()
+	return e.w.Error()
+}
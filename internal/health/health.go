@@ -0,0 +1,124 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+const (
+	StatusOK       = "ok"
+	StatusDegraded = "degraded"
+)
+
+type SymbolStatus struct {
+	Exchange         string    `json:"exchange"`
+	Symbol           string    `json:"symbol"`
+	Connected        bool      `json:"connected"`
+	Synchronized     bool      `json:"synchronized"`
+	Degraded         bool      `json:"degraded"`
+	Provisional      bool      `json:"provisional,omitempty"`
+	LastUpdate       time.Time `json:"lastUpdate,omitzero"`
+	LastUpdateAgeMs  int64     `json:"lastUpdateAgeMs"`
+	UnsyncedSince    time.Time `json:"unsyncedSince,omitzero"`
+	LastResyncReason string    `json:"lastResyncReason,omitempty"`
+	Ready            bool      `json:"ready"`
+
+	Feeds []FeedStatus `json:"feeds,omitempty"`
+}
+
+// FeedStatus describes one leg of a redundant upstream feed.
+type FeedStatus struct {
+	Leg              string  `json:"leg"`
+	URL              string  `json:"url"`
+	Connected        bool    `json:"connected"`
+	Leading          bool    `json:"leading"`
+	FirstArrivals    int     `json:"firstArrivals"`
+	Duplicates       int     `json:"duplicates"`
+	AvgLatencyMs     float64 `json:"avgLatencyMs"`
+	LastMessageAgeMs int64   `json:"lastMessageAgeMs"`
+}
+
+// Reporter is implemented by every exchange integration that maintains books.
+type Reporter interface {
+	Health() []SymbolStatus
+}
+
+type Report struct {
+	Status    string         `json:"status"`
+	Timestamp time.Time      `json:"timestamp"`
+	Symbols   []SymbolStatus `json:"symbols"`
+}
+
+type Handler struct {
+	reporters      []Reporter
+	staleThreshold time.Duration
+	now            func() time.Time
+}
+
+func NewHandler(staleThreshold time.Duration, reporters ...Reporter) *Handler {
+	return &Handler{
+		reporters:      reporters,
+		staleThreshold: staleThreshold,
+		now:            time.Now,
+	}
+}
+
+// Evaluate collects the status of every symbol and decides whether each one is ready.
+// A symbol is not ready once it has been unsynced or degraded, or without updates, for longer than the threshold.
+func (h *Handler) Evaluate() Report {
+	now := h.now()
+	report := Report{
+		Status:    StatusOK,
+		Timestamp: now,
+		Symbols:   make([]SymbolStatus, 0),
+	}
+
+	for _, reporter := range h.reporters {
+		for _, st := range reporter.Health() {
+			if !st.LastUpdate.IsZero() {
+				st.LastUpdateAgeMs = now.Sub(st.LastUpdate).Milliseconds()
+			}
+
+			// A degraded symbol is unsynced too, so a short reconnect or a
+			// failed snapshot only counts once it outlasts the threshold.
+			st.Ready = true
+			if (!st.Synchronized || st.Degraded) && !st.UnsyncedSince.IsZero() && now.Sub(st.UnsyncedSince) > h.staleThreshold {
+				st.Ready = false
+			}
+			if st.Synchronized && !st.LastUpdate.IsZero() && now.Sub(st.LastUpdate) > h.staleThreshold {
+				st.Ready = false
+			}
+
+			if !st.Ready {
+				report.Status = StatusDegraded
+			}
+			report.Symbols = append(report.Symbols, st)
+		}
+	}
+
+	return report
+}
+
+// HandleLiveness reports the process is serving requests. Symbol detail is informational only.
+func (h *Handler) HandleLiveness(w http.ResponseWriter, r *http.Request) {
+	report := h.Evaluate()
+	report.Status = StatusOK
+	writeReport(w, http.StatusOK, report)
+}
+
+func (h *Handler) HandleReadiness(w http.ResponseWriter, r *http.Request) {
+	report := h.Evaluate()
+
+	code := http.StatusOK
+	if report.Status != StatusOK {
+		code = http.StatusServiceUnavailable
+	}
+	writeReport(w, code, report)
+}
+
+func writeReport(w http.ResponseWriter, code int, report Report) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(report)
+}
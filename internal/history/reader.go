@@ -0,0 +1,38 @@
+package history
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MaxSamples bounds a single range export.
+const MaxSamples = 100_000
+
+// Reader answers historical queries for one venue.
+type Reader struct {
+	store *Store
+	venue string
+}
+
+func NewReader(store *Store, venue string) *Reader {
+	return &Reader{store: store, venue: venue}
+}
+
+// At rebuilds the symbol's book at the given time, keeping depth levels per side
+// (all when depth is zero).
+func (r *Reader) At(symbol string, at time.Time, depth int) (Snapshot, error) {
+	return r.store.At(r.venue, strings.ToUpper(symbol), at, depth)
+}
+
+// Range rebuilds the book every step between from and to and hands each state to fn.
+func (r *Reader) Range(symbol string, from, to time.Time, step time.Duration, depth int, fn func(Snapshot) error) error {
+	if step <= 0 || to.Before(from) {
+		return fmt.Errorf("%w: step must be positive and to must not precede from", ErrInvalidRange)
+	}
+	if samples := to.Sub(from)/step + 1; samples > MaxSamples {
+		return fmt.Errorf("%w: %d samples requested, at most %d allowed", ErrInvalidRange, samples, MaxSamples)
+	}
+
+	return r.store.Replay(r.venue, strings.ToUpper(symbol), from, to, step, depth, fn)
+}
@@ -0,0 +1,190 @@
+package history
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ChethiyaNishanath/market-data-hub/internal/bus"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/config"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/domain/exchange"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/domain/orderbook"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/exchange/binance"
+)
+
+const (
+	defaultCheckpointInterval = time.Minute
+	defaultFlushInterval      = time.Second
+)
+
+// Source is a venue whose books are recorded. FullOrderBook returns the book
+// with every level, however deep the published copy is cut, so a rebuild still
+// has the levels that later move into range.
+type Source interface {
+	exchange.BookSource
+	exchange.SymbolLister
+	FullOrderBook(symbol string) *orderbook.OrderBook
+}
+
+// Recorder journals every depth update of a venue and checkpoints its full books
+// periodically and on every reset. Deltas are buffered and written once per
+// flush interval, so the most recent ones only become queryable after a flush.
+type Recorder struct {
+	cfg    config.HistoryConfig
+	store  *Store
+	bus    bus.IBus
+	source Source
+
+	mu         sync.Mutex
+	pending    map[string][]Delta
+	subscribed map[string]bool
+
+	done chan struct{}
+}
+
+func NewRecorder(cfg config.HistoryConfig, store *Store, eventBus bus.IBus, source Source) *Recorder {
+	if cfg.CheckpointInterval <= 0 {
+		cfg.CheckpointInterval = defaultCheckpointInterval
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = defaultFlushInterval
+	}
+
+	return &Recorder{
+		cfg:        cfg,
+		store:      store,
+		bus:        eventBus,
+		source:     source,
+		pending:    make(map[string][]Delta),
+		subscribed: make(map[string]bool),
+		done:       make(chan struct{}),
+	}
+}
+
+// Done is closed once Run has flushed its final writes and returned.
+func (r *Recorder) Done() <-chan struct{} {
+	return r.done
+}
+
+func (r *Recorder) Run(ctx context.Context) {
+	defer close(r.done)
+
+	for _, symbol := range r.source.SubscribedSymbols() {
+		r.AddSymbol(symbol)
+	}
+
+	// Start the journal from a known state instead of waiting for the first tick.
+	r.CheckpointAll()
+
+	flush := time.NewTicker(r.cfg.FlushInterval)
+	defer flush.Stop()
+	checkpoints := time.NewTicker(r.cfg.CheckpointInterval)
+	defer checkpoints.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			r.Flush()
+			return
+		case <-flush.C:
+			r.Flush()
+		case <-checkpoints.C:
+			r.Flush()
+			r.CheckpointAll()
+			r.prune()
+		}
+	}
+}
+
+// AddSymbol journals a symbol, including one added to the subscriptions while
+// running. Each symbol is subscribed to once.
+func (r *Recorder) AddSymbol(symbol string) {
+	symbol = strings.ToUpper(symbol)
+	lower := strings.ToLower(symbol)
+
+	r.mu.Lock()
+	if r.subscribed[symbol] {
+		r.mu.Unlock()
+		return
+	}
+	r.subscribed[symbol] = true
+	r.mu.Unlock()
+
+	r.bus.Subscribe(lower+"@depth", func(e bus.Event) {
+		if update, ok := e.Data.(binance.DepthUpdateEvent); ok {
+			r.Record(symbol, update)
+		}
+	})
+	// The reset carries the book as clients get it, cut to the depth limit.
+	r.bus.Subscribe(lower+"@depth.reset", func(e bus.Event) {
+		if _, ok := e.Data.(binance.OrderBookResetEvent); !ok {
+			return
+		}
+		if book := r.source.FullOrderBook(symbol); book != nil {
+			r.saveCheckpoint(symbol, book)
+		}
+	})
+}
+
+// Record buffers a depth update for the next flush.
+func (r *Recorder) Record(symbol string, update binance.DepthUpdateEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.pending[symbol] = append(r.pending[symbol], Delta{
+		EventTime:     int64(update.EventTime),
+		FirstUpdateID: update.FirstUpdateEventID,
+		FinalUpdateID: update.FinalUpdateEventID,
+		Bids:          update.BidsToUpdated,
+		Asks:          update.AsksToUpdated,
+	})
+}
+
+// Flush writes the buffered deltas, one transaction per symbol.
+func (r *Recorder) Flush() {
+	r.mu.Lock()
+	pending := r.pending
+	r.pending = make(map[string][]Delta, len(pending))
+	r.mu.Unlock()
+
+	for symbol, deltas := range pending {
+		if err := r.store.Append(r.source.Name(), symbol, deltas...); err != nil {
+			slog.Error("Failed to journal depth updates", "symbol", symbol, "count", len(deltas), "error", err)
+		}
+	}
+}
+
+// CheckpointAll saves the current book of every synchronised symbol.
+func (r *Recorder) CheckpointAll() {
+	for _, symbol := range r.source.SubscribedSymbols() {
+		symbol := strings.ToUpper(symbol)
+		if !r.source.IsSynchronized(symbol) {
+			continue
+		}
+		if book := r.source.FullOrderBook(symbol); book != nil {
+			r.saveCheckpoint(symbol, book)
+		}
+	}
+}
+
+// saveCheckpoint skips provisional books; they are replaced by a reconciled
+// snapshot once the live stream catches up.
+func (r *Recorder) saveCheckpoint(symbol string, book *orderbook.OrderBook) {
+	if book.Provisional {
+		return
+	}
+	if err := r.store.SaveCheckpoint(r.source.Name(), symbol, time.Now(), book); err != nil {
+		slog.Error("Failed to save history checkpoint", "symbol", symbol, "error", err)
+	}
+}
+
+func (r *Recorder) prune() {
+	if r.cfg.Retention <= 0 {
+		return
+	}
+	if err := r.store.Prune(time.Now().Add(-r.cfg.Retention)); err != nil {
+		slog.Error("Failed to prune order book history", "error", err)
+	}
+}
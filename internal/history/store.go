@@ -0,0 +1,335 @@
+package history
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ChethiyaNishanath/market-data-hub/internal/domain/orderbook"
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	checkpointsBucket = []byte("checkpoints")
+	journalBucket     = []byte("journal")
+)
+
+// clockSkew widens the journal scan before a checkpoint. Checkpoints are stamped
+// with the local clock and deltas with the exchange event time, so the deltas
+// that follow a checkpoint may carry a slightly earlier time. Update IDs decide
+// what is applied, the window only bounds the scan.
+const clockSkew = 5 * time.Second
+
+var (
+	ErrNoHistory    = errors.New("no history recorded")
+	ErrInvalidRange = errors.New("invalid time range")
+)
+
+// Delta is one journaled depth update.
+type Delta struct {
+	EventTime     int64      `json:"E"`
+	FirstUpdateID int        `json:"U"`
+	FinalUpdateID int        `json:"u"`
+	Bids          [][]string `json:"b"`
+	Asks          [][]string `json:"a"`
+}
+
+type checkpointRecord struct {
+	Time int64               `json:"time"`
+	Book orderbook.OrderBook `json:"book"`
+}
+
+// Store is a BoltDB-backed archive of full book checkpoints and the depth delta
+// journal, both indexed by time per venue and symbol.
+type Store struct {
+	db *bolt.DB
+}
+
+func Open(path string) (*Store, error) {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("create history directory: %w", err)
+		}
+	}
+
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("open history: %w", err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{checkpointsBucket, journalBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("init history: %w", err)
+	}
+
+	return &Store{db: db}, nil
+}
+
+// OpenReadOnly opens an archive for offline reading. BoltDB allows no readers
+// while the server holds the file, so use it on a stopped server or a copy.
+func OpenReadOnly(path string) (*Store, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second, ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("open history: %w", err)
+	}
+	return &Store{db: db}, nil
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// SaveCheckpoint records the full book as of the given time.
+func (s *Store) SaveCheckpoint(venue, symbol string, at time.Time, book *orderbook.OrderBook) error {
+	data, err := json.Marshal(checkpointRecord{Time: at.UnixMilli(), Book: *book})
+	if err != nil {
+		return err
+	}
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.Bucket(checkpointsBucket).CreateBucketIfNotExists(seriesKey(venue, symbol))
+		if err != nil {
+			return err
+		}
+		return b.Put(timeKey(at.UnixMilli()), data)
+	})
+}
+
+// Append journals deltas in a single transaction.
+func (s *Store) Append(venue, symbol string, deltas ...Delta) error {
+	if len(deltas) == 0 {
+		return nil
+	}
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.Bucket(journalBucket).CreateBucketIfNotExists(seriesKey(venue, symbol))
+		if err != nil {
+			return err
+		}
+		for _, d := range deltas {
+			data, err := json.Marshal(d)
+			if err != nil {
+				return err
+			}
+			if err := b.Put(journalKey(d.EventTime, d.FinalUpdateID), data); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Prune drops checkpoints older than before, and journal entries that no
+// remaining checkpoint can reach.
+func (s *Store) Prune(before time.Time) error {
+	cutoff := before.UnixMilli()
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		journals := tx.Bucket(journalBucket)
+
+		return tx.Bucket(checkpointsBucket).ForEachBucket(func(series []byte) error {
+			checkpoints := tx.Bucket(checkpointsBucket).Bucket(series)
+			if err := deleteBefore(checkpoints, timeKey(cutoff)); err != nil {
+				return err
+			}
+
+			journalCutoff := cutoff
+			if first, _ := checkpoints.Cursor().First(); first != nil {
+				journalCutoff = keyTime(first) - clockSkew.Milliseconds()
+			}
+			if journal := journals.Bucket(series); journal != nil {
+				return deleteBefore(journal, journalKey(journalCutoff, 0))
+			}
+			return nil
+		})
+	})
+}
+
+// Symbols lists the symbols with recorded history for a venue.
+func (s *Store) Symbols(venue string) ([]string, error) {
+	prefix := venue + "/"
+	seen := make(map[string]bool)
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{checkpointsBucket, journalBucket} {
+			b := tx.Bucket(name)
+			if b == nil {
+				continue
+			}
+			err := b.ForEachBucket(func(series []byte) error {
+				if symbol, ok := strings.CutPrefix(string(series), prefix); ok {
+					seen[symbol] = true
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	symbols := make([]string, 0, len(seen))
+	for symbol := range seen {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+	return symbols, err
+}
+
+// Bounds returns the time span covered by a symbol's checkpoints and journal.
+func (s *Store) Bounds(venue, symbol string) (first, last time.Time, ok bool) {
+	series := seriesKey(venue, symbol)
+	var lo, hi int64
+
+	_ = s.db.View(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{checkpointsBucket, journalBucket} {
+			b := tx.Bucket(name)
+			if b == nil || b.Bucket(series) == nil {
+				continue
+			}
+			c := b.Bucket(series).Cursor()
+			k, _ := c.First()
+			if k == nil {
+				continue
+			}
+			if !ok || keyTime(k) < lo {
+				lo = keyTime(k)
+			}
+			if k, _ = c.Last(); keyTime(k) > hi {
+				hi = keyTime(k)
+			}
+			ok = true
+		}
+		return nil
+	})
+
+	return time.UnixMilli(lo), time.UnixMilli(hi), ok
+}
+
+// Deltas hands every journaled delta with an event time between from and to,
+// inclusive, to fn in journal order.
+func (s *Store) Deltas(venue, symbol string, from, to time.Time, fn func(Delta) error) error {
+	return s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(journalBucket)
+		if b == nil || b.Bucket(seriesKey(venue, symbol)) == nil {
+			return nil
+		}
+
+		c := b.Bucket(seriesKey(venue, symbol)).Cursor()
+		for k, v := c.Seek(journalKey(from.UnixMilli(), 0)); k != nil && keyTime(k) <= to.UnixMilli(); k, v = c.Next() {
+			var d Delta
+			if err := json.Unmarshal(v, &d); err != nil {
+				return err
+			}
+			if err := fn(d); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Replay rebuilds the book at every step from from to to, inclusive, and hands
+// each state to fn. A zero step yields the single state at from. Samples taken
+// before the first checkpoint are skipped; ErrNoHistory is returned when none
+// could be rebuilt.
+func (s *Store) Replay(venue, symbol string, from, to time.Time, step time.Duration, depth int, fn func(Snapshot) error) error {
+	if to.Before(from) || (step <= 0 && !to.Equal(from)) {
+		return ErrInvalidRange
+	}
+
+	r := &replayer{series: seriesKey(venue, symbol)}
+	emitted := false
+
+	for at := from; !at.After(to); at = at.Add(step) {
+		// Each sample gets its own short read transaction so a slow consumer of
+		// fn never pins the database.
+		var ok bool
+		err := s.db.View(func(tx *bolt.Tx) error {
+			var err error
+			ok, err = r.advance(tx, at.UnixMilli())
+			return err
+		})
+		if err != nil {
+			return err
+		}
+
+		if ok {
+			emitted = true
+			if err := fn(r.snapshot(symbol, at.UnixMilli(), depth)); err != nil {
+				return err
+			}
+		}
+
+		if step <= 0 {
+			break
+		}
+	}
+
+	if !emitted {
+		return ErrNoHistory
+	}
+	return nil
+}
+
+// At rebuilds the book as it was at the given time.
+func (s *Store) At(venue, symbol string, at time.Time, depth int) (Snapshot, error) {
+	var snap Snapshot
+	err := s.Replay(venue, symbol, at, at, 0, depth, func(s Snapshot) error {
+		snap = s
+		return nil
+	})
+	return snap, err
+}
+
+func deleteBefore(b *bolt.Bucket, limit []byte) error {
+	c := b.Cursor()
+	for k, _ := c.First(); k != nil && bytes.Compare(k, limit) < 0; k, _ = c.First() {
+		if err := c.Delete(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func seriesKey(venue, symbol string) []byte {
+	return []byte(venue + "/" + symbol)
+}
+
+// timeKey encodes a millisecond timestamp so keys sort chronologically.
+func timeKey(ms int64) []byte {
+	ms = max(ms, 0)
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, uint64(ms))
+	return key
+}
+
+// journalKey orders deltas by event time and then by final update ID, which
+// keeps several updates within the same millisecond in stream order.
+func journalKey(ms int64, finalUpdateID int) []byte {
+	ms = max(ms, 0)
+	key := make([]byte, 16)
+	binary.BigEndian.PutUint64(key, uint64(ms))
+	binary.BigEndian.PutUint64(key[8:], uint64(finalUpdateID))
+	return key
+}
+
+func keyTime(key []byte) int64 {
+	return int64(binary.BigEndian.Uint64(key[:8]))
+}
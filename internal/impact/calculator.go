@@ -0,0 +1,172 @@
+package impact
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ChethiyaNishanath/market-data-hub/internal/consolidated"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/domain/exchange"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/domain/orderbook"
+)
+
+const (
+	SideBuy  = "buy"
+	SideSell = "sell"
+
+	SourceConsolidated = "consolidated"
+)
+
+var (
+	ErrInvalidRequest  = errors.New("invalid impact request")
+	ErrBookNotFound    = errors.New("order book not found")
+	ErrBookUnavailable = errors.New("order book is not synchronized")
+	ErrNoConsolidated  = errors.New("consolidated books are not enabled")
+	ErrEmptyBookSide   = errors.New("order book side is empty")
+)
+
+// Request describes a hypothetical market order. Exactly one of Quantity (base)
+// or Notional (quote) is set. With Consolidated the symbol names a consolidated
+// instrument instead of a single venue's book.
+type Request struct {
+	Symbol       string  `json:"symbol"`
+	Side         string  `json:"side"`
+	Quantity     float64 `json:"quantity,omitempty"`
+	Notional     float64 `json:"notional,omitempty"`
+	Consolidated bool    `json:"consolidated,omitempty"`
+}
+
+type Result struct {
+	Symbol            string  `json:"symbol"`
+	Side              string  `json:"side"`
+	Source            string  `json:"source"`
+	LastUpdateID      int     `json:"lastUpdateId,omitempty"`
+	RequestedQuantity float64 `json:"requestedQuantity,omitempty"`
+	RequestedNotional float64 `json:"requestedNotional,omitempty"`
+	FilledQuantity    float64 `json:"filledQuantity"`
+	FilledNotional    float64 `json:"filledNotional"`
+	AveragePrice      float64 `json:"averagePrice"`
+	WorstPrice        float64 `json:"worstPrice"`
+	Mid               float64 `json:"mid"`
+	SlippageBps       float64 `json:"slippageBps"`
+	LevelsConsumed    int     `json:"levelsConsumed"`
+	FullyFilled       bool    `json:"fullyFilled"`
+}
+
+// Calculator prices market orders against the live books. Each estimate works on
+// one immutable copy of the book, so it never mixes levels from two updates.
+type Calculator struct {
+	source       exchange.BookSource
+	consolidated *consolidated.Engine
+}
+
+// NewCalculator creates a calculator. consolidatedEngine may be nil when
+// consolidated books are disabled.
+func NewCalculator(source exchange.BookSource, consolidatedEngine *consolidated.Engine) *Calculator {
+	return &Calculator{source: source, consolidated: consolidatedEngine}
+}
+
+func (c *Calculator) Estimate(req Request) (Result, error) {
+	side := strings.ToLower(req.Side)
+	if side != SideBuy && side != SideSell {
+		return Result{}, fmt.Errorf("%w: side must be %q or %q", ErrInvalidRequest, SideBuy, SideSell)
+	}
+	if (req.Quantity > 0) == (req.Notional > 0) {
+		return Result{}, fmt.Errorf("%w: exactly one of quantity or notional must be positive", ErrInvalidRequest)
+	}
+	if req.Symbol == "" {
+		return Result{}, fmt.Errorf("%w: symbol is required", ErrInvalidRequest)
+	}
+
+	symbol := strings.ToUpper(req.Symbol)
+	var (
+		bids, asks []orderbook.Level
+		result     = Result{Symbol: symbol, Side: side}
+	)
+
+	if req.Consolidated {
+		if c.consolidated == nil {
+			return Result{}, ErrNoConsolidated
+		}
+		book, ok := c.consolidated.Get(symbol)
+		if !ok {
+			return Result{}, fmt.Errorf("%w: %s", ErrBookNotFound, symbol)
+		}
+		bids, asks = consolidatedLevels(book.Bids), consolidatedLevels(book.Asks)
+		result.Source = SourceConsolidated
+	} else {
+		if !c.source.IsSynchronized(symbol) {
+			return Result{}, fmt.Errorf("%w: %s", ErrBookUnavailable, symbol)
+		}
+		book := c.source.GetOrderBook(symbol)
+		if book == nil {
+			return Result{}, fmt.Errorf("%w: %s", ErrBookNotFound, symbol)
+		}
+		bids, asks = book.SortedBids(), book.SortedAsks()
+		result.Source = c.source.Name()
+		result.LastUpdateID = book.LastUpdateID
+	}
+
+	levels := asks
+	if side == SideSell {
+		levels = bids
+	}
+	if len(levels) == 0 {
+		return Result{}, fmt.Errorf("%w: %s %s", ErrEmptyBookSide, symbol, side)
+	}
+
+	if len(bids) > 0 && len(asks) > 0 {
+		result.Mid = (bids[0].Price + asks[0].Price) / 2
+	}
+
+	result.RequestedQuantity = req.Quantity
+	result.RequestedNotional = req.Notional
+	walk(&result, levels, req.Quantity, req.Notional)
+	return result, nil
+}
+
+// walk consumes levels best first until the quantity, or the notional when quantity
+// is zero, is filled, and fills in the execution fields of result.
+func walk(result *Result, levels []orderbook.Level, quantity, notional float64) {
+	for _, lvl := range levels {
+		remaining := quantity - result.FilledQuantity
+		if quantity <= 0 {
+			remaining = (notional - result.FilledNotional) / lvl.Price
+		}
+		if remaining <= 1e-12 {
+			break
+		}
+
+		take := min(remaining, lvl.Quantity)
+		result.FilledQuantity += take
+		result.FilledNotional += take * lvl.Price
+		result.WorstPrice = lvl.Price
+		result.LevelsConsumed++
+	}
+
+	if result.FilledQuantity > 0 {
+		result.AveragePrice = result.FilledNotional / result.FilledQuantity
+	}
+
+	if quantity > 0 {
+		result.FullyFilled = result.FilledQuantity >= quantity-1e-12
+	} else {
+		result.FullyFilled = result.FilledNotional >= notional-1e-9
+	}
+
+	if result.Mid > 0 && result.AveragePrice > 0 {
+		slippage := (result.AveragePrice - result.Mid) / result.Mid * 10_000
+		if result.Side == SideSell {
+			slippage = -slippage
+		}
+		result.SlippageBps = slippage
+	}
+}
+
+func consolidatedLevels(levels []consolidated.Level) []orderbook.Level {
+	converted := make([]orderbook.Level, 0, len(levels))
+	for _, lvl := range levels {
+		converted = append(converted, orderbook.Level{Price: lvl.Price, Quantity: lvl.Quantity})
+	}
+	return converted
+}
@@ -0,0 +1,72 @@
+package impact
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+)
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// HandleEstimate serves GET /impact?symbol=BTCUSDT&side=buy&quantity=2 (or
+// notional=100000 instead of quantity, and consolidated=true for a consolidated
+// instrument).
+func (c *Calculator) HandleEstimate(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	req := Request{
+		Symbol: query.Get("symbol"),
+		Side:   query.Get("side"),
+	}
+
+	var err error
+	if req.Quantity, err = parseFloat(query.Get("quantity")); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: "invalid quantity"})
+		return
+	}
+	if req.Notional, err = parseFloat(query.Get("notional")); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: "invalid notional"})
+		return
+	}
+	if raw := query.Get("consolidated"); raw != "" {
+		if req.Consolidated, err = strconv.ParseBool(raw); err != nil {
+			writeJSON(w, http.StatusBadRequest, errorResponse{Error: "invalid consolidated flag"})
+			return
+		}
+	}
+
+	result, err := c.Estimate(req)
+	if err != nil {
+		writeJSON(w, statusCode(err), errorResponse{Error: err.Error()})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+func parseFloat(raw string) (float64, error) {
+	if raw == "" {
+		return 0, nil
+	}
+	return strconv.ParseFloat(raw, 64)
+}
+
+func statusCode(err error) int {
+	switch {
+	case errors.Is(err, ErrInvalidRequest):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrBookNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusServiceUnavailable
+	}
+}
+
+func writeJSON(w http.ResponseWriter, code int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(body)
+}
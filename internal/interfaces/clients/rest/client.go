@@ -8,10 +8,37 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 )
 
+// StatusError is returned when the server answers with a non-2xx status.
+type StatusError struct {
+	StatusCode int
+	Header     http.Header
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("non-200 response: %d -> %s", e.StatusCode, e.Body)
+}
+
+// RetryAfter parses the Retry-After header, which Binance sends in seconds.
+func (e *StatusError) RetryAfter() (time.Duration, bool) {
+	value := e.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+
+	seconds, err := strconv.Atoi(value)
+	if err != nil || seconds < 0 {
+		return 0, false
+	}
+
+	return time.Duration(seconds) * time.Second, true
+}
+
 type Client struct {
 	BaseURL    string
 	ApiKey     string
@@ -50,11 +77,11 @@ func (c *Client) doRequest(
 	method, path string,
 	opts RequestOptions,
 	respBody any,
-) error {
+) (http.Header, error) {
 
 	u, err := url.Parse(c.BaseURL + path)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	q := u.Query()
@@ -67,14 +94,14 @@ func (c *Client) doRequest(
 	if opts.Body != nil {
 		jsonBytes, err := json.Marshal(opts.Body)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		body = bytes.NewBuffer(jsonBytes)
 	}
 
 	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	req.Header.Set("Content-Type", "application/json")
@@ -84,24 +111,24 @@ func (c *Client) doRequest(
 
 	res, err := c.HTTPClient.Do(req)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer res.Body.Close()
 
 	resBytes, err := io.ReadAll(res.Body)
 	if err != nil {
-		return err
+		return res.Header, err
 	}
 
 	if res.StatusCode < 200 || res.StatusCode >= 300 {
-		return fmt.Errorf("non-200 response: %d -> %s", res.StatusCode, string(resBytes))
+		return res.Header, &StatusError{StatusCode: res.StatusCode, Header: res.Header, Body: string(resBytes)}
 	}
 
 	contentType := res.Header.Get("Content-Type")
 
 	switch {
 	case strings.Contains(contentType, "application/json"):
-		return json.Unmarshal(resBytes, respBody)
+		return res.Header, json.Unmarshal(resBytes, respBody)
 
 	case strings.Contains(contentType, "text/plain"),
 		strings.Contains(contentType, "text/html"),
@@ -114,17 +141,17 @@ func (c *Client) doRequest(
 		case *[]byte:
 			*v = resBytes
 		default:
-			return fmt.Errorf("cannot decode non-JSON into %T", respBody)
+			return res.Header, fmt.Errorf("cannot decode non-JSON into %T", respBody)
 		}
 
-		return nil
+		return res.Header, nil
 
 	default:
 		if b, ok := respBody.(*[]byte); ok {
 			*b = resBytes
-			return nil
+			return res.Header, nil
 		}
-		return fmt.Errorf("unknown content-type '%s', cannot decode into %T", contentType, respBody)
+		return res.Header, fmt.Errorf("unknown content-type '%s', cannot decode into %T", contentType, respBody)
 	}
 }
 
@@ -185,21 +212,32 @@ func DoWithRetry(
 }
 
 func (c *Client) Get(ctx context.Context, path string, opts RequestOptions, resp any) error {
+	_, err := c.doRequest(ctx, http.MethodGet, path, opts, resp)
+	return err
+}
+
+// GetWithHeader behaves like Get and also returns the response headers, which are
+// available on non-2xx responses too.
+func (c *Client) GetWithHeader(ctx context.Context, path string, opts RequestOptions, resp any) (http.Header, error) {
 	return c.doRequest(ctx, http.MethodGet, path, opts, resp)
 }
 
 func (c *Client) Post(ctx context.Context, path string, opts RequestOptions, resp any) error {
-	return c.doRequest(ctx, http.MethodPost, path, opts, resp)
+	_, err := c.doRequest(ctx, http.MethodPost, path, opts, resp)
+	return err
 }
 
 func (c *Client) Put(ctx context.Context, path string, opts RequestOptions, resp any) error {
-	return c.doRequest(ctx, http.MethodPut, path, opts, resp)
+	_, err := c.doRequest(ctx, http.MethodPut, path, opts, resp)
+	return err
 }
 
 func (c *Client) Patch(ctx context.Context, path string, opts RequestOptions, resp any) error {
-	return c.doRequest(ctx, http.MethodPatch, path, opts, resp)
+	_, err := c.doRequest(ctx, http.MethodPatch, path, opts, resp)
+	return err
 }
 
 func (c *Client) Delete(ctx context.Context, path string, opts RequestOptions, resp any) error {
-	return c.doRequest(ctx, http.MethodDelete, path, opts, resp)
+	_, err := c.doRequest(ctx, http.MethodDelete, path, opts, resp)
+	return err
 }
@@ -6,28 +6,40 @@ import (
 	"errors"
 	"log/slog"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/ChethiyaNishanath/market-data-hub/internal/domain/exchange"
 	"github.com/coder/websocket"
 )
 
+var (
+	ErrConnectionClosed = errors.New("connection closed")
+	ErrStaleConnection  = errors.New("no frames received within stale timeout")
+	ErrMaxAgeReached    = errors.New("connection reached its maximum age")
+	ErrPingFailed       = errors.New("ping failed")
+)
+
 type Client struct {
 	model  exchange.Client
 	conn   *websocket.Conn
 	mu     sync.Mutex
 	ctx    context.Context
-	cancel context.CancelFunc
+	cancel context.CancelCauseFunc
+
+	lastFrame atomic.Int64
+	expired   chan struct{}
 
 	OnMessage func(msgType websocket.MessageType, data []byte)
 }
 
 func New(cont context.Context, model exchange.Client) *Client {
-	ctx, cancel := context.WithCancel(cont)
+	ctx, cancel := context.WithCancelCause(cont)
 	return &Client{
-		model:  model,
-		ctx:    ctx,
-		cancel: cancel,
+		model:   model,
+		ctx:     ctx,
+		cancel:  cancel,
+		expired: make(chan struct{}),
 	}
 }
 
@@ -41,16 +53,25 @@ func (c *Client) Connect() error {
 		}
 	}
 
+	dialCtx := c.ctx
+	if c.model.DialTimeout > 0 {
+		var cancel context.CancelFunc
+		dialCtx, cancel = context.WithTimeout(c.ctx, c.model.DialTimeout)
+		defer cancel()
+	}
+
 	var err error
-	c.conn, _, err = websocket.Dial(c.ctx, c.model.URL, nil)
+	c.conn, _, err = websocket.Dial(dialCtx, c.model.URL, nil)
 	if err != nil {
 		return err
 	}
 
 	c.conn.SetReadLimit(5 * 1024 * 1024)
+	c.lastFrame.Store(time.Now().UnixNano())
 
 	go c.readLoop()
 	go c.pingLoop()
+	go c.watchdog()
 
 	return nil
 }
@@ -60,10 +81,12 @@ func (c *Client) readLoop() {
 		msgType, data, err := c.conn.Read(c.ctx)
 		if err != nil {
 			slog.Error("WS read error", "error", err)
-			c.cancel()
+			c.cancel(err)
 			return
 		}
 
+		c.lastFrame.Store(time.Now().UnixNano())
+
 		if c.OnMessage != nil {
 			c.OnMessage(msgType, data)
 		}
@@ -78,20 +101,62 @@ func (c *Client) pingLoop() {
 		select {
 		case <-ticker.C:
 			c.mu.Lock()
+			var err error
 			if c.conn != nil {
-				err := c.conn.Ping(c.ctx)
-				if err != nil {
-					return
-				}
+				err = c.conn.Ping(c.ctx)
 			}
 			c.mu.Unlock()
 
+			if err != nil {
+				c.cancel(errors.Join(ErrPingFailed, err))
+				return
+			}
+
+		case <-c.ctx.Done():
+			return
+		}
+	}
+}
+
+// watchdog cancels the connection when the upstream goes quiet for longer than
+// StaleTimeout. Once it has been open for MaxConnectionAge it marks it expired
+// but keeps it open, so a replacement can be dialed before it is closed.
+func (c *Client) watchdog() {
+	var maxAge <-chan time.Time
+	if c.model.MaxConnectionAge > 0 {
+		timer := time.NewTimer(c.model.MaxConnectionAge)
+		defer timer.Stop()
+		maxAge = timer.C
+	}
+
+	var staleCheck <-chan time.Time
+	if c.model.StaleTimeout > 0 {
+		ticker := time.NewTicker(c.model.StaleTimeout / 2)
+		defer ticker.Stop()
+		staleCheck = ticker.C
+	}
+
+	for {
+		select {
+		case <-staleCheck:
+			if c.SinceLastFrame() > c.model.StaleTimeout {
+				c.cancel(ErrStaleConnection)
+				return
+			}
+		case <-maxAge:
+			close(c.expired)
+			maxAge = nil
 		case <-c.ctx.Done():
 			return
 		}
 	}
 }
 
+// SinceLastFrame reports how long ago the last frame was read.
+func (c *Client) SinceLastFrame() time.Duration {
+	return time.Since(time.Unix(0, c.lastFrame.Load()))
+}
+
 func (c *Client) SendJSON(v any) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -105,18 +170,30 @@ func (c *Client) SendJSON(v any) error {
 }
 
 func (c *Client) Close() {
-	c.cancel()
+	c.cancel(ErrConnectionClosed)
 
 	c.mu.Lock()
+	defer c.mu.Unlock()
 	if c.conn != nil {
 		if err := c.conn.Close(websocket.StatusNormalClosure, "shutdown"); err != nil {
 			return
 		}
 	}
-	c.mu.Unlock()
 }
 
+// BlockUntilClosed waits for the connection to end or to reach its maximum
+// age, and returns the reason. An expired connection stays open until Close.
 func (c *Client) BlockUntilClosed() error {
-	<-c.ctx.Done()
-	return errors.New("connection closed")
+	select {
+	case <-c.ctx.Done():
+	case <-c.expired:
+		if c.ctx.Err() == nil {
+			return ErrMaxAgeReached
+		}
+	}
+
+	if cause := context.Cause(c.ctx); cause != nil && !errors.Is(cause, context.Canceled) {
+		return cause
+	}
+	return ErrConnectionClosed
 }
@@ -0,0 +1,145 @@
+package websocket
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/ChethiyaNishanath/market-data-hub/internal/domain/exchange"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/retry"
+	"github.com/coder/websocket"
+)
+
+type ConnectionState string
+
+const (
+	StateConnecting   ConnectionState = "connecting"
+	StateConnected    ConnectionState = "connected"
+	StateDisconnected ConnectionState = "disconnected"
+	StateClosed       ConnectionState = "closed"
+)
+
+const (
+	defaultInitialBackoff = time.Second
+	defaultMaxBackoff     = time.Minute
+)
+
+// Supervisor keeps an upstream connection alive, reconnecting with jittered
+// exponential backoff. A connection that stayed up for StableAfter resets the backoff.
+type Supervisor struct {
+	model       exchange.Client
+	backoff     *retry.Backoff
+	StableAfter time.Duration
+
+	// OnConnect runs after every successful dial, e.g. to send subscriptions.
+	// Returning an error drops the connection and schedules a reconnect.
+	OnConnect func(client *Client) error
+
+	OnMessage     func(msgType websocket.MessageType, data []byte)
+	OnStateChange func(state ConnectionState, reason error)
+}
+
+func NewSupervisor(model exchange.Client, initialBackoff, maxBackoff time.Duration) *Supervisor {
+	if initialBackoff <= 0 {
+		initialBackoff = defaultInitialBackoff
+	}
+	if maxBackoff <= 0 {
+		maxBackoff = defaultMaxBackoff
+	}
+
+	return &Supervisor{
+		model:       model,
+		backoff:     retry.NewBackoff(initialBackoff, maxBackoff),
+		StableAfter: time.Minute,
+	}
+}
+
+// Run blocks until ctx is done. A connection that reaches its maximum age is
+// replaced by a new one before it is closed, so the stream has no gap and no
+// state change is reported; only if the replacement fails does it reconnect.
+func (s *Supervisor) Run(ctx context.Context) {
+	var (
+		client      *Client
+		connectedAt time.Time
+	)
+	for {
+		if ctx.Err() != nil {
+			s.notify(StateClosed, ctx.Err())
+			return
+		}
+
+		if client == nil {
+			s.notify(StateConnecting, nil)
+
+			var err error
+			if client, err = s.connect(ctx); err != nil {
+				s.notify(StateDisconnected, err)
+				s.wait(ctx)
+				continue
+			}
+
+			connectedAt = time.Now()
+			s.notify(StateConnected, nil)
+		}
+
+		reason := client.BlockUntilClosed()
+		if errors.Is(reason, ErrMaxAgeReached) && ctx.Err() == nil {
+			next, err := s.connect(ctx)
+			if err == nil {
+				client.Close()
+				client = next
+				slog.Info("WS connection reached max age - replaced", "url", s.model.URL)
+				continue
+			}
+			reason = errors.Join(reason, err)
+		}
+		client.Close()
+		client = nil
+
+		if ctx.Err() != nil {
+			s.notify(StateClosed, ctx.Err())
+			return
+		}
+
+		if time.Since(connectedAt) >= s.StableAfter {
+			s.backoff.Reset()
+		}
+
+		s.notify(StateDisconnected, reason)
+		slog.Warn("WS disconnected - reconnecting", "url", s.model.URL, "reason", reason)
+		s.wait(ctx)
+	}
+}
+
+// connect dials a new connection and runs OnConnect on it.
+func (s *Supervisor) connect(ctx context.Context) (*Client, error) {
+	client := New(ctx, s.model)
+	client.OnMessage = s.OnMessage
+
+	if err := client.Connect(); err != nil {
+		slog.Error("WS connect failed", "url", s.model.URL, "error", err)
+		return nil, err
+	}
+
+	if s.OnConnect != nil {
+		if err := s.OnConnect(client); err != nil {
+			slog.Error("WS post-connect setup failed", "url", s.model.URL, "error", err)
+			client.Close()
+			return nil, err
+		}
+	}
+	return client, nil
+}
+
+func (s *Supervisor) wait(ctx context.Context) {
+	delay := s.backoff.Next()
+	slog.Debug("WS reconnect scheduled", "url", s.model.URL, "delay", delay, "attempt", s.backoff.Attempt())
+	_ = retry.Sleep(ctx, delay)
+}
+
+func (s *Supervisor) notify(state ConnectionState, reason error) {
+	if s.OnStateChange != nil {
+		s.OnStateChange(state, reason)
+	}
+}
@@ -2,61 +2,279 @@ package grpc
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"log/slog"
 	"net"
+	"sort"
 	"strconv"
+	"strings"
+	"time"
 
 	pb "github.com/ChethiyaNishanath/market-data-hub/api/orderbook"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/analytics"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/candles"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/consolidated"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/domain/candle"
 	"github.com/ChethiyaNishanath/market-data-hub/internal/domain/orderbook"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/domain/trade"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/history"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/impact"
 	"github.com/ChethiyaNishanath/market-data-hub/internal/store/memory"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 var (
 	port = flag.Int("port", 50051, "The server port")
 )
 
+// Dependencies are the optional engines backing RPCs beyond GetSnapshot.
+// A nil engine makes its RPCs answer Unavailable. A non-empty Token must be
+// sent by every call as a bearer token.
+type Dependencies struct {
+	Consolidated *consolidated.Engine
+	Analytics    *analytics.Engine
+	Impact       *impact.Calculator
+	History      *history.Reader
+	Candles      *candles.Engine
+	Token        string
+}
+
 type server struct {
 	pb.UnimplementedOrderBookServer
+	deps Dependencies
 }
 
 func (s *server) GetSnapshot(_ context.Context, in *pb.OrderBookSnapshotRequest) (*pb.GetSnapshotReply, error) {
-	symbol := in.GetSymbol()
+	symbol := strings.ToUpper(in.GetSymbol())
+	if in.GetDepth() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "depth must not be negative")
+	}
+
 	store := memory.GetOrderBookStore()
 	orderBook, ok := store.GetItem(symbol)
 
 	if !ok {
 		slog.Warn("orderbook not found", "symbol", symbol)
-		return nil, fmt.Errorf("orderbook not found:: %s", symbol)
+		return nil, status.Errorf(codes.NotFound, "orderbook not found: %s", symbol)
 	}
 
-	resp := MapOrderBookToSnapshot(symbol, orderBook)
+	resp := MapOrderBookToSnapshot(symbol, orderBook, int(in.GetDepth()))
 
 	return resp, nil
 }
 
-func RunGrpcServer() {
+func (s *server) ListSymbols(context.Context, *pb.ListSymbolsRequest) (*pb.ListSymbolsReply, error) {
+	books := memory.GetOrderBookStore().GetAll()
+
+	symbols := make([]string, 0, len(books))
+	for symbol := range books {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+
+	return &pb.ListSymbolsReply{Symbols: symbols}, nil
+}
+
+func (s *server) GetConsolidatedSnapshot(_ context.Context, in *pb.ConsolidatedSnapshotRequest) (*pb.ConsolidatedSnapshotReply, error) {
+	if s.deps.Consolidated == nil {
+		return nil, status.Error(codes.Unavailable, "consolidated books are not enabled")
+	}
+
+	book, ok := s.deps.Consolidated.Get(in.GetInstrument())
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "consolidated book not found: %s", in.GetInstrument())
+	}
+
+	return MapConsolidatedBook(book), nil
+}
+
+func (s *server) StreamConsolidated(in *pb.ConsolidatedSnapshotRequest, stream grpc.ServerStreamingServer[pb.ConsolidatedSnapshotReply]) error {
+	if s.deps.Consolidated == nil {
+		return status.Error(codes.Unavailable, "consolidated books are not enabled")
+	}
+
+	books, stop := s.deps.Consolidated.Watch(in.GetInstrument())
+	defer stop()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case book := <-books:
+			if err := stream.Send(MapConsolidatedBook(book)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (s *server) GetStats(_ context.Context, in *pb.StatsRequest) (*pb.StatsReply, error) {
+	if s.deps.Analytics == nil {
+		return nil, status.Error(codes.Unavailable, "analytics are not enabled")
+	}
+
+	stats, ok := s.deps.Analytics.Get(in.GetSymbol())
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "stats not found: %s", in.GetSymbol())
+	}
+
+	return MapStats(stats), nil
+}
+
+func (s *server) EstimateImpact(_ context.Context, in *pb.ImpactRequest) (*pb.ImpactReply, error) {
+	if s.deps.Impact == nil {
+		return nil, status.Error(codes.Unavailable, "impact calculator is not available")
+	}
+
+	result, err := s.deps.Impact.Estimate(impact.Request{
+		Symbol:       in.GetSymbol(),
+		Side:         in.GetSide(),
+		Quantity:     in.GetQuantity(),
+		Notional:     in.GetNotional(),
+		Consolidated: in.GetConsolidated(),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, impact.ErrInvalidRequest):
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		case errors.Is(err, impact.ErrBookNotFound):
+			return nil, status.Error(codes.NotFound, err.Error())
+		default:
+			return nil, status.Error(codes.Unavailable, err.Error())
+		}
+	}
+
+	return MapImpactResult(result), nil
+}
+
+func (s *server) GetHistoricalSnapshot(_ context.Context, in *pb.HistoricalSnapshotRequest) (*pb.HistoricalSnapshotReply, error) {
+	if s.deps.History == nil {
+		return nil, status.Error(codes.Unavailable, "order book history is not enabled")
+	}
+
+	snap, err := s.deps.History.At(in.GetSymbol(), time.UnixMilli(in.GetTimestamp()), int(in.GetDepth()))
+	if err != nil {
+		return nil, historyError(err)
+	}
+
+	return MapHistoricalSnapshot(snap), nil
+}
+
+func (s *server) StreamHistoricalRange(in *pb.HistoricalRangeRequest, stream grpc.ServerStreamingServer[pb.HistoricalSnapshotReply]) error {
+	if s.deps.History == nil {
+		return status.Error(codes.Unavailable, "order book history is not enabled")
+	}
+
+	err := s.deps.History.Range(
+		in.GetSymbol(),
+		time.UnixMilli(in.GetFrom()),
+		time.UnixMilli(in.GetTo()),
+		time.Duration(in.GetIntervalMs())*time.Millisecond,
+		int(in.GetDepth()),
+		func(snap history.Snapshot) error {
+			return stream.Send(MapHistoricalSnapshot(snap))
+		},
+	)
+	if err != nil {
+		if _, ok := status.FromError(err); ok {
+			return err
+		}
+		return historyError(err)
+	}
+	return nil
+}
+
+func historyError(err error) error {
+	switch {
+	case errors.Is(err, history.ErrInvalidRange):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, history.ErrNoHistory):
+		return status.Error(codes.NotFound, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+func (s *server) GetCandles(_ context.Context, in *pb.CandlesRequest) (*pb.CandlesReply, error) {
+	if s.deps.Candles == nil {
+		return nil, status.Error(codes.Unavailable, "candles are not enabled")
+	}
+
+	list, err := s.deps.Candles.Candles(in.GetSymbol(), in.GetInterval(), optionalTime(in.GetFrom()), optionalTime(in.GetTo()), int(in.GetLimit()))
+	if err != nil {
+		return nil, candlesError(err)
+	}
+
+	return MapCandles(in.GetSymbol(), in.GetInterval(), list), nil
+}
+
+func (s *server) GetTrades(_ context.Context, in *pb.TradesRequest) (*pb.TradesReply, error) {
+	if s.deps.Candles == nil {
+		return nil, status.Error(codes.Unavailable, "trade history is not enabled")
+	}
+
+	list, err := s.deps.Candles.Trades(in.GetSymbol(), optionalTime(in.GetFrom()), optionalTime(in.GetTo()), int(in.GetLimit()))
+	if err != nil {
+		return nil, candlesError(err)
+	}
+
+	return MapTrades(in.GetSymbol(), list), nil
+}
+
+// optionalTime maps an unset millisecond timestamp to the zero time.
+func optionalTime(ms int64) time.Time {
+	if ms == 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(ms)
+}
+
+func candlesError(err error) error {
+	if errors.Is(err, candles.ErrInvalidQuery) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
+// NewServer creates the gRPC server with the order book service registered.
+func NewServer(deps Dependencies) *grpc.Server {
+	opts := []grpc.ServerOption{grpc.StatsHandler(otelgrpc.NewServerHandler())}
+	if deps.Token != "" {
+		unary, stream := tokenInterceptors(deps.Token)
+		opts = append(opts, grpc.ChainUnaryInterceptor(unary), grpc.ChainStreamInterceptor(stream))
+	}
+
+	s := grpc.NewServer(opts...)
+	pb.RegisterOrderBookServer(s, &server{deps: deps})
+	return s
+}
+
+func RunGrpcServer(deps Dependencies) {
 	flag.Parse()
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", *port))
 	if err != nil {
 		slog.Error("Failed to listen grpc: %v", "error", err)
 	}
-	s := grpc.NewServer()
-	pb.RegisterOrderBookServer(s, &server{})
+	s := NewServer(deps)
 	slog.Info("GRPC server listening at " + lis.Addr().String())
 	if err := s.Serve(lis); err != nil {
 		slog.Error("Failed to serve grpc: %v", "error", err)
 	}
 }
 
-func MapOrderBookToSnapshot(symbol string, ob *orderbook.OrderBook) *pb.GetSnapshotReply {
+// MapOrderBookToSnapshot returns the best depth levels of each side, best
+// first. A depth of 0 keeps every level.
+func MapOrderBookToSnapshot(symbol string, ob *orderbook.OrderBook, depth int) *pb.GetSnapshotReply {
 	return &pb.GetSnapshotReply{
 		Symbol:       symbol,
 		LastUpdateId: strconv.Itoa(ob.LastUpdateID),
-		Bids:         mapLevels(ob.Bids),
-		Asks:         mapLevels(ob.Asks),
+		Bids:         mapLevels(orderbook.BestLevels(ob.Bids, depth, true)),
+		Asks:         mapLevels(orderbook.BestLevels(ob.Asks, depth, false)),
 	}
 }
 
@@ -76,3 +294,125 @@ func mapLevels(levels [][]string) []*pb.Order {
 
 	return orders
 }
+
+func MapConsolidatedBook(book consolidated.Book) *pb.ConsolidatedSnapshotReply {
+	return &pb.ConsolidatedSnapshotReply{
+		Instrument:     book.Instrument,
+		Bids:           mapConsolidatedLevels(book.Bids),
+		Asks:           mapConsolidatedLevels(book.Asks),
+		Venues:         book.Venues,
+		ExcludedVenues: book.ExcludedVenues,
+		Timestamp:      book.Timestamp,
+	}
+}
+
+func mapConsolidatedLevels(levels []consolidated.Level) []*pb.ConsolidatedLevel {
+	mapped := make([]*pb.ConsolidatedLevel, 0, len(levels))
+
+	for _, lvl := range levels {
+		venues := make([]*pb.VenueQuantity, 0, len(lvl.Venues))
+		for _, v := range lvl.Venues {
+			venues = append(venues, &pb.VenueQuantity{Venue: v.Venue, Quantity: v.Quantity})
+		}
+
+		mapped = append(mapped, &pb.ConsolidatedLevel{
+			Price:    lvl.Price,
+			Quantity: lvl.Quantity,
+			Venues:   venues,
+		})
+	}
+
+	return mapped
+}
+
+func MapStats(stats analytics.Stats) *pb.StatsReply {
+	bands := make([]*pb.DepthBand, 0, len(stats.DepthBands))
+	for _, band := range stats.DepthBands {
+		bands = append(bands, &pb.DepthBand{
+			Bps:         band.Bps,
+			BidQuantity: band.BidQuantity,
+			AskQuantity: band.AskQuantity,
+			BidNotional: band.BidNotional,
+			AskNotional: band.AskNotional,
+		})
+	}
+
+	return &pb.StatsReply{
+		Symbol:          stats.Symbol,
+		LastUpdateId:    int64(stats.LastUpdateID),
+		BestBid:         stats.BestBid,
+		BestBidQuantity: stats.BestBidQuantity,
+		BestAsk:         stats.BestAsk,
+		BestAskQuantity: stats.BestAskQuantity,
+		Mid:             stats.Mid,
+		Microprice:      stats.Microprice,
+		SpreadBps:       stats.SpreadBps,
+		Imbalance:       stats.Imbalance,
+		ImbalanceLevels: int32(stats.ImbalanceLevels),
+		DepthBands:      bands,
+		Timestamp:       stats.Timestamp,
+	}
+}
+
+func MapImpactResult(result impact.Result) *pb.ImpactReply {
+	return &pb.ImpactReply{
+		Symbol:            result.Symbol,
+		Side:              result.Side,
+		Source:            result.Source,
+		LastUpdateId:      int64(result.LastUpdateID),
+		RequestedQuantity: result.RequestedQuantity,
+		RequestedNotional: result.RequestedNotional,
+		FilledQuantity:    result.FilledQuantity,
+		FilledNotional:    result.FilledNotional,
+		AveragePrice:      result.AveragePrice,
+		WorstPrice:        result.WorstPrice,
+		Mid:               result.Mid,
+		SlippageBps:       result.SlippageBps,
+		LevelsConsumed:    int32(result.LevelsConsumed),
+		FullyFilled:       result.FullyFilled,
+	}
+}
+
+func MapHistoricalSnapshot(snap history.Snapshot) *pb.HistoricalSnapshotReply {
+	return &pb.HistoricalSnapshotReply{
+		Symbol:              snap.Symbol,
+		Timestamp:           snap.Timestamp,
+		LastUpdateId:        int64(snap.LastUpdateID),
+		Bids:                mapLevels(snap.Bids),
+		Asks:                mapLevels(snap.Asks),
+		CheckpointTimestamp: snap.CheckpointTime,
+		DeltasApplied:       int32(snap.DeltasApplied),
+		Complete:            snap.Complete,
+	}
+}
+
+func MapCandles(symbol, interval string, list []candle.Candle) *pb.CandlesReply {
+	reply := &pb.CandlesReply{Symbol: symbol, Interval: interval, Candles: make([]*pb.Candle, 0, len(list))}
+	for _, c := range list {
+		reply.Candles = append(reply.Candles, &pb.Candle{
+			OpenTime:    c.OpenTime.UnixMilli(),
+			Open:        c.Open,
+			High:        c.High,
+			Low:         c.Low,
+			Close:       c.Close,
+			Volume:      c.Volume,
+			QuoteVolume: c.QuoteVolume,
+			Trades:      int32(c.Trades),
+		})
+	}
+	return reply
+}
+
+func MapTrades(symbol string, list []trade.Trade) *pb.TradesReply {
+	reply := &pb.TradesReply{Symbol: symbol, Trades: make([]*pb.Trade, 0, len(list))}
+	for _, t := range list {
+		reply.Trades = append(reply.Trades, &pb.Trade{
+			Id:       t.ID,
+			Price:    t.Price,
+			Quantity: t.Quantity,
+			Side:     t.Side,
+			Time:     t.Time.UnixMilli(),
+		})
+	}
+	return reply
+}
@@ -0,0 +1,67 @@
+package websocket
+
+import (
+	"time"
+
+	"github.com/coder/websocket"
+)
+
+// Slow-consumer policies decide what happens to the pushes of a client whose
+// queue has reached its high-water mark.
+const (
+	// PolicyResnapshot drops the client's pushes and, once it has caught up,
+	// sends the current state of each topic that lost pushes: an
+	// orderbook_reset for depth topics, a snapshot for the others.
+	PolicyResnapshot = "resnapshot"
+	// PolicyDisconnect closes the connection with StatusSlowConsumer.
+	PolicyDisconnect = "disconnect"
+	// PolicyConflate keeps the latest push of each topic and sends it once the
+	// client has caught up. Topics whose state can be resent, depth among
+	// them, get that instead, as depth updates cannot be merged by keeping the
+	// latest.
+	PolicyConflate = "conflate"
+)
+
+// Policies lists the slow-consumer policies.
+var Policies = []string{PolicyResnapshot, PolicyDisconnect, PolicyConflate}
+
+// StatusSlowConsumer is the close code a client disconnected for being slow
+// gets.
+const StatusSlowConsumer = websocket.StatusTryAgainLater
+
+// SendBufferSize is the number of messages queued for a client by default.
+const SendBufferSize = 256
+
+// DefaultWriteTimeout bounds a single write to a client by default.
+const DefaultWriteTimeout = 10 * time.Second
+
+// Options configure the outbound queue of a client. Zero values take the
+// defaults: a SendBufferSize queue, a high-water mark at its capacity,
+// DefaultWriteTimeout and PolicyResnapshot.
+type Options struct {
+	SendBuffer    int
+	HighWaterMark int
+	WriteTimeout  time.Duration
+	SlowConsumer  string
+
+	// Resync returns a message restating the current state of topic, false
+	// when the topic has none.
+	Resync func(topic string) ([]byte, bool)
+}
+
+// WithDefaults returns o with its zero values replaced by the defaults.
+func (o Options) WithDefaults() Options {
+	if o.SendBuffer <= 0 {
+		o.SendBuffer = SendBufferSize
+	}
+	if o.HighWaterMark <= 0 || o.HighWaterMark > o.SendBuffer {
+		o.HighWaterMark = o.SendBuffer
+	}
+	if o.WriteTimeout <= 0 {
+		o.WriteTimeout = DefaultWriteTimeout
+	}
+	if o.SlowConsumer == "" {
+		o.SlowConsumer = PolicyResnapshot
+	}
+	return o
+}
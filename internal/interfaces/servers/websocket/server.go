@@ -3,25 +3,63 @@ package websocket
 import (
 	"context"
 	"log/slog"
+	"maps"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/ChethiyaNishanath/market-data-hub/internal/domain/subscription"
 	"github.com/coder/websocket"
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
+var tracer = otel.Tracer("github.com/ChethiyaNishanath/market-data-hub/internal/interfaces/servers/websocket")
+
+type outboundMessage struct {
+	topic       string
+	data        []byte
+	queuedAt    time.Time
+	spanContext trace.SpanContext
+}
+
 type WSClient struct {
-	id     uuid.UUID
-	conn   *websocket.Conn
-	sendCh chan []byte
+	id          uuid.UUID
+	conn        *websocket.Conn
+	opts        Options
+	connectedAt time.Time
+	sendCh      chan outboundMessage
+
+	mu     sync.Mutex
 	topics map[string]bool
+
+	// queueMu orders the pushes of a topic while it is held back. pending
+	// holds the latest held back push of each such topic and queued counts
+	// the queued messages of each topic.
+	queueMu sync.Mutex
+	pending map[string]outboundMessage
+	queued  map[string]int
+	closing sync.Once
+
+	peak                              atomic.Int64
+	lag                               atomic.Int64
+	sent, dropped, conflated, resyncs atomic.Uint64
 }
 
-func NewWsClient(conn *websocket.Conn) *WSClient {
+func NewWsClient(conn *websocket.Conn, opts Options) *WSClient {
+	opts = opts.WithDefaults()
 	return &WSClient{
-		id:     uuid.New(),
-		conn:   conn,
-		sendCh: make(chan []byte, 256),
-		topics: make(map[string]bool),
+		id:          uuid.New(),
+		conn:        conn,
+		opts:        opts,
+		connectedAt: time.Now(),
+		sendCh:      make(chan outboundMessage, opts.SendBuffer),
+		topics:      make(map[string]bool),
+		pending:     make(map[string]outboundMessage),
+		queued:      make(map[string]int),
 	}
 }
 
@@ -34,18 +72,107 @@ func (s *WSClient) Conn() *websocket.Conn {
 }
 
 func (s *WSClient) AddTopic(topic string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	s.topics[topic] = true
 }
 
 func (s *WSClient) RemoveTopic(topic string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	delete(s.topics, topic)
 }
 
+// Topics lists the topics the client is subscribed to, sorted.
+func (s *WSClient) Topics() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	topics := make([]string, 0, len(s.topics))
+	for topic := range s.topics {
+		topics = append(topics, topic)
+	}
+	slices.Sort(topics)
+	return topics
+}
+
 func (s *WSClient) Send(data []byte) {
+	s.SendContext(context.Background(), data)
+}
+
+func (s *WSClient) SendContext(ctx context.Context, data []byte) {
+	s.SendTopic(ctx, "", data)
+}
+
+// SendTopic queues data for the client. Once the queue has reached the
+// high-water mark the slow-consumer policy decides what happens to it.
+func (s *WSClient) SendTopic(ctx context.Context, topic string, data []byte) {
+	msg := outboundMessage{topic: topic, data: data, queuedAt: time.Now(), spanContext: trace.SpanContextFromContext(ctx)}
+
+	s.queueMu.Lock()
+	defer s.queueMu.Unlock()
+
+	if _, held := s.pending[topic]; held && topic != "" {
+		s.holdBack(msg)
+		return
+	}
+	if len(s.sendCh) >= s.opts.HighWaterMark {
+		s.overflow(msg)
+		return
+	}
+
 	select {
-	case s.sendCh <- data:
+	case s.sendCh <- msg:
+		s.queued[topic]++
+		s.observeQueue()
 	default:
-		slog.Warn("send buffer full, dropping message", "client_id", s.ID())
+		s.overflow(msg)
+	}
+}
+
+// overflow applies the slow-consumer policy to msg. The caller holds queueMu.
+func (s *WSClient) overflow(msg outboundMessage) {
+	if s.opts.SlowConsumer == PolicyDisconnect {
+		s.dropped.Add(1)
+		s.closing.Do(func() {
+			slog.Warn("Disconnecting slow client", "client_id", s.ID(), "queued", len(s.sendCh))
+			go func() {
+				_ = s.conn.Close(StatusSlowConsumer, "slow consumer")
+			}()
+		})
+		return
+	}
+	if msg.topic == "" {
+		s.dropped.Add(1)
+		slog.Warn("Send queue full, dropping message", "client_id", s.ID())
+		return
+	}
+	if len(s.pending) == 0 {
+		slog.Warn("Client is slow, holding back its pushes", "client_id", s.ID(), "policy", s.opts.SlowConsumer)
+	}
+	s.holdBack(msg)
+}
+
+// holdBack keeps msg as the latest push of its topic until the client catches
+// up. The caller holds queueMu.
+func (s *WSClient) holdBack(msg outboundMessage) {
+	_, held := s.pending[msg.topic]
+	switch {
+	case s.opts.SlowConsumer != PolicyConflate:
+		s.dropped.Add(1)
+	case held:
+		s.conflated.Add(1)
+	}
+	s.pending[msg.topic] = msg
+}
+
+// observeQueue raises the peak queue length to the current one.
+func (s *WSClient) observeQueue() {
+	queued := int64(len(s.sendCh))
+	for {
+		peak := s.peak.Load()
+		if queued <= peak || s.peak.CompareAndSwap(peak, queued) {
+			return
+		}
 	}
 }
 
@@ -53,6 +180,28 @@ func (s *WSClient) Close(reason string) error {
 	return s.conn.Close(websocket.StatusNormalClosure, reason)
 }
 
+// Stats reports the client's outbound queue.
+func (s *WSClient) Stats() subscription.ClientStats {
+	s.queueMu.Lock()
+	slow := len(s.pending) > 0
+	s.queueMu.Unlock()
+
+	return subscription.ClientStats{
+		ID:            s.ID(),
+		ConnectedAt:   s.connectedAt,
+		Topics:        s.Topics(),
+		Queued:        len(s.sendCh),
+		PeakQueued:    int(s.peak.Load()),
+		HighWaterMark: s.opts.HighWaterMark,
+		Slow:          slow,
+		LagMs:         time.Duration(s.lag.Load()).Milliseconds(),
+		Sent:          s.sent.Load(),
+		Dropped:       s.dropped.Load(),
+		Conflated:     s.conflated.Load(),
+		Resyncs:       s.resyncs.Load(),
+	}
+}
+
 func (s *WSClient) WritePump(ctx context.Context) {
 	for {
 		select {
@@ -60,11 +209,15 @@ func (s *WSClient) WritePump(ctx context.Context) {
 			if !ok {
 				return
 			}
-
-			if err := s.conn.Write(ctx, websocket.MessageText, msg); err != nil {
-				slog.Error("write error:", "error", err)
+			if err := s.write(ctx, msg); err != nil {
 				return
 			}
+			s.dequeued(msg.topic)
+			if len(s.sendCh) <= s.opts.HighWaterMark/2 {
+				if err := s.catchUp(ctx); err != nil {
+					return
+				}
+			}
 
 		case <-ctx.Done():
 			return
@@ -72,6 +225,69 @@ func (s *WSClient) WritePump(ctx context.Context) {
 	}
 }
 
+func (s *WSClient) dequeued(topic string) {
+	s.queueMu.Lock()
+	defer s.queueMu.Unlock()
+	if s.queued[topic]--; s.queued[topic] <= 0 {
+		delete(s.queued, topic)
+	}
+}
+
+// catchUp sends the held back topics once the queue has drained below half
+// the high-water mark and holds none of their older pushes: the topic's
+// current state when Resync has it, and otherwise the latest push under the
+// conflate policy.
+func (s *WSClient) catchUp(ctx context.Context) error {
+	s.queueMu.Lock()
+	pending := make(map[string]outboundMessage)
+	for topic, msg := range s.pending {
+		if s.queued[topic] == 0 {
+			pending[topic] = msg
+			delete(s.pending, topic)
+		}
+	}
+	s.queueMu.Unlock()
+
+	for _, topic := range slices.Sorted(maps.Keys(pending)) {
+		msg := pending[topic]
+		if data, ok := s.resync(topic); ok {
+			s.resyncs.Add(1)
+			msg.data = data
+		} else if s.opts.SlowConsumer != PolicyConflate {
+			continue
+		}
+		if err := s.write(ctx, msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *WSClient) resync(topic string) ([]byte, bool) {
+	if s.opts.Resync == nil {
+		return nil, false
+	}
+	return s.opts.Resync(topic)
+}
+
+func (s *WSClient) write(ctx context.Context, msg outboundMessage) error {
+	_, span := tracer.Start(trace.ContextWithSpanContext(ctx, msg.spanContext), "ws.write")
+	defer span.End()
+	span.SetAttributes(attribute.String("client_id", s.ID()))
+
+	writeCtx, cancel := context.WithTimeout(ctx, s.opts.WriteTimeout)
+	defer cancel()
+
+	if err := s.conn.Write(writeCtx, websocket.MessageText, msg.data); err != nil {
+		span.RecordError(err)
+		slog.Error("write error:", "client_id", s.ID(), "error", err)
+		return err
+	}
+	s.sent.Add(1)
+	s.lag.Store(int64(time.Since(msg.queuedAt)))
+	return nil
+}
+
 func (s *WSClient) ReadPump(ctx context.Context, m subscription.ClientConnectionManager) {
 	defer func() {
 		m.Unregister(s)
@@ -0,0 +1,160 @@
+package ladder
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/ChethiyaNishanath/market-data-hub/internal/domain/orderbook"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/exchange/binance"
+)
+
+// gapTimeout is how long a missing update may stay missing before the book is
+// declared out of sync. The hub broadcasts from concurrent bus handlers, so
+// updates can arrive slightly out of order.
+const gapTimeout = time.Second
+
+// maxPending bounds the updates buffered while waiting for a snapshot or a
+// missing update.
+const maxPending = 10_000
+
+// Level is a price level of the local book. Changed is when its quantity last
+// changed, zero if it has not changed since the snapshot.
+type Level struct {
+	Price     float64
+	Quantity  float64
+	PriceText string
+	QtyText   string
+	Changed   time.Time
+}
+
+type side map[string]*Level
+
+// Book is the client's copy of one symbol's book, kept from the subscribe
+// snapshot and the depth updates that follow it.
+type Book struct {
+	Symbol       string
+	LastUpdateID int
+	Provisional  bool
+
+	bids, asks side
+	seq        *orderbook.Sequencer
+}
+
+func NewBook(symbol string) *Book {
+	return &Book{
+		Symbol: symbol,
+		bids:   make(side),
+		asks:   make(side),
+		seq:    orderbook.NewSequencer(gapTimeout, maxPending),
+	}
+}
+
+// Loaded reports whether a snapshot has been applied.
+func (b *Book) Loaded() bool {
+	return b.seq.Loaded()
+}
+
+// OutOfSync reports whether an update went missing for longer than gapTimeout.
+// The book must then be reloaded from a fresh snapshot.
+func (b *Book) OutOfSync() bool {
+	return b.seq.OutOfSync()
+}
+
+// Load replaces the book with a snapshot and applies the buffered updates that
+// follow it.
+func (b *Book) Load(snapshot orderbook.OrderBook, now time.Time) {
+	b.bids, b.asks = make(side), make(side)
+	b.LastUpdateID = snapshot.LastUpdateID
+	b.Provisional = snapshot.Provisional
+
+	for _, lvl := range snapshot.Bids {
+		b.bids.set(lvl, time.Time{})
+	}
+	for _, lvl := range snapshot.Asks {
+		b.asks.set(lvl, time.Time{})
+	}
+	b.apply(b.seq.Load(int64(snapshot.LastUpdateID), now), now)
+}
+
+// Apply adds a depth update. It returns false when the update was not applied
+// now: it is stale, or buffered until the snapshot or a missing update arrives.
+func (b *Book) Apply(ev binance.DepthUpdateEvent, now time.Time) bool {
+	return b.apply(b.seq.Push(orderbook.Delta{
+		First: int64(ev.FirstUpdateEventID),
+		Final: int64(ev.FinalUpdateEventID),
+		Bids:  ev.BidsToUpdated,
+		Asks:  ev.AsksToUpdated,
+	}, now), now) > 0
+}
+
+// Check marks the book out of sync once a missing update has been waited for
+// longer than gapTimeout.
+func (b *Book) Check(now time.Time) {
+	b.seq.Check(now)
+}
+
+func (b *Book) apply(deltas []orderbook.Delta, now time.Time) int {
+	for _, d := range deltas {
+		_ = orderbook.ApplyLevels(stamped{b, now}, d.Bids, d.Asks)
+		b.LastUpdateID = int(d.Final)
+		b.Provisional = false
+	}
+	return len(deltas)
+}
+
+// stamped writes delta levels to a book, marking them changed at now.
+type stamped struct {
+	b   *Book
+	now time.Time
+}
+
+func (s stamped) UpdateBid(price, qty string) { s.b.bids.set([]string{price, qty}, s.now) }
+func (s stamped) RemoveBid(price string)      { delete(s.b.bids, price) }
+func (s stamped) UpdateAsk(price, qty string) { s.b.asks.set([]string{price, qty}, s.now) }
+func (s stamped) RemoveAsk(price string)      { delete(s.b.asks, price) }
+
+// Bids returns up to n bids, best first.
+func (b *Book) Bids(n int) []Level {
+	return b.bids.sorted(n, func(a, c float64) bool { return a > c })
+}
+
+// Asks returns up to n asks, best first.
+func (b *Book) Asks(n int) []Level {
+	return b.asks.sorted(n, func(a, c float64) bool { return a < c })
+}
+
+func (s side) set(lvl []string, now time.Time) {
+	if len(lvl) < 2 {
+		return
+	}
+	price, err := strconv.ParseFloat(lvl[0], 64)
+	if err != nil {
+		return
+	}
+	qty, err := strconv.ParseFloat(lvl[1], 64)
+	if err != nil {
+		return
+	}
+
+	if qty == 0 {
+		delete(s, lvl[0])
+		return
+	}
+	if existing, ok := s[lvl[0]]; ok && existing.Quantity == qty {
+		return
+	}
+	s[lvl[0]] = &Level{Price: price, Quantity: qty, PriceText: lvl[0], QtyText: lvl[1], Changed: now}
+}
+
+func (s side) sorted(n int, better func(a, b float64) bool) []Level {
+	levels := make([]Level, 0, len(s))
+	for _, lvl := range s {
+		levels = append(levels, *lvl)
+	}
+	sort.Slice(levels, func(i, j int) bool { return better(levels[i].Price, levels[j].Price) })
+	if n > 0 && len(levels) > n {
+		levels = levels[:n]
+	}
+	return levels
+}
@@ -0,0 +1,238 @@
+package ladder
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ChethiyaNishanath/market-data-hub/internal/domain/orderbook"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/exchange/binance"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/retry"
+	"github.com/coder/websocket"
+)
+
+// Messages delivered by Feed. Each carries the symbol it belongs to, so the
+// ones still in flight after a switch can be told apart.
+type (
+	SnapshotMsg struct {
+		Symbol string
+		Book   orderbook.OrderBook
+	}
+	DepthMsg struct {
+		Update binance.DepthUpdateEvent
+	}
+	ResetMsg struct {
+		Event binance.OrderBookResetEvent
+	}
+	TradeMsg struct {
+		Trade binance.TradeEvent
+	}
+	ConnectionMsg struct {
+		Event binance.ConnectionStateEvent
+	}
+	// HubMsg reports the client's own connection to the hub.
+	HubMsg struct {
+		Connected bool
+		Err       error
+	}
+	// ErrorMsg is a request the hub rejected, e.g. an unknown symbol.
+	ErrorMsg struct {
+		Err error
+	}
+)
+
+// Feed follows one symbol at a time over the hub's WebSocket endpoint and
+// reconnects with backoff when the connection drops.
+type Feed struct {
+	url     string
+	msgs    chan any
+	backoff *retry.Backoff
+
+	mu     sync.Mutex
+	conn   *websocket.Conn
+	symbol string
+}
+
+func NewFeed(url string) *Feed {
+	return &Feed{
+		url:     url,
+		msgs:    make(chan any, 4096),
+		backoff: retry.NewBackoff(500*time.Millisecond, 10*time.Second),
+	}
+}
+
+// Messages returns the decoded hub messages.
+func (f *Feed) Messages() <-chan any {
+	return f.msgs
+}
+
+// Watch switches the feed to symbol. The book arrives as a SnapshotMsg.
+func (f *Feed) Watch(symbol string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	previous := f.symbol
+	f.symbol = strings.ToUpper(symbol)
+	if f.conn == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if previous != "" {
+		if err := f.send(ctx, "unsubscribe", previous); err != nil {
+			return err
+		}
+	}
+	return f.send(ctx, "subscribe", f.symbol)
+}
+
+// Resync subscribes to the current symbol again, which makes the hub send a
+// fresh snapshot.
+func (f *Feed) Resync() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.conn == nil || f.symbol == "" {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return f.send(ctx, "subscribe", f.symbol)
+}
+
+// Run connects and reads until ctx is done.
+func (f *Feed) Run(ctx context.Context) {
+	for ctx.Err() == nil {
+		err := f.session(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		f.deliver(ctx, HubMsg{Err: err})
+		if retry.Sleep(ctx, f.backoff.Next()) != nil {
+			return
+		}
+	}
+}
+
+func (f *Feed) session(ctx context.Context) error {
+	conn, _, err := websocket.Dial(ctx, f.url, nil)
+	if err != nil {
+		return err
+	}
+	conn.SetReadLimit(16 * 1024 * 1024)
+	defer conn.CloseNow()
+
+	f.mu.Lock()
+	f.conn = conn
+	err = nil
+	if f.symbol != "" {
+		err = f.send(ctx, "subscribe", f.symbol)
+	}
+	f.mu.Unlock()
+	defer func() {
+		f.mu.Lock()
+		f.conn = nil
+		f.mu.Unlock()
+	}()
+	if err != nil {
+		return err
+	}
+
+	f.backoff.Reset()
+	f.deliver(ctx, HubMsg{Connected: true})
+
+	for {
+		_, data, err := conn.Read(ctx)
+		if err != nil {
+			return err
+		}
+		if msg, ok := Decode(data); ok {
+			f.deliver(ctx, msg)
+		}
+	}
+}
+
+// send (un)subscribes every topic of symbol. The caller holds f.mu.
+func (f *Feed) send(ctx context.Context, method, symbol string) error {
+	lower := strings.ToLower(symbol)
+	for _, topic := range []string{lower + "@depth", lower + "@depth.reset", lower + "@trade", lower + "@connection"} {
+		data, err := json.Marshal(map[string]any{"method": method, "params": map[string]string{"topic": topic}})
+		if err != nil {
+			return err
+		}
+		if err := f.conn.Write(ctx, websocket.MessageText, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *Feed) deliver(ctx context.Context, msg any) {
+	select {
+	case f.msgs <- msg:
+	case <-ctx.Done():
+	}
+}
+
+// Decode maps a hub WebSocket message to one of the Feed messages.
+func Decode(data []byte) (any, bool) {
+	var msg struct {
+		Method  string          `json:"method"`
+		Success bool            `json:"success"`
+		Error   string          `json:"error"`
+		Topic   string          `json:"topic"`
+		Data    json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return nil, false
+	}
+
+	switch msg.Method {
+	case "subscribe":
+		if msg.Error != "" {
+			return ErrorMsg{Err: errors.New(msg.Error)}, true
+		}
+		symbol, event, _ := strings.Cut(msg.Topic, "@")
+		if event != "depth" {
+			return nil, false
+		}
+		var book orderbook.OrderBook
+		if err := json.Unmarshal(msg.Data, &book); err != nil {
+			return nil, false
+		}
+		return SnapshotMsg{Symbol: strings.ToUpper(symbol), Book: book}, true
+
+	case "orderbook_reset":
+		var ev binance.OrderBookResetEvent
+		if err := json.Unmarshal(msg.Data, &ev); err != nil {
+			return nil, false
+		}
+		return ResetMsg{Event: ev}, true
+
+	case binance.Trade:
+		var ev binance.TradeEvent
+		if err := json.Unmarshal(msg.Data, &ev); err != nil {
+			return nil, false
+		}
+		return TradeMsg{Trade: ev}, true
+
+	case "connection_state":
+		var ev binance.ConnectionStateEvent
+		if err := json.Unmarshal(msg.Data, &ev); err != nil {
+			return nil, false
+		}
+		return ConnectionMsg{Event: ev}, true
+
+	case "":
+		var ev binance.DepthUpdateEvent
+		if len(msg.Data) == 0 || json.Unmarshal(msg.Data, &ev) != nil || ev.Symbol == "" {
+			return nil, false
+		}
+		return DepthMsg{Update: ev}, true
+	}
+	return nil, false
+}
@@ -0,0 +1,276 @@
+package ladder
+
+import (
+	"strings"
+	"time"
+
+	"github.com/ChethiyaNishanath/market-data-hub/internal/exchange/binance"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	MaxDepth = 100
+
+	tickInterval = 100 * time.Millisecond
+	// highlightFor is how long a changed level stays highlighted.
+	highlightFor = 700 * time.Millisecond
+)
+
+// Watcher switches the hub feed between symbols.
+type Watcher interface {
+	Watch(symbol string) error
+	Resync() error
+}
+
+type tickMsg time.Time
+
+// feedMsg wraps a Feed message so only those re-arm the listener; a single
+// listener keeps them in order.
+type feedMsg struct {
+	msg any
+}
+
+// Model is the ladder TUI. It shows one symbol at a time; the others in the
+// list, or any typed after "/", are a key press away.
+type Model struct {
+	feed    Watcher
+	msgs    <-chan any
+	symbols []string
+	current int
+	depth   int
+
+	book      *Book
+	lastTrade *binance.TradeEvent
+	// tradeTick is the direction of the last trade price: 1 up, -1 down, 0 unchanged.
+	tradeTick    int
+	hubConnected bool
+	hubErr       error
+	venueState   string
+	resetReason  string
+	err          error
+
+	updates   int
+	rate      float64
+	rateSince time.Time
+
+	typing bool
+	input  string
+
+	width  int
+	height int
+}
+
+func NewModel(feed Watcher, msgs <-chan any, symbols []string, depth int) Model {
+	for i, s := range symbols {
+		symbols[i] = strings.ToUpper(strings.TrimSpace(s))
+	}
+	return Model{
+		feed:      feed,
+		msgs:      msgs,
+		symbols:   symbols,
+		depth:     max(1, min(depth, MaxDepth)),
+		book:      NewBook(symbols[0]),
+		rateSince: time.Now(),
+	}
+}
+
+// Symbol returns the symbol on screen.
+func (m Model) Symbol() string {
+	return m.symbols[m.current]
+}
+
+// Book returns the local book of the symbol on screen.
+func (m Model) Book() *Book {
+	return m.book
+}
+
+func (m Model) Init() tea.Cmd {
+	return tea.Batch(m.watch(), m.listen(), tick())
+}
+
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		return m.handleKey(msg)
+
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		return m, nil
+
+	case tickMsg:
+		return m.handleTick(time.Time(msg))
+
+	case feedMsg:
+		m.handleFeed(msg.msg, time.Now())
+		return m, m.listen()
+	}
+
+	// Feed messages may also arrive directly, e.g. through Program.Send.
+	m.handleFeed(msg, time.Now())
+	return m, nil
+}
+
+func (m *Model) handleFeed(msg any, now time.Time) {
+	switch msg := msg.(type) {
+	case SnapshotMsg:
+		if msg.Symbol == m.Symbol() {
+			m.book.Load(msg.Book, now)
+			m.err = nil
+		}
+
+	case DepthMsg:
+		if msg.Update.Symbol == m.Symbol() && m.book.Apply(msg.Update, now) {
+			m.updates++
+		}
+
+	case ResetMsg:
+		if msg.Event.Symbol == m.Symbol() {
+			m.book.Load(msg.Event.Snapshot, now)
+			m.resetReason = msg.Event.Reason
+		}
+
+	case TradeMsg:
+		if msg.Trade.Symbol == m.Symbol() {
+			if m.lastTrade != nil && msg.Trade.TradeID < m.lastTrade.TradeID {
+				return
+			}
+			m.tradeTick = 0
+			if m.lastTrade != nil {
+				price, previous := parsePrice(msg.Trade.Price), parsePrice(m.lastTrade.Price)
+				if price > previous {
+					m.tradeTick = 1
+				} else if price < previous {
+					m.tradeTick = -1
+				}
+			}
+			trade := msg.Trade
+			m.lastTrade = &trade
+		}
+
+	case ConnectionMsg:
+		if msg.Event.Symbol == m.Symbol() {
+			m.venueState = msg.Event.State
+		}
+
+	case HubMsg:
+		m.hubConnected, m.hubErr = msg.Connected, msg.Err
+		if !msg.Connected {
+			// The hub sends a fresh snapshot after the feed reconnects.
+			m.book = NewBook(m.Symbol())
+		}
+
+	case ErrorMsg:
+		m.err = msg.Err
+	}
+}
+
+func (m Model) handleTick(now time.Time) (tea.Model, tea.Cmd) {
+	if elapsed := now.Sub(m.rateSince); elapsed >= time.Second {
+		m.rate = float64(m.updates) / elapsed.Seconds()
+		m.updates = 0
+		m.rateSince = now
+	}
+
+	m.book.Check(now)
+	if m.book.OutOfSync() {
+		m.book = NewBook(m.Symbol())
+		return m, tea.Batch(tick(), m.resync())
+	}
+	return m, tick()
+}
+
+func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.typing {
+		switch msg.Type {
+		case tea.KeyEnter:
+			m.typing = false
+			symbol := strings.ToUpper(strings.TrimSpace(m.input))
+			if symbol == "" {
+				return m, nil
+			}
+			for i, s := range m.symbols {
+				if s == symbol {
+					return m.switchTo(i)
+				}
+			}
+			m.symbols = append(m.symbols, symbol)
+			return m.switchTo(len(m.symbols) - 1)
+		case tea.KeyEsc:
+			m.typing = false
+		case tea.KeyBackspace:
+			if len(m.input) > 0 {
+				m.input = m.input[:len(m.input)-1]
+			}
+		case tea.KeyRunes:
+			m.input += string(msg.Runes)
+		case tea.KeyCtrlC:
+			return m, tea.Quit
+		}
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "q", "ctrl+c":
+		return m, tea.Quit
+	case "right", "tab", "n":
+		return m.switchTo((m.current + 1) % len(m.symbols))
+	case "left", "shift+tab", "p":
+		return m.switchTo((m.current + len(m.symbols) - 1) % len(m.symbols))
+	case "/":
+		m.typing, m.input = true, ""
+	case "+", "=":
+		m.depth = min(m.depth+1, MaxDepth)
+	case "-":
+		m.depth = max(m.depth-1, 1)
+	case "r":
+		m.book = NewBook(m.Symbol())
+		return m, m.resync()
+	}
+	return m, nil
+}
+
+func (m Model) switchTo(i int) (tea.Model, tea.Cmd) {
+	if i == m.current && m.book.Loaded() {
+		return m, nil
+	}
+	m.current = i
+	m.book = NewBook(m.Symbol())
+	m.lastTrade, m.tradeTick = nil, 0
+	m.venueState = ""
+	m.resetReason = ""
+	m.err = nil
+	m.updates, m.rate = 0, 0
+	return m, m.watch()
+}
+
+func (m Model) watch() tea.Cmd {
+	feed, symbol := m.feed, m.Symbol()
+	return func() tea.Msg {
+		if err := feed.Watch(symbol); err != nil {
+			return ErrorMsg{Err: err}
+		}
+		return nil
+	}
+}
+
+func (m Model) resync() tea.Cmd {
+	feed := m.feed
+	return func() tea.Msg {
+		if err := feed.Resync(); err != nil {
+			return ErrorMsg{Err: err}
+		}
+		return nil
+	}
+}
+
+// listen waits for the next feed message.
+func (m Model) listen() tea.Cmd {
+	msgs := m.msgs
+	return func() tea.Msg {
+		return feedMsg{msg: <-msgs}
+	}
+}
+
+func tick() tea.Cmd {
+	return tea.Tick(tickInterval, func(t time.Time) tea.Msg { return tickMsg(t) })
+}
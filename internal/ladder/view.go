@@ -0,0 +1,197 @@
+package ladder
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	defaultWidth = 80
+	maxBarWidth  = 40
+	// chromeLines is the header, column titles, spread row and footer.
+	chromeLines = 7
+)
+
+var (
+	titleStyle   = lipgloss.NewStyle().Bold(true)
+	mutedStyle   = lipgloss.NewStyle().Faint(true)
+	bidStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("2"))
+	askStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("1"))
+	changedStyle = lipgloss.NewStyle().Reverse(true)
+	okStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("2")).Bold(true)
+	warnStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("3")).Bold(true)
+	errStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("1")).Bold(true)
+)
+
+func (m Model) View() string {
+	return m.render(time.Now())
+}
+
+func (m Model) render(now time.Time) string {
+	width := m.width
+	if width <= 0 {
+		width = defaultWidth
+	}
+	rows := m.depth
+	if m.height > 0 {
+		rows = max(1, min(rows, (m.height-chromeLines)/2))
+	}
+
+	var out strings.Builder
+	out.WriteString(m.header() + "\n")
+	out.WriteString(m.summary() + "\n\n")
+
+	bids, asks := m.book.Bids(rows), m.book.Asks(rows)
+	priceDec, qtyDec := decimals(bids, asks)
+	largest := 0.0
+	for _, lvl := range append(append([]Level{}, bids...), asks...) {
+		largest = math.Max(largest, lvl.Quantity)
+	}
+	barWidth := max(0, min(maxBarWidth, width-32))
+
+	out.WriteString(mutedStyle.Render(fmt.Sprintf("%14s %14s", "PRICE", "SIZE")) + "\n")
+	for i := len(asks) - 1; i >= 0; i-- {
+		out.WriteString(row(asks[i], askStyle, priceDec, qtyDec, largest, barWidth, now) + "\n")
+	}
+	out.WriteString(mutedStyle.Render(spreadLine(bids, asks, priceDec)) + "\n")
+	for _, lvl := range bids {
+		out.WriteString(row(lvl, bidStyle, priceDec, qtyDec, largest, barWidth, now) + "\n")
+	}
+
+	out.WriteString("\n" + m.footer())
+	return out.String()
+}
+
+func (m Model) header() string {
+	title := titleStyle.Render(m.Symbol())
+	if len(m.symbols) > 1 {
+		title += mutedStyle.Render(fmt.Sprintf(" [%d/%d]", m.current+1, len(m.symbols)))
+	}
+	return fmt.Sprintf("%s  %s  %s", title, m.status(), mutedStyle.Render(fmt.Sprintf("%.1f upd/s", m.rate)))
+}
+
+// status is the sync state of the book on screen, worst first.
+func (m Model) status() string {
+	switch {
+	case !m.hubConnected:
+		if m.hubErr != nil {
+			return errStyle.Render("● HUB DISCONNECTED") + mutedStyle.Render(" "+m.hubErr.Error())
+		}
+		return warnStyle.Render("● CONNECTING")
+	case !m.book.Loaded():
+		return warnStyle.Render("● LOADING")
+	case m.venueState != "" && m.venueState != "connected":
+		return warnStyle.Render("● VENUE " + strings.ToUpper(m.venueState))
+	case m.book.Provisional:
+		return warnStyle.Render("● PROVISIONAL")
+	default:
+		return okStyle.Render("● LIVE")
+	}
+}
+
+func (m Model) summary() string {
+	parts := make([]string, 0, 3)
+
+	bids, asks := m.book.Bids(1), m.book.Asks(1)
+	if len(bids) == 1 && len(asks) == 1 {
+		mid := (bids[0].Price + asks[0].Price) / 2
+		spread := asks[0].Price - bids[0].Price
+		parts = append(parts, fmt.Sprintf("mid %s  spread %s (%.2f bps)",
+			formatFloat(mid, 8), formatFloat(spread, 8), spread/mid*10_000))
+	}
+
+	if t := m.lastTrade; t != nil {
+		arrow, style := "•", titleStyle
+		switch m.tradeTick {
+		case 1:
+			arrow, style = "▲", bidStyle
+		case -1:
+			arrow, style = "▼", askStyle
+		}
+		parts = append(parts, style.Render(fmt.Sprintf("last %s %s %s %s", trimZeros(t.Price), arrow, t.Side, trimZeros(t.Quantity)))+
+			mutedStyle.Render(" "+time.UnixMilli(t.TradeTime).Format("15:04:05.000")))
+	}
+
+	if m.err != nil {
+		parts = append(parts, errStyle.Render(m.err.Error()))
+	} else if m.resetReason != "" {
+		parts = append(parts, warnStyle.Render("resynced: "+m.resetReason))
+	}
+	return strings.Join(parts, "   ")
+}
+
+func (m Model) footer() string {
+	if m.typing {
+		return "symbol: " + m.input + "█" + mutedStyle.Render("  enter watch · esc cancel")
+	}
+	return mutedStyle.Render(fmt.Sprintf("←/→ switch · / symbol · +/- depth (%d) · r resync · q quit", m.depth))
+}
+
+func row(lvl Level, style lipgloss.Style, priceDec, qtyDec int, largest float64, barWidth int, now time.Time) string {
+	text := fmt.Sprintf("%14s %14s ", strconv.FormatFloat(lvl.Price, 'f', priceDec, 64), strconv.FormatFloat(lvl.Quantity, 'f', qtyDec, 64))
+	if !lvl.Changed.IsZero() && now.Sub(lvl.Changed) < highlightFor {
+		text = changedStyle.Render(text)
+	}
+	return style.Render(text + bar(lvl.Quantity, largest, barWidth))
+}
+
+// bar draws quantity relative to largest in eighths of a cell.
+func bar(quantity, largest float64, width int) string {
+	if largest <= 0 || width <= 0 {
+		return ""
+	}
+	eighths := int(math.Round(quantity / largest * float64(width*8)))
+	if eighths == 0 && quantity > 0 {
+		eighths = 1
+	}
+	partial := []string{"", "▏", "▎", "▍", "▌", "▋", "▊", "▉"}
+	return strings.Repeat("█", eighths/8) + partial[eighths%8]
+}
+
+func spreadLine(bids, asks []Level, priceDec int) string {
+	if len(bids) == 0 || len(asks) == 0 {
+		return fmt.Sprintf("%14s", "—")
+	}
+	return fmt.Sprintf("%14s %14s", strconv.FormatFloat(asks[0].Price-bids[0].Price, 'f', priceDec, 64), "spread")
+}
+
+// decimals is the precision needed to show every visible price and quantity
+// exactly, so the columns line up.
+func decimals(sides ...[]Level) (price, qty int) {
+	for _, levels := range sides {
+		for _, lvl := range levels {
+			price = max(price, fraction(lvl.PriceText))
+			qty = max(qty, fraction(lvl.QtyText))
+		}
+	}
+	return price, qty
+}
+
+func fraction(s string) int {
+	s = trimZeros(s)
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		return len(s) - i - 1
+	}
+	return 0
+}
+
+func trimZeros(s string) string {
+	if strings.Contains(s, ".") {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+	return s
+}
+
+func formatFloat(v float64, maxDecimals int) string {
+	return trimZeros(strconv.FormatFloat(v, 'f', maxDecimals, 64))
+}
+
+func parsePrice(s string) float64 {
+	v, _ := strconv.ParseFloat(s, 64)
+	return v
+}
@@ -0,0 +1,69 @@
+package retry
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+)
+
+// Backoff produces exponentially growing delays with equal jitter: each delay
+// keeps half of its step and randomises the other half.
+// It is not safe for concurrent use.
+type Backoff struct {
+	Initial time.Duration
+	Max     time.Duration
+
+	attempt int
+}
+
+func NewBackoff(initial, max time.Duration) *Backoff {
+	return &Backoff{Initial: initial, Max: max}
+}
+
+// Next returns the delay before the next attempt, picked uniformly between
+// half and the whole of the current exponential step.
+func (b *Backoff) Next() time.Duration {
+	d := b.Delay(b.attempt)
+	b.attempt++
+	return d
+}
+
+// Delay returns the jittered delay for the given zero-based attempt without
+// touching the backoff's own counter, for callers that persist attempts elsewhere.
+func (b *Backoff) Delay(attempt int) time.Duration {
+	step := b.Max
+	if attempt < 32 {
+		if d := b.Initial << attempt; d > 0 && d < b.Max {
+			step = d
+		}
+	}
+
+	half := step / 2
+	return half + rand.N(half+1)
+}
+
+func (b *Backoff) Reset() {
+	b.attempt = 0
+}
+
+// Attempt returns how many delays were handed out since the last Reset.
+func (b *Backoff) Attempt() int {
+	return b.attempt
+}
+
+// Sleep waits for d or until ctx is done, returning ctx.Err() in the latter case.
+func Sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,366 @@
+package simulator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coder/websocket"
+)
+
+const (
+	DefaultRate            = 10
+	DefaultLevels          = 100
+	DefaultLevelsPerUpdate = 3
+
+	tick        = 0.01
+	sendBuffer  = 4096
+	maxSnapshot = 5000
+)
+
+// Config controls the simulated market. The same Seed and Symbols always
+// produce the same books and the same sequence of updates.
+type Config struct {
+	Symbols         []string
+	Rate            float64 // depth updates per second per symbol
+	Levels          int     // price levels per side
+	LevelsPerUpdate int     // levels changed by each update
+	Seed            uint64
+}
+
+// Exchange mimics the parts of Binance the hub uses: the depth and trade
+// streams on /ws and REST depth snapshots on /api/v3/depth. Point
+// integrations.binance.wsStreamUrl and restApiUrlV3 at it to feed a hub with a
+// reproducible market.
+type Exchange struct {
+	cfg   Config
+	books map[string]*book
+
+	mu      sync.Mutex
+	streams map[string]map[*subscriber]struct{}
+	dropped int64
+}
+
+type subscriber struct {
+	out chan []byte
+}
+
+type book struct {
+	symbol string
+	mu     sync.Mutex
+	rng    *rand.Rand
+	base   int64 // price of the lowest ask, in ticks
+	levels int
+	bids   map[int64]int64 // ticks -> quantity in lots
+	asks   map[int64]int64
+	lastID int64
+}
+
+func New(cfg Config) *Exchange {
+	if cfg.Rate <= 0 {
+		cfg.Rate = DefaultRate
+	}
+	if cfg.Levels <= 0 {
+		cfg.Levels = DefaultLevels
+	}
+	if cfg.LevelsPerUpdate <= 0 {
+		cfg.LevelsPerUpdate = DefaultLevelsPerUpdate
+	}
+
+	e := &Exchange{
+		cfg:     cfg,
+		books:   make(map[string]*book, len(cfg.Symbols)),
+		streams: make(map[string]map[*subscriber]struct{}),
+	}
+	for _, symbol := range cfg.Symbols {
+		symbol = strings.ToUpper(strings.TrimSpace(symbol))
+		if symbol != "" {
+			e.books[symbol] = newBook(symbol, cfg.Levels, cfg.Seed)
+		}
+	}
+	return e
+}
+
+func newBook(symbol string, levels int, seed uint64) *book {
+	h := fnv.New64a()
+	h.Write([]byte(symbol))
+	rng := rand.New(rand.NewPCG(seed, h.Sum64()))
+
+	b := &book{
+		symbol: symbol,
+		rng:    rng,
+		base:   1_000_000 + rng.Int64N(9_000_000),
+		levels: levels,
+		bids:   make(map[int64]int64, levels),
+		asks:   make(map[int64]int64, levels),
+		lastID: 1_000_000,
+	}
+	for i := range int64(levels) {
+		b.asks[b.base+i] = 1 + rng.Int64N(1000)
+		b.bids[b.base-1-i] = 1 + rng.Int64N(1000)
+	}
+	return b
+}
+
+// Symbols lists the simulated symbols.
+func (e *Exchange) Symbols() []string {
+	symbols := make([]string, 0, len(e.books))
+	for symbol := range e.books {
+		symbols = append(symbols, symbol)
+	}
+	slices.Sort(symbols)
+	return symbols
+}
+
+// Dropped is the number of updates not sent because a subscriber fell behind.
+func (e *Exchange) Dropped() int64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.dropped
+}
+
+// Handler serves the stream and REST endpoints.
+func (e *Exchange) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ws", e.handleStream)
+	mux.HandleFunc("/api/v3/depth", e.handleDepth)
+	return mux
+}
+
+// Run publishes updates for every symbol at the configured rate until ctx is done.
+func (e *Exchange) Run(ctx context.Context) {
+	interval := time.Duration(float64(time.Second) / e.cfg.Rate)
+
+	wg := sync.WaitGroup{}
+	for _, b := range e.books {
+		wg.Go(func() {
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					e.publish(strings.ToLower(b.symbol)+"@depth", b.next(e.cfg.LevelsPerUpdate))
+				}
+			}
+		})
+	}
+	wg.Wait()
+}
+
+func (e *Exchange) publish(stream string, data []byte) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for sub := range e.streams[stream] {
+		select {
+		case sub.out <- data:
+		default:
+			e.dropped++
+		}
+	}
+}
+
+// next changes n random levels and returns the depth update describing them.
+// The event time is taken when the update is built, which is what the bench
+// command measures latency from.
+func (b *book) next(n int) []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	bids := make([][]string, 0, n)
+	asks := make([][]string, 0, n)
+	for range n {
+		offset := b.rng.Int64N(int64(b.levels))
+		qty := b.rng.Int64N(1000)
+		if b.rng.IntN(2) == 0 {
+			price := b.base - 1 - offset
+			b.set(b.bids, price, qty)
+			bids = append(bids, level(price, qty))
+		} else {
+			price := b.base + offset
+			b.set(b.asks, price, qty)
+			asks = append(asks, level(price, qty))
+		}
+	}
+
+	b.lastID++
+	data, _ := json.Marshal(map[string]any{
+		"e": "depthUpdate",
+		"E": time.Now().UnixMilli(),
+		"s": b.symbol,
+		"U": b.lastID,
+		"u": b.lastID,
+		"b": bids,
+		"a": asks,
+	})
+	return data
+}
+
+func (b *book) set(side map[int64]int64, price, qty int64) {
+	if qty == 0 {
+		delete(side, price)
+		return
+	}
+	side[price] = qty
+}
+
+// snapshot returns the best limit levels of each side in the REST depth format.
+func (b *book) snapshot(limit int) map[string]any {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return map[string]any{
+		"lastUpdateId": b.lastID,
+		"bids":         sideLevels(b.bids, limit, true),
+		"asks":         sideLevels(b.asks, limit, false),
+	}
+}
+
+func sideLevels(side map[int64]int64, limit int, descending bool) [][]string {
+	prices := make([]int64, 0, len(side))
+	for price := range side {
+		prices = append(prices, price)
+	}
+	slices.Sort(prices)
+	if descending {
+		slices.Reverse(prices)
+	}
+	if len(prices) > limit {
+		prices = prices[:limit]
+	}
+
+	levels := make([][]string, len(prices))
+	for i, price := range prices {
+		levels[i] = level(price, side[price])
+	}
+	return levels
+}
+
+func level(price, qty int64) []string {
+	return []string{
+		strconv.FormatFloat(float64(price)*tick, 'f', 2, 64),
+		strconv.FormatFloat(float64(qty)*0.001, 'f', 3, 64),
+	}
+}
+
+func (e *Exchange) handleDepth(w http.ResponseWriter, r *http.Request) {
+	b, ok := e.books[strings.ToUpper(r.URL.Query().Get("symbol"))]
+	if !ok {
+		http.Error(w, `{"code":-1121,"msg":"Invalid symbol."}`, http.StatusBadRequest)
+		return
+	}
+
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = 100
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(b.snapshot(min(limit, maxSnapshot))); err != nil {
+		slog.Debug("Simulator snapshot write failed", "error", err)
+	}
+}
+
+// handleStream accepts the Binance SUBSCRIBE/UNSUBSCRIBE requests and forwards
+// the subscribed streams. Unknown streams, such as trades, are acknowledged
+// but stay silent.
+func (e *Exchange) handleStream(w http.ResponseWriter, r *http.Request) {
+	conn, err := websocket.Accept(w, r, nil)
+	if err != nil {
+		slog.Error("Simulator failed to accept websocket", "error", err)
+		return
+	}
+	defer conn.CloseNow()
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	sub := &subscriber{out: make(chan []byte, sendBuffer)}
+	defer e.unsubscribeAll(sub)
+
+	go func() {
+		defer cancel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case data := <-sub.out:
+				if err := conn.Write(ctx, websocket.MessageText, data); err != nil {
+					return
+				}
+			}
+		}
+	}()
+
+	for {
+		_, data, err := conn.Read(ctx)
+		if err != nil {
+			return
+		}
+
+		var req struct {
+			Method string          `json:"method"`
+			Params []string        `json:"params"`
+			ID     json.RawMessage `json:"id"`
+		}
+		if err := json.Unmarshal(data, &req); err != nil {
+			continue
+		}
+
+		for _, stream := range req.Params {
+			switch strings.ToUpper(req.Method) {
+			case "SUBSCRIBE":
+				e.subscribe(sub, strings.ToLower(stream))
+			case "UNSUBSCRIBE":
+				e.unsubscribe(sub, strings.ToLower(stream))
+			}
+		}
+
+		ack, _ := json.Marshal(map[string]any{"result": nil, "id": req.ID})
+		select {
+		case sub.out <- ack:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (e *Exchange) subscribe(sub *subscriber, stream string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if _, ok := e.streams[stream]; !ok {
+		e.streams[stream] = make(map[*subscriber]struct{})
+	}
+	e.streams[stream][sub] = struct{}{}
+}
+
+func (e *Exchange) unsubscribe(sub *subscriber, stream string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	delete(e.streams[stream], sub)
+}
+
+func (e *Exchange) unsubscribeAll(sub *subscriber) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, subs := range e.streams {
+		delete(subs, sub)
+	}
+}
+
+// String describes the simulated market for logs.
+func (e *Exchange) String() string {
+	return fmt.Sprintf("%d symbols at %v updates/s, %d levels", len(e.books), e.cfg.Rate, e.cfg.Levels)
+}
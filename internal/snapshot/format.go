@@ -0,0 +1,159 @@
+package snapshot
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	pb "github.com/ChethiyaNishanath/market-data-hub/api/orderbook"
+)
+
+const (
+	FormatTable  = "table"
+	FormatJSON   = "json"
+	FormatCSV    = "csv"
+	FormatNDJSON = "ndjson"
+)
+
+// Formats lists the supported output formats.
+var Formats = []string{FormatTable, FormatJSON, FormatCSV, FormatNDJSON}
+
+// Book is one symbol's snapshot as fetched from the hub. Levels are best first.
+type Book struct {
+	Symbol       string  `json:"symbol"`
+	LastUpdateID int64   `json:"lastUpdateId"`
+	Bids         []Level `json:"bids"`
+	Asks         []Level `json:"asks"`
+}
+
+// Level keeps the hub's price and quantity strings so no precision is lost. It
+// encodes as a [price, quantity] pair, like the WebSocket snapshots.
+type Level struct {
+	Price    string
+	Quantity string
+}
+
+func (l Level) MarshalJSON() ([]byte, error) {
+	return json.Marshal([2]string{l.Price, l.Quantity})
+}
+
+func (l *Level) UnmarshalJSON(data []byte) error {
+	var pair [2]string
+	if err := json.Unmarshal(data, &pair); err != nil {
+		return err
+	}
+	l.Price, l.Quantity = pair[0], pair[1]
+	return nil
+}
+
+// FromReply converts a gRPC snapshot reply.
+func FromReply(reply *pb.GetSnapshotReply) Book {
+	id, _ := strconv.ParseInt(reply.GetLastUpdateId(), 10, 64)
+	return Book{
+		Symbol:       reply.GetSymbol(),
+		LastUpdateID: id,
+		Bids:         fromOrders(reply.GetBids()),
+		Asks:         fromOrders(reply.GetAsks()),
+	}
+}
+
+func fromOrders(orders []*pb.Order) []Level {
+	levels := make([]Level, len(orders))
+	for i, o := range orders {
+		levels[i] = Level{Price: o.GetPrice(), Quantity: o.GetAmount()}
+	}
+	return levels
+}
+
+// ValidFormat reports whether format is one of Formats.
+func ValidFormat(format string) bool {
+	return slices.Contains(Formats, format)
+}
+
+// Write renders books to w in the given format:
+//
+//   - table: bids and asks side by side, one block per symbol
+//   - json: a single array of books
+//   - ndjson: one book per line
+//   - csv: one row per level, with level 0 the best price
+func Write(w io.Writer, format string, books []Book) error {
+	switch format {
+	case FormatTable:
+		return writeTable(w, books)
+	case FormatJSON:
+		if books == nil {
+			books = []Book{}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(books)
+	case FormatNDJSON:
+		enc := json.NewEncoder(w)
+		for _, b := range books {
+			if err := enc.Encode(b); err != nil {
+				return err
+			}
+		}
+		return nil
+	case FormatCSV:
+		return writeCSV(w, books)
+	default:
+		return fmt.Errorf("unknown output format %q, expected one of %s", format, strings.Join(Formats, ", "))
+	}
+}
+
+func writeTable(w io.Writer, books []Book) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+
+	for i, b := range books {
+		if i > 0 {
+			fmt.Fprintln(tw)
+		}
+		// A line without tabs ends the column block, so the header is not
+		// aligned with the levels.
+		fmt.Fprintf(tw, "%s  last update %d\n", b.Symbol, b.LastUpdateID)
+		fmt.Fprint(tw, "BID QTY\tBID\tASK\tASK QTY\t\n")
+
+		for n := 0; n < max(len(b.Bids), len(b.Asks)); n++ {
+			var bid, ask Level
+			if n < len(b.Bids) {
+				bid = b.Bids[n]
+			}
+			if n < len(b.Asks) {
+				ask = b.Asks[n]
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t\n", bid.Quantity, bid.Price, ask.Price, ask.Quantity)
+		}
+	}
+
+	return tw.Flush()
+}
+
+func writeCSV(w io.Writer, books []Book) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"symbol", "last_update_id", "side", "level", "price", "quantity"}); err != nil {
+		return err
+	}
+
+	for _, b := range books {
+		id := strconv.FormatInt(b.LastUpdateID, 10)
+		for _, side := range []struct {
+			name   string
+			levels []Level
+		}{{"bid", b.Bids}, {"ask", b.Asks}} {
+			for n, lvl := range side.levels {
+				if err := cw.Write([]string{b.Symbol, id, side.name, strconv.Itoa(n), lvl.Price, lvl.Quantity}); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,65 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ChethiyaNishanath/market-data-hub/internal/domain/candle"
+)
+
+// CandleStore is an in-process candle.Repository.
+type CandleStore struct {
+	mu      sync.RWMutex
+	candles map[string]map[int64]candle.Candle
+}
+
+func NewCandleStore() *CandleStore {
+	return &CandleStore{candles: make(map[string]map[int64]candle.Candle)}
+}
+
+func (s *CandleStore) Upsert(candles ...candle.Candle) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, c := range candles {
+		key := seriesKey(c.Exchange, c.Symbol) + "/" + c.Interval
+		if s.candles[key] == nil {
+			s.candles[key] = make(map[int64]candle.Candle)
+		}
+		s.candles[key][c.OpenTime.UnixMilli()] = c
+	}
+	return nil
+}
+
+func (s *CandleStore) Range(exchange, symbol, interval string, from, to time.Time, limit int) ([]candle.Candle, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	matched := make([]candle.Candle, 0)
+	for _, c := range s.candles[seriesKey(exchange, symbol)+"/"+interval] {
+		if inRange(c.OpenTime, from, to) {
+			matched = append(matched, c)
+		}
+	}
+
+	sort.Slice(matched, func(i, j int) bool { return matched[i].OpenTime.Before(matched[j].OpenTime) })
+	if limit > 0 && len(matched) > limit {
+		matched = matched[len(matched)-limit:]
+	}
+	return matched, nil
+}
+
+func (s *CandleStore) Prune(before time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, series := range s.candles {
+		for openTime, c := range series {
+			if c.OpenTime.Before(before) {
+				delete(series, openTime)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ChethiyaNishanath/market-data-hub/internal/domain/trade"
+)
+
+// TradeStore is an in-process trade.Repository holding at most maxPerSymbol
+// trades for each exchange and symbol.
+type TradeStore struct {
+	mu           sync.RWMutex
+	maxPerSymbol int
+	trades       map[string][]trade.Trade
+}
+
+func NewTradeStore(maxPerSymbol int) *TradeStore {
+	return &TradeStore{maxPerSymbol: maxPerSymbol, trades: make(map[string][]trade.Trade)}
+}
+
+func (s *TradeStore) Add(trades ...trade.Trade) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	touched := make(map[string]bool)
+	for _, t := range trades {
+		key := seriesKey(t.Exchange, t.Symbol)
+		s.trades[key] = append(s.trades[key], t)
+		touched[key] = true
+	}
+
+	for key := range touched {
+		list := s.trades[key]
+		sort.SliceStable(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+		if s.maxPerSymbol > 0 && len(list) > s.maxPerSymbol {
+			list = append([]trade.Trade(nil), list[len(list)-s.maxPerSymbol:]...)
+		}
+		s.trades[key] = list
+	}
+	return nil
+}
+
+func (s *TradeStore) Recent(exchange, symbol string, from, to time.Time, limit int) ([]trade.Trade, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	matched := make([]trade.Trade, 0)
+	for _, t := range s.trades[seriesKey(exchange, symbol)] {
+		if inRange(t.Time, from, to) {
+			matched = append(matched, t)
+		}
+	}
+	if limit > 0 && len(matched) > limit {
+		matched = matched[len(matched)-limit:]
+	}
+	return matched, nil
+}
+
+func (s *TradeStore) Prune(before time.Time, keep int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, list := range s.trades {
+		i := sort.Search(len(list), func(i int) bool { return !list[i].Time.Before(before) })
+		if keep > 0 && len(list)-i > keep {
+			i = len(list) - keep
+		}
+		s.trades[key] = append([]trade.Trade(nil), list[i:]...)
+	}
+	return nil
+}
+
+func seriesKey(exchange, symbol string) string {
+	return exchange + "/" + symbol
+}
+
+// inRange treats a zero bound as open.
+func inRange(t, from, to time.Time) bool {
+	return (from.IsZero() || !t.Before(from)) && (to.IsZero() || !t.After(to))
+}
@@ -0,0 +1,122 @@
+package sqlite
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/ChethiyaNishanath/market-data-hub/internal/alerting"
+)
+
+// SaveAlert stores the alert as JSON, so new alert fields need no migration.
+func (s *AlertStore) SaveAlert(alert alerting.Alert) error {
+	data, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec(`INSERT INTO alerts (id, created_at, alert) VALUES (?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET alert = excluded.alert`, alert.ID, alert.CreatedAt.UnixMilli(), string(data))
+	return err
+}
+
+func (s *AlertStore) DeleteAlert(id string) error {
+	_, err := s.db.Exec(`DELETE FROM alerts WHERE id = ?`, id)
+	return err
+}
+
+func (s *AlertStore) LoadAlerts() ([]alerting.Alert, error) {
+	rows, err := s.db.Query(`SELECT alert FROM alerts ORDER BY created_at`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	alerts := make([]alerting.Alert, 0)
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+		var alert alerting.Alert
+		if err := json.Unmarshal([]byte(data), &alert); err != nil {
+			return nil, err
+		}
+		alerts = append(alerts, alert)
+	}
+	return alerts, rows.Err()
+}
+
+func (o *Outbox) Enqueue(url string, payload []byte, now time.Time) (alerting.Delivery, error) {
+	d := alerting.Delivery{URL: url, Payload: payload, NextAttempt: now, CreatedAt: now}
+
+	res, err := o.db.Exec(`INSERT INTO webhook_deliveries (url, payload, next_attempt, created_at) VALUES (?, ?, ?, ?)`,
+		url, payload, now.UnixMilli(), now.UnixMilli())
+	if err != nil {
+		return alerting.Delivery{}, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return alerting.Delivery{}, err
+	}
+	d.ID = uint64(id)
+	return d, nil
+}
+
+func (o *Outbox) Due(now time.Time) ([]alerting.Delivery, error) {
+	return o.query(`WHERE dead = 0 AND next_attempt <= ?`, now.UnixMilli())
+}
+
+func (o *Outbox) Pending() ([]alerting.Delivery, error) {
+	return o.query(`WHERE dead = 0`)
+}
+
+func (o *Outbox) Dead() ([]alerting.Delivery, error) {
+	return o.query(`WHERE dead = 1`)
+}
+
+func (o *Outbox) Complete(id uint64) error {
+	_, err := o.db.Exec(`DELETE FROM webhook_deliveries WHERE id = ? AND dead = 0`, id)
+	return err
+}
+
+func (o *Outbox) Reschedule(d alerting.Delivery) error {
+	_, err := o.db.Exec(`UPDATE webhook_deliveries SET attempts = ?, next_attempt = ?, last_error = ? WHERE id = ?`,
+		d.Attempts, d.NextAttempt.UnixMilli(), d.LastError, d.ID)
+	return err
+}
+
+func (o *Outbox) Bury(d alerting.Delivery) error {
+	_, err := o.db.Exec(`UPDATE webhook_deliveries SET attempts = ?, last_error = ?, dead = 1 WHERE id = ?`,
+		d.Attempts, d.LastError, d.ID)
+	return err
+}
+
+func (o *Outbox) query(where string, args ...any) ([]alerting.Delivery, error) {
+	rows, err := o.db.Query(`SELECT id, url, payload, attempts, next_attempt, last_error, created_at
+		FROM webhook_deliveries `+where+` ORDER BY id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := make([]alerting.Delivery, 0)
+	for rows.Next() {
+		var (
+			d               alerting.Delivery
+			next, createdAt int64
+			payload         []byte
+		)
+		if err := rows.Scan(&d.ID, &d.URL, &payload, &d.Attempts, &next, &d.LastError, &createdAt); err != nil {
+			return nil, err
+		}
+		d.Payload = payload
+		d.NextAttempt = time.UnixMilli(next).UTC()
+		d.CreatedAt = time.UnixMilli(createdAt).UTC()
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
+
+var (
+	_ alerting.Repository    = (*AlertStore)(nil)
+	_ alerting.DeliveryStore = (*Outbox)(nil)
+)
@@ -0,0 +1,74 @@
+package sqlite
+
+import (
+	"slices"
+	"time"
+
+	"github.com/ChethiyaNishanath/market-data-hub/internal/domain/candle"
+)
+
+func (s *CandleStore) Upsert(candles ...candle.Candle) error {
+	if len(candles) == 0 {
+		return nil
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	stmt, err := tx.Prepare(`INSERT INTO candles
+		(exchange, symbol, interval, open_time, open, high, low, close, volume, quote_volume, trades)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (exchange, symbol, interval, open_time) DO UPDATE SET
+			open = excluded.open, high = excluded.high, low = excluded.low, close = excluded.close,
+			volume = excluded.volume, quote_volume = excluded.quote_volume, trades = excluded.trades`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, c := range candles {
+		if _, err := stmt.Exec(c.Exchange, c.Symbol, c.Interval, c.OpenTime.UnixMilli(),
+			c.Open, c.High, c.Low, c.Close, c.Volume, c.QuoteVolume, c.Trades); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (s *CandleStore) Range(exchange, symbol, interval string, from, to time.Time, limit int) ([]candle.Candle, error) {
+	lo, hi := bounds(from, to)
+	rows, err := s.db.Query(`SELECT open_time, open, high, low, close, volume, quote_volume, trades FROM candles
+		WHERE exchange = ? AND symbol = ? AND interval = ? AND open_time BETWEEN ? AND ?
+		ORDER BY open_time DESC LIMIT ?`, exchange, symbol, interval, lo, hi, sqlLimit(limit))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	candles := make([]candle.Candle, 0)
+	for rows.Next() {
+		c := candle.Candle{Exchange: exchange, Symbol: symbol, Interval: interval}
+		var ms int64
+		if err := rows.Scan(&ms, &c.Open, &c.High, &c.Low, &c.Close, &c.Volume, &c.QuoteVolume, &c.Trades); err != nil {
+			return nil, err
+		}
+		c.OpenTime = time.UnixMilli(ms).UTC()
+		candles = append(candles, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	slices.Reverse(candles)
+	return candles, nil
+}
+
+func (s *CandleStore) Prune(before time.Time) error {
+	_, err := s.db.Exec(`DELETE FROM candles WHERE open_time < ?`, before.UnixMilli())
+	return err
+}
+
+var _ candle.Repository = (*CandleStore)(nil)
@@ -0,0 +1,29 @@
+CREATE TABLE trades (
+    exchange TEXT    NOT NULL,
+    symbol   TEXT    NOT NULL,
+    id       INTEGER NOT NULL,
+    price    REAL    NOT NULL,
+    quantity REAL    NOT NULL,
+    side     TEXT    NOT NULL,
+    time     INTEGER NOT NULL,
+    PRIMARY KEY (exchange, symbol, id)
+) WITHOUT ROWID;
+
+CREATE INDEX trades_time ON trades (exchange, symbol, time);
+
+CREATE TABLE candles (
+    exchange     TEXT    NOT NULL,
+    symbol       TEXT    NOT NULL,
+    interval     TEXT    NOT NULL,
+    open_time    INTEGER NOT NULL,
+    open         REAL    NOT NULL,
+    high         REAL    NOT NULL,
+    low          REAL    NOT NULL,
+    close        REAL    NOT NULL,
+    volume       REAL    NOT NULL,
+    quote_volume REAL    NOT NULL,
+    trades       INTEGER NOT NULL,
+    PRIMARY KEY (exchange, symbol, interval, open_time)
+) WITHOUT ROWID;
+
+CREATE INDEX candles_open_time ON candles (open_time);
@@ -0,0 +1,18 @@
+CREATE TABLE alerts (
+    id         TEXT    PRIMARY KEY,
+    created_at INTEGER NOT NULL,
+    alert      TEXT    NOT NULL
+);
+
+CREATE TABLE webhook_deliveries (
+    id           INTEGER PRIMARY KEY AUTOINCREMENT,
+    url          TEXT    NOT NULL,
+    payload      BLOB    NOT NULL,
+    attempts     INTEGER NOT NULL DEFAULT 0,
+    next_attempt INTEGER NOT NULL,
+    last_error   TEXT    NOT NULL DEFAULT '',
+    created_at   INTEGER NOT NULL,
+    dead         INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX webhook_deliveries_due ON webhook_deliveries (dead, next_attempt);
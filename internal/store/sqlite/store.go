@@ -0,0 +1,166 @@
+package sqlite
+
+import (
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// Store keeps trades, candles, alerts and webhook deliveries in one SQLite file.
+// Each is reached through its own repository, which all share the connection.
+type Store struct {
+	db *sql.DB
+}
+
+// TradeStore is a trade.Repository.
+type TradeStore struct {
+	db *sql.DB
+}
+
+// CandleStore is a candle.Repository.
+type CandleStore struct {
+	db *sql.DB
+}
+
+// AlertStore is an alerting.Repository.
+type AlertStore struct {
+	db *sql.DB
+}
+
+// Outbox is an alerting.DeliveryStore. Dead letters stay in the same table,
+// flagged dead.
+type Outbox struct {
+	db *sql.DB
+}
+
+// Open opens or creates the database at path and applies pending migrations.
+func Open(path string) (*Store, error) {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("create storage directory: %w", err)
+		}
+	}
+
+	db, err := sql.Open("sqlite", "file:"+path+"?_pragma=journal_mode(WAL)&_pragma=busy_timeout(5000)&_pragma=synchronous(NORMAL)")
+	if err != nil {
+		return nil, fmt.Errorf("open storage: %w", err)
+	}
+	// SQLite serialises writers anyway; one connection avoids busy errors
+	// between the hub's own goroutines.
+	db.SetMaxOpenConns(1)
+
+	if err := migrate(db); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("migrate storage: %w", err)
+	}
+
+	return &Store{db: db}, nil
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+func (s *Store) Trades() *TradeStore   { return &TradeStore{db: s.db} }
+func (s *Store) Candles() *CandleStore { return &CandleStore{db: s.db} }
+func (s *Store) Alerts() *AlertStore   { return &AlertStore{db: s.db} }
+func (s *Store) Outbox() *Outbox       { return &Outbox{db: s.db} }
+
+// SchemaVersion returns the version of the latest applied migration.
+func (s *Store) SchemaVersion() (int, error) {
+	var version int
+	err := s.db.QueryRow(`SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&version)
+	return version, err
+}
+
+// migrate applies, in order and each in its own transaction, the embedded
+// migrations newer than the recorded schema version. Files are named
+// NNNN_description.sql and must never change once released.
+func migrate(db *sql.DB) error {
+	if _, err := db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    INTEGER PRIMARY KEY,
+		applied_at INTEGER NOT NULL
+	)`); err != nil {
+		return err
+	}
+
+	var current int
+	if err := db.QueryRow(`SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&current); err != nil {
+		return err
+	}
+
+	names, err := fs.Glob(migrations, "migrations/*.sql")
+	if err != nil {
+		return err
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		base := filepath.Base(name)
+		version, err := strconv.Atoi(strings.SplitN(base, "_", 2)[0])
+		if err != nil {
+			return fmt.Errorf("migration %s: bad version prefix", base)
+		}
+		if version <= current {
+			continue
+		}
+
+		script, err := migrations.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		if err := apply(db, version, string(script)); err != nil {
+			return fmt.Errorf("migration %s: %w", base, err)
+		}
+	}
+	return nil
+}
+
+func apply(db *sql.DB, version int, script string) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.Exec(script); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`INSERT INTO schema_migrations (version, applied_at) VALUES (?, ?)`, version, time.Now().UnixMilli()); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// bounds maps an optional time range to inclusive millisecond bounds.
+func bounds(from, to time.Time) (int64, int64) {
+	lo, hi := int64(math.MinInt64), int64(math.MaxInt64)
+	if !from.IsZero() {
+		lo = from.UnixMilli()
+	}
+	if !to.IsZero() {
+		hi = to.UnixMilli()
+	}
+	return lo, hi
+}
+
+// sqlLimit maps a non-positive limit to SQLite's "no limit".
+func sqlLimit(limit int) int {
+	if limit <= 0 {
+		return -1
+	}
+	return limit
+}
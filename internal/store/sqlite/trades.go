@@ -0,0 +1,82 @@
+package sqlite
+
+import (
+	"slices"
+	"time"
+
+	"github.com/ChethiyaNishanath/market-data-hub/internal/domain/trade"
+)
+
+func (s *TradeStore) Add(trades ...trade.Trade) error {
+	if len(trades) == 0 {
+		return nil
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	stmt, err := tx.Prepare(`INSERT OR IGNORE INTO trades (exchange, symbol, id, price, quantity, side, time)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, t := range trades {
+		if _, err := stmt.Exec(t.Exchange, t.Symbol, t.ID, t.Price, t.Quantity, t.Side, t.Time.UnixMilli()); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (s *TradeStore) Recent(exchange, symbol string, from, to time.Time, limit int) ([]trade.Trade, error) {
+	lo, hi := bounds(from, to)
+	rows, err := s.db.Query(`SELECT id, price, quantity, side, time FROM trades
+		WHERE exchange = ? AND symbol = ? AND time BETWEEN ? AND ?
+		ORDER BY id DESC LIMIT ?`, exchange, symbol, lo, hi, sqlLimit(limit))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	trades := make([]trade.Trade, 0)
+	for rows.Next() {
+		t := trade.Trade{Exchange: exchange, Symbol: symbol}
+		var ms int64
+		if err := rows.Scan(&t.ID, &t.Price, &t.Quantity, &t.Side, &ms); err != nil {
+			return nil, err
+		}
+		t.Time = time.UnixMilli(ms).UTC()
+		trades = append(trades, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	slices.Reverse(trades)
+	return trades, nil
+}
+
+func (s *TradeStore) Prune(before time.Time, keep int) error {
+	if _, err := s.db.Exec(`DELETE FROM trades WHERE time < ?`, before.UnixMilli()); err != nil {
+		return err
+	}
+	if keep <= 0 {
+		return nil
+	}
+
+	_, err := s.db.Exec(`DELETE FROM trades WHERE (exchange, symbol, id) IN (
+		SELECT exchange, symbol, id FROM (
+			SELECT exchange, symbol, id,
+				ROW_NUMBER() OVER (PARTITION BY exchange, symbol ORDER BY id DESC) AS n
+			FROM trades
+		) WHERE n > ?
+	)`, keep)
+	return err
+}
+
+var _ trade.Repository = (*TradeStore)(nil)
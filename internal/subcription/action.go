@@ -1,6 +1,11 @@
 package subcription
 
 const (
-	Subscribe   = "subscribe"
-	Unsubscribe = "unsubscribe"
+	Subscribe         = "subscribe"
+	Unsubscribe       = "unsubscribe"
+	ListSubscriptions = "list_subscriptions"
+	ListSymbols       = "list_symbols"
+	Ping              = "ping"
+	Pong              = "pong"
+	ServerInfoMethod  = "server_info"
 )
@@ -0,0 +1,34 @@
+package subcription
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/ChethiyaNishanath/market-data-hub/internal/domain/subscription"
+)
+
+// HandleClients serves GET /admin/clients: the outbound queue, lag, dropped
+// counts and subscriptions of every connected client, the slowest first.
+// With ?slow=true only the clients whose pushes are being held back are listed.
+func (h *Handler) HandleClients(w http.ResponseWriter, r *http.Request) {
+	onlySlow := strings.EqualFold(r.URL.Query().Get("slow"), "true")
+
+	clients := h.connMgr.Clients()
+	stats := make([]subscription.ClientStats, 0, len(clients))
+	for _, client := range clients {
+		if s := client.Stats(); s.Slow || !onlySlow {
+			stats = append(stats, s)
+		}
+	}
+	slices.SortFunc(stats, func(a, b subscription.ClientStats) int {
+		if a.LagMs != b.LagMs {
+			return int(b.LagMs - a.LagMs)
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(stats)
+}
@@ -5,12 +5,18 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"maps"
+	"slices"
 	"sync"
 
 	"github.com/ChethiyaNishanath/market-data-hub/internal/domain/subscription"
 	"github.com/coder/websocket"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
 )
 
+var tracer = otel.Tracer("github.com/ChethiyaNishanath/market-data-hub/internal/subcription")
+
 type ConnectionManager struct {
 	Ctx           context.Context
 	mu            sync.RWMutex
@@ -80,6 +86,10 @@ func (m *ConnectionManager) Unsubscribe(client subscription.Client, topic string
 }
 
 func (m *ConnectionManager) Broadcast(topic string, msg any) {
+	m.BroadcastContext(context.Background(), topic, msg)
+}
+
+func (m *ConnectionManager) BroadcastContext(ctx context.Context, topic string, msg any) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
@@ -88,6 +98,13 @@ func (m *ConnectionManager) Broadcast(topic string, msg any) {
 		return
 	}
 
+	ctx, span := tracer.Start(ctx, "ws.broadcast")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("topic", topic),
+		attribute.Int("subscribers", len(subs)),
+	)
+
 	data, err := json.Marshal(msg)
 	if err != nil {
 		slog.Warn("Failed to marshal broadcast", "warning", err)
@@ -95,7 +112,7 @@ func (m *ConnectionManager) Broadcast(topic string, msg any) {
 	}
 
 	for _, client := range subs {
-		client.Send(data)
+		client.SendTopic(ctx, topic, data)
 	}
 }
 
@@ -116,3 +133,10 @@ func (m *ConnectionManager) GetClientByID(id string) subscription.Client {
 	defer m.mu.RUnlock()
 	return m.clients[id]
 }
+
+// Clients lists the connected clients in no particular order.
+func (m *ConnectionManager) Clients() []subscription.Client {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return slices.Collect(maps.Values(m.clients))
+}
@@ -0,0 +1,142 @@
+package subcription
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/ChethiyaNishanath/market-data-hub/internal/config"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/exchange/binance"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/version"
+	"github.com/coder/websocket"
+)
+
+// BuiltinEvents are the topic events served by the exchange services rather
+// than a registered SnapshotProvider.
+var BuiltinEvents = []string{"depth", "depth.reset", "connection", "trade"}
+
+// Exchange is a source of books whose symbols clients can list.
+type Exchange interface {
+	Name() string
+	SubscribedSymbols() []string
+}
+
+// ExchangeSymbols is one entry of the list_symbols reply.
+type ExchangeSymbols struct {
+	Exchange string   `json:"exchange"`
+	Symbols  []string `json:"symbols"`
+}
+
+// PongData is the data of the pong reply.
+type PongData struct {
+	ServerTime int64 `json:"serverTime"`
+}
+
+// ServerInfo is the server_info reply.
+type ServerInfo struct {
+	Version    string   `json:"version"`
+	Commit     string   `json:"commit"`
+	Exchanges  []string `json:"exchanges"`
+	Events     []string `json:"events"`
+	Limits     Limits   `json:"limits"`
+	ServerTime int64    `json:"serverTime"`
+}
+
+// Limits are the limits a client can run into. RateLimitRequests is 0 when
+// HTTP requests, WebSocket upgrades included, are not limited. SlowConsumer is
+// the policy applied once HighWaterMark pushes are queued for a client.
+type Limits struct {
+	MaxRequestBytes   int    `json:"maxRequestBytes"`
+	SendBuffer        int    `json:"sendBuffer"`
+	HighWaterMark     int    `json:"highWaterMark"`
+	SlowConsumer      string `json:"slowConsumer"`
+	RateLimitRequests int    `json:"rateLimitRequests"`
+	RateLimitWindow   string `json:"rateLimitWindow,omitempty"`
+}
+
+// RegisterExchange lets clients list the symbols of ex.
+func (h *Handler) RegisterExchange(ex Exchange) {
+	h.exchanges = append(h.exchanges, ex)
+}
+
+// UseRateLimit makes server_info report the HTTP rate limit current returns.
+func (h *Handler) UseRateLimit(current func() config.RateLimitConfig) {
+	h.rateLimit = current
+}
+
+// HandlePing answers "ping" with a "pong" carrying the server time in
+// milliseconds.
+func (h *Handler) HandlePing(ctx context.Context, conn *websocket.Conn, _ json.RawMessage) {
+	_ = binance.Reply(ctx, conn, binance.WSMessage{
+		Method:  Pong,
+		Success: true,
+		Data:    PongData{ServerTime: time.Now().UnixMilli()},
+	})
+}
+
+// HandleListSubscriptions answers "list_subscriptions" with the topics the
+// client is subscribed to.
+func (h *Handler) HandleListSubscriptions(ctx context.Context, conn *websocket.Conn, _ json.RawMessage) {
+	client := h.connMgr.GetClient(conn)
+	if client == nil {
+		return
+	}
+
+	_ = binance.Reply(ctx, conn, binance.WSMessage{
+		Method:  ListSubscriptions,
+		Success: true,
+		Data:    client.Topics(),
+	})
+}
+
+// HandleListSymbols answers "list_symbols" with the symbols of every exchange.
+func (h *Handler) HandleListSymbols(ctx context.Context, conn *websocket.Conn, _ json.RawMessage) {
+	list := make([]ExchangeSymbols, 0, len(h.exchanges))
+	for _, ex := range h.exchanges {
+		list = append(list, ExchangeSymbols{Exchange: ex.Name(), Symbols: ex.SubscribedSymbols()})
+	}
+
+	_ = binance.Reply(ctx, conn, binance.WSMessage{
+		Method:  ListSymbols,
+		Success: true,
+		Data:    list,
+	})
+}
+
+// HandleServerInfo answers "server_info" with the version, exchanges, topic
+// events and limits of the hub.
+func (h *Handler) HandleServerInfo(ctx context.Context, conn *websocket.Conn, _ json.RawMessage) {
+	_ = binance.Reply(ctx, conn, binance.WSMessage{
+		Method:  ServerInfoMethod,
+		Success: true,
+		Data:    h.serverInfo(),
+	})
+}
+
+func (h *Handler) serverInfo() ServerInfo {
+	info := ServerInfo{
+		Version:   version.Version,
+		Commit:    version.Commit,
+		Exchanges: make([]string, 0, len(h.exchanges)),
+		Events:    append(slices.Clone(BuiltinEvents), slices.Sorted(maps.Keys(h.providers))...),
+		Limits: Limits{
+			MaxRequestBytes: maxRequestBytes,
+			SendBuffer:      h.outbound.SendBuffer,
+			HighWaterMark:   h.outbound.HighWaterMark,
+			SlowConsumer:    h.outbound.SlowConsumer,
+		},
+		ServerTime: time.Now().UnixMilli(),
+	}
+	for _, ex := range h.exchanges {
+		info.Exchanges = append(info.Exchanges, ex.Name())
+	}
+	if h.rateLimit != nil {
+		if limit := h.rateLimit(); limit.Requests > 0 {
+			info.Limits.RateLimitRequests = limit.Requests
+			info.Limits.RateLimitWindow = limit.Window.String()
+		}
+	}
+	return info
+}
@@ -6,22 +6,63 @@ import (
 	"log/slog"
 	"net/http"
 	"strings"
+	"time"
 
+	"github.com/ChethiyaNishanath/market-data-hub/internal/config"
 	"github.com/ChethiyaNishanath/market-data-hub/internal/domain/subscription"
 	"github.com/ChethiyaNishanath/market-data-hub/internal/exchange/binance"
-	wsInterface "github.com/ChethiyaNishanath/market-data-hub/internal/interfaces/clients/websocket"
 	wsserver "github.com/ChethiyaNishanath/market-data-hub/internal/interfaces/servers/websocket"
 	orderBookStore "github.com/ChethiyaNishanath/market-data-hub/internal/store/memory"
 	"github.com/coder/websocket"
 )
 
+// SnapshotProvider answers a subscribe with the current state of a topic, keyed by
+// the upper-cased part before the "@".
+type SnapshotProvider interface {
+	Snapshot(key string) (any, bool)
+}
+
+// Hello is the first message on a connection, before any request is read.
+type Hello struct {
+	ClientID string `json:"client_id"`
+}
+
+// maxRequestBytes is the largest request a client may send; larger ones close
+// the connection.
+const maxRequestBytes = 32 * 1024
+
 type Handler struct {
-	router  *binance.Router
-	connMgr subscription.ClientConnectionManager
+	router    *binance.Router
+	connMgr   subscription.ClientConnectionManager
+	providers map[string]SnapshotProvider
+	exchanges []Exchange
+	rateLimit func() config.RateLimitConfig
+	outbound  wsserver.Options
 }
 
 func NewHandler(router *binance.Router, connMgr subscription.ClientConnectionManager) *Handler {
-	return &Handler{router: router, connMgr: connMgr}
+	return &Handler{
+		router:    router,
+		connMgr:   connMgr,
+		providers: make(map[string]SnapshotProvider),
+		outbound:  wsserver.Options{}.WithDefaults(),
+	}
+}
+
+// UseOutbound sets the outbound queue and slow-consumer policy of the clients
+// that connect from now on.
+func (h *Handler) UseOutbound(cfg config.WebSocketConfig) {
+	h.outbound = wsserver.Options{
+		SendBuffer:    cfg.SendBuffer,
+		HighWaterMark: cfg.HighWaterMark,
+		WriteTimeout:  cfg.WriteTimeout,
+		SlowConsumer:  cfg.SlowConsumer,
+	}.WithDefaults()
+}
+
+// RegisterTopic makes "<key>@<event>" topics subscribable, served by provider.
+func (h *Handler) RegisterTopic(event string, provider SnapshotProvider) {
+	h.providers[strings.ToLower(event)] = provider
 }
 
 func (h *Handler) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
@@ -31,14 +72,18 @@ func (h *Handler) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	clientSubscription := wsserver.NewWsClient(conn)
+	conn.SetReadLimit(maxRequestBytes)
+	outbound := h.outbound
+	outbound.Resync = h.resync
+	clientSubscription := wsserver.NewWsClient(conn, outbound)
 
 	slog.Info("WebSocket clientSubscription connected")
 	h.connMgr.Register(clientSubscription)
 	defer h.connMgr.Unregister(clientSubscription)
 
 	background := context.Background()
-	if err := conn.Write(background, websocket.MessageText, []byte(`{"client_id":"`+clientSubscription.ID()+`"}`)); err != nil {
+	hello, _ := json.Marshal(Hello{ClientID: clientSubscription.ID()})
+	if err := conn.Write(background, websocket.MessageText, hello); err != nil {
 		return
 	}
 
@@ -58,12 +103,15 @@ func (h *Handler) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
 		var msg binance.WSRequest
 		if err := json.Unmarshal(data, &msg); err != nil {
 			slog.Error("Invalid Payload:", "error", err)
+			_ = binance.Reply(ctx, conn, binance.WSMessage{Code: binance.CodeInvalidRequest, Error: "invalid request: " + err.Error()})
 			continue
 		}
 
 		slog.Debug("Received:", "data", string(data))
 
-		go h.router.Dispatch(ctx, conn, msg)
+		// Requests of one client are handled one at a time, so replies come in
+		// the order the requests were sent.
+		h.router.Dispatch(ctx, conn, msg)
 	}
 
 	if err := conn.Close(websocket.StatusNormalClosure, ""); err != nil {
@@ -77,7 +125,7 @@ func (h *Handler) HandleSubscribe(ctx context.Context, conn *websocket.Conn, pay
 	}
 
 	if err := json.Unmarshal(payload, &data); err != nil || data.Topic == "" {
-		h.writeError(conn, "subscribe", "Invalid payload or missing topic")
+		h.writeError(ctx, conn, "subscribe", binance.CodeInvalidParams, "Invalid payload or missing topic")
 		return
 	}
 
@@ -91,7 +139,7 @@ func (h *Handler) HandleSubscribe(ctx context.Context, conn *websocket.Conn, pay
 
 	parts := strings.Split(data.Topic, "@")
 	if len(parts) != 2 {
-		h.writeError(conn, "subscribe", "Invalid topic format. Expect <symbol>@<event>")
+		h.writeError(ctx, conn, "subscribe", binance.CodeInvalidTopic, "Invalid topic format. Expect <symbol>@<event>")
 		return
 	}
 
@@ -101,8 +149,15 @@ func (h *Handler) HandleSubscribe(ctx context.Context, conn *websocket.Conn, pay
 	switch event {
 	case "depth":
 		h.handleDepthSubscription(ctx, conn, symbol)
+	case "connection", "depth.reset", "trade":
+		h.writeSuccess(ctx, conn, "subscribe", data.Topic)
 	default:
-		h.writeError(conn, "subscribe", "Unsupported event type: "+event)
+		provider, ok := h.providers[event]
+		if !ok {
+			h.writeError(ctx, conn, "subscribe", binance.CodeUnsupportedEvent, "Unsupported event type: "+event)
+			return
+		}
+		h.handleProviderSubscription(ctx, conn, data.Topic, symbol, provider)
 	}
 }
 
@@ -115,10 +170,11 @@ func (h *Handler) HandleUnsubscribe(ctx context.Context, conn *websocket.Conn, p
 		msg := binance.WSMessage{
 			Method:  "unsubscribe",
 			Success: false,
+			Code:    binance.CodeInvalidParams,
 			Error:   "invalid payload or missing topic",
 		}
 
-		if writerErr := wsInterface.WriteJSON(ctx, conn, msg); writerErr != nil {
+		if writerErr := binance.Reply(ctx, conn, msg); writerErr != nil {
 			return
 		}
 
@@ -139,7 +195,7 @@ func (h *Handler) HandleUnsubscribe(ctx context.Context, conn *websocket.Conn, p
 		Topic:   data.Topic,
 	}
 
-	err := wsInterface.WriteJSON(ctx, conn, msg)
+	err := binance.Reply(ctx, conn, msg)
 	if err != nil {
 		return
 	}
@@ -150,7 +206,7 @@ func (h *Handler) handleDepthSubscription(ctx context.Context, conn *websocket.C
 	snapshot, ok := store.GetItem(symbol)
 
 	if !ok {
-		h.writeError(conn, "subscribe", "Unknown symbol: "+symbol)
+		h.writeError(ctx, conn, "subscribe", binance.CodeUnknownSymbol, "Unknown symbol: "+symbol)
 		return
 	}
 
@@ -160,19 +216,94 @@ func (h *Handler) handleDepthSubscription(ctx context.Context, conn *websocket.C
 		Success: true,
 		Data:    snapshot,
 	}
-	err := wsInterface.WriteJSON(ctx, conn, msg)
+	err := binance.Reply(ctx, conn, msg)
+	if err != nil {
+		return
+	}
+}
+
+// resync restates the current state of topic for a client that lost pushes of
+// it: an orderbook_reset for a depth topic and a snapshot for a provider's.
+func (h *Handler) resync(topic string) ([]byte, bool) {
+	key, event, ok := strings.Cut(topic, "@")
+	if !ok {
+		return nil, false
+	}
+	key, event = strings.ToUpper(key), strings.ToLower(event)
+
+	var msg binance.WSMessage
+	switch provider, ok := h.providers[event]; {
+	case event == "depth":
+		snapshot, ok := orderBookStore.GetOrderBookStore().GetItem(key)
+		if !ok {
+			return nil, false
+		}
+		msg = binance.WSMessage{
+			Method: "orderbook_reset",
+			Data: binance.OrderBookResetEvent{
+				Symbol:    key,
+				Snapshot:  *snapshot,
+				Reason:    "Slow consumer",
+				Timestamp: time.Now().Unix(),
+			},
+		}
+	case ok:
+		snapshot, ok := provider.Snapshot(key)
+		if !ok {
+			return nil, false
+		}
+		msg = binance.WSMessage{Topic: topic, Data: snapshot}
+	default:
+		return nil, false
+	}
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		slog.Warn("Failed to marshal resync", "topic", topic, "error", err)
+		return nil, false
+	}
+	return data, true
+}
+
+func (h *Handler) handleProviderSubscription(ctx context.Context, conn *websocket.Conn, topic, key string, provider SnapshotProvider) {
+	snapshot, ok := provider.Snapshot(key)
+	if !ok {
+		h.writeError(ctx, conn, "subscribe", binance.CodeUnknownSymbol, "Unknown instrument: "+key)
+		return
+	}
+
+	msg := binance.WSMessage{
+		Method:  "subscribe",
+		Topic:   topic,
+		Success: true,
+		Data:    snapshot,
+	}
+	err := binance.Reply(ctx, conn, msg)
+	if err != nil {
+		return
+	}
+}
+
+func (h *Handler) writeSuccess(ctx context.Context, conn *websocket.Conn, method, topic string) {
+	msg := binance.WSMessage{
+		Method:  method,
+		Success: true,
+		Topic:   topic,
+	}
+	err := binance.Reply(ctx, conn, msg)
 	if err != nil {
 		return
 	}
 }
 
-func (h *Handler) writeError(conn *websocket.Conn, method, errMsg string) {
+func (h *Handler) writeError(ctx context.Context, conn *websocket.Conn, method, code, errMsg string) {
 	msg := binance.WSMessage{
 		Method:  method,
 		Success: false,
+		Code:    code,
 		Error:   errMsg,
 	}
-	err := wsInterface.WriteJSON(context.Background(), conn, msg)
+	err := binance.Reply(ctx, conn, msg)
 	if err != nil {
 		return
 	}
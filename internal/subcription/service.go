@@ -18,6 +18,10 @@ func NewService(connMgr subscription.ClientConnectionManager) *Service {
 
 	router.Handle(Subscribe, handler.HandleSubscribe)
 	router.Handle(Unsubscribe, handler.HandleUnsubscribe)
+	router.Handle(ListSubscriptions, handler.HandleListSubscriptions)
+	router.Handle(ListSymbols, handler.HandleListSymbols)
+	router.Handle(Ping, handler.HandlePing)
+	router.Handle(ServerInfoMethod, handler.HandleServerInfo)
 
 	return &Service{
 		Handler: handler,
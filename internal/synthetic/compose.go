@@ -0,0 +1,128 @@
+package synthetic
+
+import (
+	"github.com/ChethiyaNishanath/market-data-hub/internal/domain/orderbook"
+)
+
+const (
+	OpMultiply = "multiply"
+	OpDivide   = "divide"
+)
+
+// Ladder is one side-sorted book: bids best first (highest), asks best first (lowest).
+type Ladder struct {
+	Bids []orderbook.Level `json:"bids"`
+	Asks []orderbook.Level `json:"asks"`
+}
+
+func LadderFromBook(ob *orderbook.OrderBook) Ladder {
+	return Ladder{Bids: ob.SortedBids(), Asks: ob.SortedAsks()}
+}
+
+// Invert turns a C/B ladder into B/C. Selling C for B becomes buying B with C, so
+// the sides swap; prices become 1/p and quantities are expressed in B (p*q).
+func Invert(l Ladder) Ladder {
+	return Ladder{
+		Bids: invertSide(l.Asks),
+		Asks: invertSide(l.Bids),
+	}
+}
+
+func invertSide(levels []orderbook.Level) []orderbook.Level {
+	inverted := make([]orderbook.Level, 0, len(levels))
+	for _, lvl := range levels {
+		if lvl.Price <= 0 {
+			continue
+		}
+		inverted = append(inverted, orderbook.Level{Price: 1 / lvl.Price, Quantity: lvl.Price * lvl.Quantity})
+	}
+	return inverted
+}
+
+// Multiply composes an A/B ladder with a B/C ladder into A/C by walking both
+// depths: every unit of A sold on the first book must be sold again as B on the
+// second, so quantity is limited by whichever side runs out first.
+func Multiply(first, second Ladder, depth int) Ladder {
+	return Ladder{
+		Bids: walk(first.Bids, second.Bids, depth),
+		Asks: walk(first.Asks, second.Asks, depth),
+	}
+}
+
+// Compose folds the ladders left to right with the given operations, where ops[i]
+// applies ladders[i+1] to the running result.
+func Compose(ladders []Ladder, ops []string, depth int) Ladder {
+	if len(ladders) == 0 {
+		return Ladder{}
+	}
+
+	result := ladders[0]
+	for i, next := range ladders[1:] {
+		if ops[i] == OpDivide {
+			next = Invert(next)
+		}
+		result = Multiply(result, next, depth)
+	}
+
+	if depth > 0 {
+		result.Bids = truncate(result.Bids, depth)
+		result.Asks = truncate(result.Asks, depth)
+	}
+	return result
+}
+
+func walk(outer, inner []orderbook.Level, depth int) []orderbook.Level {
+	levels := make([]orderbook.Level, 0)
+
+	i, j := 0, 0
+	var outerLeft, innerLeft float64
+	if len(outer) > 0 {
+		outerLeft = outer[0].Quantity
+	}
+	if len(inner) > 0 {
+		innerLeft = inner[0].Quantity
+	}
+
+	for i < len(outer) && j < len(inner) {
+		p1, p2 := outer[i].Price, inner[j].Price
+
+		// Units of A that both the current outer level and the B available on the
+		// current inner level can absorb.
+		qty := min(outerLeft, innerLeft/p1)
+		price := p1 * p2
+
+		if n := len(levels); n > 0 && levels[n-1].Price == price {
+			levels[n-1].Quantity += qty
+		} else {
+			if depth > 0 && len(levels) == depth {
+				break
+			}
+			levels = append(levels, orderbook.Level{Price: price, Quantity: qty})
+		}
+
+		outerLeft -= qty
+		innerLeft -= qty * p1
+
+		if outerLeft <= 1e-12 {
+			i++
+			if i < len(outer) {
+				outerLeft = outer[i].Quantity
+			}
+		}
+		if innerLeft <= 1e-12 {
+			j++
+			if j < len(inner) {
+				innerLeft = inner[j].Quantity
+			}
+		}
+	}
+
+	return levels
+}
+
+func truncate(levels []orderbook.Level, depth int) []orderbook.Level {
+	if len(levels) > depth {
+		return levels[:depth]
+	}
+	return levels
+}
@@ -0,0 +1,248 @@
+package synthetic
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ChethiyaNishanath/market-data-hub/internal/bus"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/config"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/domain/exchange"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/domain/orderbook"
+)
+
+const (
+	SyntheticBookUpdate = "syntheticBook"
+	TopicSuffix         = "@synthetic"
+
+	defaultDepth = 50
+	defaultVenue = "binance"
+)
+
+type Book struct {
+	Instrument string            `json:"instrument"`
+	Valid      bool              `json:"valid"`
+	Reason     string            `json:"reason,omitempty"`
+	Legs       []string          `json:"legs"`
+	Bids       []orderbook.Level `json:"bids"`
+	Asks       []orderbook.Level `json:"asks"`
+	Timestamp  int64             `json:"timestamp"`
+}
+
+// Engine rebuilds a synthetic book whenever one of its source books changes. Bursts
+// of source updates are coalesced into a single rebuild rather than throttled.
+type Engine struct {
+	cfg     config.SyntheticConfig
+	bus     bus.IBus
+	sources map[string]exchange.BookSource
+	wake    chan struct{}
+
+	mu    sync.RWMutex
+	books map[string]Book
+	dirty map[string]bool
+}
+
+func NewEngine(cfg config.SyntheticConfig, eventBus bus.IBus, sources ...exchange.BookSource) *Engine {
+	if cfg.Depth <= 0 {
+		cfg.Depth = defaultDepth
+	}
+
+	bySource := make(map[string]exchange.BookSource, len(sources))
+	for _, src := range sources {
+		bySource[strings.ToLower(src.Name())] = src
+	}
+
+	return &Engine{
+		cfg:     cfg,
+		bus:     eventBus,
+		sources: bySource,
+		wake:    make(chan struct{}, 1),
+		books:   make(map[string]Book),
+		dirty:   make(map[string]bool),
+	}
+}
+
+// Topic is the bus and WebSocket topic a synthetic instrument is published on.
+func Topic(instrument string) string {
+	return strings.ToLower(instrument) + TopicSuffix
+}
+
+// Topics lists the topics of every configured instrument.
+func (e *Engine) Topics() []string {
+	topics := make([]string, 0, len(e.cfg.Instruments))
+	for _, inst := range e.cfg.Instruments {
+		topics = append(topics, Topic(inst.Name))
+	}
+	return topics
+}
+
+// Validate rejects instruments that cannot be composed.
+func Validate(cfg config.SyntheticConfig) error {
+	for _, inst := range cfg.Instruments {
+		if inst.Name == "" {
+			return fmt.Errorf("synthetic instrument without a name")
+		}
+		if len(inst.Legs) < 2 {
+			return fmt.Errorf("synthetic instrument %s needs at least two legs", inst.Name)
+		}
+		for i, leg := range inst.Legs {
+			if leg.Symbol == "" {
+				return fmt.Errorf("synthetic instrument %s leg %d has no symbol", inst.Name, i)
+			}
+			if i == 0 {
+				continue
+			}
+			switch strings.ToLower(leg.Operation) {
+			case OpMultiply, OpDivide:
+			default:
+				return fmt.Errorf("synthetic instrument %s leg %d has unknown operation %q", inst.Name, i, leg.Operation)
+			}
+		}
+	}
+	return nil
+}
+
+func (e *Engine) Start(ctx context.Context) {
+	for _, inst := range e.cfg.Instruments {
+		name := strings.ToUpper(inst.Name)
+		for _, leg := range inst.Legs {
+			symbol := strings.ToLower(leg.Symbol)
+			for _, topic := range []string{symbol + "@depth", symbol + "@depth.reset", symbol + "@connection"} {
+				e.bus.Subscribe(topic, func(bus.Event) {
+					e.markDirty(name)
+				})
+			}
+		}
+		e.markDirty(name)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-e.wake:
+			e.publishDirty()
+		}
+	}
+}
+
+func (e *Engine) markDirty(instrument string) {
+	e.mu.Lock()
+	e.dirty[instrument] = true
+	e.mu.Unlock()
+
+	select {
+	case e.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (e *Engine) publishDirty() {
+	e.mu.Lock()
+	pending := e.dirty
+	e.dirty = make(map[string]bool)
+	e.mu.Unlock()
+
+	for _, inst := range e.cfg.Instruments {
+		name := strings.ToUpper(inst.Name)
+		if !pending[name] {
+			continue
+		}
+
+		book := e.build(inst)
+
+		e.mu.Lock()
+		e.books[name] = book
+		e.mu.Unlock()
+
+		e.bus.Publish(SyntheticBookUpdate, Topic(name), book)
+	}
+}
+
+// build composes the legs. If any source is missing or resyncing the book is
+// published as invalid with no levels, so consumers never price off a stale leg.
+func (e *Engine) build(inst config.SyntheticInstrument) Book {
+	book := Book{
+		Instrument: strings.ToUpper(inst.Name),
+		Legs:       make([]string, 0, len(inst.Legs)),
+		Bids:       []orderbook.Level{},
+		Asks:       []orderbook.Level{},
+		Timestamp:  time.Now().UnixMilli(),
+	}
+
+	ladders := make([]Ladder, 0, len(inst.Legs))
+	ops := make([]string, 0, len(inst.Legs))
+
+	for i, leg := range inst.Legs {
+		symbol := strings.ToUpper(leg.Symbol)
+		book.Legs = append(book.Legs, symbol)
+
+		venue := leg.Venue
+		if venue == "" {
+			venue = defaultVenue
+		}
+
+		source, ok := e.sources[strings.ToLower(venue)]
+		if !ok {
+			slog.Warn("Synthetic source venue not configured", "instrument", inst.Name, "venue", venue)
+			book.Reason = fmt.Sprintf("venue %s not configured", venue)
+			continue
+		}
+		if !source.IsSynchronized(symbol) {
+			if book.Reason == "" {
+				book.Reason = fmt.Sprintf("%s is resyncing", symbol)
+			}
+			continue
+		}
+
+		ob := source.GetOrderBook(symbol)
+		if ob == nil {
+			if book.Reason == "" {
+				book.Reason = fmt.Sprintf("%s has no book", symbol)
+			}
+			continue
+		}
+
+		ladders = append(ladders, LadderFromBook(ob))
+		if i > 0 {
+			ops = append(ops, strings.ToLower(leg.Operation))
+		}
+	}
+
+	if book.Reason != "" {
+		return book
+	}
+
+	composed := Compose(ladders, ops, e.cfg.Depth)
+	book.Valid = true
+	book.Bids = composed.Bids
+	book.Asks = composed.Asks
+	return book
+}
+
+// Get returns the last published synthetic book for an instrument.
+func (e *Engine) Get(instrument string) (Book, bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	book, ok := e.books[strings.ToUpper(instrument)]
+	return book, ok
+}
+
+// Snapshot lets the WebSocket handler answer a subscribe with the current book.
+func (e *Engine) Snapshot(instrument string) (any, bool) {
+	return e.Get(instrument)
+}
+
+// Instruments lists the configured synthetic instrument names.
+func (e *Engine) Instruments() []string {
+	names := make([]string, 0, len(e.cfg.Instruments))
+	for _, inst := range e.cfg.Instruments {
+		names = append(names, strings.ToUpper(inst.Name))
+	}
+	sort.Strings(names)
+	return names
+}
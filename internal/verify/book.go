@@ -0,0 +1,158 @@
+package verify
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/ChethiyaNishanath/market-data-hub/internal/domain/orderbook"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/exchange/binance"
+)
+
+var (
+	// ErrIncomplete means a delta needed to bring the books to the same update
+	// ID has not been seen yet; align again once more deltas arrived.
+	ErrIncomplete = errors.New("waiting for deltas")
+	// ErrUnbridgeable means the deltas that would bring the older book up to
+	// date are no longer available.
+	ErrUnbridgeable = errors.New("books cannot be brought to the same update id")
+)
+
+// Delta is one depth update as the hub publishes it.
+type Delta struct {
+	First int64
+	Final int64
+	Bids  [][]string
+	Asks  [][]string
+}
+
+func DeltaFromEvent(ev binance.DepthUpdateEvent) Delta {
+	return Delta{
+		First: int64(ev.FirstUpdateEventID),
+		Final: int64(ev.FinalUpdateEventID),
+		Bids:  ev.BidsToUpdated,
+		Asks:  ev.AsksToUpdated,
+	}
+}
+
+// Book is a price-indexed copy of an order book that deltas can be applied to.
+// A depth-limited book only holds the best levels, so it remembers the price
+// range it covered; levels outside it are not compared.
+type Book struct {
+	LastUpdateID int64
+	Bids         map[float64]float64
+	Asks         map[float64]float64
+
+	bounded  bool
+	bidFloor float64
+	askCeil  float64
+}
+
+// FromOrderBook copies a hub book, which holds every level the hub knows of.
+func FromOrderBook(ob *orderbook.OrderBook) (*Book, error) {
+	return fromLevels(int64(ob.LastUpdateID), ob.Bids, ob.Asks, false)
+}
+
+// ReferenceFromOrderBook copies a depth-limited book, such as an exchange
+// snapshot or a hub book published with a depth limit.
+func ReferenceFromOrderBook(ob *orderbook.OrderBook) (*Book, error) {
+	return fromLevels(int64(ob.LastUpdateID), ob.Bids, ob.Asks, true)
+}
+
+func fromLevels(id int64, bids, asks [][]string, bounded bool) (*Book, error) {
+	b := &Book{
+		LastUpdateID: id,
+		Bids:         make(map[float64]float64, len(bids)),
+		Asks:         make(map[float64]float64, len(asks)),
+		bounded:      bounded,
+		bidFloor:     math.Inf(1),
+		askCeil:      math.Inf(-1),
+	}
+	if err := setLevels(b.Bids, bids); err != nil {
+		return nil, err
+	}
+	if err := setLevels(b.Asks, asks); err != nil {
+		return nil, err
+	}
+
+	for price := range b.Bids {
+		b.bidFloor = min(b.bidFloor, price)
+	}
+	for price := range b.Asks {
+		b.askCeil = max(b.askCeil, price)
+	}
+	return b, nil
+}
+
+func setLevels(side map[float64]float64, levels [][]string) error {
+	for _, lvl := range levels {
+		if len(lvl) < 2 {
+			continue
+		}
+		price, err := strconv.ParseFloat(lvl[0], 64)
+		if err != nil {
+			return fmt.Errorf("price %q: %w", lvl[0], err)
+		}
+		qty, err := strconv.ParseFloat(lvl[1], 64)
+		if err != nil {
+			return fmt.Errorf("quantity %q: %w", lvl[1], err)
+		}
+		if qty == 0 {
+			delete(side, price)
+		} else {
+			side[price] = qty
+		}
+	}
+	return nil
+}
+
+// apply follows the exchange's rule for the first delta after a snapshot: it
+// must cover LastUpdateID+1. Older deltas are ignored.
+func (b *Book) apply(d Delta) (bool, error) {
+	if orderbook.Sequence(b.LastUpdateID, d.First, d.Final) != orderbook.Apply {
+		return false, nil
+	}
+	if err := setLevels(b.Bids, d.Bids); err != nil {
+		return false, err
+	}
+	if err := setLevels(b.Asks, d.Asks); err != nil {
+		return false, err
+	}
+	b.LastUpdateID = d.Final
+	return true, nil
+}
+
+// Align rolls whichever of hub and ref is behind forward with deltas, in any
+// order, until both stand at the same update ID. It can be called again with
+// more deltas after ErrIncomplete.
+//
+// oldest is the first update ID deltas is known to be complete from; a book
+// further behind than that can never be brought up to date.
+func Align(hub, ref *Book, deltas []Delta, oldest int64) error {
+	for hub.LastUpdateID != ref.LastUpdateID {
+		behind := hub
+		if ref.LastUpdateID < hub.LastUpdateID {
+			behind = ref
+		}
+		if behind.LastUpdateID+1 < oldest {
+			return ErrUnbridgeable
+		}
+
+		progressed := false
+		for _, d := range deltas {
+			ok, err := behind.apply(d)
+			if err != nil {
+				return err
+			}
+			if ok {
+				progressed = true
+				break
+			}
+		}
+		if !progressed {
+			return ErrIncomplete
+		}
+	}
+	return nil
+}
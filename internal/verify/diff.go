@@ -0,0 +1,152 @@
+package verify
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"text/tabwriter"
+)
+
+const (
+	StatusOK       = "ok"
+	StatusMismatch = "mismatch"
+	StatusSkipped  = "skipped"
+	StatusPending  = "pending"
+
+	SideBid = "bid"
+	SideAsk = "ask"
+
+	// quantityEpsilon absorbs float parsing noise; quantities are decimal
+	// strings on both sides, so equal books compare exactly.
+	quantityEpsilon = 1e-9
+)
+
+// Mismatch is a price level whose quantity differs. A quantity of 0 means the
+// level is missing from that book.
+type Mismatch struct {
+	Side             string  `json:"side"`
+	Price            string  `json:"price"`
+	HubQuantity      float64 `json:"hubQuantity"`
+	ExchangeQuantity float64 `json:"exchangeQuantity"`
+}
+
+// Report is the result of one comparison of a hub book against an exchange
+// snapshot at the same update ID.
+type Report struct {
+	Exchange     string     `json:"exchange"`
+	Symbol       string     `json:"symbol"`
+	Status       string     `json:"status"`
+	Reason       string     `json:"reason,omitempty"`
+	LastUpdateID int64      `json:"lastUpdateId,omitempty"`
+	Levels       int        `json:"levels"`
+	Mismatches   []Mismatch `json:"mismatches,omitempty"`
+	Resynced     bool       `json:"resynced,omitempty"`
+	CheckedAt    int64      `json:"checkedAt"`
+}
+
+// WriteText prints one summary line per report followed by its mismatches.
+func WriteText(w io.Writer, reports []Report) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	for _, r := range reports {
+		switch r.Status {
+		case StatusSkipped:
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", r.Symbol, r.Status, r.Reason)
+		default:
+			fmt.Fprintf(tw, "%s\t%s\tupdate %d, %d levels, %d mismatches\n", r.Symbol, r.Status, r.LastUpdateID, r.Levels, len(r.Mismatches))
+		}
+		for _, m := range r.Mismatches {
+			fmt.Fprintf(tw, "\t%s %s\thub %v, exchange %v\n", m.Side, m.Price, m.HubQuantity, m.ExchangeQuantity)
+		}
+	}
+	return tw.Flush()
+}
+
+// WriteJSON prints the reports as a JSON array.
+func WriteJSON(w io.Writer, reports []Report) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(reports)
+}
+
+// Diff compares hub with ref level by level. Only prices within the range both
+// books covered are compared, and depth, when positive, narrows that to the
+// best depth levels of the reference on each side.
+func Diff(hub, ref *Book, depth int) Report {
+	report := Report{Status: StatusOK, LastUpdateID: ref.LastUpdateID}
+
+	bidFloor, askCeil := math.Inf(-1), math.Inf(1)
+	for _, b := range []*Book{hub, ref} {
+		if b.bounded {
+			bidFloor, askCeil = max(bidFloor, b.bidFloor), min(askCeil, b.askCeil)
+		}
+	}
+	if depth > 0 {
+		bidFloor = max(bidFloor, nthPrice(ref.Bids, depth, true))
+		askCeil = min(askCeil, nthPrice(ref.Asks, depth, false))
+	}
+
+	report.Levels += diffSide(&report, SideBid, hub.Bids, ref.Bids, func(p float64) bool { return p >= bidFloor })
+	report.Levels += diffSide(&report, SideAsk, hub.Asks, ref.Asks, func(p float64) bool { return p <= askCeil })
+
+	if len(report.Mismatches) > 0 {
+		report.Status = StatusMismatch
+	}
+	return report
+}
+
+func diffSide(report *Report, side string, hub, ref map[float64]float64, inRange func(float64) bool) int {
+	prices := make([]float64, 0, len(ref))
+	for price := range ref {
+		if inRange(price) {
+			prices = append(prices, price)
+		}
+	}
+	for price := range hub {
+		if _, ok := ref[price]; !ok && inRange(price) {
+			prices = append(prices, price)
+		}
+	}
+
+	slices.Sort(prices)
+	if side == SideBid {
+		slices.Reverse(prices)
+	}
+
+	for _, price := range prices {
+		h, r := hub[price], ref[price]
+		if math.Abs(h-r) > quantityEpsilon*max(math.Abs(h), math.Abs(r), 1) {
+			report.Mismatches = append(report.Mismatches, Mismatch{
+				Side:             side,
+				Price:            strconv.FormatFloat(price, 'f', -1, 64),
+				HubQuantity:      h,
+				ExchangeQuantity: r,
+			})
+		}
+	}
+	return len(prices)
+}
+
+// nthPrice returns the price of the nth best level, or the worst one when the
+// side is shorter.
+func nthPrice(side map[float64]float64, n int, bids bool) float64 {
+	prices := make([]float64, 0, len(side))
+	for price := range side {
+		prices = append(prices, price)
+	}
+	if len(prices) == 0 {
+		if bids {
+			return math.Inf(1)
+		}
+		return math.Inf(-1)
+	}
+
+	slices.Sort(prices)
+	if bids {
+		slices.Reverse(prices)
+	}
+	return prices[min(n, len(prices))-1]
+}
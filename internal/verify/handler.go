@@ -0,0 +1,39 @@
+package verify
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// RegisterRoutes mounts GET /verify and GET /verify/{symbol}.
+func (v *Verifier) RegisterRoutes(r chi.Router) {
+	r.Get("/verify", v.handleReports)
+	r.Get("/verify/{symbol}", v.handleReport)
+}
+
+// handleReports serves the latest report of every checked symbol.
+func (v *Verifier) handleReports(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, v.Reports())
+}
+
+// handleReport serves the latest report of one symbol.
+func (v *Verifier) handleReport(w http.ResponseWriter, r *http.Request) {
+	report, ok := v.Snapshot(chi.URLParam(r, "symbol"))
+	if !ok {
+		writeJSON(w, http.StatusNotFound, errorResponse{Error: "unknown symbol"})
+		return
+	}
+	writeJSON(w, http.StatusOK, report)
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
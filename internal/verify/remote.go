@@ -0,0 +1,207 @@
+package verify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ChethiyaNishanath/market-data-hub/internal/domain/exchange"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/domain/orderbook"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/exchange/binance"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/retry"
+	"github.com/coder/websocket"
+)
+
+// ErrUnknownSymbol means the hub rejected the subscription, usually because it
+// does not carry the symbol.
+var ErrUnknownSymbol = errors.New("hub rejected the subscription")
+
+// RemoteConfig describes a check of a running hub from outside the process.
+type RemoteConfig struct {
+	// URL is the hub's WebSocket endpoint.
+	URL      string
+	Exchange string
+	Depth    int
+}
+
+// CheckRemote compares the book a hub serves over WebSocket with a snapshot
+// from source. The book comes with the reply to a <symbol>@depth subscription
+// and the updates that follow are its deltas, so the hub's side is rebuilt
+// without access to its process. The hub may publish only the best levels of a
+// book, so only the range its book covers is compared. ctx bounds the whole
+// check; when it ends before the books line up the report is skipped.
+func CheckRemote(ctx context.Context, cfg RemoteConfig, source exchange.SnapshotSource, symbol string) (Report, error) {
+	symbol = strings.ToUpper(symbol)
+	report := Report{Exchange: cfg.Exchange, Symbol: symbol}
+
+	feed, err := dialHub(ctx, cfg.URL, symbol)
+	if err != nil {
+		return report, err
+	}
+	defer feed.close()
+
+	hubBook, err := feed.book(ctx)
+	if err != nil {
+		return report, err
+	}
+	hub, err := ReferenceFromOrderBook(hubBook)
+	if err != nil {
+		return report, fmt.Errorf("hub book: %w", err)
+	}
+	oldest := hub.LastUpdateID + 1
+
+	refBook, err := source.FetchSnapshot(ctx, symbol)
+	if err != nil {
+		return report, fmt.Errorf("exchange snapshot: %w", err)
+	}
+	ref, err := ReferenceFromOrderBook(refBook)
+	if err != nil {
+		return report, fmt.Errorf("exchange snapshot: %w", err)
+	}
+
+	for {
+		deltas, reset := feed.deltas()
+		if reset {
+			report.Status, report.Reason = StatusSkipped, "hub book was reset during the check"
+			break
+		}
+		err := Align(hub, ref, deltas, oldest)
+		if err == nil {
+			checked := Diff(hub, ref, cfg.Depth)
+			checked.Exchange, checked.Symbol = report.Exchange, report.Symbol
+			report = checked
+			break
+		}
+		if !errors.Is(err, ErrIncomplete) || retry.Sleep(ctx, alignPoll) != nil {
+			report.Status = StatusSkipped
+			report.Reason = fmt.Sprintf("hub at %d, exchange at %d: %v", hub.LastUpdateID, ref.LastUpdateID, err)
+			break
+		}
+	}
+
+	report.CheckedAt = time.Now().UnixMilli()
+	return report, nil
+}
+
+// hubFeed collects one symbol's book and deltas from the hub.
+type hubFeed struct {
+	conn   *websocket.Conn
+	cancel context.CancelFunc
+	ready  chan struct{}
+
+	mu      sync.Mutex
+	snap    *orderbook.OrderBook
+	err     error
+	updates []Delta
+	reset   bool
+}
+
+func dialHub(ctx context.Context, url, symbol string) (*hubFeed, error) {
+	conn, _, err := websocket.Dial(ctx, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("hub: %w", err)
+	}
+	conn.SetReadLimit(64 * 1024 * 1024)
+
+	lower := strings.ToLower(symbol)
+	for _, topic := range []string{lower + "@depth", lower + "@depth.reset"} {
+		data, _ := json.Marshal(map[string]any{"method": "subscribe", "params": map[string]string{"topic": topic}})
+		if err := conn.Write(ctx, websocket.MessageText, data); err != nil {
+			conn.CloseNow()
+			return nil, fmt.Errorf("hub: %w", err)
+		}
+	}
+
+	readCtx, cancel := context.WithCancel(ctx)
+	f := &hubFeed{conn: conn, cancel: cancel, ready: make(chan struct{})}
+	go f.read(readCtx, symbol)
+	return f, nil
+}
+
+func (f *hubFeed) close() {
+	f.cancel()
+	f.conn.CloseNow()
+}
+
+func (f *hubFeed) read(ctx context.Context, symbol string) {
+	var once sync.Once
+	done := func(book *orderbook.OrderBook, err error) {
+		once.Do(func() {
+			f.mu.Lock()
+			f.snap, f.err = book, err
+			f.mu.Unlock()
+			close(f.ready)
+		})
+	}
+
+	for {
+		_, data, err := f.conn.Read(ctx)
+		if err != nil {
+			done(nil, fmt.Errorf("hub: %w", err))
+			return
+		}
+
+		var msg struct {
+			Method string          `json:"method"`
+			Error  string          `json:"error"`
+			Topic  string          `json:"topic"`
+			Data   json.RawMessage `json:"data"`
+		}
+		if json.Unmarshal(data, &msg) != nil {
+			continue
+		}
+
+		switch msg.Method {
+		case "subscribe":
+			if msg.Error != "" {
+				done(nil, fmt.Errorf("%w: %s", ErrUnknownSymbol, msg.Error))
+				continue
+			}
+			if !strings.EqualFold(msg.Topic, symbol+"@depth") {
+				continue
+			}
+			var book orderbook.OrderBook
+			if err := json.Unmarshal(msg.Data, &book); err != nil {
+				done(nil, fmt.Errorf("hub book: %w", err))
+				continue
+			}
+			done(&book, nil)
+
+		case "orderbook_reset":
+			f.mu.Lock()
+			f.reset = true
+			f.mu.Unlock()
+
+		case "":
+			var ev binance.DepthUpdateEvent
+			if len(msg.Data) == 0 || json.Unmarshal(msg.Data, &ev) != nil || !strings.EqualFold(ev.Symbol, symbol) {
+				continue
+			}
+			f.mu.Lock()
+			f.updates = append(f.updates, DeltaFromEvent(ev))
+			f.mu.Unlock()
+		}
+	}
+}
+
+// book waits for the hub's reply to the depth subscription.
+func (f *hubFeed) book(ctx context.Context) (*orderbook.OrderBook, error) {
+	select {
+	case <-f.ready:
+	case <-ctx.Done():
+		return nil, fmt.Errorf("hub: %w", ctx.Err())
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.snap, f.err
+}
+
+func (f *hubFeed) deltas() ([]Delta, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Delta(nil), f.updates...), f.reset
+}
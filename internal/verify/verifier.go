@@ -0,0 +1,280 @@
+package verify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ChethiyaNishanath/market-data-hub/internal/bus"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/config"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/domain/exchange"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/exchange/binance"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/retry"
+)
+
+const (
+	VerifyReport = "verifyReport"
+	TopicSuffix  = "@verify"
+
+	defaultInterval     = 5 * time.Minute
+	defaultDepth        = 100
+	defaultAlignTimeout = 10 * time.Second
+	alignPoll           = 25 * time.Millisecond
+)
+
+// Source is a venue whose books can be checked against its own snapshots.
+type Source interface {
+	exchange.BookSource
+	exchange.SymbolLister
+	exchange.SnapshotSource
+	exchange.Resyncer
+}
+
+// Verifier periodically compares every book the source maintains with a fresh
+// exchange snapshot at the same update ID and publishes the result on
+// <symbol>@verify. Deltas are buffered only while a symbol is being checked.
+type Verifier struct {
+	cfg    config.VerifyConfig
+	bus    bus.IBus
+	source Source
+
+	mu         sync.Mutex
+	buffers    map[string]*buffer
+	reports    map[string]Report
+	subscribed map[string]bool
+}
+
+type buffer struct {
+	mu     sync.Mutex
+	deltas []Delta
+}
+
+func (b *buffer) add(d Delta) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.deltas = append(b.deltas, d)
+}
+
+func (b *buffer) snapshot() []Delta {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return slices.Clone(b.deltas)
+}
+
+func NewVerifier(cfg config.VerifyConfig, eventBus bus.IBus, source Source) *Verifier {
+	if cfg.Interval <= 0 {
+		cfg.Interval = defaultInterval
+	}
+	if cfg.Depth < 0 {
+		cfg.Depth = 0
+	} else if cfg.Depth == 0 {
+		cfg.Depth = defaultDepth
+	}
+	if cfg.AlignTimeout <= 0 {
+		cfg.AlignTimeout = defaultAlignTimeout
+	}
+
+	return &Verifier{
+		cfg:        cfg,
+		bus:        eventBus,
+		source:     source,
+		buffers:    make(map[string]*buffer),
+		reports:    make(map[string]Report),
+		subscribed: make(map[string]bool),
+	}
+}
+
+// Topic is the bus and WebSocket topic a symbol's reports are published on.
+func Topic(symbol string) string {
+	return strings.ToLower(symbol) + TopicSuffix
+}
+
+func (v *Verifier) Topics() []string {
+	symbols := v.source.SubscribedSymbols()
+	topics := make([]string, 0, len(symbols))
+	for _, symbol := range symbols {
+		topics = append(topics, Topic(symbol))
+	}
+	return topics
+}
+
+func (v *Verifier) Start(ctx context.Context) {
+	for _, symbol := range v.source.SubscribedSymbols() {
+		v.AddSymbol(symbol)
+	}
+
+	ticker := time.NewTicker(v.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, symbol := range v.source.SubscribedSymbols() {
+				if ctx.Err() != nil {
+					return
+				}
+				v.Check(ctx, strings.ToUpper(symbol))
+			}
+		}
+	}
+}
+
+// AddSymbol buffers the deltas of a symbol while it is checked, including one
+// added to the subscriptions while running. Each symbol is subscribed to once.
+func (v *Verifier) AddSymbol(symbol string) {
+	symbol = strings.ToUpper(symbol)
+	v.mu.Lock()
+	if v.subscribed[symbol] {
+		v.mu.Unlock()
+		return
+	}
+	v.subscribed[symbol] = true
+	v.mu.Unlock()
+
+	v.bus.Subscribe(strings.ToLower(symbol)+"@depth", func(e bus.Event) {
+		ev, ok := e.Data.(binance.DepthUpdateEvent)
+		if !ok {
+			return
+		}
+		v.mu.Lock()
+		buf := v.buffers[symbol]
+		v.mu.Unlock()
+		if buf != nil {
+			buf.add(DeltaFromEvent(ev))
+		}
+	})
+}
+
+// Check compares one symbol's book with a fresh exchange snapshot, publishes
+// the report and, when more than MaxMismatches levels differ and Resync is on,
+// has the source rebuild the book.
+func (v *Verifier) Check(ctx context.Context, symbol string) Report {
+	report, err := v.compare(ctx, symbol)
+	if err != nil {
+		report = Report{Status: StatusSkipped, Reason: err.Error()}
+	}
+	report.Exchange = v.source.Name()
+	report.Symbol = symbol
+	report.CheckedAt = time.Now().UnixMilli()
+
+	if report.Status == StatusMismatch {
+		slog.Warn("Order book differs from exchange snapshot", "symbol", symbol,
+			"lastUpdateId", report.LastUpdateID, "levels", report.Levels, "mismatches", len(report.Mismatches))
+
+		if v.cfg.Resync && len(report.Mismatches) > v.cfg.MaxMismatches {
+			reason := fmt.Sprintf("verification failed: %d of %d levels differ", len(report.Mismatches), report.Levels)
+			report.Resynced = v.source.RequestResync(symbol, reason)
+		}
+	} else if report.Status == StatusSkipped {
+		slog.Debug("Order book verification skipped", "symbol", symbol, "reason", report.Reason)
+	}
+
+	v.mu.Lock()
+	v.reports[symbol] = report
+	v.mu.Unlock()
+
+	v.bus.Publish(VerifyReport, Topic(symbol), report)
+	return report
+}
+
+func (v *Verifier) compare(ctx context.Context, symbol string) (Report, error) {
+	if !v.source.IsSynchronized(symbol) {
+		return Report{}, errors.New("book not synchronized")
+	}
+
+	buf := &buffer{}
+	v.mu.Lock()
+	v.buffers[symbol] = buf
+	v.mu.Unlock()
+	defer func() {
+		v.mu.Lock()
+		delete(v.buffers, symbol)
+		v.mu.Unlock()
+	}()
+
+	// Every delta after the anchor is published once buffering is on, so the
+	// buffer is complete from anchor+1.
+	anchor := v.source.GetOrderBook(symbol)
+	if anchor == nil {
+		return Report{}, errors.New("no book")
+	}
+	oldest := int64(anchor.LastUpdateID) + 1
+
+	refBook, err := v.source.FetchSnapshot(ctx, symbol)
+	if err != nil {
+		return Report{}, fmt.Errorf("exchange snapshot: %w", err)
+	}
+	ref, err := ReferenceFromOrderBook(refBook)
+	if err != nil {
+		return Report{}, fmt.Errorf("exchange snapshot: %w", err)
+	}
+
+	hubBook := v.source.GetOrderBook(symbol)
+	if hubBook == nil {
+		return Report{}, errors.New("no book")
+	}
+	hubCopy := FromOrderBook
+	if limiter, ok := v.source.(exchange.DepthLimiter); ok && limiter.DepthLimit(symbol) > 0 {
+		hubCopy = ReferenceFromOrderBook
+	}
+	hub, err := hubCopy(hubBook)
+	if err != nil {
+		return Report{}, fmt.Errorf("hub book: %w", err)
+	}
+
+	deadline := time.Now().Add(v.cfg.AlignTimeout)
+	for {
+		err := Align(hub, ref, buf.snapshot(), oldest)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, ErrIncomplete) || time.Now().After(deadline) {
+			return Report{}, fmt.Errorf("hub at %d, exchange at %d: %w", hub.LastUpdateID, ref.LastUpdateID, err)
+		}
+		if err := retry.Sleep(ctx, alignPoll); err != nil {
+			return Report{}, err
+		}
+	}
+
+	return Diff(hub, ref, v.cfg.Depth), nil
+}
+
+// Report returns the latest report of a symbol.
+func (v *Verifier) Report(symbol string) (Report, bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	report, ok := v.reports[strings.ToUpper(symbol)]
+	return report, ok
+}
+
+// Reports returns the latest report of every checked symbol, by symbol.
+func (v *Verifier) Reports() []Report {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	reports := make([]Report, 0, len(v.reports))
+	for _, report := range v.reports {
+		reports = append(reports, report)
+	}
+	slices.SortFunc(reports, func(a, b Report) int { return strings.Compare(a.Symbol, b.Symbol) })
+	return reports
+}
+
+// Snapshot serves the latest report to WebSocket subscribers of <symbol>@verify,
+// or a pending one before the first check.
+func (v *Verifier) Snapshot(key string) (any, bool) {
+	if report, ok := v.Report(key); ok {
+		return report, true
+	}
+	if !slices.Contains(v.source.SubscribedSymbols(), strings.ToUpper(key)) {
+		return nil, false
+	}
+	return Report{Exchange: v.source.Name(), Symbol: strings.ToUpper(key), Status: StatusPending}, true
+}
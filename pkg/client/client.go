@@ -0,0 +1,613 @@
+// Package client is a Go client of the hub's WebSocket and gRPC APIs.
+//
+// A Client holds one WebSocket connection. It matches replies to requests by
+// ID. It also reconnects with backoff and resumes: every topic subscribed
+// through it is subscribed again on the new connection. Books opened with
+// Client.Book are kept from the subscribe snapshot and the depth updates that
+// follow. A gap in the updates, or a reconnect, makes the client fetch the
+// snapshot again.
+//
+// The protocol is described in api/asyncapi.yaml.
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ChethiyaNishanath/market-data-hub/internal/retry"
+	"github.com/ChethiyaNishanath/market-data-hub/pkg/orderbook"
+	"github.com/coder/websocket"
+)
+
+// Request methods of the WebSocket API.
+const (
+	MethodSubscribe         = "subscribe"
+	MethodUnsubscribe       = "unsubscribe"
+	MethodListSubscriptions = "list_subscriptions"
+	MethodListSymbols       = "list_symbols"
+	MethodPing              = "ping"
+	MethodServerInfo        = "server_info"
+	MethodAlert             = "alert"
+)
+
+const (
+	defaultRequestTimeout = 10 * time.Second
+	defaultMinBackoff     = 500 * time.Millisecond
+	defaultMaxBackoff     = 10 * time.Second
+
+	// readLimit bounds one message from the hub. Depth snapshots of deep books
+	// are large.
+	readLimit = 16 * 1024 * 1024
+
+	// checkInterval is how often books are checked for gaps.
+	checkInterval = 250 * time.Millisecond
+)
+
+var (
+	// ErrDisconnected is returned by requests made while the client is
+	// reconnecting, and by requests whose connection dropped before the reply.
+	ErrDisconnected = errors.New("client: not connected to the hub")
+	// ErrClosed is returned by requests made after Close.
+	ErrClosed = errors.New("client: closed")
+)
+
+// Options configure a Client. The handlers run on the client's read loop, one
+// at a time and in the order messages arrive; they must not block or make
+// requests of the client.
+type Options struct {
+	// Header is sent with every WebSocket handshake.
+	Header http.Header
+	// RequestTimeout bounds a request that has no earlier deadline. It
+	// defaults to 10s.
+	RequestTimeout time.Duration
+	// MinBackoff and MaxBackoff bound the delay between reconnects. They
+	// default to 500ms and 10s.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+
+	OnDepth           func(DepthUpdate)
+	OnReset           func(Reset)
+	OnTrade           func(Trade)
+	OnConnectionState func(ConnectionState)
+	// OnTopic receives the pushes of derived topics (cbbo, synthetic, stats,
+	// arb, verify) and fired alerts.
+	OnTopic func(topic string, data json.RawMessage)
+	// OnStatus reports the connection to the hub: connected, or the error
+	// that ended it.
+	OnStatus func(connected bool, err error)
+	// OnError reports failures there is no caller to return to, e.g. a topic
+	// that could not be subscribed again after a reconnect.
+	OnError func(err error)
+}
+
+// Client is a connection to the hub's WebSocket API. It is safe for concurrent
+// use.
+type Client struct {
+	url     string
+	opts    Options
+	backoff *retry.Backoff
+	nextID  atomic.Int64
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	mu       sync.Mutex
+	conn     *websocket.Conn
+	clientID string
+	pending  map[string]*call
+	topics   map[string]struct{}
+	books    map[string]*orderbook.Book
+}
+
+// call is a request waiting for its reply. Requests the client makes on its
+// own, to resume or resync, have no done channel.
+type call struct {
+	method string
+	topic  string
+	done   chan Message
+}
+
+// Dial connects to the hub's WebSocket endpoint, e.g. ws://localhost:8080/ws.
+// The client reconnects on its own until Close.
+func Dial(ctx context.Context, url string, opts Options) (*Client, error) {
+	if opts.RequestTimeout <= 0 {
+		opts.RequestTimeout = defaultRequestTimeout
+	}
+	if opts.MinBackoff <= 0 {
+		opts.MinBackoff = defaultMinBackoff
+	}
+	if opts.MaxBackoff < opts.MinBackoff {
+		opts.MaxBackoff = max(defaultMaxBackoff, opts.MinBackoff)
+	}
+
+	c := &Client{
+		url:     url,
+		opts:    opts,
+		backoff: retry.NewBackoff(opts.MinBackoff, opts.MaxBackoff),
+		done:    make(chan struct{}),
+		pending: make(map[string]*call),
+		topics:  make(map[string]struct{}),
+		books:   make(map[string]*orderbook.Book),
+	}
+
+	conn, err := c.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c.ctx, c.cancel = context.WithCancel(context.Background())
+	go c.run(conn)
+	go c.checkBooks()
+	return c, nil
+}
+
+// ClientID is the ID the hub gave the current connection.
+func (c *Client) ClientID() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.clientID
+}
+
+// Close disconnects and stops reconnecting. Books stop updating.
+func (c *Client) Close() error {
+	c.cancel()
+	<-c.done
+	return nil
+}
+
+// Subscribe subscribes to topic, e.g. "btcusdt@trade", and returns the data of
+// the reply: the current value of the topic, or null. The topic is subscribed
+// again after a reconnect.
+func (c *Client) Subscribe(ctx context.Context, topic string) (json.RawMessage, error) {
+	topic = strings.ToLower(topic)
+
+	c.mu.Lock()
+	c.topics[topic] = struct{}{}
+	c.mu.Unlock()
+
+	msg, err := c.request(ctx, MethodSubscribe, topicParams{Topic: topic}, topic)
+	if err != nil {
+		c.mu.Lock()
+		delete(c.topics, topic)
+		c.mu.Unlock()
+		return nil, err
+	}
+	return msg.Data, nil
+}
+
+// Unsubscribe stops the pushes of topic.
+func (c *Client) Unsubscribe(ctx context.Context, topic string) error {
+	topic = strings.ToLower(topic)
+
+	c.mu.Lock()
+	delete(c.topics, topic)
+	c.mu.Unlock()
+
+	_, err := c.request(ctx, MethodUnsubscribe, topicParams{Topic: topic}, topic)
+	return err
+}
+
+// ListSubscriptions returns the topics the hub has this client subscribed to.
+func (c *Client) ListSubscriptions(ctx context.Context) ([]string, error) {
+	var topics []string
+	return topics, c.call(ctx, MethodListSubscriptions, nil, &topics)
+}
+
+// ListSymbols returns the symbols of every exchange.
+func (c *Client) ListSymbols(ctx context.Context) ([]ExchangeSymbols, error) {
+	var symbols []ExchangeSymbols
+	return symbols, c.call(ctx, MethodListSymbols, nil, &symbols)
+}
+
+// ServerInfo returns the version, exchanges, topic events and limits of the hub.
+func (c *Client) ServerInfo(ctx context.Context) (ServerInfo, error) {
+	var info ServerInfo
+	return info, c.call(ctx, MethodServerInfo, nil, &info)
+}
+
+// Ping returns the server time.
+func (c *Client) Ping(ctx context.Context) (time.Time, error) {
+	var pong struct {
+		ServerTime int64 `json:"serverTime"`
+	}
+	if err := c.call(ctx, MethodPing, nil, &pong); err != nil {
+		return time.Time{}, err
+	}
+	return time.UnixMilli(pong.ServerTime), nil
+}
+
+// Do sends a request of any method, e.g. "alert", and returns the data of the
+// reply. A rejected request returns an *Error.
+func (c *Client) Do(ctx context.Context, method string, params any) (json.RawMessage, error) {
+	msg, err := c.request(ctx, method, params, "")
+	return msg.Data, err
+}
+
+// Book subscribes to the depth of symbol and returns its local book, kept with
+// opts. A book that falls out of sync is sent a new snapshot by subscribing
+// again, before opts.RequestSnapshot is called. Calling Book again for the
+// same symbol returns the same book and ignores opts.
+func (c *Client) Book(ctx context.Context, symbol string, opts orderbook.Options) (*orderbook.Book, error) {
+	symbol = strings.ToUpper(symbol)
+	depth := strings.ToLower(symbol) + "@depth"
+
+	requestSnapshot := opts.RequestSnapshot
+	opts.RequestSnapshot = func(reason string) {
+		go c.sendAsync(MethodSubscribe, depth)
+		if requestSnapshot != nil {
+			requestSnapshot(reason)
+		}
+	}
+
+	c.mu.Lock()
+	book, ok := c.books[symbol]
+	if !ok {
+		book = orderbook.New(symbol, opts)
+		c.books[symbol] = book
+	}
+	c.mu.Unlock()
+	if ok {
+		return book, nil
+	}
+
+	// Resets are subscribed first, so none is missed after the snapshot.
+	reset := strings.ToLower(symbol) + "@depth.reset"
+	_, err := c.Subscribe(ctx, reset)
+	if err == nil {
+		if _, err = c.Subscribe(ctx, depth); err != nil {
+			_ = c.Unsubscribe(ctx, reset)
+		}
+	}
+	if err != nil {
+		c.mu.Lock()
+		delete(c.books, symbol)
+		c.mu.Unlock()
+		return nil, err
+	}
+	return book, nil
+}
+
+type topicParams struct {
+	Topic string `json:"topic"`
+}
+
+type request struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Params any             `json:"params,omitempty"`
+}
+
+// call makes a request and decodes the data of its reply into out.
+func (c *Client) call(ctx context.Context, method string, params, out any) error {
+	msg, err := c.request(ctx, method, params, "")
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(msg.Data, out); err != nil {
+		return fmt.Errorf("client: decoding %s reply: %w", method, err)
+	}
+	return nil
+}
+
+// request sends a request and waits for its reply.
+func (c *Client) request(ctx context.Context, method string, params any, topic string) (Message, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.opts.RequestTimeout)
+	defer cancel()
+
+	pending := &call{method: method, topic: topic, done: make(chan Message, 1)}
+	id, err := c.send(ctx, pending, params)
+	if err != nil {
+		return Message{}, err
+	}
+
+	select {
+	case msg, ok := <-pending.done:
+		if !ok {
+			return Message{}, ErrDisconnected
+		}
+		if !msg.Success {
+			return msg, &Error{Method: method, Code: msg.Code, Message: msg.Error}
+		}
+		return msg, nil
+	case <-ctx.Done():
+		c.forget(id)
+		return Message{}, ctx.Err()
+	}
+}
+
+// send writes the request of pending and registers it for the reply.
+func (c *Client) send(ctx context.Context, pending *call, params any) (string, error) {
+	id := strconv.FormatInt(c.nextID.Add(1), 10)
+	data, err := json.Marshal(request{ID: json.RawMessage(id), Method: pending.method, Params: params})
+	if err != nil {
+		return "", err
+	}
+
+	c.mu.Lock()
+	conn := c.conn
+	if conn == nil {
+		c.mu.Unlock()
+		if c.ctx != nil && c.ctx.Err() != nil {
+			return "", ErrClosed
+		}
+		return "", ErrDisconnected
+	}
+	c.pending[id] = pending
+	c.mu.Unlock()
+
+	if err := conn.Write(ctx, websocket.MessageText, data); err != nil {
+		c.forget(id)
+		return "", err
+	}
+	return id, nil
+}
+
+// sendAsync sends a request the client makes on its own. Its reply is handled
+// by dispatch.
+func (c *Client) sendAsync(method, topic string) {
+	ctx, cancel := context.WithTimeout(c.ctx, c.opts.RequestTimeout)
+	defer cancel()
+
+	if _, err := c.send(ctx, &call{method: method, topic: topic}, topicParams{Topic: topic}); err != nil && c.ctx.Err() == nil {
+		c.report(fmt.Errorf("client: %s %s: %w", method, topic, err))
+	}
+}
+
+func (c *Client) forget(id string) {
+	c.mu.Lock()
+	delete(c.pending, id)
+	c.mu.Unlock()
+}
+
+// connect dials the hub and reads its hello.
+func (c *Client) connect(ctx context.Context) (*websocket.Conn, error) {
+	conn, _, err := websocket.Dial(ctx, c.url, &websocket.DialOptions{HTTPHeader: c.opts.Header})
+	if err != nil {
+		return nil, err
+	}
+	conn.SetReadLimit(readLimit)
+
+	var hello struct {
+		ClientID string `json:"client_id"`
+	}
+	_, data, err := conn.Read(ctx)
+	if err == nil {
+		err = json.Unmarshal(data, &hello)
+	}
+	if err != nil {
+		conn.CloseNow()
+		return nil, fmt.Errorf("client: reading hello: %w", err)
+	}
+
+	c.mu.Lock()
+	c.conn = conn
+	c.clientID = hello.ClientID
+	c.mu.Unlock()
+	return conn, nil
+}
+
+// run reads conn, and the connections that replace it, until Close.
+func (c *Client) run(conn *websocket.Conn) {
+	defer close(c.done)
+
+	c.status(true, nil)
+	for {
+		err := c.read(conn)
+		c.disconnected(conn)
+		if c.ctx.Err() != nil {
+			return
+		}
+		c.status(false, err)
+
+		for {
+			if retry.Sleep(c.ctx, c.backoff.Next()) != nil {
+				return
+			}
+			if conn, err = c.connect(c.ctx); err == nil {
+				break
+			}
+			c.status(false, err)
+		}
+		c.backoff.Reset()
+		c.status(true, nil)
+		c.resume()
+	}
+}
+
+func (c *Client) read(conn *websocket.Conn) error {
+	for {
+		_, data, err := conn.Read(c.ctx)
+		if err != nil {
+			return err
+		}
+		var msg Message
+		if err := json.Unmarshal(data, &msg); err != nil {
+			continue
+		}
+		c.dispatch(msg)
+	}
+}
+
+// disconnected fails the requests waiting on conn and marks the books stale
+// until their next snapshot.
+func (c *Client) disconnected(conn *websocket.Conn) {
+	conn.CloseNow()
+
+	c.mu.Lock()
+	c.conn = nil
+	for id, pending := range c.pending {
+		if pending.done != nil {
+			close(pending.done)
+		}
+		delete(c.pending, id)
+	}
+	books := make([]*orderbook.Book, 0, len(c.books))
+	for _, book := range c.books {
+		books = append(books, book)
+	}
+	c.mu.Unlock()
+
+	for _, book := range books {
+		book.Invalidate()
+	}
+}
+
+// resume subscribes every topic again. The depth replies reload the books.
+func (c *Client) resume() {
+	c.mu.Lock()
+	topics := make([]string, 0, len(c.topics))
+	for topic := range c.topics {
+		// Reset topics go first, so no reset is missed after a depth snapshot.
+		if strings.HasSuffix(topic, "@depth.reset") {
+			topics = append([]string{topic}, topics...)
+		} else {
+			topics = append(topics, topic)
+		}
+	}
+	c.mu.Unlock()
+
+	for _, topic := range topics {
+		c.sendAsync(MethodSubscribe, topic)
+	}
+}
+
+func (c *Client) dispatch(msg Message) {
+	if len(msg.ID) > 0 {
+		c.mu.Lock()
+		pending, ok := c.pending[string(msg.ID)]
+		delete(c.pending, string(msg.ID))
+		c.mu.Unlock()
+		if ok {
+			c.handleReply(pending, msg)
+		}
+		return
+	}
+
+	switch msg.Method {
+	case "":
+		if msg.Topic != "" {
+			c.topic(msg)
+			return
+		}
+		var update DepthUpdate
+		if err := json.Unmarshal(msg.Data, &update); err != nil || update.Symbol == "" {
+			return
+		}
+		if book := c.book(update.Symbol); book != nil {
+			book.Apply(update)
+		}
+		if c.opts.OnDepth != nil {
+			c.opts.OnDepth(update)
+		}
+
+	case "orderbook_reset":
+		var reset Reset
+		if err := json.Unmarshal(msg.Data, &reset); err != nil {
+			return
+		}
+		if book := c.book(reset.Symbol); book != nil {
+			book.Load(reset.Snapshot)
+		}
+		if c.opts.OnReset != nil {
+			c.opts.OnReset(reset)
+		}
+
+	case "connection_state":
+		var state ConnectionState
+		if err := json.Unmarshal(msg.Data, &state); err == nil && c.opts.OnConnectionState != nil {
+			c.opts.OnConnectionState(state)
+		}
+
+	case "trade":
+		var trade Trade
+		if err := json.Unmarshal(msg.Data, &trade); err == nil && c.opts.OnTrade != nil {
+			c.opts.OnTrade(trade)
+		}
+
+	default:
+		c.topic(msg)
+	}
+}
+
+func (c *Client) topic(msg Message) {
+	if c.opts.OnTopic != nil && msg.Topic != "" {
+		c.opts.OnTopic(msg.Topic, msg.Data)
+	}
+}
+
+// handleReply loads the snapshot of a depth subscribe into its book before the
+// caller, if any, sees the reply, so the book never misses the updates after it.
+func (c *Client) handleReply(pending *call, msg Message) {
+	if pending.method == MethodSubscribe {
+		symbol, event, _ := strings.Cut(pending.topic, "@")
+		switch {
+		case !msg.Success && pending.done == nil:
+			c.mu.Lock()
+			delete(c.topics, pending.topic)
+			c.mu.Unlock()
+			c.report(fmt.Errorf("client: subscribing %s again: %w", pending.topic,
+				&Error{Method: pending.method, Code: msg.Code, Message: msg.Error}))
+		case msg.Success && event == "depth":
+			var snapshot OrderBook
+			if book := c.book(symbol); book != nil && json.Unmarshal(msg.Data, &snapshot) == nil {
+				book.Load(snapshot)
+			}
+		}
+	}
+
+	if pending.done != nil {
+		pending.done <- msg
+	}
+}
+
+func (c *Client) book(symbol string) *orderbook.Book {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.books[strings.ToUpper(symbol)]
+}
+
+// checkBooks lets the books notice updates that have been missing for too
+// long.
+func (c *Client) checkBooks() {
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-ticker.C:
+			c.mu.Lock()
+			books := make([]*orderbook.Book, 0, len(c.books))
+			for _, book := range c.books {
+				books = append(books, book)
+			}
+			c.mu.Unlock()
+
+			for _, book := range books {
+				book.Check()
+			}
+		}
+	}
+}
+
+func (c *Client) status(connected bool, err error) {
+	if c.opts.OnStatus != nil {
+		c.opts.OnStatus(connected, err)
+	}
+}
+
+func (c *Client) report(err error) {
+	if c.opts.OnError != nil {
+		c.opts.OnError(err)
+	}
+}
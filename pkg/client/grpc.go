@@ -0,0 +1,123 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+
+	pb "github.com/ChethiyaNishanath/market-data-hub/api/orderbook"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/retry"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+// GRPC is a client of the hub's gRPC API. The generated client is embedded, so
+// every RPC is available next to the typed helpers.
+type GRPC struct {
+	pb.OrderBookClient
+	conn *grpc.ClientConn
+}
+
+// DialGRPC connects to the hub's gRPC endpoint, e.g. localhost:50051. Without
+// options the connection is not encrypted.
+func DialGRPC(addr string, opts ...grpc.DialOption) (*GRPC, error) {
+	if len(opts) == 0 {
+		opts = []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	}
+	conn, err := grpc.NewClient(addr, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &GRPC{OrderBookClient: pb.NewOrderBookClient(conn), conn: conn}, nil
+}
+
+// Close closes the connection.
+func (g *GRPC) Close() error {
+	return g.conn.Close()
+}
+
+// Snapshot returns up to depth levels a side of the book of symbol. A depth of
+// zero returns every level.
+func (g *GRPC) Snapshot(ctx context.Context, symbol string, depth int) (OrderBook, error) {
+	res, err := g.GetSnapshot(ctx, &pb.OrderBookSnapshotRequest{Symbol: strings.ToUpper(symbol), Depth: int32(depth)})
+	if err != nil {
+		return OrderBook{}, err
+	}
+
+	lastUpdateID, _ := strconv.ParseInt(res.GetLastUpdateId(), 10, 64)
+	return OrderBook{
+		LastUpdateID: lastUpdateID,
+		Bids:         orders(res.GetBids()),
+		Asks:         orders(res.GetAsks()),
+	}, nil
+}
+
+func orders(in []*pb.Order) [][]string {
+	out := make([][]string, len(in))
+	for i, o := range in {
+		out[i] = []string{o.GetPrice(), o.GetAmount()}
+	}
+	return out
+}
+
+// Symbols returns the symbols the hub keeps books for.
+func (g *GRPC) Symbols(ctx context.Context) ([]string, error) {
+	res, err := g.ListSymbols(ctx, &pb.ListSymbolsRequest{})
+	if err != nil {
+		return nil, err
+	}
+	return res.GetSymbols(), nil
+}
+
+// FollowConsolidated calls fn with every consolidated book of instrument
+// until ctx is done. A broken stream is opened again with backoff. The first
+// book of each stream is the current one. An unknown instrument, or another
+// error a retry cannot fix, ends it.
+func (g *GRPC) FollowConsolidated(ctx context.Context, instrument string, fn func(*pb.ConsolidatedSnapshotReply)) error {
+	backoff := retry.NewBackoff(defaultMinBackoff, defaultMaxBackoff)
+	req := &pb.ConsolidatedSnapshotRequest{Instrument: instrument}
+
+	for {
+		err := g.followOnce(ctx, req, fn, backoff)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if !retryable(err) {
+			return err
+		}
+		if retry.Sleep(ctx, backoff.Next()) != nil {
+			return ctx.Err()
+		}
+	}
+}
+
+func (g *GRPC) followOnce(ctx context.Context, req *pb.ConsolidatedSnapshotRequest, fn func(*pb.ConsolidatedSnapshotReply), backoff *retry.Backoff) error {
+	stream, err := g.StreamConsolidated(ctx, req)
+	if err != nil {
+		return err
+	}
+	for {
+		book, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+		backoff.Reset()
+		fn(book)
+	}
+}
+
+// retryable reports whether opening the stream again can help.
+func retryable(err error) bool {
+	if errors.Is(err, io.EOF) {
+		return true
+	}
+	switch status.Code(err) {
+	case codes.Unavailable, codes.Aborted, codes.Internal, codes.Unknown, codes.DeadlineExceeded, codes.ResourceExhausted:
+		return true
+	}
+	return false
+}
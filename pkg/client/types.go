@@ -0,0 +1,107 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ChethiyaNishanath/market-data-hub/pkg/orderbook"
+)
+
+// Error codes a failed reply can carry. api/asyncapi.yaml describes each.
+const (
+	CodeInvalidRequest   = "invalid_request"
+	CodeUnknownMethod    = "unknown_method"
+	CodeInvalidParams    = "invalid_params"
+	CodeInvalidTopic     = "invalid_topic"
+	CodeUnsupportedEvent = "unsupported_event"
+	CodeUnknownSymbol    = "unknown_symbol"
+	CodeNotFound         = "not_found"
+	CodeInternal         = "internal"
+)
+
+// Message is a reply or a push from the hub. Data is left undecoded.
+type Message struct {
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Success bool            `json:"success,omitempty"`
+	Code    string          `json:"code,omitempty"`
+	Error   string          `json:"error,omitempty"`
+	Topic   string          `json:"topic,omitempty"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+// Error is a request the hub rejected.
+type Error struct {
+	Method  string
+	Code    string
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s (%s)", e.Method, e.Message, e.Code)
+}
+
+// OrderBook is a book as the hub sends it, and DepthUpdate a delta of a
+// <symbol>@depth topic. They are the types the local books are fed with.
+type (
+	OrderBook   = orderbook.Snapshot
+	DepthUpdate = orderbook.Update
+)
+
+// Reset replaces the book of Symbol. It is pushed on <symbol>@depth.reset.
+type Reset struct {
+	Symbol    string    `json:"symbol"`
+	Snapshot  OrderBook `json:"snapshot"`
+	Reason    string    `json:"reason"`
+	Timestamp int64     `json:"timestamp"`
+}
+
+// ConnectionState is the hub's upstream connection state, pushed on
+// <symbol>@connection.
+type ConnectionState struct {
+	Exchange  string `json:"exchange"`
+	Symbol    string `json:"symbol"`
+	Leg       string `json:"leg,omitempty"`
+	State     string `json:"state"`
+	Reason    string `json:"reason,omitempty"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+// Trade is pushed on <symbol>@trade. Side is the taker's side.
+type Trade struct {
+	Symbol    string `json:"symbol"`
+	TradeID   int64  `json:"tradeId"`
+	Price     string `json:"price"`
+	Quantity  string `json:"quantity"`
+	Side      string `json:"side"`
+	TradeTime int64  `json:"tradeTime"`
+	EventTime int64  `json:"eventTime"`
+}
+
+// ExchangeSymbols is one entry of the list_symbols reply.
+type ExchangeSymbols struct {
+	Exchange string   `json:"exchange"`
+	Symbols  []string `json:"symbols"`
+}
+
+// ServerInfo is the server_info reply.
+type ServerInfo struct {
+	Version    string   `json:"version"`
+	Commit     string   `json:"commit"`
+	Exchanges  []string `json:"exchanges"`
+	Events     []string `json:"events"`
+	Limits     Limits   `json:"limits"`
+	ServerTime int64    `json:"serverTime"`
+}
+
+// Limits are the limits a client can run into. RateLimitRequests is 0 when
+// HTTP requests are not limited. SlowConsumer is what the hub does once
+// HighWaterMark pushes are queued for the client.
+type Limits struct {
+	MaxRequestBytes   int    `json:"maxRequestBytes"`
+	SendBuffer        int    `json:"sendBuffer"`
+	HighWaterMark     int    `json:"highWaterMark"`
+	SlowConsumer      string `json:"slowConsumer"`
+	RateLimitRequests int    `json:"rateLimitRequests"`
+	RateLimitWindow   string `json:"rateLimitWindow,omitempty"`
+}
@@ -0,0 +1,382 @@
+// Package orderbook keeps a local copy of an order book from the hub's depth
+// stream: the snapshot a subscribe to <symbol>@depth answers with, the
+// depthUpdate pushes that follow it and the orderbook_reset pushes that
+// replace it.
+//
+// Deltas are sequenced and applied by the same engine as the hub's own books,
+// so a local book at an update ID holds the levels the hub published at that
+// ID: the whole book, or the best depthLimit levels per side of a depth-limited
+// symbol, whose pushes carry the changes to those levels only.
+// Deltas that arrive early are held until the ones before them arrive. If an
+// update stays missing, the book asks for a new snapshot through
+// Options.RequestSnapshot.
+package orderbook
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ChethiyaNishanath/market-data-hub/internal/domain/orderbook"
+)
+
+const (
+	// DefaultGapTimeout is how long a missing update may stay missing. The hub
+	// can deliver updates slightly out of order.
+	DefaultGapTimeout = time.Second
+	// DefaultMaxPending bounds the updates held while waiting for a snapshot
+	// or a missing update.
+	DefaultMaxPending = 10_000
+)
+
+// Level is a price level.
+type Level struct {
+	Price    float64
+	Quantity float64
+}
+
+// Snapshot is a whole book as the hub sends it: [price, quantity] pairs of
+// decimal strings.
+type Snapshot struct {
+	LastUpdateID int64      `json:"lastUpdateId"`
+	Bids         [][]string `json:"bids"`
+	Asks         [][]string `json:"asks"`
+	Provisional  bool       `json:"provisional,omitempty"`
+}
+
+// Update is a depthUpdate push. Levels with quantity "0" are removed.
+type Update struct {
+	EventType     string     `json:"e"`
+	EventTime     int64      `json:"E"`
+	Symbol        string     `json:"s"`
+	FirstUpdateID int64      `json:"U"`
+	FinalUpdateID int64      `json:"u"`
+	Bids          [][]string `json:"b"`
+	Asks          [][]string `json:"a"`
+}
+
+// Change is one change of a book. Bids and Asks hold the levels that changed,
+// with a zero Quantity for a removed level. A Reset replaced the whole book
+// and lists no levels.
+type Change struct {
+	Symbol       string
+	LastUpdateID int64
+	Reset        bool
+	Bids         []Level
+	Asks         []Level
+}
+
+// Options configure a Book. The callbacks run on the goroutine that feeds the
+// book, without the book's lock held, so they may read the book.
+type Options struct {
+	// GapTimeout and MaxPending default to DefaultGapTimeout and
+	// DefaultMaxPending.
+	GapTimeout time.Duration
+	MaxPending int
+
+	// OnChange is called after each snapshot and each applied update.
+	OnChange func(Change)
+	// RequestSnapshot is called when the book has fallen out of sync. The
+	// caller should get a new snapshot, e.g. by subscribing to the depth topic
+	// again, and Load it. It is not called again until then.
+	RequestSnapshot func(reason string)
+}
+
+// Book is a local order book. Its accessors are safe for concurrent use. Feed
+// it from one goroutine, so changes are reported in order.
+type Book struct {
+	symbol string
+	opts   Options
+
+	mu          sync.RWMutex
+	seq         *orderbook.Sequencer
+	bids, asks  side
+	provisional bool
+	requested   bool
+}
+
+// New returns an empty book of symbol.
+func New(symbol string, opts Options) *Book {
+	if opts.GapTimeout <= 0 {
+		opts.GapTimeout = DefaultGapTimeout
+	}
+	if opts.MaxPending <= 0 {
+		opts.MaxPending = DefaultMaxPending
+	}
+	return &Book{
+		symbol: strings.ToUpper(symbol),
+		opts:   opts,
+		seq:    orderbook.NewSequencer(opts.GapTimeout, opts.MaxPending),
+		bids:   make(side),
+		asks:   make(side),
+	}
+}
+
+// Symbol is the upper-cased symbol of the book.
+func (b *Book) Symbol() string {
+	return b.symbol
+}
+
+// Load replaces the book with a snapshot and applies the held updates that
+// follow it.
+func (b *Book) Load(snapshot Snapshot) {
+	now := time.Now()
+
+	b.mu.Lock()
+	b.bids, b.asks = make(side), make(side)
+	_ = orderbook.ApplyLevels(sides{b}, snapshot.Bids, snapshot.Asks)
+	b.provisional = snapshot.Provisional
+	b.requested = false
+	changes := []Change{{Symbol: b.symbol, LastUpdateID: snapshot.LastUpdateID, Reset: true}}
+	changes = append(changes, b.apply(b.seq.Load(snapshot.LastUpdateID, now))...)
+	b.mu.Unlock()
+
+	b.notify(changes, "")
+}
+
+// Apply adds an update. Stale updates are dropped, and early ones are held
+// until the snapshot or the updates before them arrive.
+func (b *Book) Apply(update Update) {
+	b.mu.Lock()
+	changes := b.apply(b.seq.Push(orderbook.Delta{
+		First: update.FirstUpdateID,
+		Final: update.FinalUpdateID,
+		Bids:  update.Bids,
+		Asks:  update.Asks,
+	}, time.Now()))
+	reason := b.resync("too many updates held")
+	b.mu.Unlock()
+
+	b.notify(changes, reason)
+}
+
+// Check requests a new snapshot once an update has been missing for longer
+// than the gap timeout. Call it periodically, e.g. every 250ms.
+func (b *Book) Check() {
+	b.mu.Lock()
+	b.seq.Check(time.Now())
+	reason := b.resync("update missing for " + b.opts.GapTimeout.String())
+	b.mu.Unlock()
+
+	b.notify(nil, reason)
+}
+
+// Invalidate marks the book out of sync until the next Load, e.g. after the
+// connection to the hub dropped. Updates are held meanwhile.
+func (b *Book) Invalidate() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.seq.Clear()
+	b.requested = false
+}
+
+// Handle feeds the book a raw message from the hub: the reply to a subscribe
+// to its depth, a depthUpdate push or an orderbook_reset push. Messages of
+// other symbols and topics are ignored.
+func (b *Book) Handle(data []byte) error {
+	var msg struct {
+		Method  string          `json:"method"`
+		Success bool            `json:"success"`
+		Topic   string          `json:"topic"`
+		Data    json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return err
+	}
+
+	switch msg.Method {
+	case "subscribe":
+		if !msg.Success || !strings.EqualFold(msg.Topic, b.symbol+"@depth") {
+			return nil
+		}
+		var snapshot Snapshot
+		if err := json.Unmarshal(msg.Data, &snapshot); err != nil {
+			return err
+		}
+		b.Load(snapshot)
+
+	case "orderbook_reset":
+		var reset struct {
+			Symbol   string   `json:"symbol"`
+			Snapshot Snapshot `json:"snapshot"`
+		}
+		if err := json.Unmarshal(msg.Data, &reset); err != nil {
+			return err
+		}
+		if strings.EqualFold(reset.Symbol, b.symbol) {
+			b.Load(reset.Snapshot)
+		}
+
+	case "":
+		if msg.Topic != "" || len(msg.Data) == 0 {
+			return nil
+		}
+		var update Update
+		if err := json.Unmarshal(msg.Data, &update); err != nil {
+			return err
+		}
+		if strings.EqualFold(update.Symbol, b.symbol) {
+			b.Apply(update)
+		}
+	}
+	return nil
+}
+
+// Synced reports whether the book follows the stream: a snapshot is loaded and
+// no update has gone missing since.
+func (b *Book) Synced() bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.seq.Loaded() && !b.seq.OutOfSync()
+}
+
+// Provisional reports whether the hub's book was restored from a checkpoint
+// and has not been confirmed by a live update yet.
+func (b *Book) Provisional() bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.provisional
+}
+
+// LastUpdateID is the update ID the book is at.
+func (b *Book) LastUpdateID() int64 {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.seq.Last()
+}
+
+// BestBid returns the highest bid, false if there is none.
+func (b *Book) BestBid() (Level, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.bids.best(func(a, c float64) bool { return a > c })
+}
+
+// BestAsk returns the lowest ask, false if there is none.
+func (b *Book) BestAsk() (Level, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.asks.best(func(a, c float64) bool { return a < c })
+}
+
+// Bids returns up to n bids, best first. A non-positive n returns them all.
+func (b *Book) Bids(n int) []Level {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.bids.top(n, func(a, c float64) bool { return a > c })
+}
+
+// Asks returns up to n asks, best first. A non-positive n returns them all.
+func (b *Book) Asks(n int) []Level {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.asks.top(n, func(a, c float64) bool { return a < c })
+}
+
+// apply writes deltas to the book. The caller holds b.mu.
+func (b *Book) apply(deltas []orderbook.Delta) []Change {
+	changes := make([]Change, 0, len(deltas))
+	for _, d := range deltas {
+		_ = orderbook.ApplyLevels(sides{b}, d.Bids, d.Asks)
+		b.provisional = false
+		changes = append(changes, Change{
+			Symbol:       b.symbol,
+			LastUpdateID: d.Final,
+			Bids:         parse(d.Bids),
+			Asks:         parse(d.Asks),
+		})
+	}
+	return changes
+}
+
+// resync returns reason the first time the book is found out of sync after a
+// Load, and "" otherwise. The caller holds b.mu.
+func (b *Book) resync(reason string) string {
+	if !b.seq.Loaded() || !b.seq.OutOfSync() || b.requested {
+		return ""
+	}
+	b.requested = true
+	return reason
+}
+
+func (b *Book) notify(changes []Change, resync string) {
+	if b.opts.OnChange != nil {
+		for _, c := range changes {
+			b.opts.OnChange(c)
+		}
+	}
+	if resync != "" && b.opts.RequestSnapshot != nil {
+		b.opts.RequestSnapshot(resync)
+	}
+}
+
+// side holds the levels of one side keyed by their price text, as the hub does.
+type side map[string]Level
+
+func (s side) best(better func(a, c float64) bool) (Level, bool) {
+	var (
+		best  Level
+		found bool
+	)
+	for _, lvl := range s {
+		if !found || better(lvl.Price, best.Price) {
+			best, found = lvl, true
+		}
+	}
+	return best, found
+}
+
+func (s side) top(n int, better func(a, c float64) bool) []Level {
+	levels := make([]Level, 0, len(s))
+	for _, lvl := range s {
+		levels = append(levels, lvl)
+	}
+	sort.Slice(levels, func(i, j int) bool { return better(levels[i].Price, levels[j].Price) })
+	if n > 0 && len(levels) > n {
+		levels = levels[:n]
+	}
+	return levels
+}
+
+// sides lets orderbook.ApplyLevels write to a Book.
+type sides struct {
+	b *Book
+}
+
+func (s sides) UpdateBid(price, qty string) { s.b.bids.set(price, qty) }
+func (s sides) RemoveBid(price string)      { delete(s.b.bids, price) }
+func (s sides) UpdateAsk(price, qty string) { s.b.asks.set(price, qty) }
+func (s sides) RemoveAsk(price string)      { delete(s.b.asks, price) }
+
+func (s side) set(price, qty string) {
+	p, err := strconv.ParseFloat(price, 64)
+	if err != nil {
+		return
+	}
+	q, _ := strconv.ParseFloat(qty, 64) // ApplyLevels has parsed it
+	s[price] = Level{Price: p, Quantity: q}
+}
+
+// parse converts the levels of a delta, keeping removed ones with a zero
+// quantity.
+func parse(levels [][]string) []Level {
+	out := make([]Level, 0, len(levels))
+	for _, lvl := range levels {
+		if len(lvl) < 2 {
+			continue
+		}
+		price, err := strconv.ParseFloat(lvl[0], 64)
+		if err != nil {
+			continue
+		}
+		qty, err := strconv.ParseFloat(lvl[1], 64)
+		if err != nil {
+			continue
+		}
+		out = append(out, Level{Price: price, Quantity: qty})
+	}
+	return out
+}
@@ -1,6 +1,7 @@
 package events_test
 
 import (
+	"context"
 	"sync"
 	"testing"
 	"time"
@@ -140,3 +141,30 @@ func TestPublishThreadSafe(t *testing.T) {
 		t.Errorf("expected 50 bus received, got %d", received)
 	}
 }
+
+func TestPublishContextPropagatesContext(t *testing.T) {
+	bus := events.New()
+
+	type ctxKey struct{}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "trace-123")
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	var received events.Event
+
+	bus.Subscribe("btcusd@depth", func(e events.Event) {
+		received = e
+		wg.Done()
+	})
+
+	bus.PublishContext(ctx, "depthUpdate", "btcusd@depth", "ABC123")
+
+	if !wait(&wg) {
+		t.Fatalf("subscriber did not receive event")
+	}
+
+	if received.Context == nil || received.Context.Value(ctxKey{}) != "trace-123" {
+		t.Errorf("expected publisher context to reach subscriber")
+	}
+}
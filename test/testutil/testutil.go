@@ -0,0 +1,116 @@
+// Package testutil holds the fakes and helpers the unit tests share.
+package testutil
+
+import (
+	"maps"
+	"math"
+	"slices"
+	"sync"
+
+	"github.com/ChethiyaNishanath/market-data-hub/internal/domain/orderbook"
+)
+
+// Approx reports whether a and b are equal up to float rounding.
+func Approx(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+// Resync is a resync a BookSource was asked for.
+type Resync struct {
+	Symbol string
+	Reason string
+}
+
+// BookSource is an in-memory venue serving fixed order books. A symbol is
+// synchronized while it has a book, unless SetSynced says otherwise, and
+// subscribed when it has a book, unless Subscribe lists the symbols. It is
+// safe for concurrent use.
+type BookSource struct {
+	name string
+
+	mu      sync.Mutex
+	books   map[string]*orderbook.OrderBook
+	synced  map[string]bool
+	symbols []string
+	resyncs []Resync
+}
+
+// NewBookSource returns a source named name, "binance" when empty, serving
+// books by symbol.
+func NewBookSource(name string, books map[string]*orderbook.OrderBook) *BookSource {
+	if name == "" {
+		name = "binance"
+	}
+	if books == nil {
+		books = make(map[string]*orderbook.OrderBook)
+	}
+	return &BookSource{name: name, books: books, synced: make(map[string]bool)}
+}
+
+func (s *BookSource) Name() string { return s.name }
+
+func (s *BookSource) GetOrderBook(symbol string) *orderbook.OrderBook {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.books[symbol]
+}
+
+// FullOrderBook returns the book of symbol, which a BookSource never cuts.
+func (s *BookSource) FullOrderBook(symbol string) *orderbook.OrderBook {
+	return s.GetOrderBook(symbol)
+}
+
+func (s *BookSource) IsSynchronized(symbol string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if synced, ok := s.synced[symbol]; ok {
+		return synced
+	}
+	return s.books[symbol] != nil
+}
+
+func (s *BookSource) SubscribedSymbols() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.symbols != nil {
+		return slices.Clone(s.symbols)
+	}
+	return slices.Sorted(maps.Keys(s.books))
+}
+
+// RequestResync records the request and accepts it.
+func (s *BookSource) RequestResync(symbol, reason string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.resyncs = append(s.resyncs, Resync{Symbol: symbol, Reason: reason})
+	return true
+}
+
+// SetBook replaces the book of symbol.
+func (s *BookSource) SetBook(symbol string, book *orderbook.OrderBook) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.books[symbol] = book
+}
+
+// SetSynced overrides whether symbol is synchronized.
+func (s *BookSource) SetSynced(symbol string, synced bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.synced[symbol] = synced
+}
+
+// Subscribe sets the symbols SubscribedSymbols lists and returns s.
+func (s *BookSource) Subscribe(symbols ...string) *BookSource {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.symbols = symbols
+	return s
+}
+
+// Resyncs lists the resyncs requested so far.
+func (s *BookSource) Resyncs() []Resync {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.resyncs)
+}
@@ -0,0 +1,382 @@
+package alerting_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ChethiyaNishanath/market-data-hub/internal/alerting"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/config"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/domain/orderbook"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/domain/subscription"
+	"github.com/ChethiyaNishanath/market-data-hub/test/testutil"
+	"github.com/coder/websocket"
+)
+
+type fakeClient struct {
+	id   string
+	mu   sync.Mutex
+	sent [][]byte
+}
+
+func (c *fakeClient) ID() string                                                     { return c.id }
+func (c *fakeClient) Conn() *websocket.Conn                                          { return nil }
+func (c *fakeClient) AddTopic(string)                                                {}
+func (c *fakeClient) RemoveTopic(string)                                             {}
+func (c *fakeClient) Topics() []string                                               { return nil }
+func (c *fakeClient) Send(data []byte)                                               { c.SendContext(context.Background(), data) }
+func (c *fakeClient) SendTopic(ctx context.Context, _ string, data []byte)           { c.SendContext(ctx, data) }
+func (c *fakeClient) Close(string) error                                             { return nil }
+func (c *fakeClient) Stats() subscription.ClientStats                                { return subscription.ClientStats{ID: c.id} }
+func (c *fakeClient) ReadPump(context.Context, subscription.ClientConnectionManager) {}
+func (c *fakeClient) WritePump(context.Context)                                      {}
+
+func (c *fakeClient) SendContext(_ context.Context, data []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sent = append(c.sent, data)
+}
+
+func (c *fakeClient) count() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.sent)
+}
+
+type fakeConnManager struct {
+	subscription.ClientConnectionManager
+	client *fakeClient
+}
+
+func (m *fakeConnManager) GetClientByID(id string) subscription.Client {
+	if m.client != nil && m.client.id == id {
+		return m.client
+	}
+	return nil
+}
+
+func newSource() *testutil.BookSource {
+	return testutil.NewBookSource("", map[string]*orderbook.OrderBook{
+		"BTCUSDT": {
+			Bids: [][]string{{"100", "1"}, {"99.9", "2"}},
+			Asks: [][]string{{"100.2", "1"}, {"100.3", "2"}},
+		},
+	})
+}
+
+func TestRuleValidation(t *testing.T) {
+	engine := alerting.NewEngine(config.AlertsConfig{}, newSource(), &fakeConnManager{}, nil)
+
+	cases := []alerting.Alert{
+		{Symbol: "BTCUSDT", ClientID: "c", Deliver: alerting.Target{WebSocket: true}, Rule: alerting.Rule{Type: "price_cross", Side: "bid", Price: 1}},
+		{Symbol: "BTCUSDT", ClientID: "c", Deliver: alerting.Target{WebSocket: true}, Rule: alerting.Rule{Type: "unknown"}},
+		{Symbol: "BTCUSDT", Deliver: alerting.Target{WebSocket: true}, Rule: alerting.Rule{Type: "spread", SpreadBps: 5}},
+		{Symbol: "BTCUSDT", Deliver: alerting.Target{WebhookURL: "http://example"}, Rule: alerting.Rule{Type: "spread", SpreadBps: 5}},
+	}
+
+	for i, alert := range cases {
+		if _, err := engine.Create(alert); !errors.Is(err, alerting.ErrInvalidAlert) {
+			t.Errorf("case %d: expected invalid alert, got %v", i, err)
+		}
+	}
+}
+
+func TestWebhookURLValidation(t *testing.T) {
+	outbox, err := alerting.OpenOutbox(filepath.Join(t.TempDir(), "outbox.db"))
+	if err != nil {
+		t.Fatalf("open outbox: %v", err)
+	}
+	defer outbox.Close()
+	dispatcher := alerting.NewDispatcher(outbox, config.WebhookConfig{})
+	engine := alerting.NewEngine(config.AlertsConfig{}, newSource(), &fakeConnManager{}, dispatcher)
+
+	alert := func(url string) alerting.Alert {
+		return alerting.Alert{Symbol: "BTCUSDT", Deliver: alerting.Target{WebhookURL: url}, Rule: alerting.Rule{Type: "spread", SpreadBps: 5}}
+	}
+	for _, url := range []string{"file:///etc/passwd", "gopher://example.com", "http://", "https:///hook", "example.com/hook", "://bad"} {
+		if _, err := engine.Create(alert(url)); !errors.Is(err, alerting.ErrInvalidAlert) {
+			t.Errorf("%q: expected invalid alert, got %v", url, err)
+		}
+	}
+	for _, url := range []string{"http://example.com/hook", "https://example.com:8443/hook"} {
+		if _, err := engine.Create(alert(url)); err != nil {
+			t.Errorf("%q: %v", url, err)
+		}
+	}
+}
+
+func TestAlertFiresOnceUnlessRepeating(t *testing.T) {
+	client := &fakeClient{id: "client-1"}
+	engine := alerting.NewEngine(config.AlertsConfig{}, newSource(), &fakeConnManager{client: client}, nil)
+
+	once, err := engine.Create(alerting.Alert{
+		Symbol:   "btcusdt",
+		ClientID: client.id,
+		Deliver:  alerting.Target{WebSocket: true},
+		Rule:     alerting.Rule{Type: "price_cross", Side: "bid", Direction: "above", Price: 99.5},
+	})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if _, err := engine.Create(alerting.Alert{
+		Symbol:   "BTCUSDT",
+		ClientID: client.id,
+		Repeat:   true,
+		Deliver:  alerting.Target{WebSocket: true},
+		Rule:     alerting.Rule{Type: "spread", SpreadBps: 10},
+	}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	engine.Evaluate()
+	engine.Evaluate()
+
+	if got := client.count(); got != 3 {
+		t.Fatalf("expected 3 notifications (1 once + 2 repeating), got %d", got)
+	}
+
+	stored, _ := engine.Get(once.ID)
+	if stored.Active || stored.FireCount != 1 {
+		t.Errorf("expected one-shot alert disarmed after one fire, got %+v", stored)
+	}
+
+	var msg struct {
+		Method string                `json:"method"`
+		Topic  string                `json:"topic"`
+		Data   alerting.Notification `json:"data"`
+	}
+	if err := json.Unmarshal(client.sent[0], &msg); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if msg.Method != "alert" || msg.Topic != "btcusdt@alert" {
+		t.Errorf("unexpected message %+v", msg)
+	}
+}
+
+func TestRepeatingAlertHonoursCooldown(t *testing.T) {
+	client := &fakeClient{id: "client-1"}
+	engine := alerting.NewEngine(config.AlertsConfig{}, newSource(), &fakeConnManager{client: client}, nil)
+
+	if _, err := engine.Create(alerting.Alert{
+		Symbol:          "BTCUSDT",
+		ClientID:        client.id,
+		Repeat:          true,
+		CooldownSeconds: 60,
+		Deliver:         alerting.Target{WebSocket: true},
+		Rule:            alerting.Rule{Type: "depth", BandBps: 5, MinQuantity: 10},
+	}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	engine.Evaluate()
+	engine.Evaluate()
+
+	if got := client.count(); got != 1 {
+		t.Errorf("expected cooldown to suppress the second fire, got %d notifications", got)
+	}
+}
+
+func TestDesyncRule(t *testing.T) {
+	rule := alerting.Rule{Type: "desync", DesyncSeconds: 5}
+	now := time.Now()
+
+	if hit, _, _ := rule.Evaluate(alerting.MarketState{UnsyncedSince: now.Add(-2 * time.Second)}, now); hit {
+		t.Error("did not expect a short desync to fire")
+	}
+	if hit, _, _ := rule.Evaluate(alerting.MarketState{UnsyncedSince: now.Add(-6 * time.Second)}, now); !hit {
+		t.Error("expected a long desync to fire")
+	}
+	if hit, _, _ := rule.Evaluate(alerting.MarketState{Synchronized: true}, now); hit {
+		t.Error("did not expect a synchronized book to fire")
+	}
+}
+
+func TestWebhookRetriesAndSigns(t *testing.T) {
+	var calls atomic.Int32
+	var signatureOK atomic.Bool
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		ts, _ := strconv.ParseInt(r.Header.Get(alerting.TimestampHeader), 10, 64)
+		signatureOK.Store(r.Header.Get(alerting.SignatureHeader) == alerting.Sign("s3cret", ts, body))
+
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	outbox, err := alerting.OpenOutbox(filepath.Join(t.TempDir(), "outbox.db"))
+	if err != nil {
+		t.Fatalf("open outbox: %v", err)
+	}
+	defer outbox.Close()
+
+	dispatcher := alerting.NewDispatcher(outbox, config.WebhookConfig{
+		Secret:         "s3cret",
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     2 * time.Millisecond,
+	})
+	if err := dispatcher.Enqueue(server.URL, []byte(`{"alertId":"a"}`)); err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+
+	ctx := context.Background()
+	dispatcher.Flush(ctx)
+
+	pending, _ := outbox.Pending()
+	if len(pending) != 1 || pending[0].Attempts != 1 {
+		t.Fatalf("expected the failed delivery to be rescheduled, got %+v", pending)
+	}
+
+	time.Sleep(5 * time.Millisecond)
+	dispatcher.Flush(ctx)
+
+	pending, _ = outbox.Pending()
+	if len(pending) != 0 {
+		t.Errorf("expected the outbox to be drained, got %+v", pending)
+	}
+	if calls.Load() != 2 || !signatureOK.Load() {
+		t.Errorf("expected 2 signed calls, got %d (signature ok: %v)", calls.Load(), signatureOK.Load())
+	}
+}
+
+func TestWebhookSecretChangesLive(t *testing.T) {
+	var signature atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		ts, _ := strconv.ParseInt(r.Header.Get(alerting.TimestampHeader), 10, 64)
+		signature.Store(r.Header.Get(alerting.SignatureHeader) == alerting.Sign("rotated", ts, body))
+	}))
+	defer server.Close()
+
+	outbox, err := alerting.OpenOutbox(filepath.Join(t.TempDir(), "outbox.db"))
+	if err != nil {
+		t.Fatalf("open outbox: %v", err)
+	}
+	defer outbox.Close()
+
+	dispatcher := alerting.NewDispatcher(outbox, config.WebhookConfig{Secret: "s3cret"})
+	dispatcher.SetSecret("rotated")
+	if err := dispatcher.Enqueue(server.URL, []byte(`{"alertId":"a"}`)); err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+	dispatcher.Flush(context.Background())
+
+	if ok, _ := signature.Load().(bool); !ok {
+		t.Error("expected the delivery to be signed with the new secret")
+	}
+}
+
+func TestEvaluationIntervalChangesLive(t *testing.T) {
+	client := &fakeClient{id: "client-1"}
+	engine := alerting.NewEngine(config.AlertsConfig{EvaluationInterval: time.Hour}, newSource(), &fakeConnManager{client: client}, nil)
+	if _, err := engine.Create(alerting.Alert{
+		Symbol:   "BTCUSDT",
+		ClientID: client.id,
+		Repeat:   true,
+		Deliver:  alerting.Target{WebSocket: true},
+		Rule:     alerting.Rule{Type: "spread", SpreadBps: 10},
+	}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go engine.Start(ctx)
+	engine.SetEvaluationInterval(10 * time.Millisecond)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for client.count() < 2 {
+		if time.Now().After(deadline) {
+			t.Fatal("alerts not evaluated on the new interval")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestOutboxSurvivesReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "outbox.db")
+
+	outbox, err := alerting.OpenOutbox(path)
+	if err != nil {
+		t.Fatalf("open outbox: %v", err)
+	}
+	if _, err := outbox.Enqueue("http://example.invalid", []byte(`{}`), time.Now()); err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+	_ = outbox.Close()
+
+	reopened, err := alerting.OpenOutbox(path)
+	if err != nil {
+		t.Fatalf("reopen outbox: %v", err)
+	}
+	defer reopened.Close()
+
+	pending, err := reopened.Pending()
+	if err != nil || len(pending) != 1 {
+		t.Errorf("expected 1 pending delivery after reopen, got %d (%v)", len(pending), err)
+	}
+}
+
+func TestConfiguredRulesReplaceKeepingFireState(t *testing.T) {
+	outbox, err := alerting.OpenOutbox(filepath.Join(t.TempDir(), "outbox.db"))
+	if err != nil {
+		t.Fatalf("open outbox: %v", err)
+	}
+	defer outbox.Close()
+	dispatcher := alerting.NewDispatcher(outbox, config.WebhookConfig{})
+	engine := alerting.NewEngine(config.AlertsConfig{}, newSource(), &fakeConnManager{}, dispatcher)
+
+	wide := config.AlertRuleConfig{ID: "wide", Symbol: "btcusdt", Type: "spread", SpreadBps: 10, WebhookURL: "http://example.com/hook"}
+	deep := config.AlertRuleConfig{ID: "deep", Symbol: "BTCUSDT", Type: "depth", BandBps: 5, MinQuantity: 10, Repeat: true, Cooldown: time.Minute, WebhookURL: "http://example.com/hook"}
+	if err := engine.SetRules([]config.AlertRuleConfig{wide, deep}); err != nil {
+		t.Fatalf("set rules: %v", err)
+	}
+	engine.Evaluate()
+
+	fired, ok := engine.Get("config:wide")
+	if !ok || fired.Active || fired.FireCount != 1 {
+		t.Fatalf("expected the one-shot configured alert to fire once, got %+v", fired)
+	}
+	if err := engine.Delete("config:wide", ""); !errors.Is(err, alerting.ErrInvalidAlert) {
+		t.Errorf("expected configured alerts to be undeletable over the API, got %v", err)
+	}
+
+	bad := config.AlertRuleConfig{ID: "bad", Symbol: "BTCUSDT", Type: "unknown", WebhookURL: "http://example.com/hook"}
+	if err := engine.SetRules([]config.AlertRuleConfig{wide, bad}); !errors.Is(err, alerting.ErrInvalidAlert) {
+		t.Fatalf("expected an invalid rule to be rejected, got %v", err)
+	}
+	if _, ok := engine.Get("config:deep"); !ok {
+		t.Fatal("expected a rejected update to leave the rules unchanged")
+	}
+
+	deep.MinQuantity = 20
+	if err := engine.SetRules([]config.AlertRuleConfig{wide, deep}); err != nil {
+		t.Fatalf("set rules: %v", err)
+	}
+	if kept, _ := engine.Get("config:wide"); kept.Active || kept.FireCount != 1 {
+		t.Errorf("expected an unchanged rule to keep its fire state, got %+v", kept)
+	}
+	if changed, _ := engine.Get("config:deep"); !changed.Active || changed.FireCount != 0 || changed.Rule.MinQuantity != 20 {
+		t.Errorf("expected a changed rule to be re-armed, got %+v", changed)
+	}
+
+	if err := engine.SetRules(nil); err != nil {
+		t.Fatalf("set rules: %v", err)
+	}
+	if list := engine.List(""); len(list) != 0 {
+		t.Errorf("expected removed rules to be dropped, got %+v", list)
+	}
+}
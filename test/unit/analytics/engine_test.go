@@ -0,0 +1,100 @@
+package analytics_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ChethiyaNishanath/market-data-hub/internal/analytics"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/bus"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/config"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/domain/orderbook"
+	"github.com/ChethiyaNishanath/market-data-hub/test/testutil"
+)
+
+// syncBus delivers every event before Publish returns.
+type syncBus struct {
+	mu          sync.Mutex
+	subscribers map[string][]bus.Subscriber
+}
+
+func (b *syncBus) Publish(action, topic string, data any) {
+	b.PublishContext(context.Background(), action, topic, data)
+}
+
+func (b *syncBus) PublishContext(ctx context.Context, action, topic string, data any) {
+	b.mu.Lock()
+	subs := b.subscribers[topic]
+	b.mu.Unlock()
+	for _, sub := range subs {
+		sub(bus.Event{Action: action, Topic: topic, Data: data, Context: ctx})
+	}
+}
+
+func (b *syncBus) Subscribe(topic string, fn bus.Subscriber) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.subscribers == nil {
+		b.subscribers = make(map[string][]bus.Subscriber)
+	}
+	b.subscribers[topic] = append(b.subscribers[topic], fn)
+}
+
+// countingSource counts the books read from it.
+type countingSource struct {
+	*testutil.BookSource
+	reads atomic.Int32
+}
+
+func (s *countingSource) GetOrderBook(symbol string) *orderbook.OrderBook {
+	s.reads.Add(1)
+	return s.BookSource.GetOrderBook(symbol)
+}
+
+func TestEngineComputesOncePerPublishNotPerDelta(t *testing.T) {
+	source := &countingSource{BookSource: testutil.NewBookSource("", map[string]*orderbook.OrderBook{
+		"BTCUSDT": {LastUpdateID: 7, Bids: [][]string{{"100", "1"}}, Asks: [][]string{{"101", "1"}}},
+	})}
+	eventBus := &syncBus{}
+	published := make(chan analytics.Stats, 10)
+	eventBus.Subscribe(analytics.Topic("BTCUSDT"), func(ev bus.Event) { published <- ev.Data.(analytics.Stats) })
+
+	engine := analytics.NewEngine(config.AnalyticsConfig{PublishInterval: 10 * time.Millisecond}, eventBus, source, nil)
+	engine.AddSymbol("BTCUSDT")
+
+	for range 100 {
+		eventBus.Publish("depthUpdate", "btcusdt@depth", nil)
+	}
+	if n := source.reads.Load(); n != 0 {
+		t.Fatalf("expected deltas only to mark the symbol dirty, %d books read", n)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go engine.Start(ctx)
+
+	select {
+	case stats := <-published:
+		if stats.LastUpdateID != 7 {
+			t.Errorf("published stats of update %d, want 7", stats.LastUpdateID)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no statistics published")
+	}
+	time.Sleep(50 * time.Millisecond)
+	if n := source.reads.Load(); n != 1 {
+		t.Errorf("expected one book read for 100 deltas, got %d", n)
+	}
+
+	cancel()
+	time.Sleep(20 * time.Millisecond)
+	eventBus.Publish("depthUpdate", "btcusdt@depth", nil)
+	if _, ok := engine.Get("btcusdt"); !ok {
+		t.Fatal("no statistics for BTCUSDT")
+	}
+	if n := source.reads.Load(); n != 2 {
+		t.Errorf("expected Get to recompute a dirty book once, %d books read", n)
+	}
+}
@@ -0,0 +1,76 @@
+package analytics_test
+
+import (
+	"testing"
+
+	"github.com/ChethiyaNishanath/market-data-hub/internal/analytics"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/domain/orderbook"
+	"github.com/ChethiyaNishanath/market-data-hub/test/testutil"
+)
+
+func TestComputeTopOfBook(t *testing.T) {
+	ob := &orderbook.OrderBook{
+		LastUpdateID: 42,
+		Bids:         [][]string{{"99", "3"}, {"100", "1"}},
+		Asks:         [][]string{{"102", "1"}, {"101", "3"}},
+	}
+
+	stats := analytics.Compute("BTCUSDT", ob, 1, nil)
+
+	if stats.LastUpdateID != 42 {
+		t.Errorf("expected last update id 42, got %d", stats.LastUpdateID)
+	}
+	if stats.BestBid != 100 || stats.BestAsk != 101 {
+		t.Fatalf("unexpected best prices %v / %v", stats.BestBid, stats.BestAsk)
+	}
+	if !testutil.Approx(stats.Mid, 100.5) {
+		t.Errorf("expected mid 100.5, got %v", stats.Mid)
+	}
+	if !testutil.Approx(stats.SpreadBps, 1/100.5*10_000) {
+		t.Errorf("unexpected spread %v", stats.SpreadBps)
+	}
+	// Thin bid, thick ask: the microprice sits closer to the bid.
+	if !testutil.Approx(stats.Microprice, (100*3+101*1)/4.0) {
+		t.Errorf("unexpected microprice %v", stats.Microprice)
+	}
+	if !testutil.Approx(stats.Imbalance, (1-3)/4.0) {
+		t.Errorf("unexpected top-1 imbalance %v", stats.Imbalance)
+	}
+}
+
+func TestComputeDepthBands(t *testing.T) {
+	ob := &orderbook.OrderBook{
+		Bids: [][]string{{"99.9", "1"}, {"99.5", "2"}, {"98", "5"}},
+		Asks: [][]string{{"100.1", "1"}, {"100.4", "2"}, {"102", "5"}},
+	}
+
+	stats := analytics.Compute("BTCUSDT", ob, 10, []float64{10, 50})
+
+	if len(stats.DepthBands) != 2 {
+		t.Fatalf("expected 2 bands, got %d", len(stats.DepthBands))
+	}
+
+	narrow, wide := stats.DepthBands[0], stats.DepthBands[1]
+	if narrow.BidQuantity != 1 || narrow.AskQuantity != 1 {
+		t.Errorf("unexpected 10bps band %+v", narrow)
+	}
+	if wide.BidQuantity != 3 || wide.AskQuantity != 3 {
+		t.Errorf("unexpected 50bps band %+v", wide)
+	}
+	if !testutil.Approx(wide.BidNotional, 99.9+99.5*2) {
+		t.Errorf("unexpected bid notional %v", wide.BidNotional)
+	}
+}
+
+func TestComputeOneSidedBook(t *testing.T) {
+	ob := &orderbook.OrderBook{Bids: [][]string{{"100", "1"}}}
+
+	stats := analytics.Compute("BTCUSDT", ob, 10, []float64{10})
+
+	if stats.Mid != 0 || stats.Microprice != 0 || len(stats.DepthBands) != 0 {
+		t.Errorf("expected empty price stats for one-sided book, got %+v", stats)
+	}
+	if stats.Imbalance != 1 {
+		t.Errorf("expected full bid imbalance, got %v", stats.Imbalance)
+	}
+}
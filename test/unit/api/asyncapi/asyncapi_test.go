@@ -0,0 +1,203 @@
+package asyncapi_test
+
+import (
+	"os"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/ChethiyaNishanath/market-data-hub/internal/alerting"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/analytics"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/arbitrage"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/consolidated"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/domain/orderbook"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/exchange/binance"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/subcription"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/synthetic"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/verify"
+	"github.com/ChethiyaNishanath/market-data-hub/pkg/client"
+	"go.yaml.in/yaml/v3"
+)
+
+const specPath = "../../../../api/asyncapi.yaml"
+
+type schema struct {
+	Enum       []string          `yaml:"enum"`
+	Properties map[string]schema `yaml:"properties"`
+}
+
+type spec struct {
+	Operations map[string]struct {
+		Messages []struct {
+			Ref string `yaml:"$ref"`
+		} `yaml:"messages"`
+	} `yaml:"operations"`
+	Components struct {
+		Messages map[string]struct {
+			Name string `yaml:"name"`
+		} `yaml:"messages"`
+		Schemas map[string]schema `yaml:"schemas"`
+	} `yaml:"components"`
+}
+
+func load(t *testing.T) spec {
+	t.Helper()
+	data, err := os.ReadFile(specPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var s spec
+	if err := yaml.Unmarshal(data, &s); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func sorted(values []string) []string {
+	values = slices.Clone(values)
+	sort.Strings(values)
+	return values
+}
+
+func assertSameSet(t *testing.T, what string, spec, code []string) {
+	t.Helper()
+	if !reflect.DeepEqual(sorted(spec), sorted(code)) {
+		t.Errorf("%s: spec has %v, code has %v", what, sorted(spec), sorted(code))
+	}
+}
+
+func TestMethodsMatchRouter(t *testing.T) {
+	s := load(t)
+
+	service := subcription.NewService(subcription.NewConnectionManager())
+	routes := []string{alerting.Method} // mounted by the app when alerts are on
+	for method := range service.Router.Routes {
+		routes = append(routes, method)
+	}
+	assertSameSet(t, "Method enum", s.Components.Schemas["Method"].Enum, routes)
+
+	var requests []string
+	for _, ref := range s.Operations["request"].Messages {
+		name := ref.Ref[strings.LastIndex(ref.Ref, "/")+1:]
+		msg, ok := s.Components.Messages[name]
+		if !ok {
+			t.Fatalf("request message %s is not defined", ref.Ref)
+		}
+		requests = append(requests, msg.Name)
+	}
+	assertSameSet(t, "request messages", requests, routes)
+
+	assertSameSet(t, "client methods", s.Components.Schemas["Method"].Enum, []string{
+		client.MethodSubscribe, client.MethodUnsubscribe, client.MethodListSubscriptions, client.MethodListSymbols,
+		client.MethodPing, client.MethodServerInfo, client.MethodAlert,
+	})
+}
+
+func TestErrorCodesMatchCode(t *testing.T) {
+	s := load(t)
+
+	assertSameSet(t, "ErrorCode enum", s.Components.Schemas["ErrorCode"].Enum, binance.ErrorCodes)
+	assertSameSet(t, "client error codes", s.Components.Schemas["ErrorCode"].Enum, []string{
+		client.CodeInvalidRequest, client.CodeUnknownMethod, client.CodeInvalidParams, client.CodeInvalidTopic,
+		client.CodeUnsupportedEvent, client.CodeUnknownSymbol, client.CodeNotFound, client.CodeInternal,
+	})
+}
+
+func TestTopicEventsMatchProviders(t *testing.T) {
+	s := load(t)
+
+	events := slices.Clone(subcription.BuiltinEvents)
+	for _, suffix := range []string{
+		consolidated.TopicSuffix, synthetic.TopicSuffix, analytics.TopicSuffix, arbitrage.TopicSuffix, verify.TopicSuffix,
+	} {
+		events = append(events, strings.TrimPrefix(suffix, "@"))
+	}
+	assertSameSet(t, "TopicEvent enum", s.Components.Schemas["TopicEvent"].Enum, events)
+}
+
+// schemaTypes are the Go types each object schema describes: the one the hub
+// encodes and, where the client decodes it, the client's.
+var schemaTypes = map[string][]any{
+	"Hello":   {subcription.Hello{}},
+	"Request": {binance.WSRequest{}},
+	"Message": {binance.WSMessage{}, client.Message{}},
+	"TopicParams": {struct {
+		Topic string `json:"topic"`
+	}{}},
+	"OrderBook":         {orderbook.OrderBook{}, client.OrderBook{}},
+	"DepthUpdate":       {binance.DepthUpdateEvent{}, client.DepthUpdate{}},
+	"OrderBookReset":    {binance.OrderBookResetEvent{}, client.Reset{}},
+	"ConnectionState":   {binance.ConnectionStateEvent{}, client.ConnectionState{}},
+	"Trade":             {binance.TradeEvent{}, client.Trade{}},
+	"PongData":          {subcription.PongData{}},
+	"ExchangeSymbols":   {subcription.ExchangeSymbols{}, client.ExchangeSymbols{}},
+	"ServerInfo":        {subcription.ServerInfo{}, client.ServerInfo{}},
+	"Limits":            {subcription.Limits{}, client.Limits{}},
+	"Level":             {orderbook.Level{}},
+	"VenueQuantity":     {consolidated.VenueQuantity{}},
+	"ConsolidatedLevel": {consolidated.Level{}},
+	"ConsolidatedBook":  {consolidated.Book{}},
+	"SyntheticBook":     {synthetic.Book{}},
+	"DepthBand":         {analytics.DepthBand{}},
+	"Stats":             {analytics.Stats{}},
+	"Opportunity":       {arbitrage.Opportunity{}},
+	"CrossedBook":       {arbitrage.CrossedBook{}},
+	"Mismatch":          {verify.Mismatch{}},
+	"VerifyReport":      {verify.Report{}},
+	"AlertRequest":      {alerting.AlertRequest{}},
+	"Rule":              {alerting.Rule{}},
+	"Target":            {alerting.Target{}},
+	"Alert":             {alerting.Alert{}},
+	"AlertNotification": {alerting.Notification{}},
+}
+
+func jsonFields(v any) []string {
+	typ := reflect.TypeOf(v)
+	var fields []string
+	for i := range typ.NumField() {
+		f := typ.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		switch name {
+		case "-":
+			continue
+		case "":
+			name = f.Name
+		}
+		fields = append(fields, name)
+	}
+	return fields
+}
+
+func TestSchemasMatchTypes(t *testing.T) {
+	s := load(t)
+
+	for name, sch := range s.Components.Schemas {
+		if sch.Properties == nil {
+			continue
+		}
+		types, ok := schemaTypes[name]
+		if !ok {
+			t.Errorf("schema %s has no Go type to check against", name)
+			continue
+		}
+
+		var props []string
+		for prop := range sch.Properties {
+			props = append(props, prop)
+		}
+		for _, v := range types {
+			assertSameSet(t, name+" vs "+reflect.TypeOf(v).String(), props, jsonFields(v))
+		}
+	}
+
+	for name := range schemaTypes {
+		if _, ok := s.Components.Schemas[name]; !ok {
+			t.Errorf("schema %s is missing from the spec", name)
+		}
+	}
+}
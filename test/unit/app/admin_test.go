@@ -0,0 +1,85 @@
+package app_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ChethiyaNishanath/market-data-hub/internal/app"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/config"
+	"github.com/go-chi/chi/v5"
+)
+
+const newAlert = `{"symbol":"BTCUSDT","clientId":"c","rule":{"type":"spread","spreadBps":5},"deliver":{"websocket":true}}`
+
+// adminRoutes lists the admin routes with the status each answers an admin.
+var adminRoutes = []struct {
+	method, path, body string
+	want               int
+}{
+	{http.MethodGet, "/admin/clients", "", http.StatusOK},
+	{http.MethodPost, "/alerts", newAlert, http.StatusCreated},
+	{http.MethodGet, "/alerts", "", http.StatusOK},
+	{http.MethodGet, "/alerts/unknown", "", http.StatusNotFound},
+	{http.MethodDelete, "/alerts/unknown", "", http.StatusNotFound},
+}
+
+func serve(t *testing.T, token string) *httptest.Server {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	cfg := &config.Config{}
+	cfg.Server.Admin.Token = token
+	cfg.Alerts.Enabled = true
+
+	r := chi.NewRouter()
+	app.NewApp(&ctx, cfg).RegisterRoutes(r)
+	srv := httptest.NewServer(r)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func call(t *testing.T, srv *httptest.Server, method, path, body, token string) int {
+	t.Helper()
+	req, err := http.NewRequest(method, srv.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestAdminRoutesNeedToken(t *testing.T) {
+	srv := serve(t, "s3cr3t-admin")
+
+	for _, route := range adminRoutes {
+		for _, token := range []string{"", "wrong"} {
+			if code := call(t, srv, route.method, route.path, route.body, token); code != http.StatusUnauthorized {
+				t.Errorf("%s %s with token %q: status %d, want 401", route.method, route.path, token, code)
+			}
+		}
+		if code := call(t, srv, route.method, route.path, route.body, "s3cr3t-admin"); code != route.want {
+			t.Errorf("%s %s as admin: status %d, want %d", route.method, route.path, code, route.want)
+		}
+	}
+}
+
+func TestAdminRoutesOffWithoutToken(t *testing.T) {
+	srv := serve(t, "")
+
+	for _, route := range adminRoutes {
+		if code := call(t, srv, route.method, route.path, route.body, "anything"); code != http.StatusNotFound {
+			t.Errorf("%s %s: status %d, want 404", route.method, route.path, code)
+		}
+	}
+}
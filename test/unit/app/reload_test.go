@@ -0,0 +1,110 @@
+package app_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ChethiyaNishanath/market-data-hub/internal/app"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/config"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/simulator"
+)
+
+func TestReloadAddsSymbolsToRunningEngines(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	exchange := simulator.New(simulator.Config{Symbols: []string{"BTCUSDT", "ETHBTC"}, Rate: 50, Levels: 10, Seed: 3})
+	go exchange.Run(ctx)
+	srv := httptest.NewServer(exchange.Handler())
+	defer srv.Close()
+
+	cfg := &config.Config{}
+	cfg.Integrations.Binance = config.BinanceConfig{
+		WsStreamUrl:   "ws" + strings.TrimPrefix(srv.URL, "http") + "/ws",
+		RestApiUrlV3:  srv.URL + "/api/v3",
+		Subscriptions: []config.SymbolConfig{{Symbol: "BTCUSDT", Enabled: true}},
+	}
+	cfg.Analytics = config.AnalyticsConfig{Enabled: true, PublishInterval: 10 * time.Millisecond}
+	a := app.NewApp(&ctx, cfg)
+	waitFor(t, "BTCUSDT statistics", func() bool { _, ok := a.Analytics.Get("BTCUSDT"); return ok })
+
+	next := *cfg
+	next.Integrations.Binance.Subscriptions = []config.SymbolConfig{{Symbol: "ETHBTC", Enabled: true}}
+	applied, rejected := a.Reload(&next)
+	if !slices.Contains(applied, "integrations.binance.subscriptions") || len(rejected) != 0 {
+		t.Fatalf("applied %v, rejected %v", applied, rejected)
+	}
+
+	waitFor(t, "ETHBTC statistics", func() bool { _, ok := a.Analytics.Get("ETHBTC"); return ok })
+	if _, ok := a.Analytics.Get("BTCUSDT"); ok {
+		t.Fatal("statistics of the removed BTCUSDT are still served")
+	}
+	if topics := a.Analytics.Topics(); !slices.Equal(topics, []string{"ethbtc@stats"}) {
+		t.Fatalf("analytics topics = %v", topics)
+	}
+}
+
+func TestReloadReplacesConfiguredAlerts(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cfg := &config.Config{}
+	cfg.Alerts = config.AlertsConfig{
+		Enabled: true,
+		Webhook: config.WebhookConfig{Enabled: true, OutboxPath: filepath.Join(t.TempDir(), "outbox.db")},
+		Rules:   []config.AlertRuleConfig{spreadRule("wide")},
+	}
+	a := app.NewApp(&ctx, cfg)
+	defer a.Close()
+	if ids := alertIDs(a); !slices.Equal(ids, []string{"config:wide"}) {
+		t.Fatalf("alerts on start = %v", ids)
+	}
+
+	next := *cfg
+	next.Alerts.Rules = []config.AlertRuleConfig{spreadRule("wider")}
+	applied, rejected := a.Reload(&next)
+	if !slices.Equal(applied, []string{"alerts.rules"}) || len(rejected) != 0 {
+		t.Fatalf("applied %v, rejected %v", applied, rejected)
+	}
+	if ids := alertIDs(a); !slices.Equal(ids, []string{"config:wider"}) {
+		t.Fatalf("alerts after reload = %v", ids)
+	}
+
+	invalid := next
+	invalid.Alerts.Rules = []config.AlertRuleConfig{{ID: "bad", Symbol: "BTCUSDT", Type: "unknown", WebhookURL: "http://example.com/hook"}}
+	applied, rejected = a.Reload(&invalid)
+	if len(applied) != 0 || !slices.Equal(rejected, []string{"alerts.rules"}) {
+		t.Fatalf("applied %v, rejected %v", applied, rejected)
+	}
+	if ids := alertIDs(a); !slices.Equal(ids, []string{"config:wider"}) {
+		t.Fatalf("alerts after a rejected reload = %v", ids)
+	}
+}
+
+func spreadRule(id string) config.AlertRuleConfig {
+	return config.AlertRuleConfig{ID: id, Symbol: "BTCUSDT", Type: "spread", SpreadBps: 5, WebhookURL: "http://example.com/hook"}
+}
+
+func alertIDs(a *app.App) []string {
+	var ids []string
+	for _, alert := range a.Alerts.List("") {
+		ids = append(ids, alert.ID)
+	}
+	return ids
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,127 @@
+package arbitrage_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ChethiyaNishanath/market-data-hub/internal/arbitrage"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/bus"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/config"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/domain/orderbook"
+	"github.com/ChethiyaNishanath/market-data-hub/test/testutil"
+)
+
+func book(bid, ask string) *orderbook.OrderBook {
+	return &orderbook.OrderBook{
+		Bids: [][]string{{bid, "1"}},
+		Asks: [][]string{{ask, "1"}},
+	}
+}
+
+func collect(eventBus *bus.Bus, topic string) <-chan any {
+	ch := make(chan any, 10)
+	eventBus.Subscribe(topic, func(e bus.Event) { ch <- e.Data })
+	return ch
+}
+
+func next(t *testing.T, ch <-chan any) any {
+	t.Helper()
+	select {
+	case v := <-ch:
+		return v
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for arb event")
+	}
+	return nil
+}
+
+func TestCrossedBookRequestsResyncOnce(t *testing.T) {
+	source := testutil.NewBookSource("", map[string]*orderbook.OrderBook{
+		"BTCUSDT": book("101", "100"),
+	})
+	eventBus := bus.New()
+	events := collect(eventBus, "btcusdt@arb")
+
+	detector := arbitrage.NewDetector(config.ArbitrageConfig{DetectCrossed: true}, eventBus, source)
+	detector.Check()
+	detector.Check()
+
+	event, ok := next(t, events).(arbitrage.CrossedBook)
+	if !ok || event.Symbol != "BTCUSDT" || !event.ResyncRequested {
+		t.Fatalf("unexpected event %+v", event)
+	}
+
+	if resyncs := source.Resyncs(); len(resyncs) != 1 {
+		t.Errorf("expected a single resync request, got %v", resyncs)
+	}
+}
+
+func TestCrossVenueOpportunityAfterFees(t *testing.T) {
+	venueA := testutil.NewBookSource("a", map[string]*orderbook.OrderBook{"BTCUSDT": book("99", "100")})
+	venueB := testutil.NewBookSource("b", map[string]*orderbook.OrderBook{"BTCUSDT": book("101", "102")})
+
+	cfg := config.ArbitrageConfig{
+		MinProfitBps: 10,
+		Instruments: []config.ConsolidatedInstrument{{
+			Name: "BTC-USDT",
+			Sources: []config.VenueSource{
+				{Venue: "a", Symbol: "BTCUSDT", FeeBps: 10},
+				{Venue: "b", Symbol: "BTCUSDT", FeeBps: 10},
+			},
+		}},
+	}
+
+	eventBus := bus.New()
+	events := collect(eventBus, "btc-usdt@arb")
+	detector := arbitrage.NewDetector(cfg, eventBus, venueA, venueB)
+	detector.Check()
+
+	opp := next(t, events).(arbitrage.Opportunity)
+	if !opp.Active || opp.BuyVenue != "a" || opp.SellVenue != "b" {
+		t.Fatalf("unexpected opportunity %+v", opp)
+	}
+	if opp.ProfitBps < 79 || opp.ProfitBps > 81 {
+		t.Errorf("expected ~80bps after fees, got %v", opp.ProfitBps)
+	}
+	if len(detector.Open("BTC-USDT")) != 1 {
+		t.Errorf("expected one open opportunity")
+	}
+
+	venueB.SetBook("BTCUSDT", book("100", "101"))
+	detector.Check()
+
+	closed := next(t, events).(arbitrage.Opportunity)
+	if closed.Active {
+		t.Errorf("expected the opportunity to close, got %+v", closed)
+	}
+}
+
+func TestTriangleOpportunity(t *testing.T) {
+	source := testutil.NewBookSource("", map[string]*orderbook.OrderBook{
+		"ETHBTC":  book("0.05", "0.0501"),
+		"BTCUSDT": book("60000", "60010"),
+		// Direct ETHUSDT ask is well below the 3000 USDT synthetic bid.
+		"ETHUSDT": book("2900", "2950"),
+	})
+
+	cfg := config.ArbitrageConfig{
+		Triangles: []config.ArbitrageTriangle{{
+			Name:   "ETH-BTC-USDT",
+			Direct: config.SyntheticLeg{Symbol: "ETHUSDT"},
+			Legs: []config.SyntheticLeg{
+				{Symbol: "ETHBTC"},
+				{Symbol: "BTCUSDT", Operation: "multiply"},
+			},
+			FeeBps: 5,
+		}},
+	}
+
+	eventBus := bus.New()
+	events := collect(eventBus, "eth-btc-usdt@arb")
+	arbitrage.NewDetector(cfg, eventBus, source).Check()
+
+	opp := next(t, events).(arbitrage.Opportunity)
+	if !opp.Active || opp.BuySymbol != "ETHUSDT" || opp.SellSymbol != "ETHBTC/BTCUSDT" {
+		t.Fatalf("unexpected opportunity %+v", opp)
+	}
+}
@@ -0,0 +1,249 @@
+package bench_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	pb "github.com/ChethiyaNishanath/market-data-hub/api/orderbook"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/bench"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/simulator"
+	"github.com/coder/websocket"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func TestSummarizePercentiles(t *testing.T) {
+	samples := make([]time.Duration, 0, 100)
+	for i := 100; i >= 1; i-- {
+		samples = append(samples, time.Duration(i)*time.Millisecond)
+	}
+
+	got := bench.Summarize(samples)
+
+	if got.Samples != 100 || got.P50 != 50*time.Millisecond || got.P99 != 99*time.Millisecond || got.Max != 100*time.Millisecond {
+		t.Fatalf("summary = %+v", got)
+	}
+	if got.Mean != 50500*time.Microsecond {
+		t.Fatalf("mean = %v, want 50.5ms", got.Mean)
+	}
+	if (bench.Summarize(nil) != bench.Latency{}) {
+		t.Fatal("empty samples should give a zero summary")
+	}
+}
+
+type depthSnapshot struct {
+	LastUpdateID int64      `json:"lastUpdateId"`
+	Bids         [][]string `json:"bids"`
+	Asks         [][]string `json:"asks"`
+}
+
+func fetchSnapshot(t *testing.T, url string) depthSnapshot {
+	t.Helper()
+	res, err := http.Get(url + "/api/v3/depth?symbol=BTCUSDT&limit=5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	var snap depthSnapshot
+	if err := json.NewDecoder(res.Body).Decode(&snap); err != nil {
+		t.Fatal(err)
+	}
+	return snap
+}
+
+func TestSimulatorIsDeterministic(t *testing.T) {
+	a := httptest.NewServer(simulator.New(simulator.Config{Symbols: []string{"BTCUSDT"}, Seed: 7}).Handler())
+	defer a.Close()
+	b := httptest.NewServer(simulator.New(simulator.Config{Symbols: []string{"btcusdt"}, Seed: 7}).Handler())
+	defer b.Close()
+
+	snapA, snapB := fetchSnapshot(t, a.URL), fetchSnapshot(t, b.URL)
+	if fmt.Sprint(snapA) != fmt.Sprint(snapB) {
+		t.Fatalf("same seed gave different books:\n%v\n%v", snapA, snapB)
+	}
+	if len(snapA.Bids) != 5 || len(snapA.Asks) != 5 {
+		t.Fatalf("limit not applied: %d bids, %d asks", len(snapA.Bids), len(snapA.Asks))
+	}
+	if snapA.Bids[0][0] >= snapA.Asks[0][0] {
+		t.Fatalf("book is crossed: bid %s, ask %s", snapA.Bids[0][0], snapA.Asks[0][0])
+	}
+}
+
+func TestSimulatorStreamContinuesFromSnapshot(t *testing.T) {
+	exchange := simulator.New(simulator.Config{Symbols: []string{"BTCUSDT"}, Rate: 200, Seed: 1})
+	server := httptest.NewServer(exchange.Handler())
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	go exchange.Run(ctx)
+
+	conn, _, err := websocket.Dial(ctx, "ws"+strings.TrimPrefix(server.URL, "http")+"/ws", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.CloseNow()
+
+	sub := `{"method":"SUBSCRIBE","params":["btcusdt@depth","btcusdt@trade"],"id":"req-1"}`
+	if err := conn.Write(ctx, websocket.MessageText, []byte(sub)); err != nil {
+		t.Fatal(err)
+	}
+
+	var ack struct {
+		ID string `json:"id"`
+	}
+	_, data, err := conn.Read(ctx)
+	if err != nil || json.Unmarshal(data, &ack) != nil || ack.ID != "req-1" {
+		t.Fatalf("ack = %s (%v)", data, err)
+	}
+
+	snap := fetchSnapshot(t, server.URL)
+
+	next := snap.LastUpdateID + 1
+	for next < snap.LastUpdateID+5 {
+		_, data, err := conn.Read(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var update struct {
+			EventType string `json:"e"`
+			EventTime int64  `json:"E"`
+			Symbol    string `json:"s"`
+			First     int64  `json:"U"`
+			Final     int64  `json:"u"`
+		}
+		if err := json.Unmarshal(data, &update); err != nil || update.EventType != "depthUpdate" || update.Symbol != "BTCUSDT" {
+			t.Fatalf("unexpected message %s", data)
+		}
+		if update.Final < next {
+			continue
+		}
+		if update.First != next {
+			t.Fatalf("update %d-%d does not continue from %d", update.First, update.Final, next)
+		}
+		next = update.Final + 1
+	}
+}
+
+// fakeHub answers every client with the given depth updates, in order.
+func fakeHub(ids []int64) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := websocket.Accept(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.CloseNow()
+
+		ctx := r.Context()
+		conn.Write(ctx, websocket.MessageText, []byte(`{"client_id":"c1"}`))
+		for range 2 {
+			if _, _, err := conn.Read(ctx); err != nil {
+				return
+			}
+		}
+		conn.Write(ctx, websocket.MessageText, []byte(`{"method":"subscribe","success":true,"topic":"BTCUSDT@depth","data":{"lastUpdateId":1}}`))
+
+		for _, id := range ids {
+			msg := fmt.Sprintf(`{"data":{"e":"depthUpdate","E":%d,"s":"BTCUSDT","U":%d,"u":%d,"b":[],"a":[]}}`,
+				time.Now().UnixMilli(), id, id)
+			if err := conn.Write(ctx, websocket.MessageText, []byte(msg)); err != nil {
+				return
+			}
+		}
+		<-ctx.Done()
+	}))
+}
+
+func TestRunWSCountsDropsAndReorders(t *testing.T) {
+	// 4 never arrives and 6 arrives after 7.
+	server := fakeHub([]int64{2, 3, 5, 7, 6, 8})
+	defer server.Close()
+
+	report, err := bench.RunWS(context.Background(), bench.WSConfig{
+		URL:           "ws" + strings.TrimPrefix(server.URL, "http"),
+		Clients:       2,
+		Subscriptions: 1,
+		Symbols:       []string{"BTCUSDT"},
+		Duration:      300 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if report.Connected != 2 || report.Messages != 12 {
+		t.Fatalf("connected %d, messages %d; want 2 and 12", report.Connected, report.Messages)
+	}
+	if report.Dropped != 2 || report.Reordered != 2 {
+		t.Fatalf("dropped %d, reordered %d; want 2 each", report.Dropped, report.Reordered)
+	}
+	if report.Errors != 0 || report.Latency.Samples != 12 {
+		t.Fatalf("errors %d, latency samples %d", report.Errors, report.Latency.Samples)
+	}
+}
+
+func TestRunWSRejectsMoreSubscriptionsThanSymbols(t *testing.T) {
+	_, err := bench.RunWS(context.Background(), bench.WSConfig{
+		URL:           "ws://127.0.0.1:1/ws",
+		Clients:       1,
+		Subscriptions: 2,
+		Symbols:       []string{"BTCUSDT"},
+	})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+type countingServer struct {
+	pb.UnimplementedOrderBookServer
+	calls atomic.Int64
+}
+
+func (s *countingServer) GetSnapshot(_ context.Context, in *pb.OrderBookSnapshotRequest) (*pb.GetSnapshotReply, error) {
+	s.calls.Add(1)
+	return &pb.GetSnapshotReply{Symbol: in.GetSymbol()}, nil
+}
+
+func TestRunGRPCHoldsTheRate(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := grpc.NewServer()
+	counting := &countingServer{}
+	pb.RegisterOrderBookServer(srv, counting)
+	go srv.Serve(lis)
+	defer srv.Stop()
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	report, err := bench.RunGRPC(context.Background(), pb.NewOrderBookClient(conn), bench.GRPCConfig{
+		Target:      lis.Addr().String(),
+		Symbols:     []string{"BTCUSDT", "ETHBTC"},
+		Rate:        200,
+		Concurrency: 4,
+		Duration:    500 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if report.Errors != 0 || report.Messages < 50 || report.Messages > 110 {
+		t.Fatalf("messages %d, errors %d; want about 100 and none", report.Messages, report.Errors)
+	}
+	if report.Latency.Samples != int(report.Messages) || counting.calls.Load() < report.Messages {
+		t.Fatalf("samples %d, server calls %d, messages %d", report.Latency.Samples, counting.calls.Load(), report.Messages)
+	}
+}
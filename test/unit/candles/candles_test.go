@@ -0,0 +1,162 @@
+package candles_test
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ChethiyaNishanath/market-data-hub/internal/bus"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/candles"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/config"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/exchange/binance"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/store/memory"
+	"github.com/ChethiyaNishanath/market-data-hub/test/testutil"
+)
+
+func newEngine(t *testing.T, trades *memory.TradeStore, stored *memory.CandleStore) *candles.Engine {
+	t.Helper()
+	engine, err := candles.NewEngine(config.CandlesConfig{Intervals: []string{"1m", "1h"}}, bus.New(), testutil.NewBookSource("", nil).Subscribe("BTCUSDT"), trades, stored)
+	if err != nil {
+		t.Fatalf("engine: %v", err)
+	}
+	return engine
+}
+
+func tradeAt(id int64, price string, at time.Time) binance.TradeEvent {
+	return binance.TradeEvent{
+		Symbol: "BTCUSDT", TradeID: id, Price: price, Quantity: "2", Side: "buy",
+		TradeTime: at.UnixMilli(), EventTime: at.UnixMilli(),
+	}
+}
+
+func TestCandleFollowsTradeIDsNotArrival(t *testing.T) {
+	minute := time.Now().UTC().Truncate(time.Minute)
+	engine := newEngine(t, memory.NewTradeStore(0), memory.NewCandleStore())
+
+	engine.Record(tradeAt(3, "103", minute.Add(3*time.Second)))
+	engine.Record(tradeAt(1, "101", minute.Add(time.Second)))
+	engine.Record(tradeAt(2, "99", minute.Add(2*time.Second)))
+
+	list, err := engine.Candles("btcusdt", "1m", time.Time{}, time.Time{}, 0)
+	if err != nil {
+		t.Fatalf("candles: %v", err)
+	}
+	if len(list) != 1 {
+		t.Fatalf("expected one candle, got %+v", list)
+	}
+	c := list[0]
+	if c.Open != 101 || c.Close != 103 || c.High != 103 || c.Low != 99 {
+		t.Errorf("unexpected OHLC %+v", c)
+	}
+	if c.Volume != 6 || c.QuoteVolume != 606 || c.Trades != 3 || !c.OpenTime.Equal(minute) {
+		t.Errorf("unexpected totals %+v", c)
+	}
+}
+
+func TestQueriesSeeFlushedAndBufferedData(t *testing.T) {
+	minute := time.Now().UTC().Truncate(time.Minute)
+	trades, stored := memory.NewTradeStore(0), memory.NewCandleStore()
+	engine := newEngine(t, trades, stored)
+
+	engine.Record(tradeAt(1, "100", minute))
+	engine.Flush()
+	engine.Record(tradeAt(2, "110", minute.Add(time.Second)))
+
+	persisted, _ := stored.Range("binance", "BTCUSDT", "1m", time.Time{}, time.Time{}, 0)
+	if len(persisted) != 1 || persisted[0].Close != 100 {
+		t.Fatalf("expected the flushed candle in the repository, got %+v", persisted)
+	}
+
+	list, err := engine.Trades("BTCUSDT", time.Time{}, time.Time{}, 0)
+	if err != nil {
+		t.Fatalf("trades: %v", err)
+	}
+	if len(list) != 2 || list[0].ID != 1 || list[1].ID != 2 {
+		t.Errorf("expected stored and buffered trades oldest first, got %+v", list)
+	}
+
+	candleList, _ := engine.Candles("BTCUSDT", "1m", time.Time{}, time.Time{}, 0)
+	if len(candleList) != 1 || candleList[0].Close != 110 {
+		t.Errorf("expected the buffered close, got %+v", candleList)
+	}
+}
+
+func TestResumesStoredCandleAfterRestart(t *testing.T) {
+	hour := time.Now().UTC().Truncate(time.Hour)
+	trades, stored := memory.NewTradeStore(0), memory.NewCandleStore()
+
+	first := newEngine(t, trades, stored)
+	first.Record(tradeAt(1, "100", hour))
+	first.Flush()
+
+	second := newEngine(t, trades, stored)
+	second.Record(tradeAt(2, "90", hour.Add(time.Second)))
+
+	list, _ := second.Candles("BTCUSDT", "1h", time.Time{}, time.Time{}, 0)
+	if len(list) != 1 {
+		t.Fatalf("expected one candle, got %+v", list)
+	}
+	if c := list[0]; c.Open != 100 || c.Close != 90 || c.Low != 90 || c.Trades != 2 || c.Volume != 4 {
+		t.Errorf("expected the stored candle to be continued, got %+v", c)
+	}
+}
+
+func TestDropsTradesForLongClosedCandles(t *testing.T) {
+	engine := newEngine(t, memory.NewTradeStore(0), memory.NewCandleStore())
+
+	engine.Record(tradeAt(1, "100", time.Now().Add(-10*time.Minute)))
+
+	list, _ := engine.Candles("BTCUSDT", "1m", time.Time{}, time.Time{}, 0)
+	if len(list) != 0 {
+		t.Errorf("expected no 1m candle, got %+v", list)
+	}
+	trades, _ := engine.Trades("BTCUSDT", time.Time{}, time.Time{}, 0)
+	if len(trades) != 1 {
+		t.Errorf("expected the trade to be kept in the history, got %+v", trades)
+	}
+}
+
+func TestRejectsInvalidQueries(t *testing.T) {
+	engine := newEngine(t, memory.NewTradeStore(0), memory.NewCandleStore())
+
+	if _, err := engine.Candles("BTCUSDT", "15m", time.Time{}, time.Time{}, 0); !errors.Is(err, candles.ErrInvalidQuery) {
+		t.Errorf("expected an unconfigured interval to be rejected, got %v", err)
+	}
+	if _, err := engine.Trades("", time.Time{}, time.Time{}, 0); !errors.Is(err, candles.ErrInvalidQuery) {
+		t.Errorf("expected a missing symbol to be rejected, got %v", err)
+	}
+	if _, err := engine.Trades("BTCUSDT", time.Time{}, time.Time{}, candles.MaxLimit+1); !errors.Is(err, candles.ErrInvalidQuery) {
+		t.Errorf("expected an oversized limit to be rejected, got %v", err)
+	}
+}
+
+func TestParseInterval(t *testing.T) {
+	for label, want := range map[string]time.Duration{"1m": time.Minute, "4h": 4 * time.Hour, "1d": 24 * time.Hour, "7d": 7 * 24 * time.Hour} {
+		got, err := candles.ParseInterval(label)
+		if err != nil || got != want {
+			t.Errorf("%s: got %v, %v", label, got, err)
+		}
+	}
+	for _, label := range []string{"", "7m", "0s", "500ms", "1w", "d"} {
+		if _, err := candles.ParseInterval(label); err == nil {
+			t.Errorf("expected %q to be rejected", label)
+		}
+	}
+}
+
+func TestTradeStoreKeepsNewestPerSymbol(t *testing.T) {
+	trades := memory.NewTradeStore(2)
+	engine := newEngine(t, trades, memory.NewCandleStore())
+
+	now := time.Now()
+	for id := int64(1); id <= 3; id++ {
+		engine.Record(tradeAt(id, strconv.Itoa(int(100+id)), now))
+	}
+	engine.Flush()
+
+	list, _ := trades.Recent("binance", "BTCUSDT", time.Time{}, time.Time{}, 0)
+	if len(list) != 2 || list[0].ID != 2 {
+		t.Errorf("expected the two newest trades, got %+v", list)
+	}
+}
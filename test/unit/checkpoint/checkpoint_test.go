@@ -0,0 +1,70 @@
+package checkpoint_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/ChethiyaNishanath/market-data-hub/internal/checkpoint"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/domain/orderbook"
+	"github.com/ChethiyaNishanath/market-data-hub/test/testutil"
+)
+
+func TestStoreSurvivesReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoints.db")
+
+	store, err := checkpoint.Open(path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+
+	book := &orderbook.OrderBook{
+		LastUpdateID: 42,
+		Bids:         [][]string{{"100", "1"}},
+		Asks:         [][]string{{"101", "2"}},
+	}
+	if err := store.Save("binance", "BTCUSDT", book); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	_ = store.Close()
+
+	store, err = checkpoint.Open(path)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	defer store.Close()
+
+	loaded, savedAt, ok := store.Load("binance", "BTCUSDT")
+	if !ok || savedAt.IsZero() {
+		t.Fatal("expected the checkpoint to be found after reopen")
+	}
+	if loaded.LastUpdateID != 42 || loaded.Asks[0][1] != "2" {
+		t.Errorf("unexpected book %+v", loaded)
+	}
+
+	if _, _, ok := store.Load("binance", "ETHUSDT"); ok {
+		t.Error("did not expect a checkpoint for an unknown symbol")
+	}
+}
+
+func TestWriterSkipsUnsynchronizedBooks(t *testing.T) {
+	store, err := checkpoint.Open(filepath.Join(t.TempDir(), "checkpoints.db"))
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer store.Close()
+
+	source := testutil.NewBookSource("", map[string]*orderbook.OrderBook{
+		"BTCUSDT": {LastUpdateID: 1},
+		"BNBBTC":  {LastUpdateID: 2},
+	})
+	source.SetSynced("BNBBTC", false)
+
+	checkpoint.NewWriter(store, 0, source).SaveAll()
+
+	if _, _, ok := store.Load("binance", "BTCUSDT"); !ok {
+		t.Error("expected the synchronized book to be saved")
+	}
+	if _, _, ok := store.Load("binance", "BNBBTC"); ok {
+		t.Error("did not expect the resyncing book to be saved")
+	}
+}
@@ -0,0 +1,216 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	events "github.com/ChethiyaNishanath/market-data-hub/internal/bus"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/config"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/exchange/binance"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/simulator"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/subcription"
+	"github.com/ChethiyaNishanath/market-data-hub/pkg/client"
+	"github.com/ChethiyaNishanath/market-data-hub/pkg/orderbook"
+)
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// dropListener lets a test cut every connection accepted so far.
+type dropListener struct {
+	net.Listener
+	mu    sync.Mutex
+	conns []net.Conn
+}
+
+func (l *dropListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err == nil {
+		l.mu.Lock()
+		l.conns = append(l.conns, conn)
+		l.mu.Unlock()
+	}
+	return conn, err
+}
+
+func (l *dropListener) drop() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, conn := range l.conns {
+		conn.Close()
+	}
+	l.conns = nil
+}
+
+// startHub runs a hub fed by a simulated exchange and returns its WebSocket URL.
+func startHub(t *testing.T) (string, *binance.Service, *dropListener) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	exchange := simulator.New(simulator.Config{Symbols: []string{"BTCUSDT"}, Rate: 5, Levels: 20, Seed: 11})
+	go exchange.Run(ctx)
+	upstream := httptest.NewServer(exchange.Handler())
+	t.Cleanup(upstream.Close)
+
+	connMgr := subcription.NewConnectionManager()
+	service := binance.NewService(ctx, events.New(), connMgr, config.BinanceConfig{
+		WsStreamUrl:   "ws" + strings.TrimPrefix(upstream.URL, "http") + "/ws",
+		RestApiUrlV3:  upstream.URL + "/api/v3",
+		Subscriptions: []config.SymbolConfig{{Symbol: "BTCUSDT", Enabled: true}},
+	})
+	go service.Start(ctx)
+	waitFor(t, "BTCUSDT to sync", func() bool { return service.IsSynchronized("BTCUSDT") })
+
+	subscriptions := subcription.NewService(connMgr)
+	subscriptions.Handler.RegisterExchange(service)
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(subscriptions.Handler.HandleWebSocket))
+	listener := &dropListener{Listener: srv.Listener}
+	srv.Listener = listener
+	srv.Start()
+	t.Cleanup(srv.Close)
+
+	return "ws" + strings.TrimPrefix(srv.URL, "http"), service, listener
+}
+
+func levelMap(levels [][]string) map[float64]float64 {
+	m := make(map[float64]float64, len(levels))
+	for _, lvl := range levels {
+		price, _ := strconv.ParseFloat(lvl[0], 64)
+		qty, _ := strconv.ParseFloat(lvl[1], 64)
+		if qty > 0 {
+			m[price] = qty
+		}
+	}
+	return m
+}
+
+func clientMap(levels []orderbook.Level) map[float64]float64 {
+	m := make(map[float64]float64, len(levels))
+	for _, lvl := range levels {
+		m[lvl.Price] = lvl.Quantity
+	}
+	return m
+}
+
+// matchesHub reports whether book equals the hub's book at the same update.
+func matchesHub(service *binance.Service, book *orderbook.Book) bool {
+	hub := service.GetOrderBook("BTCUSDT")
+	if hub == nil || int64(hub.LastUpdateID) != book.LastUpdateID() || !book.Synced() {
+		return false
+	}
+	return reflect.DeepEqual(levelMap(hub.Bids), clientMap(book.Bids(0))) &&
+		reflect.DeepEqual(levelMap(hub.Asks), clientMap(book.Asks(0)))
+}
+
+func TestClientKeepsBookAndResumesAfterReconnect(t *testing.T) {
+	url, service, listener := startHub(t)
+	ctx := context.Background()
+
+	var (
+		mu       sync.Mutex
+		statuses []bool
+	)
+	c, err := client.Dial(ctx, url, client.Options{
+		MinBackoff: 10 * time.Millisecond,
+		MaxBackoff: 50 * time.Millisecond,
+		OnStatus: func(connected bool, _ error) {
+			mu.Lock()
+			statuses = append(statuses, connected)
+			mu.Unlock()
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	firstID := c.ClientID()
+	if firstID == "" {
+		t.Fatal("no client id")
+	}
+
+	book, err := c.Book(ctx, "btcusdt", orderbook.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Subscribe(ctx, "BTCUSDT@trade"); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, "the local book to match the hub", func() bool { return matchesHub(service, book) })
+
+	bid, okBid := book.BestBid()
+	ask, okAsk := book.BestAsk()
+	if !okBid || !okAsk || bid.Price >= ask.Price {
+		t.Fatalf("best bid %v, best ask %v", bid, ask)
+	}
+
+	listener.drop()
+	waitFor(t, "a new connection", func() bool { return c.ClientID() != firstID })
+
+	waitFor(t, "the topics to be resumed", func() bool {
+		topics, err := c.ListSubscriptions(ctx)
+		return err == nil && reflect.DeepEqual(topics, []string{"btcusdt@depth", "btcusdt@depth.reset", "btcusdt@trade"})
+	})
+	waitFor(t, "the book to match the hub again", func() bool { return matchesHub(service, book) })
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(statuses) < 3 || !statuses[0] || statuses[1] || !statuses[len(statuses)-1] {
+		t.Fatalf("statuses = %v", statuses)
+	}
+}
+
+func TestClientRequests(t *testing.T) {
+	url, _, _ := startHub(t)
+	ctx := context.Background()
+
+	c, err := client.Dial(ctx, url, client.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	if now, err := c.Ping(ctx); err != nil || time.Since(now).Abs() > time.Minute {
+		t.Fatalf("ping = %v, %v", now, err)
+	}
+
+	symbols, err := c.ListSymbols(ctx)
+	if err != nil || len(symbols) != 1 || !reflect.DeepEqual(symbols[0].Symbols, []string{"BTCUSDT"}) {
+		t.Fatalf("symbols = %+v, %v", symbols, err)
+	}
+
+	info, err := c.ServerInfo(ctx)
+	if err != nil || !reflect.DeepEqual(info.Exchanges, []string{"binance"}) || len(info.Events) == 0 {
+		t.Fatalf("server info = %+v, %v", info, err)
+	}
+
+	var rejected *client.Error
+	if _, err := c.Book(ctx, "DOGEUSDT", orderbook.Options{}); !errors.As(err, &rejected) || rejected.Code != client.CodeUnknownSymbol {
+		t.Fatalf("unknown symbol error = %v", err)
+	}
+	if _, err := c.Subscribe(ctx, "btcusdt"); !errors.As(err, &rejected) || rejected.Code != client.CodeInvalidTopic {
+		t.Fatalf("invalid topic error = %v", err)
+	}
+	if _, err := c.Do(ctx, "nope", nil); !errors.As(err, &rejected) || rejected.Code != client.CodeUnknownMethod {
+		t.Fatalf("unknown method error = %v", err)
+	}
+}
@@ -0,0 +1,339 @@
+package config_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ChethiyaNishanath/market-data-hub/internal/config"
+	"github.com/spf13/viper"
+)
+
+const base = `
+integrations:
+  binance:
+    wsStreamUrl: wss://stream.binance.com:9443/ws
+    restApiUrlV3: https://api.binance.com/api/v3
+`
+
+func load(t *testing.T, doc string) (*config.Config, error) {
+	t.Helper()
+	v := viper.New()
+	v.SetConfigType("yaml")
+	config.SetDefaults(v)
+	if err := v.ReadConfig(strings.NewReader(doc)); err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	return config.Load(v)
+}
+
+func TestLoadAcceptsSubscriptionsAsStringOrObjects(t *testing.T) {
+	cfg, err := load(t, base+"    subscriptions: btcusdt, ETHBTC\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := cfg.Integrations.Binance.Symbols(); !reflect.DeepEqual(got, []string{"BTCUSDT", "ETHBTC"}) {
+		t.Fatalf("symbols = %v", got)
+	}
+
+	cfg, err = load(t, base+`    subscriptions:
+      - BNBBTC
+      - symbol: btcusdt
+        depthLimit: 20
+        snapshotLimit: 5000
+        conflation: 100ms
+      - symbol: ETHBTC
+        enabled: false
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	binance := cfg.Integrations.Binance
+	if got := binance.Symbols(); !reflect.DeepEqual(got, []string{"BNBBTC", "BTCUSDT"}) {
+		t.Fatalf("symbols = %v", got)
+	}
+	want := config.SymbolConfig{Symbol: "BTCUSDT", Enabled: true, DepthLimit: 20, SnapshotLimit: 5000, Conflation: 100 * time.Millisecond}
+	if got, ok := binance.Symbol("btcusdt"); !ok || got != want {
+		t.Fatalf("BTCUSDT = %+v, %v", got, ok)
+	}
+	if _, ok := binance.Symbol("ETHBTC"); ok {
+		t.Fatal("disabled symbol reported")
+	}
+}
+
+func TestLoadAppliesDefaultsAndTypes(t *testing.T) {
+	cfg, err := load(t, base+"    subscriptions: BTCUSDT\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Server.Port != 8080 || cfg.Server.ShutdownTimeout != 10*time.Second {
+		t.Fatalf("server = %+v", cfg.Server)
+	}
+}
+
+func TestLoadRejectsDurationsWithoutUnit(t *testing.T) {
+	_, err := load(t, base+"    subscriptions: BTCUSDT\nserver:\n  shutdownTimeout: 10\n")
+	if err == nil || !strings.Contains(err.Error(), "server.shutdownTimeout: 10 has no unit") {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestValidateReportsEveryProblem(t *testing.T) {
+	_, err := load(t, `
+server:
+  port: 0
+  rateLimit:
+    requests: -1
+logging:
+  level: loud
+integrations:
+  binance:
+    wsStreamUrl: http://stream.binance.com
+    restApiUrlV3: https://api.binance.com/api/v3
+    subscriptions:
+      - symbol: BTC-USDT
+      - symbol: ETHBTC
+        snapshotLimit: 6000
+      - ethbtc
+    snapshot:
+      limit: -1
+    stream:
+      pingInterval: -1s
+`)
+	if err == nil {
+		t.Fatal("expected errors")
+	}
+	for _, want := range []string{
+		"server.port: 0 is not a port",
+		`logging.level: unknown level "loud"`,
+		`integrations.binance.wsStreamUrl: scheme "http" is not supported`,
+		`integrations.binance.subscriptions[0].symbol: "BTC-USDT" is not a symbol`,
+		"integrations.binance.snapshot.limit: -1 is outside 0-5000, where 0 means the default",
+		"integrations.binance.subscriptions[1].snapshotLimit: 6000 is outside 0-5000, where 0 means the default",
+		"integrations.binance.subscriptions[2].symbol: ETHBTC is listed more than once",
+		"integrations.binance.stream.pingInterval: must not be negative",
+		"server.rateLimit.requests: must not be negative",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("missing %q in:\n%v", want, err)
+		}
+	}
+}
+
+func TestValidateRequiresSubscribedSymbols(t *testing.T) {
+	doc := base + `    subscriptions: BTCUSDT, ETHBTC
+arbitrage:
+  enabled: %v
+  triangles:
+    - name: ETH-BTC-USDT
+      direct:
+        symbol: ETHUSDT
+      legs:
+        - symbol: ETHBTC
+        - symbol: BTCUSDT
+          operation: multiply
+`
+	if _, err := load(t, fmt.Sprintf(doc, false)); err != nil {
+		t.Fatalf("disabled feature checked: %v", err)
+	}
+	_, err := load(t, fmt.Sprintf(doc, true))
+	if want := "arbitrage.triangles[0].direct.symbol: ETHUSDT is not subscribed"; err == nil || err.Error() != want {
+		t.Fatalf("err = %v, want %s", err, want)
+	}
+}
+
+func TestValidateAlertRules(t *testing.T) {
+	_, err := load(t, base+`    subscriptions: BTCUSDT
+alerts:
+  enabled: true
+  rules:
+    - id: wide
+      symbol: BTCUSDT
+      webhookUrl: ftp://example.com/hook
+    - id: wide
+      symbol: ETHBTC
+    - symbol: BTCUSDT
+      webhookUrl: https://example.com/hook
+`)
+	if err == nil {
+		t.Fatal("expected errors")
+	}
+	for _, want := range []string{
+		"alerts.rules: configured alerts deliver to webhooks, which are not enabled",
+		`alerts.rules[0].webhookUrl: scheme "ftp" is not supported`,
+		"alerts.rules[1].id: wide is listed more than once",
+		"alerts.rules[1].webhookUrl: is required",
+		"alerts.rules[1].symbol: ETHBTC is not subscribed",
+		"alerts.rules[2].id: is required",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("missing %q in:\n%v", want, err)
+		}
+	}
+}
+
+func TestShippedConfigIsValid(t *testing.T) {
+	data, err := os.ReadFile("../../../config.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := load(t, string(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The shipped examples of opt-in features must only read subscribed symbols.
+	cfg.Consolidated.Enabled, cfg.Synthetic.Enabled, cfg.Arbitrage.Enabled = true, true, true
+	if err := cfg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWriteYAMLRoundTrips(t *testing.T) {
+	cfg, err := load(t, base+`    subscriptions:
+      - symbol: BTCUSDT
+        conflation: 250ms
+alerts:
+  webhook:
+    secret: s3cret
+    maxBackoff: 5m
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := cfg.WriteYAML(&out); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(out.String(), "s3cret") {
+		t.Fatalf("secret printed:\n%s", out.String())
+	}
+
+	again, err := load(t, out.String())
+	if err != nil {
+		t.Fatalf("printed config does not load: %v\n%s", err, out.String())
+	}
+	var reprinted bytes.Buffer
+	if err := again.WriteYAML(&reprinted); err != nil {
+		t.Fatal(err)
+	}
+	if reprinted.String() != out.String() {
+		t.Fatalf("round trip changed the config:\n%s\n%s", out.String(), reprinted.String())
+	}
+	if again.Alerts.Webhook.MaxBackoff != 5*time.Minute || again.Integrations.Binance.Subscriptions[0].Conflation != 250*time.Millisecond {
+		t.Fatalf("durations not kept: %+v", again)
+	}
+}
+
+func TestPrintRedactsSecrets(t *testing.T) {
+	cfg, err := load(t, base+`    subscriptions: BTCUSDT
+server:
+  admin:
+    token: s3cr3t-admin
+alerts:
+  webhook:
+    secret: s3cret
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var yml, js bytes.Buffer
+	if err := cfg.WriteYAML(&yml); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.WriteJSON(&js); err != nil {
+		t.Fatal(err)
+	}
+	for _, out := range []string{yml.String(), js.String()} {
+		if strings.Contains(out, "s3cr3t-admin") || strings.Contains(out, "s3cret") {
+			t.Fatalf("secret printed:\n%s", out)
+		}
+		if strings.Count(out, "REDACTED") != 2 {
+			t.Fatalf("secrets not marked as redacted:\n%s", out)
+		}
+	}
+}
+
+func TestChangesListsLeafKeys(t *testing.T) {
+	old, err := load(t, base+"    subscriptions: BTCUSDT, ETHBTC\nalerts:\n  webhook:\n    secret: a\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	new, err := load(t, base+`    subscriptions:
+      - BTCUSDT
+      - symbol: ETHBTC
+        depthLimit: 20
+      - BNBBTC
+server:
+  port: 9090
+alerts:
+  webhook:
+    secret: b
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"alerts.webhook.secret",
+		"integrations.binance.subscriptions[1].depthLimit",
+		"integrations.binance.subscriptions[2].conflation",
+		"integrations.binance.subscriptions[2].depthLimit",
+		"integrations.binance.subscriptions[2].enabled",
+		"integrations.binance.subscriptions[2].snapshotLimit",
+		"integrations.binance.subscriptions[2].symbol",
+		"server.port",
+	}
+	if got := config.Changes(old, new); !reflect.DeepEqual(got, want) {
+		t.Fatalf("changes = %v", got)
+	}
+	if got := config.Changes(old, old); len(got) != 0 {
+		t.Fatalf("changes of an unchanged config = %v", got)
+	}
+}
+
+func TestWatchReportsReplacedFile(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(path, []byte(base), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	changed := make(chan struct{}, 1)
+	if err := config.Watch(ctx, path, changed); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "other.yaml"), []byte(base), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tmp := filepath.Join(dir, "config.yaml.tmp")
+	if err := os.WriteFile(tmp, []byte(base+"    subscriptions: BTCUSDT\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-changed:
+	case <-time.After(2 * time.Second):
+		t.Fatal("no change reported")
+	}
+	select {
+	case <-changed:
+		t.Fatal("one replacement reported twice")
+	case <-time.After(300 * time.Millisecond):
+	}
+}
@@ -0,0 +1,103 @@
+package consolidated_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ChethiyaNishanath/market-data-hub/internal/bus"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/config"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/consolidated"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/domain/orderbook"
+	"github.com/ChethiyaNishanath/market-data-hub/test/testutil"
+)
+
+func TestMergeAggregatesLevelsByPrice(t *testing.T) {
+	venueA := &orderbook.OrderBook{
+		Bids: [][]string{{"100", "1"}, {"99", "2"}},
+		Asks: [][]string{{"101", "1"}},
+	}
+	venueB := &orderbook.OrderBook{
+		Bids: [][]string{{"100", "3"}},
+		Asks: [][]string{{"102", "4"}, {"101", "2"}},
+	}
+
+	book := consolidated.Merge("BTC-USDT", []consolidated.VenueInput{
+		{Venue: "a", Book: venueA},
+		{Venue: "b", Book: venueB},
+	}, 0)
+
+	if len(book.Bids) != 2 || !testutil.Approx(book.Bids[0].Price, 100) || !testutil.Approx(book.Bids[0].Quantity, 4) {
+		t.Fatalf("unexpected bids: %+v", book.Bids)
+	}
+	if len(book.Bids[0].Venues) != 2 {
+		t.Errorf("expected both venues at 100, got %+v", book.Bids[0].Venues)
+	}
+	if len(book.Asks) != 2 || !testutil.Approx(book.Asks[0].Price, 101) || !testutil.Approx(book.Asks[0].Quantity, 3) {
+		t.Fatalf("unexpected asks: %+v", book.Asks)
+	}
+}
+
+func TestMergeAppliesWeightFeeAndDepth(t *testing.T) {
+	venue := &orderbook.OrderBook{
+		Bids: [][]string{{"100", "2"}, {"99", "1"}},
+		Asks: [][]string{{"101", "2"}, {"102", "1"}},
+	}
+
+	book := consolidated.Merge("BTC-USDT", []consolidated.VenueInput{
+		{Venue: "a", Book: venue, Weight: 0.5, FeeBps: 10},
+	}, 1)
+
+	if len(book.Bids) != 1 || len(book.Asks) != 1 {
+		t.Fatalf("expected depth 1, got %d/%d", len(book.Bids), len(book.Asks))
+	}
+	if !testutil.Approx(book.Bids[0].Price, 99.9) || !testutil.Approx(book.Bids[0].Quantity, 1) {
+		t.Errorf("expected fee-adjusted bid 99.9 x 1, got %+v", book.Bids[0])
+	}
+	if !testutil.Approx(book.Asks[0].Price, 101.101) {
+		t.Errorf("expected fee-adjusted ask 101.101, got %+v", book.Asks[0])
+	}
+}
+
+func TestEngineExcludesUnsyncedVenues(t *testing.T) {
+	synced := testutil.NewBookSource("alpha", map[string]*orderbook.OrderBook{
+		"BTCUSDT": {Bids: [][]string{{"100", "1"}}, Asks: [][]string{{"101", "1"}}},
+	})
+	unsynced := testutil.NewBookSource("beta", map[string]*orderbook.OrderBook{
+		"BTCUSDT": {Bids: [][]string{{"200", "1"}}},
+	})
+	unsynced.SetSynced("BTCUSDT", false)
+
+	cfg := config.ConsolidatedConfig{
+		PublishInterval: 10 * time.Millisecond,
+		Instruments: []config.ConsolidatedInstrument{{
+			Name: "BTC-USDT",
+			Sources: []config.VenueSource{
+				{Venue: "alpha", Symbol: "BTCUSDT"},
+				{Venue: "beta", Symbol: "BTCUSDT"},
+			},
+		}},
+	}
+
+	engine := consolidated.NewEngine(cfg, bus.New(), synced, unsynced)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	books, stop := engine.Watch("btc-usdt")
+	defer stop()
+
+	go engine.Start(ctx)
+
+	select {
+	case book := <-books:
+		if len(book.ExcludedVenues) != 1 || book.ExcludedVenues[0] != "beta" {
+			t.Errorf("expected beta to be excluded, got %v", book.ExcludedVenues)
+		}
+		if len(book.Bids) != 1 || !testutil.Approx(book.Bids[0].Price, 100) {
+			t.Errorf("expected only alpha levels, got %+v", book.Bids)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("consolidated book was not published")
+	}
+}
@@ -0,0 +1,233 @@
+package export_test
+
+import (
+	"bytes"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ChethiyaNishanath/market-data-hub/internal/domain/orderbook"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/exchange/binance"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/export"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/export/parquet"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/history"
+	pq "github.com/parquet-go/parquet-go"
+)
+
+var t0 = time.Date(2026, 10, 19, 14, 3, 7, 250_000_000, time.UTC)
+
+func TestDepthRowsFlattenLevels(t *testing.T) {
+	rows := export.DepthRows("binance", "BTCUSDT", history.Delta{
+		EventTime:     t0.UnixMilli(),
+		FirstUpdateID: 5,
+		FinalUpdateID: 6,
+		Bids:          [][]string{{"100", "1"}, {"bad"}},
+		Asks:          [][]string{{"101", "0"}},
+	})
+
+	if len(rows) != 2 {
+		t.Fatalf("expected 2 rows, got %d", len(rows))
+	}
+	if rows[0][5] != "bid" || rows[0][6] != 100.0 || rows[1][5] != "ask" || rows[1][7] != 0.0 {
+		t.Errorf("unexpected rows %v", rows)
+	}
+	if len(rows[0]) != len(export.Depth.Columns) {
+		t.Errorf("row width %d does not match the schema", len(rows[0]))
+	}
+}
+
+func TestBookRowsAreBestFirstAndCut(t *testing.T) {
+	book := &orderbook.OrderBook{
+		LastUpdateID: 9,
+		Bids:         [][]string{{"99", "1"}, {"100", "2"}, {"98", "3"}},
+		Asks:         [][]string{{"102", "1"}, {"101", "2"}},
+	}
+
+	rows := export.BookRows("binance", "BTCUSDT", t0, book, 2)
+
+	if len(rows) != 4 {
+		t.Fatalf("expected 2 levels per side, got %d rows", len(rows))
+	}
+	if rows[0][6] != 100.0 || rows[0][5] != int32(0) || rows[2][6] != 101.0 {
+		t.Errorf("expected best prices first, got %v", rows)
+	}
+}
+
+func TestSinkPartitionsAndFinishesFiles(t *testing.T) {
+	root := t.TempDir()
+
+	csvSink, err := export.NewSink(root, export.Trades, export.FormatCSV, export.SinkOptions{})
+	if err != nil {
+		t.Fatalf("csv sink: %v", err)
+	}
+	parquetSink, err := export.NewSink(root, export.Trades, export.FormatParquet, export.SinkOptions{Compression: "gzip"})
+	if err != nil {
+		t.Fatalf("parquet sink: %v", err)
+	}
+
+	row, ok := export.TradeRow("binance", binance.TradeEvent{
+		Symbol: "BTCUSDT", TradeID: 7, Price: "100.5", Quantity: "0.25", Side: "buy",
+		TradeTime: t0.UnixMilli(), EventTime: t0.UnixMilli() + 1,
+	})
+	if !ok {
+		t.Fatal("expected a trade row")
+	}
+
+	for _, sink := range []*export.Sink{csvSink, parquetSink} {
+		if err := sink.Write("binance", "BTCUSDT", t0, row); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		if len(sink.Written()) != 0 {
+			t.Error("did not expect a finished file before close")
+		}
+		if err := sink.Close(); err != nil {
+			t.Fatalf("close: %v", err)
+		}
+		if len(sink.Written()) != 1 {
+			t.Fatalf("expected one finished file, got %v", sink.Written())
+		}
+	}
+
+	dir := filepath.Join(root, "trades", "v1", "date=2026-10-19", "exchange=binance", "symbol=BTCUSDT")
+	if !strings.HasPrefix(csvSink.Written()[0], dir) {
+		t.Errorf("unexpected partition %s", csvSink.Written()[0])
+	}
+
+	data, err := os.ReadFile(csvSink.Written()[0])
+	if err != nil {
+		t.Fatalf("read csv: %v", err)
+	}
+	want := "trade_time,event_time,exchange,symbol,trade_id,side,price,quantity\n" +
+		"2026-10-19T14:03:07.250Z,2026-10-19T14:03:07.251Z,binance,BTCUSDT,7,buy,100.5,0.25\n"
+	if string(data) != want {
+		t.Errorf("unexpected csv:\n%s", data)
+	}
+
+	data, err = os.ReadFile(parquetSink.Written()[0])
+	if err != nil {
+		t.Fatalf("read parquet: %v", err)
+	}
+	if !bytes.HasPrefix(data, []byte("PAR1")) || !bytes.HasSuffix(data, []byte("PAR1")) {
+		t.Error("expected parquet magic at both ends")
+	}
+	if !bytes.Contains(data, []byte("trades/v1")) {
+		t.Error("expected the schema version in the footer")
+	}
+}
+
+func TestSinkRollsOldFiles(t *testing.T) {
+	sink, err := export.NewSink(t.TempDir(), export.Depth, export.FormatCSV, export.SinkOptions{RollInterval: time.Minute})
+	if err != nil {
+		t.Fatalf("sink: %v", err)
+	}
+
+	row := []any{t0.UnixMilli(), "binance", "BTCUSDT", int64(1), int64(1), "bid", 1.0, 1.0}
+	if err := sink.Write("binance", "BTCUSDT", t0, row); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	_ = sink.Roll(time.Now())
+	if len(sink.Written()) != 0 {
+		t.Error("did not expect a fresh file to roll")
+	}
+
+	_ = sink.Roll(time.Now().Add(time.Minute))
+	if len(sink.Written()) != 1 {
+		t.Errorf("expected the file to roll, got %v", sink.Written())
+	}
+}
+
+func TestParquetWriterRejectsMismatchedRow(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := parquet.NewWriter(&buf, export.Depth.Columns, parquet.Options{})
+	if err != nil {
+		t.Fatalf("writer: %v", err)
+	}
+
+	if err := w.Write(t0.UnixMilli(), "binance", "BTCUSDT", int64(1), int64(1), "bid", "1", 1.0); err == nil {
+		t.Error("expected a string price to be rejected")
+	}
+	if err := w.Write(t0.UnixMilli(), "binance", "BTCUSDT", int64(1), int64(1), "bid", 1.0, 1.0); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if w.Rows() != 1 {
+		t.Errorf("expected only the valid row, got %d", w.Rows())
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+}
+
+func TestParquetFilesRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := parquet.NewWriter(&buf, export.Book.Columns, parquet.Options{
+		Codec:        parquet.Gzip,
+		RowGroupSize: 2,
+		Metadata:     map[string]string{"mdh.schema": export.Book.String()},
+	})
+	if err != nil {
+		t.Fatalf("writer: %v", err)
+	}
+	rows := [][]any{
+		{t0, "binance", "BTCUSDT", int64(9), "bid", 0, 100.5, 1.0},
+		{t0.UnixMilli(), "binance", "BTCUSDT", int64(9), "bid", int32(1), 100.0, 2.0},
+		{t0, "binance", "BTCUSDT", int64(9), "ask", 0, 101.0, 0.5},
+	}
+	for _, row := range rows {
+		if err := w.Write(row...); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	f, err := pq.OpenFile(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	if f.NumRows() != 3 || len(f.RowGroups()) != 2 {
+		t.Errorf("expected 3 rows in 2 row groups, got %d in %d", f.NumRows(), len(f.RowGroups()))
+	}
+	if v, ok := f.Lookup("mdh.schema"); !ok || v != export.Book.String() {
+		t.Errorf("expected the schema version in the footer, got %q", v)
+	}
+
+	fields := f.Schema().Fields()
+	if len(fields) != len(export.Book.Columns) {
+		t.Fatalf("expected %d columns, got %d", len(export.Book.Columns), len(fields))
+	}
+	for i, col := range export.Book.Columns {
+		if fields[i].Name() != col.Name {
+			t.Errorf("column %d is %s, want %s", i, fields[i].Name(), col.Name)
+		}
+	}
+	if typ := fields[0].Type().String(); !strings.Contains(typ, "TIMESTAMP") || !strings.Contains(typ, "MILLIS") {
+		t.Errorf("expected a millisecond timestamp, got %s", typ)
+	}
+
+	read := make([]pq.Row, 3)
+	n, _ := pq.NewReader(f).ReadRows(read)
+	if n != 3 {
+		t.Fatalf("read %d rows", n)
+	}
+	last := read[2]
+	if last[0].Int64() != t0.UnixMilli() || last[4].String() != "ask" || last[5].Int32() != 0 || last[6].Double() != 101.0 {
+		t.Errorf("unexpected row %v", last)
+	}
+	if read[1][5].Int32() != 1 || read[1][7].Double() != 2.0 {
+		t.Errorf("unexpected row %v", read[1])
+	}
+}
+
+func TestParquetWriterRejectsOversizedRowGroups(t *testing.T) {
+	if math.MaxInt == math.MaxInt32 {
+		t.Skip("int cannot exceed int32")
+	}
+	if _, err := parquet.NewWriter(&bytes.Buffer{}, export.Depth.Columns, parquet.Options{RowGroupSize: math.MaxInt32 + 1}); err == nil {
+		t.Error("expected a row group size above int32 to be rejected")
+	}
+}
@@ -0,0 +1,137 @@
+package health_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ChethiyaNishanath/market-data-hub/internal/health"
+)
+
+type fakeReporter struct {
+	statuses []health.SymbolStatus
+}
+
+func (f *fakeReporter) Health() []health.SymbolStatus {
+	return f.statuses
+}
+
+func TestReadinessAllSynchronized(t *testing.T) {
+	reporter := &fakeReporter{statuses: []health.SymbolStatus{
+		{Exchange: "binance", Symbol: "BTCUSDT", Connected: true, Synchronized: true, LastUpdate: time.Now()},
+	}}
+
+	handler := health.NewHandler(30*time.Second, reporter)
+
+	rec := httptest.NewRecorder()
+	handler.HandleReadiness(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+
+	var report health.Report
+	if err := json.NewDecoder(rec.Body).Decode(&report); err != nil {
+		t.Fatalf("invalid body: %v", err)
+	}
+
+	if len(report.Symbols) != 1 || !report.Symbols[0].Ready {
+		t.Errorf("expected BTCUSDT to be ready, got %+v", report.Symbols)
+	}
+}
+
+func TestReadinessUnsyncedPastThreshold(t *testing.T) {
+	reporter := &fakeReporter{statuses: []health.SymbolStatus{
+		{Exchange: "binance", Symbol: "BTCUSDT", Synchronized: true, LastUpdate: time.Now()},
+		{Exchange: "binance", Symbol: "BNBBTC", UnsyncedSince: time.Now().Add(-time.Minute), LastResyncReason: "snapshot load failed"},
+	}}
+
+	handler := health.NewHandler(30*time.Second, reporter)
+
+	rec := httptest.NewRecorder()
+	handler.HandleReadiness(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503, got %d", rec.Code)
+	}
+
+	var report health.Report
+	if err := json.NewDecoder(rec.Body).Decode(&report); err != nil {
+		t.Fatalf("invalid body: %v", err)
+	}
+
+	if report.Status != health.StatusDegraded {
+		t.Errorf("expected degraded status, got %s", report.Status)
+	}
+	if report.Symbols[1].Ready {
+		t.Errorf("expected BNBBTC not ready")
+	}
+	if report.Symbols[1].LastResyncReason != "snapshot load failed" {
+		t.Errorf("expected resync reason to be reported, got %q", report.Symbols[1].LastResyncReason)
+	}
+}
+
+func TestReadinessUnsyncedWithinThreshold(t *testing.T) {
+	reporter := &fakeReporter{statuses: []health.SymbolStatus{
+		{Exchange: "binance", Symbol: "BTCUSDT", UnsyncedSince: time.Now()},
+	}}
+
+	handler := health.NewHandler(30*time.Second, reporter)
+
+	rec := httptest.NewRecorder()
+	handler.HandleReadiness(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 during grace period, got %d", rec.Code)
+	}
+}
+
+func TestReadinessStaleBook(t *testing.T) {
+	reporter := &fakeReporter{statuses: []health.SymbolStatus{
+		{Exchange: "binance", Symbol: "BTCUSDT", Connected: true, Synchronized: true, LastUpdate: time.Now().Add(-2 * time.Minute)},
+	}}
+
+	handler := health.NewHandler(30*time.Second, reporter)
+
+	rec := httptest.NewRecorder()
+	handler.HandleReadiness(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 for stale book, got %d", rec.Code)
+	}
+}
+
+func TestLivenessAlwaysOK(t *testing.T) {
+	reporter := &fakeReporter{statuses: []health.SymbolStatus{
+		{Exchange: "binance", Symbol: "BTCUSDT", UnsyncedSince: time.Now().Add(-time.Hour)},
+	}}
+
+	handler := health.NewHandler(30*time.Second, reporter)
+
+	rec := httptest.NewRecorder()
+	handler.HandleLiveness(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+}
+
+func TestReadinessDegradedOnlyPastThreshold(t *testing.T) {
+	reporter := &fakeReporter{statuses: []health.SymbolStatus{
+		{Exchange: "binance", Symbol: "BTCUSDT", Degraded: true, UnsyncedSince: time.Now(), LastResyncReason: "snapshot load failed"},
+	}}
+
+	handler := health.NewHandler(50*time.Millisecond, reporter)
+
+	if report := handler.Evaluate(); report.Status != health.StatusOK || !report.Symbols[0].Ready {
+		t.Fatalf("expected a freshly degraded symbol to stay ready, got %+v", report)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	if report := handler.Evaluate(); report.Status != health.StatusDegraded || report.Symbols[0].Ready {
+		t.Fatalf("expected the symbol not ready once degraded past the threshold, got %+v", report)
+	}
+}
@@ -0,0 +1,173 @@
+package history_test
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ChethiyaNishanath/market-data-hub/internal/config"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/domain/orderbook"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/exchange/binance"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/history"
+	"github.com/ChethiyaNishanath/market-data-hub/test/testutil"
+)
+
+var t0 = time.UnixMilli(1_760_000_000_000)
+
+func openStore(t *testing.T) *history.Store {
+	t.Helper()
+	store, err := history.Open(filepath.Join(t.TempDir(), "history.db"))
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	return store
+}
+
+func at(ms int) time.Time {
+	return t0.Add(time.Duration(ms) * time.Millisecond)
+}
+
+func seed(t *testing.T, store *history.Store) {
+	t.Helper()
+	book := &orderbook.OrderBook{
+		LastUpdateID: 10,
+		Bids:         [][]string{{"100", "1"}, {"99", "4"}},
+		Asks:         [][]string{{"101", "1"}},
+	}
+	if err := store.SaveCheckpoint("binance", "BTCUSDT", t0, book); err != nil {
+		t.Fatalf("checkpoint: %v", err)
+	}
+
+	err := store.Append("binance", "BTCUSDT",
+		// Already part of the checkpoint, journaled just before it was taken.
+		history.Delta{EventTime: at(-50).UnixMilli(), FirstUpdateID: 9, FinalUpdateID: 10, Bids: [][]string{{"100", "9"}}},
+		history.Delta{EventTime: at(100).UnixMilli(), FirstUpdateID: 11, FinalUpdateID: 12, Bids: [][]string{{"100", "2"}}},
+		history.Delta{EventTime: at(200).UnixMilli(), FirstUpdateID: 13, FinalUpdateID: 13, Asks: [][]string{{"101", "0"}, {"102", "3"}}},
+	)
+	if err != nil {
+		t.Fatalf("append: %v", err)
+	}
+}
+
+func TestRebuildsBookAtPastInstant(t *testing.T) {
+	store := openStore(t)
+	seed(t, store)
+
+	snap, err := store.At("binance", "BTCUSDT", at(150), 0)
+	if err != nil {
+		t.Fatalf("at: %v", err)
+	}
+	if snap.LastUpdateID != 12 || snap.DeltasApplied != 1 || !snap.Complete {
+		t.Fatalf("unexpected snapshot %+v", snap)
+	}
+	if snap.Bids[0][0] != "100" || snap.Bids[0][1] != "2" || snap.Asks[0][0] != "101" {
+		t.Errorf("unexpected levels bids=%v asks=%v", snap.Bids, snap.Asks)
+	}
+
+	snap, err = store.At("binance", "BTCUSDT", at(250), 1)
+	if err != nil {
+		t.Fatalf("at: %v", err)
+	}
+	if len(snap.Bids) != 1 || len(snap.Asks) != 1 || snap.Asks[0][0] != "102" {
+		t.Errorf("expected the 101 ask removed and depth applied, got bids=%v asks=%v", snap.Bids, snap.Asks)
+	}
+
+	if _, err := store.At("binance", "BTCUSDT", at(-1), 0); !errors.Is(err, history.ErrNoHistory) {
+		t.Errorf("expected ErrNoHistory before the first checkpoint, got %v", err)
+	}
+}
+
+func TestGapIsFlaggedUntilNextCheckpoint(t *testing.T) {
+	store := openStore(t)
+	seed(t, store)
+
+	_ = store.Append("binance", "BTCUSDT",
+		history.Delta{EventTime: at(300).UnixMilli(), FirstUpdateID: 20, FinalUpdateID: 21, Bids: [][]string{{"98", "1"}}})
+	_ = store.SaveCheckpoint("binance", "BTCUSDT", at(1000), &orderbook.OrderBook{
+		LastUpdateID: 30,
+		Bids:         [][]string{{"97", "1"}},
+		Asks:         [][]string{{"103", "1"}},
+	})
+
+	var samples []history.Snapshot
+	err := history.NewReader(store, "binance").Range("btcusdt", at(-500), at(1000), 500*time.Millisecond, 0, func(s history.Snapshot) error {
+		samples = append(samples, s)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("range: %v", err)
+	}
+
+	// The sample before the first checkpoint is skipped.
+	if len(samples) != 3 {
+		t.Fatalf("expected 3 samples, got %d", len(samples))
+	}
+	if !samples[0].Complete {
+		t.Errorf("expected the first sample to be complete, got %+v", samples[0])
+	}
+	if samples[1].Complete || samples[1].LastUpdateID != 21 {
+		t.Errorf("expected the gap to be flagged, got %+v", samples[1])
+	}
+	if !samples[2].Complete || samples[2].LastUpdateID != 30 || samples[2].CheckpointTime != at(1000).UnixMilli() {
+		t.Errorf("expected a rebase on the newer checkpoint, got %+v", samples[2])
+	}
+}
+
+func TestRangeRejectsTooManySamples(t *testing.T) {
+	store := openStore(t)
+	seed(t, store)
+
+	err := history.NewReader(store, "binance").Range("BTCUSDT", t0, t0.Add(time.Hour), time.Millisecond, 0, func(history.Snapshot) error { return nil })
+	if !errors.Is(err, history.ErrInvalidRange) {
+		t.Errorf("expected ErrInvalidRange, got %v", err)
+	}
+}
+
+func TestPruneKeepsReachableJournal(t *testing.T) {
+	store := openStore(t)
+	seed(t, store)
+	_ = store.SaveCheckpoint("binance", "BTCUSDT", at(60_000), &orderbook.OrderBook{LastUpdateID: 13})
+
+	if err := store.Prune(at(30_000)); err != nil {
+		t.Fatalf("prune: %v", err)
+	}
+
+	if _, err := store.At("binance", "BTCUSDT", at(150), 0); !errors.Is(err, history.ErrNoHistory) {
+		t.Errorf("expected the old checkpoint to be pruned, got %v", err)
+	}
+	if snap, err := store.At("binance", "BTCUSDT", at(60_000), 0); err != nil || snap.LastUpdateID != 13 {
+		t.Errorf("expected the newer checkpoint to remain, got %+v %v", snap, err)
+	}
+}
+
+func TestRecorderJournalsBufferedUpdates(t *testing.T) {
+	store := openStore(t)
+	source := testutil.NewBookSource("", map[string]*orderbook.OrderBook{
+		"BTCUSDT": {LastUpdateID: 1, Bids: [][]string{{"100", "1"}}},
+	})
+
+	recorder := history.NewRecorder(config.HistoryConfig{}, store, nil, source)
+	recorder.CheckpointAll()
+	recorder.Record("BTCUSDT", binance.DepthUpdateEvent{
+		EventTime:          int(time.Now().UnixMilli()),
+		FirstUpdateEventID: 2,
+		FinalUpdateEventID: 2,
+		BidsToUpdated:      [][]string{{"100", "5"}},
+	})
+
+	if snap, _ := store.At("binance", "BTCUSDT", time.Now(), 0); snap.LastUpdateID != 1 {
+		t.Fatalf("expected the update to stay buffered until flushed, got %+v", snap)
+	}
+
+	recorder.Flush()
+
+	snap, err := store.At("binance", "BTCUSDT", time.Now().Add(time.Second), 0)
+	if err != nil {
+		t.Fatalf("at: %v", err)
+	}
+	if snap.LastUpdateID != 2 || snap.Bids[0][1] != "5" {
+		t.Errorf("unexpected snapshot %+v", snap)
+	}
+}
@@ -0,0 +1,115 @@
+package impact_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ChethiyaNishanath/market-data-hub/internal/domain/orderbook"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/impact"
+	"github.com/ChethiyaNishanath/market-data-hub/test/testutil"
+)
+
+func newCalculator() *impact.Calculator {
+	return impact.NewCalculator(testutil.NewBookSource("", map[string]*orderbook.OrderBook{
+		"BTCUSDT": {
+			LastUpdateID: 7,
+			Bids:         [][]string{{"99", "1"}, {"98", "2"}},
+			Asks:         [][]string{{"101", "1"}, {"102", "2"}, {"103", "5"}},
+		},
+	}), nil)
+}
+
+func TestEstimateBuyByQuantity(t *testing.T) {
+	result, err := newCalculator().Estimate(impact.Request{Symbol: "btcusdt", Side: "buy", Quantity: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !result.FullyFilled || result.LevelsConsumed != 2 {
+		t.Fatalf("unexpected fill %+v", result)
+	}
+	if !testutil.Approx(result.AveragePrice, 101.5) || result.WorstPrice != 102 {
+		t.Errorf("unexpected prices avg=%v worst=%v", result.AveragePrice, result.WorstPrice)
+	}
+	if !testutil.Approx(result.SlippageBps, 1.5/100*10_000) {
+		t.Errorf("unexpected slippage %v", result.SlippageBps)
+	}
+	if result.LastUpdateID != 7 || result.Source != "binance" {
+		t.Errorf("unexpected source %+v", result)
+	}
+}
+
+func TestEstimateSellByNotional(t *testing.T) {
+	result, err := newCalculator().Estimate(impact.Request{Symbol: "BTCUSDT", Side: "sell", Notional: 197})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !result.FullyFilled || !testutil.Approx(result.FilledQuantity, 2) || !testutil.Approx(result.FilledNotional, 197) {
+		t.Fatalf("unexpected fill %+v", result)
+	}
+	if !testutil.Approx(result.SlippageBps, 1.5/100*10_000) {
+		t.Errorf("expected positive slippage for a sell below mid, got %v", result.SlippageBps)
+	}
+}
+
+func TestEstimatePartialFill(t *testing.T) {
+	result, err := newCalculator().Estimate(impact.Request{Symbol: "BTCUSDT", Side: "sell", Quantity: 10})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.FullyFilled || result.FilledQuantity != 3 || result.LevelsConsumed != 2 {
+		t.Errorf("unexpected partial fill %+v", result)
+	}
+}
+
+func TestEstimateValidation(t *testing.T) {
+	calc := newCalculator()
+
+	cases := []struct {
+		name string
+		req  impact.Request
+		want error
+	}{
+		{"both sizes", impact.Request{Symbol: "BTCUSDT", Side: "buy", Quantity: 1, Notional: 1}, impact.ErrInvalidRequest},
+		{"bad side", impact.Request{Symbol: "BTCUSDT", Side: "hold", Quantity: 1}, impact.ErrInvalidRequest},
+		{"unsynced", impact.Request{Symbol: "ETHUSDT", Side: "buy", Quantity: 1}, impact.ErrBookUnavailable},
+		{"no consolidated", impact.Request{Symbol: "BTC-USDT", Side: "buy", Quantity: 1, Consolidated: true}, impact.ErrNoConsolidated},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := calc.Estimate(tc.req); !errors.Is(err, tc.want) {
+				t.Errorf("expected %v, got %v", tc.want, err)
+			}
+		})
+	}
+}
+
+func TestHandleEstimate(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/impact?symbol=BTCUSDT&side=buy&quantity=1", nil)
+
+	newCalculator().HandleEstimate(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var result impact.Result
+	if err := json.NewDecoder(rec.Body).Decode(&result); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if result.AveragePrice != 101 {
+		t.Errorf("unexpected average price %v", result.AveragePrice)
+	}
+
+	rec = httptest.NewRecorder()
+	newCalculator().HandleEstimate(rec, httptest.NewRequest(http.MethodGet, "/impact?symbol=BTCUSDT&side=buy", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 without a size, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,117 @@
+package binance_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ChethiyaNishanath/market-data-hub/internal/exchange/binance"
+)
+
+func depthUpdate(first, final int) binance.DepthUpdateMessage {
+	return binance.DepthUpdateMessage{
+		EventType:          binance.OrderBookUpdate,
+		Symbol:             "BTCUSDT",
+		FirstUpdateEventID: first,
+		FinalUpdateEventID: final,
+	}
+}
+
+func newArbiter(out chan binance.DepthUpdateMessage, gapTimeout time.Duration) *binance.FeedArbiter {
+	return binance.NewFeedArbiter("BTCUSDT", map[string]string{
+		binance.PrimaryLeg:   "wss://stream.binance.com:9443/ws",
+		binance.SecondaryLeg: "wss://data-stream.binance.vision/ws",
+	}, out, gapTimeout)
+}
+
+func drain(out chan binance.DepthUpdateMessage) []int {
+	ids := make([]int, 0)
+	for {
+		select {
+		case update := <-out:
+			ids = append(ids, update.FinalUpdateEventID)
+		default:
+			return ids
+		}
+	}
+}
+
+func TestArbiterForwardsEachUpdateOnce(t *testing.T) {
+	out := make(chan binance.DepthUpdateMessage, 10)
+	arbiter := newArbiter(out, time.Second)
+
+	arbiter.Offer(binance.PrimaryLeg, depthUpdate(1, 5))
+	arbiter.Offer(binance.SecondaryLeg, depthUpdate(1, 5))
+	arbiter.Offer(binance.SecondaryLeg, depthUpdate(6, 9))
+	arbiter.Offer(binance.PrimaryLeg, depthUpdate(6, 9))
+
+	got := drain(out)
+	if len(got) != 2 || got[0] != 5 || got[1] != 9 {
+		t.Fatalf("expected [5 9], got %v", got)
+	}
+}
+
+func TestArbiterFillsGapFromOtherLeg(t *testing.T) {
+	out := make(chan binance.DepthUpdateMessage, 10)
+	arbiter := newArbiter(out, time.Second)
+
+	arbiter.Offer(binance.PrimaryLeg, depthUpdate(1, 5))
+	// Leg A missed 6-9 and jumps ahead.
+	arbiter.Offer(binance.PrimaryLeg, depthUpdate(10, 12))
+	// Leg B delivers the missing update.
+	arbiter.Offer(binance.SecondaryLeg, depthUpdate(6, 9))
+
+	got := drain(out)
+	if len(got) != 3 || got[0] != 5 || got[1] != 9 || got[2] != 12 {
+		t.Fatalf("expected [5 9 12], got %v", got)
+	}
+}
+
+func TestArbiterForwardsGapAfterTimeout(t *testing.T) {
+	out := make(chan binance.DepthUpdateMessage, 10)
+	arbiter := newArbiter(out, 20*time.Millisecond)
+
+	arbiter.Offer(binance.PrimaryLeg, depthUpdate(1, 5))
+	arbiter.Offer(binance.PrimaryLeg, depthUpdate(10, 12))
+
+	if got := drain(out); len(got) != 1 {
+		t.Fatalf("expected gap to be held, got %v", got)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	got := drain(out)
+	if len(got) != 1 || got[0] != 12 {
+		t.Fatalf("expected held update after timeout, got %v", got)
+	}
+}
+
+func TestArbiterReportsLeadingLeg(t *testing.T) {
+	out := make(chan binance.DepthUpdateMessage, 10)
+	arbiter := newArbiter(out, time.Second)
+
+	arbiter.SetConnected(binance.PrimaryLeg, true)
+	arbiter.SetConnected(binance.SecondaryLeg, true)
+
+	arbiter.Offer(binance.SecondaryLeg, depthUpdate(1, 5))
+	arbiter.Offer(binance.PrimaryLeg, depthUpdate(1, 5))
+	arbiter.Offer(binance.SecondaryLeg, depthUpdate(6, 9))
+	arbiter.Offer(binance.PrimaryLeg, depthUpdate(6, 9))
+
+	feeds := arbiter.Status()
+	if len(feeds) != 2 {
+		t.Fatalf("expected 2 legs, got %d", len(feeds))
+	}
+
+	for _, feed := range feeds {
+		switch feed.Leg {
+		case binance.PrimaryLeg:
+			if feed.Leading || feed.Duplicates != 2 {
+				t.Errorf("expected leg A trailing with 2 duplicates, got %+v", feed)
+			}
+		case binance.SecondaryLeg:
+			if !feed.Leading || feed.FirstArrivals != 2 {
+				t.Errorf("expected leg B leading with 2 first arrivals, got %+v", feed)
+			}
+		}
+	}
+}
@@ -16,7 +16,7 @@ func TestNewBinanceModule(t *testing.T) {
 	cfg := config.BinanceConfig{
 		WsStreamUrl:   "ws://localhost:8080/ws",
 		RestApiUrlV3:  "http://localhost:8080/rest",
-		Subscriptions: "BNBBTC,BTCUSDT",
+		Subscriptions: []config.SymbolConfig{{Symbol: "BNBBTC", Enabled: true}, {Symbol: "BTCUSDT", Enabled: true}},
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(time.Second*1)*time.Second)
@@ -0,0 +1,66 @@
+package binance_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	events "github.com/ChethiyaNishanath/market-data-hub/internal/bus"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/checkpoint"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/config"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/exchange/binance"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/simulator"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/subcription"
+)
+
+func TestCheckpointKeepsLevelsBeyondDepthLimit(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	exchange := simulator.New(simulator.Config{Symbols: []string{"BTCUSDT"}, Rate: 50, Levels: 20, Seed: 5})
+	go exchange.Run(ctx)
+	srv := httptest.NewServer(exchange.Handler())
+	defer srv.Close()
+
+	store, err := checkpoint.Open(filepath.Join(t.TempDir(), "checkpoints.db"))
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer store.Close()
+
+	cfg := config.BinanceConfig{
+		WsStreamUrl:   "ws" + strings.TrimPrefix(srv.URL, "http") + "/ws",
+		RestApiUrlV3:  srv.URL + "/api/v3",
+		Subscriptions: []config.SymbolConfig{{Symbol: "BTCUSDT", Enabled: true, DepthLimit: 5}},
+	}
+
+	firstCtx, stopFirst := context.WithCancel(ctx)
+	first := binance.NewService(firstCtx, events.New(), subcription.NewConnectionManager(), cfg)
+	go first.Start(firstCtx)
+	waitFor(t, "BTCUSDT to sync", func() bool { return first.IsSynchronized("BTCUSDT") })
+
+	checkpoint.NewWriter(store, 0, first).SaveAll()
+	stopFirst()
+
+	saved, _, ok := store.Load(binance.ExchangeName, "BTCUSDT")
+	if !ok || len(saved.Bids) <= 5 || len(saved.Asks) <= 5 {
+		t.Fatalf("checkpoint cut to the depth limit: %+v", saved)
+	}
+
+	// Without a REST snapshot the book can only come from the checkpoint.
+	cfg.RestApiUrlV3 = "http://127.0.0.1:1/api/v3"
+	second := binance.NewService(ctx, events.New(), subcription.NewConnectionManager(), cfg)
+	second.UseCheckpoints(store, 0)
+	go second.Start(ctx)
+	waitFor(t, "the warm start", func() bool { return second.FullOrderBook("BTCUSDT") != nil })
+
+	restored := second.FullOrderBook("BTCUSDT")
+	if restored.LastUpdateID < saved.LastUpdateID || len(restored.Bids) <= 5 || len(restored.Asks) <= 5 {
+		t.Fatalf("warm start lost levels: saved %d/%d, restored %+v", len(saved.Bids), len(saved.Asks), restored)
+	}
+	if book := second.GetOrderBook("BTCUSDT"); book == nil || len(book.Bids) > 5 || len(book.Asks) > 5 {
+		t.Fatalf("published book not limited to 5 levels: %+v", book)
+	}
+}
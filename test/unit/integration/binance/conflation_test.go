@@ -0,0 +1,94 @@
+package binance_test
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ChethiyaNishanath/market-data-hub/internal/bus"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/config"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/domain/subscription"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/exchange/binance"
+)
+
+// syncBus delivers events in the order they are published.
+type syncBus struct {
+	subscribers map[string][]bus.Subscriber
+}
+
+func (b *syncBus) Publish(action, topic string, data any) {
+	b.PublishContext(context.Background(), action, topic, data)
+}
+
+func (b *syncBus) PublishContext(ctx context.Context, action, topic string, data any) {
+	for _, sub := range b.subscribers[topic] {
+		sub(bus.Event{Action: action, Topic: topic, Data: data, Context: ctx})
+	}
+}
+
+func (b *syncBus) Subscribe(topic string, fn bus.Subscriber) {
+	if b.subscribers == nil {
+		b.subscribers = make(map[string][]bus.Subscriber)
+	}
+	b.subscribers[topic] = append(b.subscribers[topic], fn)
+}
+
+// depthRecorder keeps the depth updates broadcast to clients.
+type depthRecorder struct {
+	subscription.ClientConnectionManager
+	mu      sync.Mutex
+	updates []binance.DepthUpdateEvent
+}
+
+func (r *depthRecorder) Broadcast(string, any) {}
+
+func (r *depthRecorder) BroadcastContext(_ context.Context, _ string, msg any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.updates = append(r.updates, msg.(binance.WSMessage).Data.(binance.DepthUpdateEvent))
+}
+
+func (r *depthRecorder) ids() [][2]int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ids := make([][2]int, len(r.updates))
+	for i, u := range r.updates {
+		ids[i] = [2]int{u.FirstUpdateEventID, u.FinalUpdateEventID}
+	}
+	return ids
+}
+
+func TestConflationMergesOnlyContiguousUpdates(t *testing.T) {
+	cfg := config.BinanceConfig{
+		Subscriptions: []config.SymbolConfig{{Symbol: "BTCUSDT", Enabled: true, Conflation: 50 * time.Millisecond}},
+	}
+	eventBus := &syncBus{}
+	clients := &depthRecorder{}
+	service := binance.NewService(context.Background(), eventBus, clients, cfg)
+	service.RegisterEventSubscribers(cfg, clients, eventBus)
+
+	update := func(first, final int, bid string) {
+		eventBus.Publish(binance.OrderBookUpdate, "btcusdt@depth", binance.DepthUpdateEvent{
+			Symbol:             "BTCUSDT",
+			FirstUpdateEventID: first,
+			FinalUpdateEventID: final,
+			BidsToUpdated:      [][]string{{"100", bid}},
+		})
+	}
+	update(1, 1, "1")
+	update(2, 2, "2")
+	update(4, 4, "4") // gap after 2
+	update(3, 3, "3") // out of order
+	update(5, 6, "6")
+
+	want := [][2]int{{1, 2}, {4, 4}, {3, 3}, {5, 6}}
+	waitFor(t, "the last window to flush", func() bool { return len(clients.ids()) == len(want) })
+	if got := clients.ids(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("broadcast updates %v, want %v", got, want)
+	}
+	if bids := clients.updates[0].BidsToUpdated; !reflect.DeepEqual(bids, [][]string{{"100", "2"}}) {
+		t.Errorf("merged bids = %v", bids)
+	}
+}
@@ -0,0 +1,79 @@
+package binance_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ChethiyaNishanath/market-data-hub/internal/config"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/domain/orderbook"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/exchange/binance"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/simulator"
+	local "github.com/ChethiyaNishanath/market-data-hub/pkg/orderbook"
+)
+
+func TestDepthLimitedStreamMatchesPublishedBook(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	exchangeCtx, stopExchange := context.WithCancel(ctx)
+	exchange := simulator.New(simulator.Config{Symbols: []string{"BTCUSDT"}, Rate: 200, Levels: 20, LevelsPerUpdate: 8, Seed: 11})
+	go exchange.Run(exchangeCtx)
+	srv := httptest.NewServer(exchange.Handler())
+	defer srv.Close()
+
+	cfg := config.BinanceConfig{
+		WsStreamUrl:   "ws" + strings.TrimPrefix(srv.URL, "http") + "/ws",
+		RestApiUrlV3:  srv.URL + "/api/v3",
+		Subscriptions: []config.SymbolConfig{{Symbol: "BTCUSDT", Enabled: true, DepthLimit: 3}},
+	}
+	eventBus := &syncBus{}
+	clients := &depthRecorder{}
+	service := binance.NewService(ctx, eventBus, clients, cfg)
+	service.RegisterEventSubscribers(cfg, clients, eventBus)
+	go service.Start(ctx)
+	waitFor(t, "BTCUSDT to sync", func() bool { return service.IsSynchronized("BTCUSDT") })
+
+	snapshot := service.GetOrderBook("BTCUSDT")
+	book := local.New("BTCUSDT", local.Options{})
+	book.Load(local.Snapshot{LastUpdateID: int64(snapshot.LastUpdateID), Bids: snapshot.Bids, Asks: snapshot.Asks})
+
+	time.Sleep(300 * time.Millisecond)
+	stopExchange()
+	waitFor(t, "the stream to settle", func() bool {
+		ids := clients.ids()
+		return len(ids) > 0 && ids[len(ids)-1][1] == service.GetOrderBook("BTCUSDT").LastUpdateID
+	})
+
+	clients.mu.Lock()
+	for _, u := range clients.updates {
+		book.Apply(local.Update{FirstUpdateID: int64(u.FirstUpdateEventID), FinalUpdateID: int64(u.FinalUpdateEventID), Bids: u.BidsToUpdated, Asks: u.AsksToUpdated})
+	}
+	clients.mu.Unlock()
+
+	published := service.GetOrderBook("BTCUSDT")
+	if book.LastUpdateID() != int64(published.LastUpdateID) {
+		t.Fatalf("local book at %d, hub at %d", book.LastUpdateID(), published.LastUpdateID)
+	}
+	if got, want := levels(book.Bids(0)), orderbook.ParseLevels(published.Bids); !reflect.DeepEqual(got, want) {
+		t.Errorf("bids %v, hub published %v", got, want)
+	}
+	if got, want := levels(book.Asks(0)), orderbook.ParseLevels(published.Asks); !reflect.DeepEqual(got, want) {
+		t.Errorf("asks %v, hub published %v", got, want)
+	}
+	if full := service.FullOrderBook("BTCUSDT"); len(full.Bids) <= 3 {
+		t.Fatalf("full book has %d bids", len(full.Bids))
+	}
+}
+
+// levels converts the local book's levels for comparison with the hub's.
+func levels(in []local.Level) []orderbook.Level {
+	out := make([]orderbook.Level, len(in))
+	for i, l := range in {
+		out[i] = orderbook.Level{Price: l.Price, Quantity: l.Quantity}
+	}
+	return out
+}
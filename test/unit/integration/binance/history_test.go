@@ -0,0 +1,63 @@
+package binance_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	events "github.com/ChethiyaNishanath/market-data-hub/internal/bus"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/config"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/exchange/binance"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/history"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/simulator"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/subcription"
+)
+
+func TestHistoryKeepsLevelsBeyondDepthLimit(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	exchange := simulator.New(simulator.Config{Symbols: []string{"BTCUSDT"}, Rate: 5, Levels: 20, Seed: 7})
+	go exchange.Run(ctx)
+	srv := httptest.NewServer(exchange.Handler())
+	defer srv.Close()
+
+	store, err := history.Open(filepath.Join(t.TempDir(), "history.db"))
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer store.Close()
+
+	cfg := config.BinanceConfig{
+		WsStreamUrl:   "ws" + strings.TrimPrefix(srv.URL, "http") + "/ws",
+		RestApiUrlV3:  srv.URL + "/api/v3",
+		Subscriptions: []config.SymbolConfig{{Symbol: "BTCUSDT", Enabled: true, DepthLimit: 5}},
+	}
+	eventBus := events.New()
+	service := binance.NewService(ctx, eventBus, subcription.NewConnectionManager(), cfg)
+
+	// Started before the book syncs, so the only checkpoint comes from the reset.
+	recorder := history.NewRecorder(config.HistoryConfig{CheckpointInterval: time.Hour}, store, eventBus, service)
+	go recorder.Run(ctx)
+	go service.Start(ctx)
+	waitFor(t, "BTCUSDT to sync", func() bool { return service.IsSynchronized("BTCUSDT") })
+
+	service.RequestResync("BTCUSDT", "test")
+	var snap history.Snapshot
+	waitFor(t, "the reset checkpoint", func() bool {
+		snap, err = store.At(binance.ExchangeName, "BTCUSDT", time.Now(), 0)
+		return err == nil
+	})
+	if len(snap.Bids) <= 10 || len(snap.Asks) <= 10 {
+		t.Fatalf("reset checkpoint cut to the depth limit: %d bids, %d asks", len(snap.Bids), len(snap.Asks))
+	}
+
+	recorder.CheckpointAll()
+	snap, err = store.At(binance.ExchangeName, "BTCUSDT", time.Now(), 0)
+	if err != nil || len(snap.Bids) <= 10 || len(snap.Asks) <= 10 {
+		t.Fatalf("checkpoint cut to the depth limit: %d bids, %d asks (%v)", len(snap.Bids), len(snap.Asks), err)
+	}
+}
@@ -0,0 +1,65 @@
+package binance_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	events "github.com/ChethiyaNishanath/market-data-hub/internal/bus"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/config"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/exchange/binance"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/simulator"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/subcription"
+)
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestReconfigureAddsAndRemovesSymbolsLive(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	exchange := simulator.New(simulator.Config{Symbols: []string{"BTCUSDT", "ETHBTC"}, Rate: 50, Levels: 20, Seed: 3})
+	go exchange.Run(ctx)
+	srv := httptest.NewServer(exchange.Handler())
+	defer srv.Close()
+
+	cfg := config.BinanceConfig{
+		WsStreamUrl:   "ws" + strings.TrimPrefix(srv.URL, "http") + "/ws",
+		RestApiUrlV3:  srv.URL + "/api/v3",
+		Subscriptions: []config.SymbolConfig{{Symbol: "BTCUSDT", Enabled: true}},
+	}
+	service := binance.NewService(ctx, events.New(), subcription.NewConnectionManager(), cfg)
+	go service.Start(ctx)
+	waitFor(t, "BTCUSDT to sync", func() bool { return service.IsSynchronized("BTCUSDT") })
+
+	added, removed := service.Reconfigure([]config.SymbolConfig{
+		{Symbol: "BTCUSDT", Enabled: false},
+		{Symbol: "ETHBTC", Enabled: true, DepthLimit: 5},
+	})
+	if !reflect.DeepEqual(added, []string{"ETHBTC"}) || !reflect.DeepEqual(removed, []string{"BTCUSDT"}) {
+		t.Fatalf("added %v, removed %v", added, removed)
+	}
+
+	waitFor(t, "ETHBTC to sync", func() bool { return service.IsSynchronized("ETHBTC") })
+	if service.IsSynchronized("BTCUSDT") || service.GetOrderBook("BTCUSDT") != nil {
+		t.Fatal("removed symbol still served")
+	}
+	if got := service.SubscribedSymbols(); !reflect.DeepEqual(got, []string{"ETHBTC"}) {
+		t.Fatalf("subscribed = %v", got)
+	}
+	if book := service.GetOrderBook("ETHBTC"); book == nil || len(book.Bids) > 5 || len(book.Asks) > 5 {
+		t.Fatalf("ETHBTC book not limited to 5 levels: %+v", book)
+	}
+}
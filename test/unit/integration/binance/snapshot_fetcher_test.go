@@ -0,0 +1,134 @@
+package binance_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ChethiyaNishanath/market-data-hub/internal/config"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/exchange/binance"
+)
+
+func newFetcherConfig(url string) config.BinanceConfig {
+	return config.BinanceConfig{
+		RestApiUrlV3: url,
+		Snapshot: config.SnapshotConfig{
+			Limit:          100,
+			MaxRetries:     3,
+			InitialBackoff: time.Millisecond,
+			MaxBackoff:     5 * time.Millisecond,
+		},
+	}
+}
+
+func TestSnapshotFetcherRetriesServerErrors(t *testing.T) {
+	var attempts atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) < 3 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		if r.URL.Query().Get("limit") != "100" {
+			t.Errorf("expected limit=100, got %s", r.URL.Query().Get("limit"))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-MBX-USED-WEIGHT-1M", "15")
+		w.Write([]byte(`{"lastUpdateId":42,"bids":[["1.0","2.0"]],"asks":[["1.1","3.0"]]}`))
+	}))
+	defer server.Close()
+
+	fetcher := binance.NewSnapshotFetcher(newFetcherConfig(server.URL))
+
+	snapshot, err := fetcher.Fetch(context.Background(), "BTCUSDT")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if snapshot.LastUpdateID != 42 {
+		t.Errorf("expected lastUpdateId 42, got %d", snapshot.LastUpdateID)
+	}
+	if attempts.Load() != 3 {
+		t.Errorf("expected 3 attempts, got %d", attempts.Load())
+	}
+	if fetcher.UsedWeight() != 15 {
+		t.Errorf("expected used weight 15 from header, got %d", fetcher.UsedWeight())
+	}
+}
+
+func TestSnapshotFetcherDoesNotRetryClientErrors(t *testing.T) {
+	var attempts atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"code":-1121,"msg":"Invalid symbol."}`))
+	}))
+	defer server.Close()
+
+	fetcher := binance.NewSnapshotFetcher(newFetcherConfig(server.URL))
+
+	_, err := fetcher.Fetch(context.Background(), "NOPE")
+	if !errors.Is(err, binance.ErrSnapshotUnavailable) {
+		t.Fatalf("expected ErrSnapshotUnavailable, got %v", err)
+	}
+	if attempts.Load() != 1 {
+		t.Errorf("expected a single attempt, got %d", attempts.Load())
+	}
+}
+
+func TestSnapshotFetcherHonoursRetryAfter(t *testing.T) {
+	var attempts atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"lastUpdateId":7,"bids":[],"asks":[]}`))
+	}))
+	defer server.Close()
+
+	fetcher := binance.NewSnapshotFetcher(newFetcherConfig(server.URL))
+
+	start := time.Now()
+	snapshot, err := fetcher.Fetch(context.Background(), "BTCUSDT")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("expected fetch to wait for Retry-After, took %s", elapsed)
+	}
+	if snapshot.LastUpdateID != 7 {
+		t.Errorf("expected lastUpdateId 7, got %d", snapshot.LastUpdateID)
+	}
+}
+
+func TestSnapshotFetcherGivesUpAfterMaxRetries(t *testing.T) {
+	var attempts atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	fetcher := binance.NewSnapshotFetcher(newFetcherConfig(server.URL))
+
+	_, err := fetcher.Fetch(context.Background(), "BTCUSDT")
+	if !errors.Is(err, binance.ErrSnapshotUnavailable) {
+		t.Fatalf("expected ErrSnapshotUnavailable, got %v", err)
+	}
+	if attempts.Load() != 4 {
+		t.Errorf("expected 4 attempts, got %d", attempts.Load())
+	}
+}
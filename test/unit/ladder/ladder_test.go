@@ -0,0 +1,228 @@
+package ladder_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ChethiyaNishanath/market-data-hub/internal/domain/orderbook"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/exchange/binance"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/ladder"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/coder/websocket"
+)
+
+var t0 = time.UnixMilli(1_760_000_000_000)
+
+func snapshot() orderbook.OrderBook {
+	return orderbook.OrderBook{
+		LastUpdateID: 10,
+		Bids:         [][]string{{"100.00", "1.5"}, {"99.50", "2"}},
+		Asks:         [][]string{{"101.00", "1"}},
+	}
+}
+
+func update(first, final int, bids, asks [][]string) binance.DepthUpdateEvent {
+	return binance.DepthUpdateEvent{Symbol: "BTCUSDT", FirstUpdateEventID: first, FinalUpdateEventID: final, BidsToUpdated: bids, AsksToUpdated: asks}
+}
+
+func TestBookAppliesBufferedAndReorderedUpdates(t *testing.T) {
+	book := ladder.NewBook("BTCUSDT")
+
+	// Arrives before the snapshot and is partly covered by it.
+	book.Apply(update(9, 11, [][]string{{"100.00", "3"}}, nil), t0)
+	book.Load(snapshot(), t0)
+	if book.LastUpdateID != 11 || book.Bids(1)[0].Quantity != 3 {
+		t.Fatalf("expected the buffered update on top of the snapshot, got %d %+v", book.LastUpdateID, book.Bids(1))
+	}
+
+	if book.Apply(update(13, 13, nil, [][]string{{"101.00", "0"}}), t0) {
+		t.Fatal("did not expect an update past a missing one to be applied")
+	}
+	if !book.Apply(update(12, 12, [][]string{{"99.50", "0"}}, nil), t0) {
+		t.Fatal("expected the missing update to be applied")
+	}
+	if book.LastUpdateID != 13 || len(book.Asks(0)) != 0 || len(book.Bids(0)) != 1 {
+		t.Errorf("expected both updates applied in order, got %d bids=%+v asks=%+v", book.LastUpdateID, book.Bids(0), book.Asks(0))
+	}
+	if book.Apply(update(12, 12, [][]string{{"1", "1"}}, nil), t0) {
+		t.Error("expected a stale update to be ignored")
+	}
+}
+
+func TestBookGoesOutOfSyncOnPersistentGap(t *testing.T) {
+	book := ladder.NewBook("BTCUSDT")
+	book.Load(snapshot(), t0)
+
+	book.Apply(update(15, 15, nil, nil), t0)
+	book.Check(t0.Add(500 * time.Millisecond))
+	if book.OutOfSync() {
+		t.Fatal("did not expect a short gap to desync the book")
+	}
+	book.Check(t0.Add(2 * time.Second))
+	if !book.OutOfSync() {
+		t.Error("expected a persistent gap to desync the book")
+	}
+
+	book.Load(snapshot(), t0)
+	if book.OutOfSync() {
+		t.Error("expected a new snapshot to restore sync")
+	}
+}
+
+func TestDecodeHubMessages(t *testing.T) {
+	cases := map[string]any{
+		`{"method":"subscribe","success":true,"topic":"BTCUSDT@depth","data":{"lastUpdateId":5,"bids":[],"asks":[]}}`: ladder.SnapshotMsg{},
+		`{"data":{"e":"depthUpdate","s":"BTCUSDT","U":6,"u":7,"b":[],"a":[]}}`:                                       ladder.DepthMsg{},
+		`{"method":"trade","topic":"btcusdt@trade","data":{"symbol":"BTCUSDT","tradeId":3,"price":"1"}}`:              ladder.TradeMsg{},
+		`{"method":"orderbook_reset","data":{"symbol":"BTCUSDT","reason":"gap"}}`:                                    ladder.ResetMsg{},
+		`{"method":"subscribe","error":"Unknown symbol: FOO"}`:                                                       ladder.ErrorMsg{},
+	}
+	for raw, want := range cases {
+		got, ok := ladder.Decode([]byte(raw))
+		if !ok {
+			t.Errorf("expected %s to decode", raw)
+			continue
+		}
+		if gotType, wantType := typeName(got), typeName(want); gotType != wantType {
+			t.Errorf("%s: got %s, want %s", raw, gotType, wantType)
+		}
+	}
+
+	if _, ok := ladder.Decode([]byte(`{"method":"subscribe","success":true,"topic":"btcusdt@trade"}`)); ok {
+		t.Error("did not expect a plain subscribe ack to decode")
+	}
+}
+
+type fakeWatcher struct {
+	watched []string
+}
+
+func (f *fakeWatcher) Watch(symbol string) error {
+	f.watched = append(f.watched, symbol)
+	return nil
+}
+
+func (f *fakeWatcher) Resync() error { return nil }
+
+func send(m tea.Model, msg tea.Msg) tea.Model {
+	m, cmd := m.Update(msg)
+	if cmd != nil {
+		cmd()
+	}
+	return m
+}
+
+func TestModelSwitchesSymbols(t *testing.T) {
+	feed := &fakeWatcher{}
+	var m tea.Model = ladder.NewModel(feed, make(chan any), []string{"btcusdt", "ethbtc"}, 10)
+
+	m = send(m, ladder.HubMsg{Connected: true})
+	m = send(m, ladder.SnapshotMsg{Symbol: "BTCUSDT", Book: snapshot()})
+	m = send(m, ladder.TradeMsg{Trade: binance.TradeEvent{Symbol: "BTCUSDT", TradeID: 1, Price: "100.5", Quantity: "0.2", Side: "buy"}})
+
+	view := m.View()
+	for _, want := range []string{"BTCUSDT", "LIVE", "101.0", "99.5", "last 100.5"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected %q in the view:\n%s", want, view)
+		}
+	}
+
+	m = send(m, tea.KeyMsg{Type: tea.KeyRight})
+	if got := m.(ladder.Model).Symbol(); got != "ETHBTC" {
+		t.Fatalf("expected ETHBTC after switching, got %s", got)
+	}
+	m = send(m, ladder.SnapshotMsg{Symbol: "BTCUSDT", Book: snapshot()})
+	if m.(ladder.Model).Book().Loaded() {
+		t.Error("expected a late message for the previous symbol to be ignored")
+	}
+
+	m = send(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	m = send(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("bnbbtc")})
+	m = send(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if got := m.(ladder.Model).Symbol(); got != "BNBBTC" {
+		t.Errorf("expected the typed symbol to be watched, got %s", got)
+	}
+	if strings.Join(feed.watched, ",") != "ETHBTC,BNBBTC" {
+		t.Errorf("unexpected watch calls %v", feed.watched)
+	}
+}
+
+func TestFeedSubscribesAndDeliversSnapshot(t *testing.T) {
+	topics := make(chan string, 8)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := websocket.Accept(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.CloseNow()
+
+		for {
+			_, data, err := conn.Read(r.Context())
+			if err != nil {
+				return
+			}
+			var req struct {
+				Method string `json:"method"`
+				Params struct {
+					Topic string `json:"topic"`
+				} `json:"params"`
+			}
+			_ = json.Unmarshal(data, &req)
+			topics <- req.Method + " " + req.Params.Topic
+			if req.Params.Topic == "btcusdt@depth" {
+				reply, _ := json.Marshal(binance.WSMessage{Method: "subscribe", Success: true, Topic: "BTCUSDT@depth", Data: snapshot()})
+				_ = conn.Write(r.Context(), websocket.MessageText, reply)
+			}
+		}
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	feed := ladder.NewFeed("ws" + strings.TrimPrefix(server.URL, "http"))
+	_ = feed.Watch("BTCUSDT")
+	go feed.Run(ctx)
+
+	var got []any
+	for len(got) < 2 {
+		select {
+		case msg := <-feed.Messages():
+			got = append(got, msg)
+		case <-ctx.Done():
+			t.Fatalf("timed out, got %v", got)
+		}
+	}
+
+	if hub, ok := got[0].(ladder.HubMsg); !ok || !hub.Connected {
+		t.Errorf("expected a connected message first, got %#v", got[0])
+	}
+	if snap, ok := got[1].(ladder.SnapshotMsg); !ok || snap.Symbol != "BTCUSDT" || snap.Book.LastUpdateID != 10 {
+		t.Errorf("expected the snapshot, got %#v", got[1])
+	}
+	if first := <-topics; first != "subscribe btcusdt@depth" {
+		t.Errorf("unexpected first request %q", first)
+	}
+}
+
+func typeName(v any) string {
+	switch v.(type) {
+	case ladder.SnapshotMsg:
+		return "snapshot"
+	case ladder.DepthMsg:
+		return "depth"
+	case ladder.TradeMsg:
+		return "trade"
+	case ladder.ResetMsg:
+		return "reset"
+	case ladder.ErrorMsg:
+		return "error"
+	default:
+		return "other"
+	}
+}
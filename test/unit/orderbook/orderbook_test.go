@@ -0,0 +1,258 @@
+package orderbook_test
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	domain "github.com/ChethiyaNishanath/market-data-hub/internal/domain/orderbook"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/exchange/binance"
+	"github.com/ChethiyaNishanath/market-data-hub/pkg/orderbook"
+)
+
+var t0 = time.UnixMilli(1_760_000_000_000)
+
+func TestSequence(t *testing.T) {
+	cases := []struct {
+		last, first, final int
+		want               domain.Step
+	}{
+		{10, 11, 12, domain.Apply}, // continues
+		{10, 9, 12, domain.Apply},  // overlaps
+		{10, 5, 10, domain.Skip},   // already held
+		{10, 12, 13, domain.Gap},   // 11 missing
+	}
+	for _, c := range cases {
+		if got := domain.Sequence(c.last, c.first, c.final); got != c.want {
+			t.Errorf("Sequence(%d, %d, %d) = %v, want %v", c.last, c.first, c.final, got, c.want)
+		}
+	}
+}
+
+func TestApplyLevelsSkipsMalformedLevels(t *testing.T) {
+	book := &binance.OrderBookSnapshot{Bids: [][]string{{"100", "1"}, {"99", "2"}}}
+
+	err := domain.ApplyLevels(book, [][]string{{"100", "0"}, {"99", "x"}, {"98"}, {"97", "3"}}, nil)
+	if err == nil {
+		t.Fatal("expected the malformed levels to be reported")
+	}
+	if want := [][]string{{"99", "2"}, {"97", "3"}}; !reflect.DeepEqual(book.Bids, want) {
+		t.Fatalf("bids = %v, want %v", book.Bids, want)
+	}
+}
+
+func TestChangedLevels(t *testing.T) {
+	before := [][]string{{"100", "1"}, {"99", "2"}, {"98", "3"}}
+	// 100 left the range, 99 changed and 97 moved into it.
+	after := [][]string{{"99", "5"}, {"98", "3"}, {"97", "4"}}
+
+	want := [][]string{{"99", "5"}, {"97", "4"}, {"100", "0"}}
+	if got := domain.ChangedLevels(before, after); !reflect.DeepEqual(got, want) {
+		t.Fatalf("ChangedLevels = %v, want %v", got, want)
+	}
+	if got := domain.ChangedLevels(after, after); len(got) != 0 {
+		t.Fatalf("ChangedLevels of an unchanged side = %v", got)
+	}
+}
+
+func TestSequencerHoldsEarlyDeltas(t *testing.T) {
+	seq := domain.NewSequencer(time.Second, 100)
+
+	if got := seq.Push(domain.Delta{First: 11, Final: 12}, t0); got != nil {
+		t.Fatalf("applied before the snapshot: %v", got)
+	}
+	if got := seq.Load(10, t0); len(got) != 1 || seq.Last() != 12 {
+		t.Fatalf("held delta not released by the snapshot: %v, last %d", got, seq.Last())
+	}
+	if got := seq.Push(domain.Delta{First: 14, Final: 14}, t0); got != nil {
+		t.Fatalf("applied past a gap: %v", got)
+	}
+	if seq.Check(t0.Add(500 * time.Millisecond)) {
+		t.Fatal("out of sync before the gap timeout")
+	}
+	if got := seq.Push(domain.Delta{First: 13, Final: 13}, t0); len(got) != 2 || seq.Last() != 14 {
+		t.Fatalf("gap not filled in order: %v, last %d", got, seq.Last())
+	}
+
+	seq.Push(domain.Delta{First: 20, Final: 20}, t0)
+	if !seq.Check(t0.Add(2 * time.Second)) {
+		t.Fatal("persistent gap not detected")
+	}
+}
+
+// stream generates deltas over a small price grid, as an exchange would.
+func stream(seed uint64, n int) []orderbook.Update {
+	rng := rand.New(rand.NewPCG(seed, seed))
+	level := func(base float64) []string {
+		qty := "0"
+		if rng.IntN(3) > 0 {
+			qty = strconv.FormatFloat(float64(rng.IntN(100))/10, 'f', 1, 64)
+		}
+		return []string{strconv.FormatFloat(base+float64(rng.IntN(20)), 'f', 2, 64), qty}
+	}
+
+	updates := make([]orderbook.Update, n)
+	id := int64(100)
+	for i := range updates {
+		first := id + 1
+		id += int64(1 + rng.IntN(3))
+		u := orderbook.Update{Symbol: "BTCUSDT", FirstUpdateID: first, FinalUpdateID: id}
+		for range 1 + rng.IntN(4) {
+			u.Bids = append(u.Bids, level(80))
+			u.Asks = append(u.Asks, level(101))
+		}
+		updates[i] = u
+	}
+	return updates
+}
+
+func levels(side [][]string) map[string]float64 {
+	m := make(map[string]float64, len(side))
+	for _, lvl := range side {
+		price, _ := strconv.ParseFloat(lvl[0], 64)
+		qty, _ := strconv.ParseFloat(lvl[1], 64)
+		m[strconv.FormatFloat(price, 'f', 2, 64)] = qty
+	}
+	return m
+}
+
+func local(side []orderbook.Level) map[string]float64 {
+	m := make(map[string]float64, len(side))
+	for _, lvl := range side {
+		m[strconv.FormatFloat(lvl.Price, 'f', 2, 64)] = lvl.Quantity
+	}
+	return m
+}
+
+func TestBookMatchesHubBookOnShuffledStream(t *testing.T) {
+	snapshot := orderbook.Snapshot{
+		LastUpdateID: 100,
+		Bids:         [][]string{{"85.00", "1.0"}, {"84.00", "2.0"}},
+		Asks:         [][]string{{"105.00", "1.0"}},
+	}
+	updates := stream(7, 500)
+
+	// The hub applies the stream in order.
+	hub := &binance.OrderBookSnapshot{LastUpdateID: 100, Bids: snapshot.Bids, Asks: snapshot.Asks}
+	hub.Bids = append([][]string(nil), hub.Bids...)
+	hub.Asks = append([][]string(nil), hub.Asks...)
+	for _, u := range updates {
+		if domain.Sequence(hub.LastUpdateID, int(u.FirstUpdateID), int(u.FinalUpdateID)) == domain.Apply {
+			if err := domain.ApplyLevels(hub, u.Bids, u.Asks); err != nil {
+				t.Fatal(err)
+			}
+			hub.LastUpdateID = int(u.FinalUpdateID)
+		}
+	}
+
+	// The client gets a few updates before the snapshot, then the rest in
+	// small bursts out of order, with duplicates.
+	rng := rand.New(rand.NewPCG(1, 2))
+	book := orderbook.New("btcusdt", orderbook.Options{})
+	for _, u := range updates[:5] {
+		book.Apply(u)
+	}
+	book.Load(snapshot)
+	for i := 5; i < len(updates); i += 4 {
+		burst := append([]orderbook.Update(nil), updates[i:min(i+4, len(updates))]...)
+		rng.Shuffle(len(burst), func(a, b int) { burst[a], burst[b] = burst[b], burst[a] })
+		for _, u := range burst {
+			book.Apply(u)
+		}
+		book.Apply(updates[i-1])
+	}
+
+	if !book.Synced() || book.LastUpdateID() != int64(hub.LastUpdateID) {
+		t.Fatalf("book at %d (synced %v), hub at %d", book.LastUpdateID(), book.Synced(), hub.LastUpdateID)
+	}
+	if !reflect.DeepEqual(local(book.Bids(0)), levels(hub.Bids)) || !reflect.DeepEqual(local(book.Asks(0)), levels(hub.Asks)) {
+		t.Fatalf("book differs from the hub's:\nbids %v\nhub  %v\nasks %v\nhub  %v",
+			local(book.Bids(0)), levels(hub.Bids), local(book.Asks(0)), levels(hub.Asks))
+	}
+}
+
+func TestBookRequestsSnapshotOnceOnGap(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		requests []string
+	)
+	book := orderbook.New("BTCUSDT", orderbook.Options{
+		GapTimeout: 20 * time.Millisecond,
+		RequestSnapshot: func(reason string) {
+			mu.Lock()
+			requests = append(requests, reason)
+			mu.Unlock()
+		},
+	})
+	book.Load(orderbook.Snapshot{LastUpdateID: 10})
+	book.Apply(orderbook.Update{FirstUpdateID: 12, FinalUpdateID: 12})
+
+	book.Check()
+	if !book.Synced() {
+		t.Fatal("out of sync before the gap timeout")
+	}
+	time.Sleep(30 * time.Millisecond)
+	book.Check()
+	book.Check()
+	if book.Synced() {
+		t.Fatal("still synced after the gap timeout")
+	}
+
+	mu.Lock()
+	if len(requests) != 1 {
+		t.Fatalf("snapshot requested %d times: %v", len(requests), requests)
+	}
+	mu.Unlock()
+
+	book.Load(orderbook.Snapshot{LastUpdateID: 12})
+	if !book.Synced() || book.LastUpdateID() != 12 {
+		t.Fatal("a new snapshot did not restore sync")
+	}
+}
+
+func TestBookHandlesHubMessagesAndReportsChanges(t *testing.T) {
+	var changes []orderbook.Change
+	book := orderbook.New("BTCUSDT", orderbook.Options{
+		OnChange: func(c orderbook.Change) { changes = append(changes, c) },
+	})
+
+	messages := []string{
+		`{"id":1,"method":"subscribe","success":true,"topic":"BTCUSDT@depth","data":{"lastUpdateId":5,"bids":[["100.0","1"],["99.0","2"]],"asks":[["101.0","1"]]}}`,
+		`{"data":{"e":"depthUpdate","s":"BTCUSDT","U":6,"u":6,"b":[["100.0","0"]],"a":[["101.5","3"]]}}`,
+		`{"data":{"e":"depthUpdate","s":"ETHBTC","U":1,"u":1,"b":[["1","1"]],"a":[]}}`,
+		`{"method":"trade","topic":"btcusdt@trade","data":{"symbol":"BTCUSDT"}}`,
+	}
+	for _, msg := range messages {
+		if err := book.Handle([]byte(msg)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	bid, _ := book.BestBid()
+	ask, _ := book.BestAsk()
+	if bid.Price != 99 || ask.Price != 101 || book.LastUpdateID() != 6 {
+		t.Fatalf("best bid %v, best ask %v at %d", bid, ask, book.LastUpdateID())
+	}
+	if top := book.Asks(1); len(top) != 1 || top[0].Price != 101 {
+		t.Fatalf("top ask = %v", top)
+	}
+	want := []orderbook.Change{
+		{Symbol: "BTCUSDT", LastUpdateID: 5, Reset: true},
+		{Symbol: "BTCUSDT", LastUpdateID: 6, Bids: []orderbook.Level{{Price: 100}}, Asks: []orderbook.Level{{Price: 101.5, Quantity: 3}}},
+	}
+	if !reflect.DeepEqual(changes, want) {
+		t.Fatalf("changes = %+v", changes)
+	}
+
+	reset := fmt.Sprintf(`{"method":"orderbook_reset","data":{"symbol":"BTCUSDT","reason":"test","snapshot":{"lastUpdateId":%d,"bids":[],"asks":[["102","1"]]}}}`, 50)
+	if err := book.Handle([]byte(reset)); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := book.BestBid(); ok || book.LastUpdateID() != 50 || !changes[len(changes)-1].Reset {
+		t.Fatal("reset did not replace the book")
+	}
+}
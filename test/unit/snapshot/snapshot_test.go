@@ -0,0 +1,173 @@
+package snapshot_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	pb "github.com/ChethiyaNishanath/market-data-hub/api/orderbook"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/domain/orderbook"
+	grpcserver "github.com/ChethiyaNishanath/market-data-hub/internal/interfaces/servers/grpc"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/snapshot"
+)
+
+func sampleBooks() []snapshot.Book {
+	return []snapshot.Book{
+		{
+			Symbol:       "BTCUSDT",
+			LastUpdateID: 42,
+			Bids:         []snapshot.Level{{Price: "100.10", Quantity: "1.5"}, {Price: "100.00", Quantity: "2"}},
+			Asks:         []snapshot.Level{{Price: "100.20", Quantity: "0.7"}},
+		},
+		{
+			Symbol:       "ETHBTC",
+			LastUpdateID: 7,
+			Bids:         []snapshot.Level{{Price: "0.05", Quantity: "10"}},
+			Asks:         []snapshot.Level{{Price: "0.06", Quantity: "3"}},
+		},
+	}
+}
+
+func TestFromReplyKeepsOrderAndParsesUpdateID(t *testing.T) {
+	book := snapshot.FromReply(&pb.GetSnapshotReply{
+		Symbol:       "BTCUSDT",
+		LastUpdateId: "123",
+		Bids:         []*pb.Order{{Price: "2", Amount: "1"}, {Price: "1", Amount: "3"}},
+		Asks:         []*pb.Order{{Price: "3", Amount: "4"}},
+	})
+
+	if book.LastUpdateID != 123 {
+		t.Fatalf("last update id = %d, want 123", book.LastUpdateID)
+	}
+	if len(book.Bids) != 2 || book.Bids[0] != (snapshot.Level{Price: "2", Quantity: "1"}) {
+		t.Fatalf("bids = %v", book.Bids)
+	}
+}
+
+func TestWriteJSONIsAnArrayOfPairs(t *testing.T) {
+	var buf bytes.Buffer
+	if err := snapshot.Write(&buf, snapshot.FormatJSON, sampleBooks()); err != nil {
+		t.Fatal(err)
+	}
+
+	var books []snapshot.Book
+	if err := json.Unmarshal(buf.Bytes(), &books); err != nil {
+		t.Fatalf("decode: %v\n%s", err, buf.String())
+	}
+	if len(books) != 2 || books[0].Bids[1].Price != "100.00" {
+		t.Fatalf("round trip = %+v", books)
+	}
+	if !strings.Contains(buf.String(), `"100.10",`) {
+		t.Fatalf("levels are not encoded as pairs:\n%s", buf.String())
+	}
+}
+
+func TestWriteJSONWithoutBooksIsEmptyArray(t *testing.T) {
+	var buf bytes.Buffer
+	if err := snapshot.Write(&buf, snapshot.FormatJSON, nil); err != nil {
+		t.Fatal(err)
+	}
+	if strings.TrimSpace(buf.String()) != "[]" {
+		t.Fatalf("got %q, want []", buf.String())
+	}
+}
+
+func TestWriteNDJSONWritesOneBookPerLine(t *testing.T) {
+	var buf bytes.Buffer
+	if err := snapshot.Write(&buf, snapshot.FormatNDJSON, sampleBooks()); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2:\n%s", len(lines), buf.String())
+	}
+	var book snapshot.Book
+	if err := json.Unmarshal([]byte(lines[1]), &book); err != nil || book.Symbol != "ETHBTC" {
+		t.Fatalf("second line = %q (%v)", lines[1], err)
+	}
+}
+
+func TestWriteCSVWritesOneRowPerLevel(t *testing.T) {
+	var buf bytes.Buffer
+	if err := snapshot.Write(&buf, snapshot.FormatCSV, sampleBooks()); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `symbol,last_update_id,side,level,price,quantity
+BTCUSDT,42,bid,0,100.10,1.5
+BTCUSDT,42,bid,1,100.00,2
+BTCUSDT,42,ask,0,100.20,0.7
+ETHBTC,7,bid,0,0.05,10
+ETHBTC,7,ask,0,0.06,3
+`
+	if buf.String() != want {
+		t.Fatalf("got\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func TestWriteTablePutsSidesNextToEachOther(t *testing.T) {
+	var buf bytes.Buffer
+	if err := snapshot.Write(&buf, snapshot.FormatTable, sampleBooks()); err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{"BTCUSDT  last update 42", "ETHBTC  last update 7", "BID QTY"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("table is missing %q:\n%s", want, out)
+		}
+	}
+
+	lines := strings.Split(out, "\n")
+	fields := strings.Fields(lines[2])
+	if len(fields) != 4 || fields[1] != "100.10" || fields[2] != "100.20" {
+		t.Fatalf("best level row = %q", lines[2])
+	}
+	if fields := strings.Fields(lines[3]); len(fields) != 2 || fields[1] != "100.00" {
+		t.Fatalf("second level row = %q", lines[3])
+	}
+}
+
+func TestWriteRejectsUnknownFormat(t *testing.T) {
+	if snapshot.ValidFormat("xml") {
+		t.Fatal("xml reported as valid")
+	}
+	if err := snapshot.Write(&bytes.Buffer{}, "xml", sampleBooks()); err == nil {
+		t.Fatal("expected an error for an unknown format")
+	}
+}
+
+func TestMapOrderBookToSnapshotSortsBestFirstAndCutsDepth(t *testing.T) {
+	book := &orderbook.OrderBook{
+		LastUpdateID: 9,
+		Bids:         [][]string{{"99.5", "1"}, {"100.5", "2"}, {"100", "3"}},
+		Asks:         [][]string{{"102", "1"}, {"101", "2"}, {"101.5", "3"}},
+	}
+
+	reply := grpcserver.MapOrderBookToSnapshot("BTCUSDT", book, 2)
+
+	if reply.GetLastUpdateId() != "9" {
+		t.Fatalf("last update id = %q", reply.GetLastUpdateId())
+	}
+	if got := prices(reply.GetBids()); got != "100.5,100" {
+		t.Fatalf("bids = %s, want 100.5,100", got)
+	}
+	if got := prices(reply.GetAsks()); got != "101,101.5" {
+		t.Fatalf("asks = %s, want 101,101.5", got)
+	}
+
+	full := grpcserver.MapOrderBookToSnapshot("BTCUSDT", book, 0)
+	if len(full.GetBids()) != 3 || len(full.GetAsks()) != 3 {
+		t.Fatalf("depth 0 kept %d bids and %d asks, want 3 each", len(full.GetBids()), len(full.GetAsks()))
+	}
+}
+
+func prices(orders []*pb.Order) string {
+	out := make([]string, len(orders))
+	for i, o := range orders {
+		out[i] = o.GetPrice()
+	}
+	return strings.Join(out, ",")
+}
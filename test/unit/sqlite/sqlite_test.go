@@ -0,0 +1,163 @@
+package sqlite_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ChethiyaNishanath/market-data-hub/internal/alerting"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/config"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/domain/candle"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/domain/trade"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/store/sqlite"
+	"github.com/ChethiyaNishanath/market-data-hub/test/testutil"
+)
+
+var t0 = time.UnixMilli(1_760_000_000_000).UTC()
+
+func open(t *testing.T, path string) *sqlite.Store {
+	t.Helper()
+	store, err := sqlite.Open(path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	return store
+}
+
+func TestMigrationsRunOnce(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hub.db")
+
+	store, err := sqlite.Open(path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	if err := store.Trades().Add(trade.Trade{Exchange: "binance", Symbol: "BTCUSDT", ID: 1, Time: t0}); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	_ = store.Close()
+
+	reopened := open(t, path)
+	version, err := reopened.SchemaVersion()
+	if err != nil || version != 2 {
+		t.Errorf("expected schema version 2, got %d (%v)", version, err)
+	}
+	list, _ := reopened.Trades().Recent("binance", "BTCUSDT", time.Time{}, time.Time{}, 0)
+	if len(list) != 1 {
+		t.Errorf("expected the trade to survive reopening, got %+v", list)
+	}
+}
+
+func TestTradesAreBoundedAndOrdered(t *testing.T) {
+	trades := open(t, filepath.Join(t.TempDir(), "hub.db")).Trades()
+
+	for id := int64(1); id <= 5; id++ {
+		err := trades.Add(trade.Trade{Exchange: "binance", Symbol: "BTCUSDT", ID: id, Price: 100, Quantity: 1, Side: "buy", Time: t0.Add(time.Duration(id) * time.Second)})
+		if err != nil {
+			t.Fatalf("add: %v", err)
+		}
+	}
+	// Duplicates are ignored.
+	_ = trades.Add(trade.Trade{Exchange: "binance", Symbol: "BTCUSDT", ID: 5, Price: 1, Time: t0})
+
+	list, _ := trades.Recent("binance", "BTCUSDT", t0.Add(2*time.Second), time.Time{}, 2)
+	if len(list) != 2 || list[0].ID != 4 || list[1].ID != 5 || list[1].Price != 100 {
+		t.Fatalf("expected the latest two oldest first, got %+v", list)
+	}
+
+	if err := trades.Prune(t0.Add(2*time.Second), 2); err != nil {
+		t.Fatalf("prune: %v", err)
+	}
+	list, _ = trades.Recent("binance", "BTCUSDT", time.Time{}, time.Time{}, 0)
+	if len(list) != 2 || list[0].ID != 4 {
+		t.Errorf("expected only the two newest trades, got %+v", list)
+	}
+}
+
+func TestCandlesUpsertAndPrune(t *testing.T) {
+	candles := open(t, filepath.Join(t.TempDir(), "hub.db")).Candles()
+
+	c := candle.Candle{Exchange: "binance", Symbol: "BTCUSDT", Interval: "1m", OpenTime: t0, Open: 1, High: 2, Low: 1, Close: 2, Volume: 3, Trades: 2}
+	_ = candles.Upsert(c)
+	c.Close, c.Trades = 5, 3
+	_ = candles.Upsert(c, candle.Candle{Exchange: "binance", Symbol: "BTCUSDT", Interval: "1m", OpenTime: t0.Add(time.Minute)})
+
+	list, err := candles.Range("binance", "BTCUSDT", "1m", time.Time{}, time.Time{}, 0)
+	if err != nil {
+		t.Fatalf("range: %v", err)
+	}
+	if len(list) != 2 || list[0].Close != 5 || list[0].Trades != 3 || !list[0].OpenTime.Equal(t0) {
+		t.Fatalf("expected the replaced candle first, got %+v", list)
+	}
+
+	_ = candles.Prune(t0.Add(time.Minute))
+	list, _ = candles.Range("binance", "BTCUSDT", "1m", time.Time{}, time.Time{}, 0)
+	if len(list) != 1 || !list[0].OpenTime.Equal(t0.Add(time.Minute)) {
+		t.Errorf("expected only the newer candle, got %+v", list)
+	}
+}
+
+func TestAlertsSurviveRestart(t *testing.T) {
+	store := open(t, filepath.Join(t.TempDir(), "hub.db"))
+	outbox := store.Outbox()
+	dispatcher := alerting.NewDispatcher(outbox, config.WebhookConfig{})
+
+	engine := alerting.NewEngine(config.AlertsConfig{}, testutil.NewBookSource("", nil), nil, dispatcher)
+	if err := engine.UseRepository(store.Alerts()); err != nil {
+		t.Fatalf("use repository: %v", err)
+	}
+	rule := alerting.Rule{Type: alerting.RuleSpread, SpreadBps: 5}
+	webhook, err := engine.Create(alerting.Alert{Symbol: "BTCUSDT", Rule: rule, Deliver: alerting.Target{WebhookURL: "http://example.invalid"}})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if _, err := engine.Create(alerting.Alert{Symbol: "BTCUSDT", ClientID: "c1", Rule: rule, Deliver: alerting.Target{WebSocket: true}}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	restarted := alerting.NewEngine(config.AlertsConfig{}, testutil.NewBookSource("", nil), nil, dispatcher)
+	if err := restarted.UseRepository(store.Alerts()); err != nil {
+		t.Fatalf("use repository: %v", err)
+	}
+	list := restarted.List("")
+	if len(list) != 1 || list[0].ID != webhook.ID {
+		t.Fatalf("expected only the webhook alert to be restored, got %+v", list)
+	}
+
+	if err := restarted.Delete(webhook.ID, ""); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	saved, _ := store.Alerts().LoadAlerts()
+	if len(saved) != 0 {
+		t.Errorf("expected no saved alerts, got %+v", saved)
+	}
+}
+
+func TestOutboxDeadLetters(t *testing.T) {
+	outbox := open(t, filepath.Join(t.TempDir(), "hub.db")).Outbox()
+
+	d, err := outbox.Enqueue("http://example.invalid", []byte(`{"a":1}`), t0)
+	if err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+
+	due, _ := outbox.Due(t0.Add(-time.Second))
+	if len(due) != 0 {
+		t.Errorf("did not expect a delivery due before it was enqueued, got %+v", due)
+	}
+
+	d.Attempts, d.NextAttempt, d.LastError = 1, t0.Add(time.Minute), "boom"
+	_ = outbox.Reschedule(d)
+	due, _ = outbox.Due(t0.Add(time.Minute))
+	if len(due) != 1 || due[0].Attempts != 1 || due[0].LastError != "boom" || string(due[0].Payload) != `{"a":1}` {
+		t.Fatalf("expected the rescheduled delivery, got %+v", due)
+	}
+
+	d.Attempts = 2
+	_ = outbox.Bury(d)
+	pending, _ := outbox.Pending()
+	dead, _ := outbox.Dead()
+	if len(pending) != 0 || len(dead) != 1 || dead[0].Attempts != 2 {
+		t.Errorf("expected the delivery dead-lettered, pending=%+v dead=%+v", pending, dead)
+	}
+}
@@ -0,0 +1,146 @@
+package subscription_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ChethiyaNishanath/market-data-hub/internal/config"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/subcription"
+	"github.com/coder/websocket"
+)
+
+type fakeExchange struct{}
+
+func (fakeExchange) Name() string                { return "binance" }
+func (fakeExchange) SubscribedSymbols() []string { return []string{"BTCUSDT", "ETHBTC"} }
+
+type reply struct {
+	ID      json.RawMessage `json:"id"`
+	Method  string          `json:"method"`
+	Success bool            `json:"success"`
+	Code    string          `json:"code"`
+	Error   string          `json:"error"`
+	Data    json.RawMessage `json:"data"`
+}
+
+func dial(t *testing.T) (*websocket.Conn, context.Context) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+
+	service := subcription.NewService(subcription.NewConnectionManager())
+	service.Handler.RegisterExchange(fakeExchange{})
+	service.Handler.UseRateLimit(func() config.RateLimitConfig {
+		return config.RateLimitConfig{Requests: 100, Window: time.Minute}
+	})
+	srv := httptest.NewServer(http.HandlerFunc(service.Handler.HandleWebSocket))
+	t.Cleanup(srv.Close)
+
+	conn, _, err := websocket.Dial(ctx, "ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.CloseNow() })
+
+	if _, _, err := conn.Read(ctx); err != nil { // client_id
+		t.Fatal(err)
+	}
+	return conn, ctx
+}
+
+func TestRepliesEchoIDsInRequestOrder(t *testing.T) {
+	conn, ctx := dial(t)
+
+	requests := []string{
+		`{"id":1,"method":"subscribe","params":{"topic":"btcusdt@trade"}}`,
+		`{"id":"two","method":"subscribe","params":{"topic":"ethbtc@connection"}}`,
+		`{"id":3,"method":"list_subscriptions"}`,
+		`{"id":4,"method":"unsubscribe","params":{"topic":"btcusdt@trade"}}`,
+		`{"id":5,"method":"list_subscriptions"}`,
+		`{"id":6,"method":"ping"}`,
+		`{"id":7,"method":"list_symbols"}`,
+		`{"id":8,"method":"server_info"}`,
+		`{"id":9,"method":"nope"}`,
+		`{"method":"ping"}`,
+	}
+	for _, req := range requests {
+		if err := conn.Write(ctx, websocket.MessageText, []byte(req)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	replies := make([]reply, len(requests))
+	for i := range replies {
+		_, data, err := conn.Read(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := json.Unmarshal(data, &replies[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	wantIDs := []string{`1`, `"two"`, `3`, `4`, `5`, `6`, `7`, `8`, `9`, ``}
+	for i, r := range replies {
+		if string(r.ID) != wantIDs[i] {
+			t.Fatalf("reply %d has id %s, want %s", i, r.ID, wantIDs[i])
+		}
+	}
+
+	var topics []string
+	json.Unmarshal(replies[2].Data, &topics)
+	if !reflect.DeepEqual(topics, []string{"btcusdt@trade", "ethbtc@connection"}) {
+		t.Fatalf("subscriptions = %v", topics)
+	}
+	json.Unmarshal(replies[4].Data, &topics)
+	if !reflect.DeepEqual(topics, []string{"ethbtc@connection"}) {
+		t.Fatalf("subscriptions after unsubscribe = %v", topics)
+	}
+
+	var pong struct {
+		ServerTime int64 `json:"serverTime"`
+	}
+	json.Unmarshal(replies[5].Data, &pong)
+	if replies[5].Method != "pong" || time.Since(time.UnixMilli(pong.ServerTime)).Abs() > time.Minute {
+		t.Fatalf("pong = %+v", replies[5])
+	}
+
+	var symbols []subcription.ExchangeSymbols
+	json.Unmarshal(replies[6].Data, &symbols)
+	if len(symbols) != 1 || symbols[0].Exchange != "binance" || len(symbols[0].Symbols) != 2 {
+		t.Fatalf("symbols = %+v", symbols)
+	}
+
+	var info subcription.ServerInfo
+	json.Unmarshal(replies[7].Data, &info)
+	if !reflect.DeepEqual(info.Exchanges, []string{"binance"}) || info.Limits.RateLimitRequests != 100 ||
+		info.Limits.RateLimitWindow != "1m0s" || info.Limits.SendBuffer == 0 || info.Limits.MaxRequestBytes == 0 {
+		t.Fatalf("server info = %+v", info)
+	}
+
+	if replies[8].Success || replies[8].Code != "unknown_method" || replies[8].Error != "unknown action" {
+		t.Fatalf("unknown method reply = %+v", replies[8])
+	}
+}
+
+func TestInvalidRequestIsAnswered(t *testing.T) {
+	conn, ctx := dial(t)
+
+	if err := conn.Write(ctx, websocket.MessageText, []byte(`{"id":`)); err != nil {
+		t.Fatal(err)
+	}
+	_, data, err := conn.Read(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var r reply
+	if err := json.Unmarshal(data, &r); err != nil || r.Success || r.Code != "invalid_request" || !strings.HasPrefix(r.Error, "invalid request") {
+		t.Fatalf("reply = %s", data)
+	}
+}
@@ -0,0 +1,195 @@
+package subscription_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ChethiyaNishanath/market-data-hub/internal/domain/subscription"
+	wsserver "github.com/ChethiyaNishanath/market-data-hub/internal/interfaces/servers/websocket"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/subcription"
+	"github.com/coder/websocket"
+)
+
+// pair returns the server and client ends of a WebSocket connection.
+func pair(t *testing.T) (server, client *websocket.Conn, ctx context.Context) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+
+	accepted := make(chan *websocket.Conn, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := websocket.Accept(w, r, nil)
+		if err != nil {
+			return
+		}
+		accepted <- conn
+		<-ctx.Done()
+	}))
+	t.Cleanup(srv.Close)
+
+	client, _, err := websocket.Dial(ctx, "ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.SetReadLimit(-1)
+	t.Cleanup(func() { client.CloseNow() })
+	return <-accepted, client, ctx
+}
+
+func resync(topic string) ([]byte, bool) {
+	if topic != "a@depth" {
+		return nil, false
+	}
+	return []byte("resync " + topic), true
+}
+
+// flood queues d1-d6 on a@depth and b1-b2 on b@trade for a client whose queue
+// holds four.
+func flood(c *wsserver.WSClient) {
+	ctx := context.Background()
+	for _, msg := range []string{"d1", "d2", "d3", "d4", "d5", "b1", "d6", "b2"} {
+		topic := "a@depth"
+		if msg[0] == 'b' {
+			topic = "b@trade"
+		}
+		c.SendTopic(ctx, topic, []byte(msg))
+	}
+}
+
+func readAll(t *testing.T, ctx context.Context, conn *websocket.Conn, n int) []string {
+	t.Helper()
+	got := make([]string, 0, n)
+	for range n {
+		_, data, err := conn.Read(ctx)
+		if err != nil {
+			t.Fatalf("after %v: %v", got, err)
+		}
+		got = append(got, string(data))
+	}
+	return got
+}
+
+func TestSlowConsumerPolicies(t *testing.T) {
+	cases := []struct {
+		policy    string
+		want      []string
+		dropped   uint64
+		conflated uint64
+	}{
+		{wsserver.PolicyResnapshot, []string{"d1", "d2", "d3", "d4", "resync a@depth"}, 4, 0},
+		// b@trade has nothing queued, so it catches up first.
+		{wsserver.PolicyConflate, []string{"d1", "d2", "b2", "d3", "d4", "resync a@depth"}, 0, 2},
+	}
+	for _, c := range cases {
+		t.Run(c.policy, func(t *testing.T) {
+			server, conn, ctx := pair(t)
+			client := wsserver.NewWsClient(server, wsserver.Options{
+				SendBuffer:   4,
+				SlowConsumer: c.policy,
+				Resync:       resync,
+			})
+
+			flood(client)
+			stats := client.Stats()
+			if !stats.Slow || stats.Queued != 4 || stats.PeakQueued != 4 || stats.Dropped != c.dropped || stats.Conflated != c.conflated {
+				t.Fatalf("stats while slow = %+v", stats)
+			}
+
+			go client.WritePump(ctx)
+			if got := readAll(t, ctx, conn, len(c.want)); !reflect.DeepEqual(got, c.want) {
+				t.Fatalf("received %v, want %v", got, c.want)
+			}
+
+			client.SendTopic(ctx, "a@depth", []byte("d7"))
+			if got := readAll(t, ctx, conn, 1); got[0] != "d7" {
+				t.Fatalf("received %v after catching up", got)
+			}
+			if stats := client.Stats(); stats.Slow || stats.Resyncs != 1 || stats.Sent != uint64(len(c.want)+1) {
+				t.Fatalf("stats after catching up = %+v", stats)
+			}
+		})
+	}
+}
+
+func TestSlowConsumerDisconnect(t *testing.T) {
+	server, conn, ctx := pair(t)
+	client := wsserver.NewWsClient(server, wsserver.Options{
+		SendBuffer:    4,
+		HighWaterMark: 2,
+		SlowConsumer:  wsserver.PolicyDisconnect,
+	})
+
+	flood(client)
+	for {
+		if _, _, err := conn.Read(ctx); err != nil {
+			if status := websocket.CloseStatus(err); status != wsserver.StatusSlowConsumer {
+				t.Fatalf("closed with %v (%v), want %v", status, err, wsserver.StatusSlowConsumer)
+			}
+			break
+		}
+	}
+	if stats := client.Stats(); stats.Dropped != 6 {
+		t.Fatalf("stats = %+v", stats)
+	}
+}
+
+func TestWritePumpTimesOut(t *testing.T) {
+	server, _, ctx := pair(t)
+	client := wsserver.NewWsClient(server, wsserver.Options{WriteTimeout: 50 * time.Millisecond})
+
+	done := make(chan struct{})
+	go func() {
+		client.WritePump(ctx)
+		close(done)
+	}()
+	// Nothing reads, so the socket buffers fill up and the write blocks.
+	client.Send(make([]byte, 64<<20))
+
+	select {
+	case <-done:
+	case <-time.After(3 * time.Second):
+		t.Fatal("write to a stalled client did not time out")
+	}
+	if err := ctx.Err(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestHandleClientsListsQueues(t *testing.T) {
+	connMgr := subcription.NewConnectionManager()
+	service := subcription.NewService(connMgr)
+
+	idle := wsserver.NewWsClient(nil, wsserver.Options{})
+	slow := wsserver.NewWsClient(nil, wsserver.Options{SendBuffer: 4, Resync: resync})
+	connMgr.Register(idle)
+	connMgr.Register(slow)
+	connMgr.Subscribe(slow, "a@depth")
+	connMgr.Subscribe(slow, "b@trade")
+	flood(slow)
+
+	list := func(query string) []subscription.ClientStats {
+		rec := httptest.NewRecorder()
+		service.Handler.HandleClients(rec, httptest.NewRequest(http.MethodGet, "/admin/clients"+query, nil))
+		var stats []subscription.ClientStats
+		if err := json.Unmarshal(rec.Body.Bytes(), &stats); err != nil {
+			t.Fatal(err)
+		}
+		return stats
+	}
+
+	all := list("")
+	if len(all) != 2 {
+		t.Fatalf("listed %d clients", len(all))
+	}
+	only := list("?slow=true")
+	if len(only) != 1 || only[0].ID != slow.ID() || only[0].Dropped != 4 ||
+		!reflect.DeepEqual(only[0].Topics, []string{"a@depth", "b@trade"}) {
+		t.Fatalf("slow clients = %+v", only)
+	}
+}
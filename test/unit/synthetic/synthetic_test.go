@@ -0,0 +1,122 @@
+package synthetic_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ChethiyaNishanath/market-data-hub/internal/bus"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/config"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/domain/orderbook"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/synthetic"
+	"github.com/ChethiyaNishanath/market-data-hub/test/testutil"
+)
+
+func TestComposeMultiplyWalksDepth(t *testing.T) {
+	bnbbtc := synthetic.Ladder{
+		Bids: []orderbook.Level{{Price: 0.01, Quantity: 10}},
+		Asks: []orderbook.Level{{Price: 0.011, Quantity: 1}},
+	}
+	btcusdt := synthetic.Ladder{
+		Bids: []orderbook.Level{{Price: 60000, Quantity: 0.05}, {Price: 59000, Quantity: 1}},
+		Asks: []orderbook.Level{{Price: 61000, Quantity: 1}},
+	}
+
+	got := synthetic.Compose([]synthetic.Ladder{bnbbtc, btcusdt}, []string{synthetic.OpMultiply}, 10)
+
+	if len(got.Bids) != 2 {
+		t.Fatalf("expected 2 bid levels, got %+v", got.Bids)
+	}
+	if !testutil.Approx(got.Bids[0].Price, 600) || !testutil.Approx(got.Bids[0].Quantity, 5) {
+		t.Errorf("unexpected best bid %+v", got.Bids[0])
+	}
+	if !testutil.Approx(got.Bids[1].Price, 590) || !testutil.Approx(got.Bids[1].Quantity, 5) {
+		t.Errorf("unexpected second bid %+v", got.Bids[1])
+	}
+
+	if len(got.Asks) != 1 || !testutil.Approx(got.Asks[0].Price, 671) || !testutil.Approx(got.Asks[0].Quantity, 1) {
+		t.Errorf("unexpected asks %+v", got.Asks)
+	}
+}
+
+func TestComposeDivideInvertsLeg(t *testing.T) {
+	bnbusdt := synthetic.Ladder{
+		Bids: []orderbook.Level{{Price: 590, Quantity: 2}},
+		Asks: []orderbook.Level{{Price: 600, Quantity: 2}},
+	}
+	btcusdt := synthetic.Ladder{
+		Bids: []orderbook.Level{{Price: 60000, Quantity: 1}},
+		Asks: []orderbook.Level{{Price: 59000, Quantity: 1}},
+	}
+
+	got := synthetic.Compose([]synthetic.Ladder{bnbusdt, btcusdt}, []string{synthetic.OpDivide}, 10)
+
+	if len(got.Asks) != 1 || !testutil.Approx(got.Asks[0].Price, 0.01) || !testutil.Approx(got.Asks[0].Quantity, 2) {
+		t.Errorf("unexpected asks %+v", got.Asks)
+	}
+	if len(got.Bids) != 1 || !testutil.Approx(got.Bids[0].Price, 590.0/59000) || !testutil.Approx(got.Bids[0].Quantity, 2) {
+		t.Errorf("unexpected bids %+v", got.Bids)
+	}
+}
+
+func TestValidateRejectsSingleLeg(t *testing.T) {
+	cfg := config.SyntheticConfig{Instruments: []config.SyntheticInstrument{
+		{Name: "X", Legs: []config.SyntheticLeg{{Symbol: "BTCUSDT"}}},
+	}}
+	if err := synthetic.Validate(cfg); err == nil {
+		t.Fatal("expected error for a single-leg instrument")
+	}
+}
+
+func TestEngineMarksBookInvalidWhileSourceResyncs(t *testing.T) {
+	source := testutil.NewBookSource("", map[string]*orderbook.OrderBook{
+		"BNBBTC":  {Bids: [][]string{{"0.01", "10"}}, Asks: [][]string{{"0.011", "10"}}},
+		"BTCUSDT": {Bids: [][]string{{"60000", "1"}}, Asks: [][]string{{"61000", "1"}}},
+	})
+
+	cfg := config.SyntheticConfig{
+		Enabled: true,
+		Instruments: []config.SyntheticInstrument{{
+			Name: "BNBUSDT-SYN",
+			Legs: []config.SyntheticLeg{
+				{Symbol: "BNBBTC"},
+				{Symbol: "BTCUSDT", Operation: "multiply"},
+			},
+		}},
+	}
+
+	eventBus := bus.New()
+	published := make(chan synthetic.Book, 10)
+	eventBus.Subscribe(synthetic.Topic("BNBUSDT-SYN"), func(e bus.Event) {
+		published <- e.Data.(synthetic.Book)
+	})
+
+	engine := synthetic.NewEngine(cfg, eventBus, source)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go engine.Start(ctx)
+
+	book := waitForBook(t, published)
+	if !book.Valid || len(book.Bids) == 0 {
+		t.Fatalf("expected a valid book, got %+v", book)
+	}
+
+	source.SetSynced("BTCUSDT", false)
+	eventBus.Publish("depthUpdateReset", "btcusdt@depth.reset", nil)
+
+	book = waitForBook(t, published)
+	if book.Valid || len(book.Bids) != 0 || book.Reason == "" {
+		t.Fatalf("expected an invalid book while resyncing, got %+v", book)
+	}
+}
+
+func waitForBook(t *testing.T, ch <-chan synthetic.Book) synthetic.Book {
+	t.Helper()
+	select {
+	case book := <-ch:
+		return book
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for synthetic book")
+	}
+	return synthetic.Book{}
+}
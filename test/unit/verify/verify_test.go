@@ -0,0 +1,208 @@
+package verify_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ChethiyaNishanath/market-data-hub/internal/bus"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/config"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/domain/orderbook"
+	"github.com/ChethiyaNishanath/market-data-hub/internal/verify"
+	"github.com/ChethiyaNishanath/market-data-hub/test/testutil"
+	"github.com/coder/websocket"
+)
+
+func book(id int, bids, asks [][]string) *orderbook.OrderBook {
+	return &orderbook.OrderBook{LastUpdateID: id, Bids: bids, Asks: asks}
+}
+
+func hubBook(t *testing.T, ob *orderbook.OrderBook) *verify.Book {
+	t.Helper()
+	b, err := verify.FromOrderBook(ob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func refBook(t *testing.T, ob *orderbook.OrderBook) *verify.Book {
+	t.Helper()
+	b, err := verify.ReferenceFromOrderBook(ob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestAlignRollsTheOlderBookForward(t *testing.T) {
+	hub := hubBook(t, book(10, [][]string{{"100", "1"}}, [][]string{{"101", "1"}}))
+	ref := refBook(t, book(12, [][]string{{"100", "2"}, {"99", "3"}}, [][]string{{"101", "1"}}))
+
+	// Delivered out of order; 9-10 is older than the hub book and ignored.
+	deltas := []verify.Delta{
+		{First: 12, Final: 12, Bids: [][]string{{"99", "3"}}},
+		{First: 9, Final: 10, Bids: [][]string{{"100", "5"}}},
+	}
+	if err := verify.Align(hub, ref, deltas, 11); !errors.Is(err, verify.ErrIncomplete) {
+		t.Fatalf("align without 11 = %v, want ErrIncomplete", err)
+	}
+
+	deltas = append(deltas, verify.Delta{First: 11, Final: 11, Bids: [][]string{{"100", "2"}}})
+	if err := verify.Align(hub, ref, deltas, 11); err != nil {
+		t.Fatalf("align: %v", err)
+	}
+	if hub.LastUpdateID != 12 {
+		t.Fatalf("hub at %d, want 12", hub.LastUpdateID)
+	}
+	if report := verify.Diff(hub, ref, 0); report.Status != verify.StatusOK || report.Levels != 3 {
+		t.Fatalf("report = %+v", report)
+	}
+}
+
+func TestAlignCannotReachBackBeforeTheBuffer(t *testing.T) {
+	hub := hubBook(t, book(20, nil, nil))
+	ref := refBook(t, book(15, nil, nil))
+
+	if err := verify.Align(hub, ref, nil, 21); !errors.Is(err, verify.ErrUnbridgeable) {
+		t.Fatalf("align = %v, want ErrUnbridgeable", err)
+	}
+}
+
+func TestDiffComparesOnlyTheReferenceRange(t *testing.T) {
+	hub := hubBook(t, book(5,
+		[][]string{{"100", "1"}, {"99", "2"}, {"98", "4"}, {"50", "9"}},
+		[][]string{{"101", "1"}, {"102", "7"}, {"500", "9"}}))
+	ref := refBook(t, book(5,
+		[][]string{{"100", "1"}, {"99", "3"}, {"97", "1"}},
+		[][]string{{"101", "1"}, {"102", "7"}}))
+
+	report := verify.Diff(hub, ref, 0)
+	if report.Status != verify.StatusMismatch {
+		t.Fatalf("status = %s", report.Status)
+	}
+	got := fmt.Sprint(report.Mismatches)
+	want := "[{bid 99 2 3} {bid 98 4 0} {bid 97 0 1}]"
+	if got != want {
+		t.Fatalf("mismatches = %s, want %s", got, want)
+	}
+
+	if report := verify.Diff(hub, ref, 1); report.Status != verify.StatusOK || report.Levels != 2 {
+		t.Fatalf("depth 1 report = %+v", report)
+	}
+}
+
+// fakeSource holds hub as the hub's BTCUSDT book and serves exchange as the
+// exchange's snapshot.
+type fakeSource struct {
+	*testutil.BookSource
+	exchange *orderbook.OrderBook
+}
+
+func newSource(hub, exchange *orderbook.OrderBook) *fakeSource {
+	source := testutil.NewBookSource("", map[string]*orderbook.OrderBook{"BTCUSDT": hub})
+	source.SetSynced("BTCUSDT", true)
+	return &fakeSource{BookSource: source, exchange: exchange}
+}
+
+func (s *fakeSource) FetchSnapshot(context.Context, string) (*orderbook.OrderBook, error) {
+	return s.exchange, nil
+}
+
+func TestCheckPublishesAndResyncsOnMismatch(t *testing.T) {
+	source := newSource(
+		book(7, [][]string{{"100", "1"}, {"99", "1"}}, [][]string{{"101", "1"}}),
+		book(7, [][]string{{"100", "1"}, {"99", "2"}}, [][]string{{"101", "1"}}),
+	)
+	eventBus := bus.New()
+	published := make(chan verify.Report, 1)
+	eventBus.Subscribe(verify.Topic("BTCUSDT"), func(e bus.Event) {
+		published <- e.Data.(verify.Report)
+	})
+
+	v := verify.NewVerifier(config.VerifyConfig{Resync: true}, eventBus, source)
+	report := v.Check(context.Background(), "BTCUSDT")
+
+	if report.Status != verify.StatusMismatch || len(report.Mismatches) != 1 || !report.Resynced {
+		t.Fatalf("report = %+v", report)
+	}
+	if resyncs := source.Resyncs(); len(resyncs) != 1 {
+		t.Fatalf("resyncs = %v", resyncs)
+	}
+	select {
+	case got := <-published:
+		if got.Symbol != "BTCUSDT" || got.Status != verify.StatusMismatch {
+			t.Fatalf("published %+v", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("report not published")
+	}
+	if latest, ok := v.Report("btcusdt"); !ok || latest.LastUpdateID != 7 {
+		t.Fatalf("latest report = %+v, %v", latest, ok)
+	}
+}
+
+func TestCheckToleratesMismatchesWithinLimit(t *testing.T) {
+	source := newSource(book(7, [][]string{{"100", "1"}}, nil), book(7, [][]string{{"100", "2"}}, nil))
+	v := verify.NewVerifier(config.VerifyConfig{Resync: true, MaxMismatches: 1}, bus.New(), source)
+
+	if report := v.Check(context.Background(), "BTCUSDT"); report.Status != verify.StatusMismatch || report.Resynced {
+		t.Fatalf("report = %+v", report)
+	}
+	if resyncs := source.Resyncs(); len(resyncs) != 0 {
+		t.Fatalf("resynced within tolerance: %v", resyncs)
+	}
+}
+
+// fakeHub serves a book at update 10 and then updates 12 and 11, in that order.
+func fakeHub() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := websocket.Accept(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.CloseNow()
+
+		ctx := r.Context()
+		for range 2 {
+			if _, _, err := conn.Read(ctx); err != nil {
+				return
+			}
+		}
+		for _, msg := range []string{
+			`{"method":"subscribe","success":true,"topic":"BTCUSDT@depth","data":{"lastUpdateId":10,"bids":[["100","1"]],"asks":[["101","1"]]}}`,
+			`{"data":{"e":"depthUpdate","E":1,"s":"BTCUSDT","U":12,"u":12,"b":[],"a":[["101","3"]]}}`,
+			`{"data":{"e":"depthUpdate","E":1,"s":"BTCUSDT","U":11,"u":11,"b":[["100","2"]],"a":[]}}`,
+		} {
+			if err := conn.Write(ctx, websocket.MessageText, []byte(msg)); err != nil {
+				return
+			}
+		}
+		<-ctx.Done()
+	}))
+}
+
+func TestCheckRemoteLinesUpWithHubUpdates(t *testing.T) {
+	server := fakeHub()
+	defer server.Close()
+
+	source := newSource(nil, book(12, [][]string{{"100", "2"}}, [][]string{{"101", "3"}}))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	report, err := verify.CheckRemote(ctx, verify.RemoteConfig{
+		URL:      "ws" + strings.TrimPrefix(server.URL, "http"),
+		Exchange: "binance",
+	}, source, "btcusdt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Status != verify.StatusOK || report.LastUpdateID != 12 || report.Symbol != "BTCUSDT" {
+		t.Fatalf("report = %+v", report)
+	}
+}
@@ -0,0 +1,186 @@
+package websocketclient_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ChethiyaNishanath/market-data-hub/internal/domain/exchange"
+	wsInterface "github.com/ChethiyaNishanath/market-data-hub/internal/interfaces/clients/websocket"
+	"github.com/coder/websocket"
+)
+
+func newServer(t *testing.T, handle func(conn *websocket.Conn)) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var connections atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := websocket.Accept(w, r, nil)
+		if err != nil {
+			return
+		}
+		connections.Add(1)
+		handle(conn)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &connections
+}
+
+func wsURL(server *httptest.Server) string {
+	return "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+func TestSupervisorReconnectsAfterServerClose(t *testing.T) {
+	server, connections := newServer(t, func(conn *websocket.Conn) {
+		conn.Close(websocket.StatusGoingAway, "bye")
+	})
+
+	model := exchange.New(wsURL(server))
+	supervisor := wsInterface.NewSupervisor(model, time.Millisecond, 10*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var mu sync.Mutex
+	states := make([]wsInterface.ConnectionState, 0)
+	supervisor.OnStateChange = func(state wsInterface.ConnectionState, reason error) {
+		mu.Lock()
+		defer mu.Unlock()
+		states = append(states, state)
+		if state == wsInterface.StateConnected && connections.Load() >= 3 {
+			cancel()
+		}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		supervisor.Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("supervisor did not reconnect, connections=%d", connections.Load())
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if states[len(states)-1] != wsInterface.StateClosed {
+		t.Errorf("expected final state closed, got %s", states[len(states)-1])
+	}
+}
+
+func TestSupervisorDetectsStaleConnection(t *testing.T) {
+	server, connections := newServer(t, func(conn *websocket.Conn) {
+		ctx := context.Background()
+		for {
+			if _, _, err := conn.Read(ctx); err != nil {
+				return
+			}
+		}
+	})
+
+	model := exchange.New(wsURL(server))
+	model.StaleTimeout = 100 * time.Millisecond
+
+	supervisor := wsInterface.NewSupervisor(model, time.Millisecond, 10*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	reasons := make(chan error, 10)
+	supervisor.OnStateChange = func(state wsInterface.ConnectionState, reason error) {
+		if state == wsInterface.StateDisconnected {
+			reasons <- reason
+		}
+	}
+
+	go supervisor.Run(ctx)
+
+	select {
+	case reason := <-reasons:
+		if !errors.Is(reason, wsInterface.ErrStaleConnection) {
+			t.Fatalf("expected stale connection, got %v", reason)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("stale connection was not detected, connections=%d", connections.Load())
+	}
+}
+
+func TestSupervisorReplacesConnectionAtMaxAge(t *testing.T) {
+	var closed atomic.Int32
+	server, connections := newServer(t, func(conn *websocket.Conn) {
+		defer closed.Add(1)
+		ctx := context.Background()
+		for {
+			if _, _, err := conn.Read(ctx); err != nil {
+				return
+			}
+		}
+	})
+
+	model := exchange.New(wsURL(server))
+	model.MaxConnectionAge = 50 * time.Millisecond
+
+	supervisor := wsInterface.NewSupervisor(model, time.Second, time.Second)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var disconnects atomic.Int32
+	supervisor.OnStateChange = func(state wsInterface.ConnectionState, reason error) {
+		if state == wsInterface.StateDisconnected {
+			disconnects.Add(1)
+		}
+	}
+
+	// The first replacement is set up before the connection it replaces closes.
+	closedAtHandover := make(chan int32, 10)
+	supervisor.OnConnect = func(client *wsInterface.Client) error {
+		if connections.Load() > 1 {
+			closedAtHandover <- closed.Load()
+		}
+		return nil
+	}
+
+	go supervisor.Run(ctx)
+
+	select {
+	case n := <-closedAtHandover:
+		if n != 0 {
+			t.Fatalf("expected the old connection open during the handover, %d closed", n)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("max age was not enforced, connections=%d", connections.Load())
+	}
+	if n := disconnects.Load(); n != 0 {
+		t.Errorf("expected no disconnect during the handover, got %d", n)
+	}
+}
+
+func TestConnectGivesUpAfterDialTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	t.Cleanup(server.Close)
+
+	model := exchange.New(wsURL(server))
+	model.DialTimeout = 50 * time.Millisecond
+	client := wsInterface.New(context.Background(), model)
+
+	start := time.Now()
+	if err := client.Connect(); err == nil {
+		t.Fatal("expected the dial to fail")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("dial took %s despite a 50ms timeout", elapsed)
+	}
+}